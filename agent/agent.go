@@ -3,7 +3,10 @@ package agent
 import (
 	"context"
 	"fmt"
+	"io"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/odpf/meteor/models"
@@ -14,21 +17,34 @@ import (
 	"github.com/pkg/errors"
 )
 
-const defaultBatchSize = 1
+const (
+	defaultBatchSize  = 1
+	defaultBufferSize = 100
+)
 
 // TimerFn of function type
 type TimerFn func() func() int
 
 // Agent runs recipes for specified plugins.
 type Agent struct {
-	extractorFactory *registry.ExtractorFactory
-	processorFactory *registry.ProcessorFactory
-	sinkFactory      *registry.SinkFactory
-	monitor          Monitor
-	logger           log.Logger
-	retrier          *retrier
-	stopOnSinkError  bool
-	timerFn          TimerFn
+	extractorFactory   *registry.ExtractorFactory
+	processorFactory   *registry.ProcessorFactory
+	sinkFactory        *registry.SinkFactory
+	monitor            Monitor
+	logger             log.Logger
+	retrier            *retrier
+	stopOnSinkError    bool
+	timerFn            TimerFn
+	batchSize          int
+	bufferSize         int
+	hooks              []Hook
+	strictHooks        bool
+	include            []string
+	exclude            []string
+	onInvalidRecord    InvalidRecordPolicy
+	processConcurrency int
+	breakerThreshold   int
+	newLogger          func(level string) log.Logger
 }
 
 // NewAgent returns an Agent with plugin factories.
@@ -43,159 +59,402 @@ func NewAgent(config Config) *Agent {
 		timerFn = startDuration
 	}
 
+	batchSize := config.DefaultBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	bufferSize := config.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+
+	newLogger := config.NewLogger
+	if newLogger == nil {
+		newLogger = newLogrusLogger
+	}
+
 	retrier := newRetrier(config.MaxRetries, config.RetryInitialInterval)
 	return &Agent{
-		extractorFactory: config.ExtractorFactory,
-		processorFactory: config.ProcessorFactory,
-		sinkFactory:      config.SinkFactory,
-		stopOnSinkError:  config.StopOnSinkError,
-		monitor:          mt,
-		logger:           config.Logger,
-		retrier:          retrier,
-		timerFn:          timerFn,
+		extractorFactory:   config.ExtractorFactory,
+		processorFactory:   config.ProcessorFactory,
+		sinkFactory:        config.SinkFactory,
+		stopOnSinkError:    config.StopOnSinkError,
+		monitor:            mt,
+		logger:             config.Logger,
+		retrier:            retrier,
+		timerFn:            timerFn,
+		batchSize:          batchSize,
+		bufferSize:         bufferSize,
+		hooks:              config.Hooks,
+		strictHooks:        config.StrictHooks,
+		include:            config.Include,
+		exclude:            config.Exclude,
+		onInvalidRecord:    config.OnInvalidRecord,
+		processConcurrency: config.ProcessConcurrency,
+		breakerThreshold:   config.CircuitBreakerThreshold,
+		newLogger:          newLogger,
 	}
 }
 
 // Validate checks the recipe for linting errors.
+// Use ValidateDetailed to get the plugin and field of each error.
 func (r *Agent) Validate(rcp recipe.Recipe) (errs []error) {
-	if ext, err := r.extractorFactory.Get(rcp.Source.Type); err != nil {
-		errs = append(errs, errors.Wrapf(err, "invalid config for %s (%s)", rcp.Source.Type, plugins.PluginTypeExtractor))
-	} else {
-		if err = ext.Validate(rcp.Source.Config); err != nil {
-			errs = append(errs, errors.Wrapf(err, "invalid config for %s (%s)", rcp.Source.Type, plugins.PluginTypeExtractor))
-		}
-	}
+	return r.ValidateDetailed(rcp).Errs()
+}
 
-	for _, s := range rcp.Sinks {
-		sink, err := r.sinkFactory.Get(s.Name)
-		if err != nil {
-			errs = append(errs, errors.Wrapf(err, "invalid config for %s (%s)", rcp.Source.Type, plugins.PluginTypeExtractor))
-			continue
-		}
-		if err = sink.Validate(s.Config); err != nil {
-			errs = append(errs, errors.Wrapf(err, "invalid config for %s (%s)", s.Name, plugins.PluginTypeSink))
-		}
-	}
+// HealthCheck verifies the source of the recipe can be reached, without extracting anything.
+// It returns nil if the extractor does not implement plugins.HealthChecker.
+func (r *Agent) HealthCheck(rcp recipe.Recipe) error {
+	return r.HealthCheckWithContext(context.Background(), rcp)
+}
 
-	for _, p := range rcp.Processors {
-		procc, err := r.processorFactory.Get(p.Name)
-		if err != nil {
-			errs = append(errs, errors.Wrapf(err, "invalid config for %s (%s)", rcp.Source.Type, plugins.PluginTypeExtractor))
-			continue
-		}
-		if err = procc.Validate(p.Config); err != nil {
-			errs = append(errs, errors.Wrapf(err, "invalid config for %s (%s)", p.Name, plugins.PluginTypeProcessor))
-		}
+// HealthCheckWithContext verifies the source of the recipe can be reached until ctx is cancelled.
+func (r *Agent) HealthCheckWithContext(ctx context.Context, rcp recipe.Recipe) (err error) {
+	extractor, err := r.extractorFactory.Get(rcp.Source.Type)
+	if err != nil {
+		return errors.Wrapf(err, "could not find extractor \"%s\"", rcp.Source.Type)
+	}
+	checker, ok := extractor.(plugins.HealthChecker)
+	if !ok {
+		return nil
+	}
+	if err = r.setupLogger(extractor, rcp.Source.Type, rcp.Source.Config); err != nil {
+		return errors.Wrapf(err, "could not setup logger of extractor \"%s\"", rcp.Source.Type)
+	}
+	if err = extractor.Init(ctx, plugins.WithoutLogLevel(rcp.Source.Config)); err != nil {
+		return errors.Wrapf(err, "could not initiate extractor \"%s\"", rcp.Source.Type)
+	}
+	// the clients opened by Init are released as Extract is not called
+	if closer, ok := extractor.(io.Closer); ok {
+		defer func() {
+			if err := closer.Close(); err != nil {
+				r.logger.Warn("error closing extractor", "extractor", rcp.Source.Type, "error", err)
+			}
+		}()
+	}
+	if err = checker.HealthCheck(ctx); err != nil {
+		return errors.Wrapf(err, "health check failed for extractor \"%s\"", rcp.Source.Type)
 	}
+
 	return
 }
 
 // RunMultiple executes multiple recipes.
 func (r *Agent) RunMultiple(recipes []recipe.Recipe) []Run {
+	return r.RunMultipleWithContext(context.Background(), recipes)
+}
+
+// RunMultipleWithContext executes multiple recipes until ctx is cancelled.
+//
+// Once ctx is cancelled no further recipe is started and the running ones are
+// interrupted: their current batches are sent to the sinks before the sinks are closed.
+// Interrupted runs are marked Incomplete with an error wrapping ctx.Err(), runs failing
+// for other reasons are only marked unsuccessful. Recipes that were not started are
+// left out of the returned runs. A panicking recipe is returned as a failed run
+// holding the panic message.
+func (r *Agent) RunMultipleWithContext(ctx context.Context, recipes []recipe.Recipe) []Run {
 	var wg sync.WaitGroup
 	runs := make([]Run, len(recipes))
+	started := make([]bool, len(recipes))
 
 	for i, recipe := range recipes {
+		if ctx.Err() != nil {
+			r.logger.Warn("skipping recipe, agent is shutting down", "recipe", recipe.Name)
+			continue
+		}
 		wg.Add(1)
+		started[i] = true
 
 		tempIndex := i
 		tempRecipe := recipe
 		go func() {
-			run := r.Run(tempRecipe)
-			runs[tempIndex] = run
-			wg.Done()
+			defer wg.Done()
+			// a panic outside of the extractor must not take the other recipes down
+			defer func() {
+				if rcv := recover(); rcv != nil {
+					r.logger.Error("recipe panicked", "recipe", tempRecipe.Name, "panic", rcv)
+					runs[tempIndex] = Run{Recipe: tempRecipe, Error: errors.Errorf("run panicked: %v", rcv)}
+				}
+			}()
+			runs[tempIndex] = r.RunWithContext(ctx, tempRecipe)
 		}()
 	}
 
 	wg.Wait()
 
-	return runs
+	var result []Run
+	for i, run := range runs {
+		if started[i] {
+			result = append(result, run)
+		}
+	}
+	return result
 }
 
 // Run executes the specified recipe.
 func (r *Agent) Run(recipe recipe.Recipe) (run Run) {
+	return r.RunWithContext(context.Background(), recipe)
+}
+
+// RunWithContext executes the specified recipe until ctx is cancelled.
+// A cancelled run stops accepting extracted records, sends its current batches
+// to the sinks, closes them and is returned as Incomplete.
+// A run taking longer than the recipe Timeout is interrupted the same way and
+// fails with an error wrapping context.DeadlineExceeded.
+// Every line logged during the run holds the recipe name and the generated run id.
+// Plugins can read the recipe name from their context with plugins.RecipeName.
+// Records are emitted at up to the recipe MaxRecordsPerSecond, if set.
+// A run otherwise successful fails if its RecordCount is out of the recipe expected range.
+func (r *Agent) RunWithContext(ctx context.Context, recipe recipe.Recipe) (run Run) {
 	run.Recipe = recipe
-	r.logger.Info("running recipe", "recipe", run.Recipe.Name)
+	run.RunID = newRunID()
+	r = r.withLogger(withFields(r.logger, "recipe", recipe.Name, "run_id", run.RunID))
+	r.logger.Info("running recipe")
+
+	parentCtx := ctx
+	ctx = plugins.WithRecipeName(ctx, recipe.Name)
+	if recipe.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, recipe.Timeout)
+		defer cancel()
+	}
 
 	var (
-		ctx         = context.Background()
-		getDuration = r.timerFn()
-		stream      = newStream()
-		recordCount = 0
+		getDuration    = r.timerFn()
+		stream         = newStream(r.bufferSize)
+		recordCount    int64
+		lineageCount   int64
+		extractedCount int64
+		processedCount int64
+		sinkedCount    int64
+		sinkErrors     = &errorList{}
+		trippedSinks   = &nameList{}
 	)
 
 	defer func() {
 		durationInMs := getDuration()
+		finished := run
+		finished.DurationInMs = durationInMs
+		if err := r.runHooks(Hook.OnFinish, finished); err != nil {
+			run.Error = errors.Wrap(err, "failed to run finish hook")
+			run.Success = false
+		}
 		r.logAndRecordMetrics(run, durationInMs)
 	}()
 
-	runExtractor, err := r.setupExtractor(ctx, recipe.Source, stream)
+	if err := r.runHooks(Hook.OnStart, run); err != nil {
+		run.Error = errors.Wrap(err, "failed to run start hook")
+		return
+	}
+
+	if recipe.MaxRecordsPerSecond < 0 {
+		run.Error = errors.Errorf("invalid max records per second %v", recipe.MaxRecordsPerSecond)
+		return
+	}
+	if err := validateExpectation(recipe); err != nil {
+		run.Error = err
+		return
+	}
+	guard, err := newRecordGuard(r.onInvalidRecord, r.logger)
+	if err != nil {
+		run.Error = errors.Wrap(err, "failed to setup record validation")
+		return
+	}
+	filter, err := newURNFilter(r.include, r.exclude)
+	if err != nil {
+		run.Error = errors.Wrap(err, "failed to setup urn filter")
+		return
+	}
+
+	// records are run through the middlewares by the extractor itself, or handed to workers
+	emit, waitProcessing := stream.push, func() {}
+	if r.processConcurrency > 1 {
+		emit, waitProcessing = stream.concurrentPush(r.processConcurrency)
+	}
+	runExtractor, err := r.setupExtractor(ctx, recipe.Source, emit)
 	if err != nil {
 		run.Error = errors.Wrap(err, "failed to setup extractor")
 		return
 	}
 
+	// invalid records are dropped or fail the run before reaching any other middleware
+	stream.setMiddleware(guard.middleware)
+
+	// throttles the extractor, as emitting blocks until the record went through the middlewares
+	if recipe.MaxRecordsPerSecond > 0 {
+		stream.setMiddleware(newRateLimiter(recipe.MaxRecordsPerSecond).middleware(ctx))
+	}
+
+	// to gather number of records extracted before any is dropped by processors
+	stream.setMiddleware(func(src models.Record) (models.Record, error) {
+		atomic.AddInt64(&extractedCount, 1)
+		return src, nil
+	})
+
+	// processors already started and sinks already initiated are ended and closed
+	// when a later plugin fails to setup, as the stream is then never broadcast
+	setupDone := false
+	defer func() {
+		if !setupDone {
+			stream.runOnCloses()
+		}
+	}()
+
 	for _, pr := range recipe.Processors {
-		if err := r.setupProcessor(ctx, pr, stream); err != nil {
+		if err := r.setupProcessor(ctx, pr, stream, ""); err != nil {
 			run.Error = errors.Wrap(err, "failed to setup processor")
 			return
 		}
 	}
 
+	// asset processor chains are set up in a stable order,
+	// each chain only receives records of its own asset type.
+	assetTypes := make([]string, 0, len(recipe.AssetProcessors))
+	for assetType := range recipe.AssetProcessors {
+		assetTypes = append(assetTypes, assetType)
+	}
+	sort.Strings(assetTypes)
+	for _, assetType := range assetTypes {
+		if !models.IsAssetType(assetType) {
+			run.Error = errors.Errorf("invalid asset type \"%s\" for asset processors", assetType)
+			return
+		}
+		for _, pr := range recipe.AssetProcessors[assetType] {
+			if err := r.setupProcessor(ctx, pr, stream, assetType); err != nil {
+				run.Error = errors.Wrapf(err, "failed to setup processor for %s", assetType)
+				return
+			}
+		}
+	}
+
 	for _, sr := range recipe.Sinks {
-		if err := r.setupSink(ctx, sr, stream); err != nil {
+		if err := r.setupSink(ctx, sr, stream, &sinkedCount, sinkErrors, trippedSinks); err != nil {
 			run.Error = errors.Wrap(err, "failed to setup sink")
 			return
 		}
 	}
+	setupDone = true
 
-	// to gather total number of records extracted
+	// assets are filtered by urn once processed, so processed records are the ones reaching sinks
+	if filter.enabled() {
+		stream.setMiddleware(filter.middleware)
+	}
+
+	// to gather total number of records processed,
+	// lineage edges are counted separately from assets
 	stream.setMiddleware(func(src models.Record) (models.Record, error) {
-		recordCount++
+		atomic.AddInt64(&processedCount, 1)
+		if models.IsLineageRecord(src) {
+			atomic.AddInt64(&lineageCount, 1)
+		} else {
+			atomic.AddInt64(&recordCount, 1)
+		}
 		return src, nil
 	})
 
 	// create a goroutine to let extractor concurrently emit data
 	// while stream is listening via stream.Listen().
+	var (
+		extractErr    error
+		extractorDone = make(chan struct{})
+	)
 	go func() {
 		defer func() {
 			if r := recover(); r != nil {
-				run.Error = fmt.Errorf("%s", r)
+				extractErr = fmt.Errorf("%s", r)
 			}
+			close(extractorDone)
 			stream.Close()
 		}()
-		err = runExtractor()
+		// records still processed by the workers are emitted before the processors are flushed
+		err := func() error {
+			defer waitProcessing()
+			return runExtractor()
+		}()
 		if err != nil {
-			run.Error = errors.Wrap(err, "failed to run extractor")
+			extractErr = errors.Wrap(err, "failed to run extractor")
+			return
+		}
+		if err := stream.flush(); err != nil {
+			extractErr = errors.Wrap(err, "failed to flush processors")
+		}
+	}()
+
+	// close the stream when ctx is cancelled, the extractor
+	// may keep running but its records are no longer emitted.
+	var (
+		interrupted   bool
+		broadcastDone = make(chan struct{})
+		watcherDone   = make(chan struct{})
+	)
+	go func() {
+		defer close(watcherDone)
+		select {
+		case <-ctx.Done():
+			interrupted = stream.stop()
+		case <-broadcastDone:
 		}
 	}()
 
 	// start listening.
 	// this process is blocking
-	if err := stream.broadcast(); err != nil {
+	err = stream.broadcast()
+	close(broadcastDone)
+	<-watcherDone
+
+	select {
+	case <-extractorDone:
+		run.Error = extractErr
+	default:
+	}
+	if err != nil {
 		run.Error = errors.Wrap(err, "failed to broadcast stream")
 	}
+	// the recipe timeout expired if only the derived context is done,
+	// the extractor may have returned the context error before the stream was stopped
+	timedOut := parentCtx.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded)
+	if timedOut && (interrupted || run.Error != nil) {
+		run.Error = errors.Wrapf(ctx.Err(), "run timed out after %s", recipe.Timeout)
+		run.Incomplete = true
+	} else if interrupted {
+		run.Error = errors.Wrap(ctx.Err(), "run interrupted")
+		run.Incomplete = true
+	}
 
 	// code will reach here stream.Listen() is done.
-	run.RecordCount = recordCount
+	run.RecordCount = int(atomic.LoadInt64(&recordCount))
+	run.LineageCount = int(atomic.LoadInt64(&lineageCount))
+	run.ExtractedCount = int(atomic.LoadInt64(&extractedCount))
+	run.ProcessedCount = int(atomic.LoadInt64(&processedCount))
+	run.SinkedCount = int(atomic.LoadInt64(&sinkedCount))
+	run.SinkErrors = sinkErrors.list()
+	run.TrippedSinks = trippedSinks.list()
+	if run.Error == nil {
+		run.Error = checkExpectation(recipe, run.RecordCount)
+	}
 	success := run.Error == nil
 	run.Success = success
 	return
 }
 
-func (r *Agent) setupExtractor(ctx context.Context, sr recipe.SourceRecipe, str *stream) (runFn func() error, err error) {
+func (r *Agent) setupExtractor(ctx context.Context, sr recipe.SourceRecipe, emit plugins.Emit) (runFn func() error, err error) {
 	extractor, err := r.extractorFactory.Get(sr.Type)
 	if err != nil {
 		err = errors.Wrapf(err, "could not find extractor \"%s\"", sr.Type)
 		return
 	}
-	if err = extractor.Init(ctx, sr.Config); err != nil {
+	if err = r.setupLogger(extractor, sr.Type, sr.Config); err != nil {
+		err = errors.Wrapf(err, "could not setup logger of extractor \"%s\"", sr.Type)
+		return
+	}
+	if err = extractor.Init(ctx, plugins.WithoutLogLevel(sr.Config)); err != nil {
 		err = errors.Wrapf(err, "could not initiate extractor \"%s\"", sr.Type)
 		return
 	}
 
 	runFn = func() (err error) {
-		if err = extractor.Extract(ctx, str.push); err != nil {
+		if err = extractor.Extract(ctx, emit); err != nil {
 			err = errors.Wrapf(err, "error running extractor \"%s\"", sr.Type)
 		}
 
@@ -204,16 +463,31 @@ func (r *Agent) setupExtractor(ctx context.Context, sr recipe.SourceRecipe, str
 	return
 }
 
-func (r *Agent) setupProcessor(ctx context.Context, pr recipe.ProcessorRecipe, str *stream) (err error) {
+// setupProcessor registers the processor as a stream middleware,
+// limited to records of assetType if it is not empty.
+func (r *Agent) setupProcessor(ctx context.Context, pr recipe.ProcessorRecipe, str *stream, assetType string) (err error) {
 	var proc plugins.Processor
 	if proc, err = r.processorFactory.Get(pr.Name); err != nil {
 		return errors.Wrapf(err, "could not find processor \"%s\"", pr.Name)
 	}
-	if err = proc.Init(ctx, pr.Config); err != nil {
+	if err = r.setupLogger(proc, pr.Name, pr.Config); err != nil {
+		return errors.Wrapf(err, "could not setup logger of processor \"%s\"", pr.Name)
+	}
+	if err = proc.Init(ctx, plugins.WithoutLogLevel(pr.Config)); err != nil {
 		return errors.Wrapf(err, "could not initiate processor \"%s\"", pr.Name)
 	}
+	if hook, ok := proc.(plugins.RunHook); ok {
+		if err = hook.OnRunStart(ctx); err != nil {
+			return errors.Wrapf(err, "could not start run for processor \"%s\"", pr.Name)
+		}
+		str.onClose(func() {
+			if err := hook.OnRunEnd(ctx); err != nil {
+				r.logger.Warn("error ending run for processor", "processor", pr.Name, "error", err)
+			}
+		})
+	}
 
-	str.setMiddleware(func(src models.Record) (dst models.Record, err error) {
+	middleware := func(src models.Record) (dst models.Record, err error) {
 		dst, err = proc.Process(ctx, src)
 		if err != nil {
 			err = errors.Wrapf(err, "error running processor \"%s\"", pr.Name)
@@ -221,17 +495,48 @@ func (r *Agent) setupProcessor(ctx context.Context, pr recipe.ProcessorRecipe, s
 		}
 
 		return
-	})
+	}
+	if assetType != "" {
+		str.setAssetMiddleware(assetType, middleware)
+	} else {
+		str.setMiddleware(middleware)
+	}
+	if flusher, ok := proc.(plugins.Flusher); ok {
+		str.setFlusher(func() (records []models.Record, err error) {
+			if records, err = flusher.Flush(ctx); err != nil {
+				err = errors.Wrapf(err, "error flushing processor \"%s\"", pr.Name)
+			}
+			return
+		})
+	}
 
 	return
 }
 
-func (r *Agent) setupSink(ctx context.Context, sr recipe.SinkRecipe, stream *stream) (err error) {
+// setupSink subscribes the sink to the stream, adding the number of records it sinked to sinkedCount.
+// The sink name is added to trippedSinks once its circuit breaker trips.
+func (r *Agent) setupSink(ctx context.Context, sr recipe.SinkRecipe, stream *stream, sinkedCount *int64, sinkErrors *errorList, trippedSinks *nameList) (err error) {
+	batchSize := r.batchSize
+	if sr.BatchSize < 0 {
+		return errors.Errorf("invalid batch size %d for sink \"%s\"", sr.BatchSize, sr.Name)
+	}
+	if sr.BatchSize > 0 {
+		batchSize = sr.BatchSize
+	}
+	for _, assetType := range sr.Types {
+		if !models.IsAssetType(assetType) {
+			return errors.Errorf("invalid asset type \"%s\" for sink \"%s\"", assetType, sr.Name)
+		}
+	}
+
 	var sink plugins.Syncer
 	if sink, err = r.sinkFactory.Get(sr.Name); err != nil {
 		return errors.Wrapf(err, "could not find sink \"%s\"", sr.Name)
 	}
-	if err = sink.Init(ctx, sr.Config); err != nil {
+	if err = r.setupLogger(sink, sr.Name, sr.Config); err != nil {
+		return errors.Wrapf(err, "could not setup logger of sink \"%s\"", sr.Name)
+	}
+	if err = sink.Init(ctx, plugins.WithoutLogLevel(sr.Config)); err != nil {
 		return errors.Wrapf(err, "could not initiate sink \"%s\"", sr.Name)
 	}
 
@@ -241,16 +546,32 @@ func (r *Agent) setupSink(ctx context.Context, sr recipe.SinkRecipe, stream *str
 			"sink", sr.Name,
 			"error", e.Error())
 	}
+	breaker := newCircuitBreaker(r.breakerThreshold)
 	stream.subscribe(func(records []models.Record) error {
-		err := r.retrier.retry(func() error {
-			err := sink.Sink(ctx, records)
-			return err
-		}, retryNotification)
+		var err error
+		if breaker.open() {
+			// the backend is deemed down, the batch fails fast instead of going through retries
+			err = errors.Errorf("circuit breaker open, %d records not sent", len(records))
+		} else {
+			err = r.retrier.retry(func() error {
+				err := sink.Sink(ctx, records)
+				return err
+			}, retryNotification)
+			if breaker.record(err) {
+				r.logger.Error("circuit breaker tripped, skipping the remaining batches", "sink", sr.Name, "consecutive_failures", r.breakerThreshold)
+				trippedSinks.add(sr.Name)
+			}
+		}
+
+		if err == nil {
+			atomic.AddInt64(sinkedCount, int64(len(records)))
+		}
 
-		// error (after exhausted retries) will just be skipped and logged
+		// error (after exhausted retries) will just be skipped, logged and reported in the run
 		if err != nil {
 			r.logger.Error("error running sink", "sink", sr.Name, "error", err.Error())
 			if !r.stopOnSinkError {
+				sinkErrors.add(errors.Wrapf(err, "error running sink \"%s\"", sr.Name))
 				err = nil
 			}
 		}
@@ -258,7 +579,7 @@ func (r *Agent) setupSink(ctx context.Context, sr recipe.SinkRecipe, stream *str
 		// TODO: create a new error to signal stopping stream.
 		// returning nil so stream wont stop.
 		return err
-	}, defaultBatchSize)
+	}, batchSize, sr.Types...)
 
 	stream.onClose(func() {
 		if err = sink.Close(); err != nil {
@@ -269,6 +590,46 @@ func (r *Agent) setupSink(ctx context.Context, sr recipe.SinkRecipe, stream *str
 	return
 }
 
+// setupLogger gives the plugin a logger of its own if its config holds a log level,
+// logging the fields of the agent logger. It must be called before the plugin Init.
+func (r *Agent) setupLogger(plugin plugins.Plugin, name string, config map[string]interface{}) error {
+	level, err := plugins.LogLevel(config)
+	if err != nil || level == "" {
+		return err
+	}
+	setter, ok := plugin.(plugins.LoggerSetter)
+	if !ok {
+		r.logger.Warn("plugin does not support log_level, logging at the agent level", "plugin", name)
+		return nil
+	}
+	setter.SetLogger(withLevel(r.logger, r.newLogger(level), "plugin", name))
+
+	return nil
+}
+
+// runHooks calls fn on each hook, hook errors are only returned if hooks are strict.
+func (r *Agent) runHooks(fn func(Hook, Run) error, run Run) error {
+	for _, hook := range r.hooks {
+		err := fn(hook, run)
+		if err == nil {
+			continue
+		}
+		if r.strictHooks {
+			return err
+		}
+		r.logger.Warn("error running hook", "error", err)
+	}
+
+	return nil
+}
+
+// withLogger returns a copy of the agent logging with logger.
+func (r *Agent) withLogger(logger log.Logger) *Agent {
+	agent := *r
+	agent.logger = logger
+	return &agent
+}
+
 // startDuration starts a timer.
 func startDuration() func() int {
 	start := time.Now()
@@ -282,8 +643,45 @@ func (r *Agent) logAndRecordMetrics(run Run, durationInMs int) {
 	run.DurationInMs = durationInMs
 	r.monitor.RecordRun(run)
 	if run.Success {
-		r.logger.Info("done running recipe", "recipe", run.Recipe.Name, "duration_ms", durationInMs, "record_count", run.RecordCount)
+		r.logger.Info("done running recipe", "duration_ms", durationInMs, "record_count", run.RecordCount, "lineage_count", run.LineageCount,
+			"extracted_count", run.ExtractedCount, "processed_count", run.ProcessedCount, "sinked_count", run.SinkedCount, "sink_error_count", len(run.SinkErrors))
 	} else {
-		r.logger.Error("error running recipe", "recipe", run.Recipe.Name, "duration_ms", durationInMs, "records_count", run.RecordCount, "err", run.Error)
+		r.logger.Error("error running recipe", "duration_ms", durationInMs, "records_count", run.RecordCount,
+			"extracted_count", run.ExtractedCount, "processed_count", run.ProcessedCount, "sinked_count", run.SinkedCount, "err", run.Error)
 	}
 }
+
+// errorList collects the errors of concurrent subscribers
+type errorList struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (l *errorList) add(err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errs = append(l.errs, err)
+}
+
+func (l *errorList) list() []error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.errs
+}
+
+type nameList struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (l *nameList) add(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.names = append(l.names, name)
+}
+
+func (l *nameList) list() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.names
+}
@@ -1,24 +1,37 @@
 package agent_test
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/odpf/meteor/agent"
 	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
 	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
 	"github.com/odpf/meteor/plugins"
 	"github.com/odpf/meteor/recipe"
 	"github.com/odpf/meteor/registry"
 	"github.com/odpf/meteor/test/mocks"
 	"github.com/odpf/meteor/test/utils"
+	configutils "github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
-var mockCtx = mock.AnythingOfType("*context.emptyCtx")
+var mockCtx = mock.AnythingOfType("*context.valueCtx")
 
 var validRecipe = recipe.Recipe{
 	Name: "sample",
@@ -37,6 +50,236 @@ var validRecipe = recipe.Recipe{
 	},
 }
 
+func TestAgentValidate(t *testing.T) {
+	newAgent := func(t *testing.T) *agent.Agent {
+		extr := mocks.NewExtractor()
+		extr.On("Validate", mock.Anything).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("oracle", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		proc := mocks.NewProcessor()
+		proc.On("Validate", mock.Anything).Return(plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor})
+		pf := registry.NewProcessorFactory()
+		if err := pf.Register("test-processor", newProcessor(proc)); err != nil {
+			t.Fatal(err)
+		}
+
+		sink := mocks.NewSink()
+		sink.On("Validate", mock.Anything).Return(plugins.InvalidConfigError{Type: plugins.PluginTypeSink})
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		return agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: pf,
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+		})
+	}
+
+	t.Run("should label sink errors with the sink name and type", func(t *testing.T) {
+		errs := newAgent(t).Validate(recipe.Recipe{
+			Source: recipe.SourceRecipe{Type: "oracle"},
+			Sinks:  []recipe.SinkRecipe{{Name: "test-sink"}, {Name: "unknown-sink"}},
+		})
+
+		assert.Len(t, errs, 2)
+		assert.EqualError(t, errs[0], "invalid config for test-sink (sink): invalid sink config")
+		assert.EqualError(t, errs[1], "invalid config for unknown-sink (sink): could not find sink \"unknown-sink\"")
+	})
+
+	t.Run("should label processor errors with the processor name and type", func(t *testing.T) {
+		errs := newAgent(t).Validate(recipe.Recipe{
+			Source:     recipe.SourceRecipe{Type: "oracle"},
+			Processors: []recipe.ProcessorRecipe{{Name: "test-processor"}, {Name: "unknown-processor"}},
+		})
+
+		assert.Len(t, errs, 2)
+		assert.EqualError(t, errs[0], "invalid config for test-processor (processor): invalid processor config")
+		assert.EqualError(t, errs[1], "invalid config for unknown-processor (processor): could not find processor \"unknown-processor\"")
+	})
+}
+
+func TestAgentValidateDetailed(t *testing.T) {
+	t.Run("should return errors per plugin and config field", func(t *testing.T) {
+		type config struct {
+			Host      string `mapstructure:"host" validate:"required"`
+			BatchSize int    `mapstructure:"batch_size" validate:"gte=1"`
+		}
+		configErr := configutils.BuildConfig(map[string]interface{}{"batch_size": 0}, &config{})
+
+		extr := mocks.NewExtractor()
+		extr.On("Validate", mock.Anything).Return(configErr).Once()
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		proc := mocks.NewProcessor()
+		proc.On("Validate", mock.Anything).Return(errors.New("invalid rule")).Once()
+		pf := registry.NewProcessorFactory()
+		if err := pf.Register("test-processor", newProcessor(proc)); err != nil {
+			t.Fatal(err)
+		}
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: pf,
+			SinkFactory:      registry.NewSinkFactory(),
+			Logger:           utils.Logger,
+		})
+		result := r.ValidateDetailed(recipe.Recipe{
+			Name:       "sample",
+			Source:     recipe.SourceRecipe{Type: "test-extractor"},
+			Processors: []recipe.ProcessorRecipe{{Name: "test-processor"}},
+			Sinks:      []recipe.SinkRecipe{{Name: "unknown-sink", BatchSize: -1, Types: []string{"table", "tables"}}},
+		})
+
+		assert.False(t, result.Valid())
+		assert.Equal(t, []agent.ValidationError{
+			{PluginName: "test-extractor", PluginType: plugins.PluginTypeExtractor, Field: "host", Message: "is required"},
+			{PluginName: "test-extractor", PluginType: plugins.PluginTypeExtractor, Field: "batch_size", Message: "failed on \"gte=1\" validation"},
+			{PluginName: "unknown-sink", PluginType: plugins.PluginTypeSink, Field: "batch_size", Message: "invalid batch size -1"},
+			{PluginName: "unknown-sink", PluginType: plugins.PluginTypeSink, Field: "types", Message: "invalid asset type \"tables\""},
+			{PluginName: "unknown-sink", PluginType: plugins.PluginTypeSink, Message: "could not find sink \"unknown-sink\""},
+			{PluginName: "test-processor", PluginType: plugins.PluginTypeProcessor, Message: "invalid rule"},
+		}, result.Errors)
+		assert.Len(t, r.Validate(recipe.Recipe{Source: recipe.SourceRecipe{Type: "unknown-extractor"}}), 1)
+	})
+
+	t.Run("should return errors of invalid log levels", func(t *testing.T) {
+		extr := mocks.NewExtractor()
+		extr.On("Validate", mock.Anything).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+		proc := mocks.NewProcessor()
+		proc.On("Validate", mock.Anything).Return(nil)
+		pf := registry.NewProcessorFactory()
+		if err := pf.Register("test-processor", newProcessor(proc)); err != nil {
+			t.Fatal(err)
+		}
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: pf,
+			SinkFactory:      registry.NewSinkFactory(),
+			Logger:           utils.Logger,
+		})
+		result := r.ValidateDetailed(recipe.Recipe{
+			Source:     recipe.SourceRecipe{Type: "test-extractor", Config: map[string]interface{}{"log_level": "debug"}},
+			Processors: []recipe.ProcessorRecipe{{Name: "test-processor", Config: map[string]interface{}{"log_level": "verbose"}}},
+		})
+		assert.Equal(t, []agent.ValidationError{
+			{PluginName: "test-processor", PluginType: plugins.PluginTypeProcessor, Field: "log_level", Message: "invalid log level \"verbose\""},
+		}, result.Errors)
+	})
+
+	t.Run("should return valid result for valid recipe", func(t *testing.T) {
+		extr := mocks.NewExtractor()
+		extr.On("Validate", mock.Anything).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      registry.NewSinkFactory(),
+			Logger:           utils.Logger,
+		})
+		result := r.ValidateDetailed(recipe.Recipe{Source: recipe.SourceRecipe{Type: "test-extractor"}})
+		assert.True(t, result.Valid())
+		assert.Empty(t, r.Validate(recipe.Recipe{Source: recipe.SourceRecipe{Type: "test-extractor"}}))
+	})
+}
+
+func TestAgentHealthCheck(t *testing.T) {
+	rcp := recipe.Recipe{
+		Name: "sample",
+		Source: recipe.SourceRecipe{
+			Type:   "test-extractor",
+			Config: map[string]interface{}{"foo": "bar"},
+		},
+	}
+	newAgent := func(t *testing.T, extr plugins.Extractor) *agent.Agent {
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+		return agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      registry.NewSinkFactory(),
+			Logger:           utils.Logger,
+		})
+	}
+
+	t.Run("should return nil without initiating extractors not implementing health checks", func(t *testing.T) {
+		extr := mocks.NewExtractor()
+		defer extr.AssertExpectations(t)
+
+		assert.NoError(t, newAgent(t, extr).HealthCheck(rcp))
+	})
+
+	t.Run("should return error if extractor could not be found", func(t *testing.T) {
+		err := newAgent(t, mocks.NewExtractor()).HealthCheck(recipe.Recipe{
+			Source: recipe.SourceRecipe{Type: "unknown-extractor"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "could not find extractor \"unknown-extractor\"")
+	})
+
+	t.Run("should return error if extractor could not be initiated", func(t *testing.T) {
+		extr := &healthCheckExtractor{}
+		extr.On("Init", mock.Anything, rcp.Source.Config).Return(errors.New("some error")).Once()
+		defer extr.AssertExpectations(t)
+
+		err := newAgent(t, extr).HealthCheck(rcp)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "could not initiate extractor \"test-extractor\": some error")
+	})
+
+	t.Run("should return health check error", func(t *testing.T) {
+		extr := &healthCheckExtractor{}
+		extr.On("Init", mock.Anything, rcp.Source.Config).Return(nil).Once()
+		extr.On("HealthCheck", mock.Anything).Return(errors.New("connection refused")).Once()
+		defer extr.AssertExpectations(t)
+
+		err := newAgent(t, extr).HealthCheck(rcp)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "health check failed for extractor \"test-extractor\": connection refused")
+	})
+
+	t.Run("should return nil without extracting when source is healthy", func(t *testing.T) {
+		extr := &healthCheckExtractor{}
+		extr.On("Init", mock.Anything, rcp.Source.Config).Return(nil).Once()
+		extr.On("HealthCheck", mock.Anything).Return(nil).Once()
+		defer extr.AssertExpectations(t)
+
+		assert.NoError(t, newAgent(t, extr).HealthCheck(rcp))
+	})
+
+	t.Run("should close extractor after health check", func(t *testing.T) {
+		for _, healthErr := range []error{nil, errors.New("connection refused")} {
+			extr := &closingHealthCheckExtractor{}
+			extr.On("Init", mock.Anything, rcp.Source.Config).Return(nil).Once()
+			extr.On("HealthCheck", mock.Anything).Return(healthErr).Once()
+			extr.On("Close").Return(nil).Once()
+
+			err := newAgent(t, extr).HealthCheck(rcp)
+			assert.Equal(t, healthErr == nil, err == nil)
+			extr.AssertExpectations(t)
+		}
+	})
+}
+
 func TestRunnerRun(t *testing.T) {
 	t.Run("should return run", func(t *testing.T) {
 		r := agent.NewAgent(agent.Config{
@@ -345,7 +588,7 @@ func TestRunnerRun(t *testing.T) {
 
 	t.Run("should return error when processing fails", func(t *testing.T) {
 		data := []models.Record{
-			models.NewRecord(&assetsv1beta1.Table{}),
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}),
 		}
 
 		extr := mocks.NewExtractor()
@@ -393,7 +636,7 @@ func TestRunnerRun(t *testing.T) {
 
 	t.Run("should return error when processing panics", func(t *testing.T) {
 		data := []models.Record{
-			models.NewRecord(&assetsv1beta1.Table{}),
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}),
 		}
 
 		extr := mocks.NewExtractor()
@@ -440,7 +683,7 @@ func TestRunnerRun(t *testing.T) {
 
 	t.Run("should not return error when sink fails", func(t *testing.T) {
 		data := []models.Record{
-			models.NewRecord(&assetsv1beta1.Table{}),
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}),
 		}
 
 		extr := mocks.NewExtractor()
@@ -485,11 +728,14 @@ func TestRunnerRun(t *testing.T) {
 		run := r.Run(validRecipe)
 		assert.True(t, run.Success)
 		assert.NoError(t, run.Error)
+		assert.Equal(t, 1, run.ExtractedCount)
+		assert.Equal(t, 1, run.ProcessedCount)
+		assert.Equal(t, 0, run.SinkedCount)
 	})
 
 	t.Run("should return error when sink fails if StopOnSinkError is true", func(t *testing.T) {
 		data := []models.Record{
-			models.NewRecord(&assetsv1beta1.Table{}),
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}),
 		}
 
 		extr := mocks.NewExtractor()
@@ -540,7 +786,7 @@ func TestRunnerRun(t *testing.T) {
 
 	t.Run("should return run on success", func(t *testing.T) {
 		data := []models.Record{
-			models.NewRecord(&assetsv1beta1.Table{}),
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}),
 		}
 
 		extr := mocks.NewExtractor()
@@ -590,7 +836,7 @@ func TestRunnerRun(t *testing.T) {
 	t.Run("should collect run metrics", func(t *testing.T) {
 		expectedDuration := 1000
 		data := []models.Record{
-			models.NewRecord(&assetsv1beta1.Table{}),
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}),
 		}
 		timerFn := func() func() int {
 			return func() int {
@@ -647,7 +893,7 @@ func TestRunnerRun(t *testing.T) {
 	t.Run("should retry if sink returns retry error", func(t *testing.T) {
 		err := errors.New("some-error")
 		data := []models.Record{
-			models.NewRecord(&assetsv1beta1.Table{}),
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}),
 		}
 
 		extr := mocks.NewExtractor()
@@ -696,37 +942,33 @@ func TestRunnerRun(t *testing.T) {
 		assert.NoError(t, run.Error)
 		assert.Equal(t, validRecipe, run.Recipe)
 	})
-}
 
-func TestRunnerRunMultiple(t *testing.T) {
-	t.Run("should return list of runs when finished", func(t *testing.T) {
-		validRecipe2 := validRecipe
-		validRecipe2.Name = "sample-2"
-		recipeList := []recipe.Recipe{validRecipe, validRecipe2}
+	t.Run("should report sink errors after exhausted retries without failing the run", func(t *testing.T) {
+		err := errors.New("some-error")
 		data := []models.Record{
-			models.NewRecord(&assetsv1beta1.Table{}),
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}),
 		}
+
 		extr := mocks.NewExtractor()
 		extr.SetEmit(data)
-		extr.On("Init", mockCtx, validRecipe.Source.Config).Return(nil)
-		extr.On("Extract", mockCtx, mock.AnythingOfType("plugins.Emit")).Return(nil)
+		extr.On("Init", mock.Anything, validRecipe.Source.Config).Return(nil).Once()
+		extr.On("Extract", mock.Anything, mock.AnythingOfType("plugins.Emit")).Return(nil)
 		ef := registry.NewExtractorFactory()
 		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
 			t.Fatal(err)
 		}
 
 		proc := mocks.NewProcessor()
-		proc.On("Init", mockCtx, validRecipe.Processors[0].Config).Return(nil)
-		proc.On("Process", mockCtx, data[0]).Return(data[0], nil)
-		defer proc.AssertExpectations(t)
+		proc.On("Init", mock.Anything, validRecipe.Processors[0].Config).Return(nil).Once()
+		proc.On("Process", mock.Anything, data[0]).Return(data[0], nil)
 		pf := registry.NewProcessorFactory()
 		if err := pf.Register("test-processor", newProcessor(proc)); err != nil {
 			t.Fatal(err)
 		}
 
 		sink := mocks.NewSink()
-		sink.On("Init", mockCtx, validRecipe.Sinks[0].Config).Return(nil)
-		sink.On("Sink", mockCtx, data).Return(nil)
+		sink.On("Init", mock.Anything, validRecipe.Sinks[0].Config).Return(nil).Once()
+		sink.On("Sink", mock.Anything, data).Return(plugins.NewRetryError(err)).Times(3)
 		sink.On("Close").Return(nil)
 		defer sink.AssertExpectations(t)
 		sf := registry.NewSinkFactory()
@@ -734,62 +976,1740 @@ func TestRunnerRunMultiple(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		monitor := newMockMonitor()
-		monitor.On("RecordRun", mock.AnythingOfType("agent.Run"))
-		defer monitor.AssertExpectations(t)
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory:     ef,
+			ProcessorFactory:     pf,
+			SinkFactory:          sf,
+			Logger:               utils.Logger,
+			MaxRetries:           2,
+			RetryInitialInterval: 1 * time.Millisecond,
+		})
+		run := r.Run(validRecipe)
+		assert.True(t, run.Success)
+		assert.NoError(t, run.Error)
+		assert.Equal(t, 0, run.SinkedCount)
+		if assert.Len(t, run.SinkErrors, 1) {
+			assert.True(t, errors.Is(run.SinkErrors[0], err))
+			assert.Contains(t, run.SinkErrors[0].Error(), "test-sink")
+		}
+	})
+}
+
+func TestRunnerRunProcessorRunHook(t *testing.T) {
+	t.Run("should call run hooks once per run", func(t *testing.T) {
+		data := []models.Record{
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}),
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}),
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}),
+		}
+
+		extr := mocks.NewExtractor()
+		extr.SetEmit(data)
+		extr.On("Init", mock.Anything, validRecipe.Source.Config).Return(nil).Once()
+		extr.On("Extract", mock.Anything, mock.AnythingOfType("plugins.Emit")).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		proc := new(hookProcessor)
+		proc.On("Init", mock.Anything, validRecipe.Processors[0].Config).Return(nil).Once()
+		proc.On("Process", mock.Anything, mock.AnythingOfType("models.Record")).Return(data[0], nil).Times(len(data))
+		defer proc.AssertExpectations(t)
+		pf := registry.NewProcessorFactory()
+		if err := pf.Register("test-processor", newProcessor(proc)); err != nil {
+			t.Fatal(err)
+		}
+
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, validRecipe.Sinks[0].Config).Return(nil).Once()
+		sink.On("Sink", mock.Anything, mock.Anything).Return(nil)
+		sink.On("Close").Return(nil)
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
 
 		r := agent.NewAgent(agent.Config{
 			ExtractorFactory: ef,
 			ProcessorFactory: pf,
 			SinkFactory:      sf,
 			Logger:           utils.Logger,
-			Monitor:          monitor,
 		})
-		runs := r.RunMultiple(recipeList)
-
-		assert.Len(t, runs, len(recipeList))
-		assert.Equal(t, []agent.Run{
-			{Recipe: validRecipe, RecordCount: len(data), Success: true},
-			{Recipe: validRecipe2, RecordCount: len(data), Success: true},
-		}, runs)
+		run := r.Run(validRecipe)
+		assert.NoError(t, run.Error)
+		assert.Equal(t, 1, proc.startCount)
+		assert.Equal(t, 1, proc.endCount)
 	})
-}
-
-func newExtractor(extr plugins.Extractor) func() plugins.Extractor {
-	return func() plugins.Extractor {
-		return extr
-	}
-}
 
-func newProcessor(proc plugins.Processor) func() plugins.Processor {
-	return func() plugins.Processor {
-		return proc
-	}
-}
+	t.Run("should return error when starting run hook fails", func(t *testing.T) {
+		extr := mocks.NewExtractor()
+		extr.On("Init", mock.Anything, validRecipe.Source.Config).Return(nil).Once()
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
 
-func newSink(sink plugins.Syncer) func() plugins.Syncer {
-	return func() plugins.Syncer {
-		return sink
-	}
-}
+		proc := &hookProcessor{startErr: errors.New("some error")}
+		proc.On("Init", mock.Anything, validRecipe.Processors[0].Config).Return(nil).Once()
+		defer proc.AssertExpectations(t)
+		pf := registry.NewProcessorFactory()
+		if err := pf.Register("test-processor", newProcessor(proc)); err != nil {
+			t.Fatal(err)
+		}
 
-type mockMonitor struct {
-	mock.Mock
-}
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: pf,
+			SinkFactory:      registry.NewSinkFactory(),
+			Logger:           utils.Logger,
+		})
+		run := r.Run(validRecipe)
+		assert.False(t, run.Success)
+		assert.Error(t, run.Error)
+		assert.Equal(t, 0, proc.endCount)
+	})
 
-func newMockMonitor() *mockMonitor {
-	return &mockMonitor{}
-}
+	t.Run("should end run of started processors when a sink fails to setup", func(t *testing.T) {
+		extr := mocks.NewExtractor()
+		extr.On("Init", mock.Anything, validRecipe.Source.Config).Return(nil).Once()
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
 
-func (m *mockMonitor) RecordRun(run agent.Run) {
-	m.Called(run)
-}
+		proc := new(hookProcessor)
+		proc.On("Init", mock.Anything, validRecipe.Processors[0].Config).Return(nil).Once()
+		defer proc.AssertExpectations(t)
+		pf := registry.NewProcessorFactory()
+		if err := pf.Register("test-processor", newProcessor(proc)); err != nil {
+			t.Fatal(err)
+		}
 
-type panicExtractor struct {
-	mocks.Extractor
-}
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, validRecipe.Sinks[0].Config).Return(errors.New("some error")).Once()
+		defer sink.AssertExpectations(t)
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
 
-func (e *panicExtractor) Extract(_ context.Context, _ plugins.Emit) (err error) {
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: pf,
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+		})
+		run := r.Run(validRecipe)
+		assert.False(t, run.Success)
+		assert.Error(t, run.Error)
+		assert.Equal(t, 1, proc.startCount)
+		assert.Equal(t, 1, proc.endCount)
+	})
+}
+
+func TestRunnerRunLineage(t *testing.T) {
+	t.Run("should send lineage records to sink and count them separately", func(t *testing.T) {
+		table := models.NewRecord(&assetsv1beta1.Table{
+			Resource: &commonv1beta1.Resource{Urn: "table-a"},
+		})
+		edge := models.NewLineageRecord(
+			&commonv1beta1.Resource{Urn: "table-a", Type: "table"},
+			&commonv1beta1.Resource{Urn: "table-b", Type: "table"},
+		)
+		data := []models.Record{table, edge}
+
+		extr := mocks.NewExtractor()
+		extr.SetEmit(data)
+		extr.On("Init", mock.Anything, validRecipe.Source.Config).Return(nil).Once()
+		extr.On("Extract", mock.Anything, mock.AnythingOfType("plugins.Emit")).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		proc := mocks.NewProcessor()
+		proc.On("Init", mock.Anything, validRecipe.Processors[0].Config).Return(nil).Once()
+		proc.On("Process", mock.Anything, table).Return(table, nil)
+		proc.On("Process", mock.Anything, edge).Return(edge, nil)
+		pf := registry.NewProcessorFactory()
+		if err := pf.Register("test-processor", newProcessor(proc)); err != nil {
+			t.Fatal(err)
+		}
+
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, validRecipe.Sinks[0].Config).Return(nil).Once()
+		sink.On("Sink", mock.Anything, []models.Record{table}).Return(nil).Once()
+		sink.On("Sink", mock.Anything, []models.Record{edge}).Return(nil).Once()
+		sink.On("Close").Return(nil)
+		defer sink.AssertExpectations(t)
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: pf,
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+		})
+		run := r.Run(validRecipe)
+		assert.NoError(t, run.Error)
+		assert.Equal(t, 1, run.RecordCount)
+		assert.Equal(t, 1, run.LineageCount)
+	})
+}
+
+func TestRunnerRunSinkTypes(t *testing.T) {
+	table := models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "table"}})
+	user := models.NewRecord(&assetsv1beta1.User{Resource: &commonv1beta1.Resource{Urn: "user"}})
+	data := []models.Record{table, user}
+
+	newAgent := func(t *testing.T, sinks map[string]*mocks.Sink) *agent.Agent {
+		extr := mocks.NewExtractor()
+		extr.SetEmit(data)
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		extr.On("Extract", mock.Anything, mock.AnythingOfType("plugins.Emit")).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		sf := registry.NewSinkFactory()
+		for name, sink := range sinks {
+			if err := sf.Register(name, newSink(sink)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		return agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+		})
+	}
+
+	t.Run("should only send records of the sink types", func(t *testing.T) {
+		tableSink := mocks.NewSink()
+		tableSink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		tableSink.On("Sink", mock.Anything, []models.Record{table}).Return(nil).Once()
+		tableSink.On("Close").Return(nil)
+		defer tableSink.AssertExpectations(t)
+
+		allSink := mocks.NewSink()
+		allSink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		allSink.On("Sink", mock.Anything, []models.Record{table}).Return(nil).Once()
+		allSink.On("Sink", mock.Anything, []models.Record{user}).Return(nil).Once()
+		allSink.On("Close").Return(nil)
+		defer allSink.AssertExpectations(t)
+
+		r := newAgent(t, map[string]*mocks.Sink{"table-sink": tableSink, "all-sink": allSink})
+		run := r.Run(recipe.Recipe{
+			Name:   "sample",
+			Source: recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks: []recipe.SinkRecipe{
+				{Name: "table-sink", Types: []string{"table", "dashboard"}},
+				{Name: "all-sink"},
+			},
+		})
+		assert.NoError(t, run.Error)
+		assert.Equal(t, 2, run.RecordCount)
+		assert.Equal(t, 3, run.SinkedCount)
+	})
+
+	t.Run("should return error for invalid sink type", func(t *testing.T) {
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil)
+
+		r := newAgent(t, map[string]*mocks.Sink{"test-sink": sink})
+		run := r.Run(recipe.Recipe{
+			Name:   "sample",
+			Source: recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:  []recipe.SinkRecipe{{Name: "test-sink", Types: []string{"tables"}}},
+		})
+		assert.False(t, run.Success)
+		assert.EqualError(t, run.Error, "failed to setup sink: invalid asset type \"tables\" for sink \"test-sink\"")
+	})
+}
+
+func TestRunnerRunBatchSize(t *testing.T) {
+	data := []models.Record{
+		models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "table-1"}}),
+		models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "table-2"}}),
+		models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "table-3"}}),
+	}
+
+	newAgent := func(t *testing.T, sink *mocks.Sink, defaultBatchSize int) *agent.Agent {
+		extr := mocks.NewExtractor()
+		extr.SetEmit(data)
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		extr.On("Extract", mock.Anything, mock.AnythingOfType("plugins.Emit")).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		return agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+			DefaultBatchSize: defaultBatchSize,
+		})
+	}
+
+	t.Run("should use default batch size when sink does not set one", func(t *testing.T) {
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink.On("Sink", mock.Anything, data[:2]).Return(nil).Once()
+		sink.On("Sink", mock.Anything, data[2:]).Return(nil).Once()
+		sink.On("Close").Return(nil)
+		defer sink.AssertExpectations(t)
+
+		r := newAgent(t, sink, 2)
+		run := r.Run(recipe.Recipe{
+			Name:   "sample",
+			Source: recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:  []recipe.SinkRecipe{{Name: "test-sink"}},
+		})
+		assert.NoError(t, run.Error)
+	})
+
+	t.Run("should prefer sink batch size over default batch size", func(t *testing.T) {
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink.On("Sink", mock.Anything, data).Return(nil).Once()
+		sink.On("Close").Return(nil)
+		defer sink.AssertExpectations(t)
+
+		r := newAgent(t, sink, 2)
+		run := r.Run(recipe.Recipe{
+			Name:   "sample",
+			Source: recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:  []recipe.SinkRecipe{{Name: "test-sink", BatchSize: 3}},
+		})
+		assert.NoError(t, run.Error)
+	})
+
+	t.Run("should fall back to batch size of one when default batch size is not positive", func(t *testing.T) {
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		for _, d := range data {
+			sink.On("Sink", mock.Anything, []models.Record{d}).Return(nil).Once()
+		}
+		sink.On("Close").Return(nil)
+		defer sink.AssertExpectations(t)
+
+		r := newAgent(t, sink, -1)
+		run := r.Run(recipe.Recipe{
+			Name:   "sample",
+			Source: recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:  []recipe.SinkRecipe{{Name: "test-sink"}},
+		})
+		assert.NoError(t, run.Error)
+	})
+
+	t.Run("should return error when sink batch size is negative", func(t *testing.T) {
+		sink := mocks.NewSink()
+
+		r := newAgent(t, sink, 2)
+		run := r.Run(recipe.Recipe{
+			Name:   "sample",
+			Source: recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:  []recipe.SinkRecipe{{Name: "test-sink", BatchSize: -1}},
+		})
+		assert.Error(t, run.Error)
+	})
+}
+
+func TestRunnerRunAssetProcessors(t *testing.T) {
+	t.Run("should only send records to the processor chain of their asset type", func(t *testing.T) {
+		table := models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "table-1"}})
+		dashboard := models.NewRecord(&assetsv1beta1.Dashboard{Resource: &commonv1beta1.Resource{Urn: "dashboard-1"}})
+		processedTable := models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "table-1", Name: "processed"}})
+		data := []models.Record{table, dashboard}
+
+		extr := mocks.NewExtractor()
+		extr.SetEmit(data)
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		extr.On("Extract", mock.Anything, mock.AnythingOfType("plugins.Emit")).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		// any unexpected call panics the mock and fails the run
+		tableProc := mocks.NewProcessor()
+		tableProc.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		tableProc.On("Process", mock.Anything, table).Return(processedTable, nil).Once()
+		defer tableProc.AssertExpectations(t)
+		tableProc2 := mocks.NewProcessor()
+		tableProc2.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		tableProc2.On("Process", mock.Anything, processedTable).Return(processedTable, nil).Once()
+		defer tableProc2.AssertExpectations(t)
+		dashboardProc := mocks.NewProcessor()
+		dashboardProc.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		dashboardProc.On("Process", mock.Anything, dashboard).Return(dashboard, nil).Once()
+		defer dashboardProc.AssertExpectations(t)
+		pf := registry.NewProcessorFactory()
+		if err := pf.Register("table-processor", newProcessor(tableProc)); err != nil {
+			t.Fatal(err)
+		}
+		if err := pf.Register("table-processor-2", newProcessor(tableProc2)); err != nil {
+			t.Fatal(err)
+		}
+		if err := pf.Register("dashboard-processor", newProcessor(dashboardProc)); err != nil {
+			t.Fatal(err)
+		}
+
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink.On("Sink", mock.Anything, []models.Record{processedTable}).Return(nil).Once()
+		sink.On("Sink", mock.Anything, []models.Record{dashboard}).Return(nil).Once()
+		sink.On("Close").Return(nil)
+		defer sink.AssertExpectations(t)
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: pf,
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+		})
+		run := r.Run(recipe.Recipe{
+			Name:   "sample",
+			Source: recipe.SourceRecipe{Type: "test-extractor"},
+			AssetProcessors: map[string][]recipe.ProcessorRecipe{
+				"table":     {{Name: "table-processor"}, {Name: "table-processor-2"}},
+				"dashboard": {{Name: "dashboard-processor"}},
+			},
+			Sinks: []recipe.SinkRecipe{{Name: "test-sink"}},
+		})
+		assert.NoError(t, run.Error)
+		assert.Equal(t, 2, run.RecordCount)
+	})
+
+	t.Run("should return error for unknown asset type", func(t *testing.T) {
+		extr := mocks.NewExtractor()
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      registry.NewSinkFactory(),
+			Logger:           utils.Logger,
+		})
+		run := r.Run(recipe.Recipe{
+			Name:   "sample",
+			Source: recipe.SourceRecipe{Type: "test-extractor"},
+			AssetProcessors: map[string][]recipe.ProcessorRecipe{
+				"tabel": {{Name: "table-processor"}},
+			},
+			Sinks: []recipe.SinkRecipe{{Name: "test-sink"}},
+		})
+		assert.Error(t, run.Error)
+	})
+}
+
+func TestRunnerRunDropRecord(t *testing.T) {
+	t.Run("should not send dropped records to sinks", func(t *testing.T) {
+		kept := models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "orders"}})
+		dropped := models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "tmp_orders"}})
+		data := []models.Record{kept, dropped}
+
+		extr := mocks.NewExtractor()
+		extr.SetEmit(data)
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		extr.On("Extract", mock.Anything, mock.AnythingOfType("plugins.Emit")).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		proc := mocks.NewProcessor()
+		proc.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		proc.On("Process", mock.Anything, kept).Return(kept, nil).Once()
+		proc.On("Process", mock.Anything, dropped).Return(dropped, plugins.NewDropRecordError("temporary table")).Once()
+		defer proc.AssertExpectations(t)
+		pf := registry.NewProcessorFactory()
+		if err := pf.Register("test-processor", newProcessor(proc)); err != nil {
+			t.Fatal(err)
+		}
+
+		// any unexpected call panics the mock and fails the run
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink.On("Sink", mock.Anything, []models.Record{kept}).Return(nil).Once()
+		sink.On("Close").Return(nil)
+		defer sink.AssertExpectations(t)
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: pf,
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+		})
+		run := r.Run(recipe.Recipe{
+			Name:       "sample",
+			Source:     recipe.SourceRecipe{Type: "test-extractor"},
+			Processors: []recipe.ProcessorRecipe{{Name: "test-processor"}},
+			Sinks:      []recipe.SinkRecipe{{Name: "test-sink"}},
+		})
+		assert.NoError(t, run.Error)
+		assert.True(t, run.Success)
+		assert.Equal(t, 1, run.RecordCount)
+		assert.Equal(t, 2, run.ExtractedCount)
+		assert.Equal(t, 1, run.ProcessedCount)
+		assert.Equal(t, 1, run.SinkedCount)
+	})
+}
+
+func TestRunnerRunURNFilter(t *testing.T) {
+	orders := models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sales.orders"}})
+	tmpOrders := models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sales.tmp_orders"}})
+	employees := models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "hr.employees"}})
+	edge := models.NewLineageRecord(
+		&commonv1beta1.Resource{Urn: "hr.employees", Type: "table"},
+		&commonv1beta1.Resource{Urn: "sales.orders", Type: "table"},
+	)
+	data := []models.Record{orders, tmpOrders, employees, edge}
+
+	newAgent := func(t *testing.T, include, exclude []string, sinked []models.Record) *agent.Agent {
+		extr := mocks.NewExtractor()
+		extr.SetEmit(data)
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil)
+		extr.On("Extract", mock.Anything, mock.AnythingOfType("plugins.Emit")).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		// any unexpected call panics the mock and fails the run
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil)
+		if len(sinked) > 0 {
+			sink.On("Sink", mock.Anything, sinked).Return(nil).Once()
+			sink.On("Close").Return(nil)
+			t.Cleanup(func() { sink.AssertExpectations(t) })
+		}
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		return agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+			DefaultBatchSize: len(data),
+			Include:          include,
+			Exclude:          exclude,
+		})
+	}
+	rcp := recipe.Recipe{
+		Name:   "sample",
+		Source: recipe.SourceRecipe{Type: "test-extractor"},
+		Sinks:  []recipe.SinkRecipe{{Name: "test-sink"}},
+	}
+
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		sinked  []models.Record
+	}{
+		{
+			name:    "should only sink assets matching an include pattern",
+			include: []string{"sales.*", "finance.*"},
+			sinked:  []models.Record{orders, tmpOrders, edge},
+		},
+		{
+			name:    "should not sink assets matching an exclude pattern",
+			exclude: []string{"*.tmp_*"},
+			sinked:  []models.Record{orders, employees, edge},
+		},
+		{
+			name:    "should exclude assets among the included ones",
+			include: []string{"sales.*"},
+			exclude: []string{"*.tmp_*"},
+			sinked:  []models.Record{orders, edge},
+		},
+		{
+			name:    "should pass lineage records when every asset is filtered",
+			include: []string{"marketing.*"},
+			sinked:  []models.Record{edge},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			run := newAgent(t, tc.include, tc.exclude, tc.sinked).Run(rcp)
+			assert.NoError(t, run.Error)
+			assert.True(t, run.Success)
+			assert.Equal(t, len(data), run.ExtractedCount)
+			assert.Equal(t, len(tc.sinked), run.ProcessedCount)
+			assert.Equal(t, len(tc.sinked), run.SinkedCount)
+		})
+	}
+
+	t.Run("should fail the run on invalid pattern", func(t *testing.T) {
+		run := newAgent(t, []string{"sales.["}, nil, nil).Run(rcp)
+		require.Error(t, run.Error)
+		assert.Contains(t, run.Error.Error(), "invalid urn pattern \"sales.[\"")
+		assert.False(t, run.Success)
+	})
+}
+
+func TestRunnerRunInvalidRecord(t *testing.T) {
+	orders := models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sales.orders"}})
+	data := []models.Record{
+		models.NewRecord(nil),
+		orders,
+		models.NewRecord((*assetsv1beta1.Table)(nil)),
+		models.NewRecord(&assetsv1beta1.Topic{Resource: &commonv1beta1.Resource{Name: "orders"}}),
+		models.NewLineageRecord(&commonv1beta1.Resource{Urn: "sales.orders"}, nil),
+	}
+
+	newAgent := func(t *testing.T, policy agent.InvalidRecordPolicy, sink *mocks.Sink) *agent.Agent {
+		extr := mocks.NewExtractor()
+		extr.SetEmit(data)
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil)
+		extr.On("Extract", mock.Anything, mock.AnythingOfType("plugins.Emit")).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		return agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+			DefaultBatchSize: len(data),
+			OnInvalidRecord:  policy,
+		})
+	}
+	rcp := recipe.Recipe{
+		Name:   "sample",
+		Source: recipe.SourceRecipe{Type: "test-extractor"},
+		Sinks:  []recipe.SinkRecipe{{Name: "test-sink"}},
+	}
+
+	for name, policy := range map[string]agent.InvalidRecordPolicy{
+		"should skip invalid records by default":       "",
+		"should skip invalid records with skip policy": agent.InvalidRecordSkip,
+	} {
+		t.Run(name, func(t *testing.T) {
+			sink := mocks.NewSink()
+			sink.On("Init", mock.Anything, mock.Anything).Return(nil)
+			sink.On("Sink", mock.Anything, []models.Record{orders}).Return(nil).Once()
+			sink.On("Close").Return(nil)
+			defer sink.AssertExpectations(t)
+
+			run := newAgent(t, policy, sink).Run(rcp)
+			assert.NoError(t, run.Error)
+			assert.True(t, run.Success)
+			assert.Equal(t, 1, run.ExtractedCount)
+			assert.Equal(t, 1, run.SinkedCount)
+		})
+	}
+
+	t.Run("should fail the run on the first invalid record with fail policy", func(t *testing.T) {
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil)
+		sink.On("Sink", mock.Anything, mock.Anything).Return(nil).Maybe()
+		sink.On("Close").Return(nil)
+
+		run := newAgent(t, agent.InvalidRecordFail, sink).Run(rcp)
+		require.Error(t, run.Error)
+		assert.Contains(t, run.Error.Error(), "invalid record: no asset")
+		assert.False(t, run.Success)
+	})
+
+	t.Run("should fail the run on unknown policy", func(t *testing.T) {
+		run := newAgent(t, "ignore", mocks.NewSink()).Run(rcp)
+		require.Error(t, run.Error)
+		assert.Contains(t, run.Error.Error(), "invalid record policy \"ignore\"")
+		assert.False(t, run.Success)
+	})
+}
+
+func TestRunnerRunRateLimit(t *testing.T) {
+	newAgent := func(t *testing.T, extr plugins.Extractor) *agent.Agent {
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil)
+		sink.On("Sink", mock.Anything, mock.Anything).Return(nil)
+		sink.On("Close").Return(nil)
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		return agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+		})
+	}
+
+	t.Run("should keep the emitted rate under max records per second", func(t *testing.T) {
+		const (
+			recordCount = 20
+			rate        = 200
+		)
+		extr := &timedExtractor{count: recordCount}
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+
+		run := newAgent(t, extr).Run(recipe.Recipe{
+			Name:                "sample",
+			Source:              recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:               []recipe.SinkRecipe{{Name: "test-sink"}},
+			MaxRecordsPerSecond: rate,
+		})
+		assert.NoError(t, run.Error)
+		assert.Equal(t, recordCount, run.RecordCount)
+
+		// the first record is emitted right away, each following one waits for its token
+		require.Len(t, extr.emittedAt, recordCount)
+		for i := 1; i < recordCount; i++ {
+			elapsed := extr.emittedAt[i].Sub(extr.emittedAt[0])
+			assert.LessOrEqual(t, float64(i)/elapsed.Seconds(), rate*1.05, "record %d emitted after %s", i, elapsed)
+		}
+	})
+
+	t.Run("should fail the run on negative max records per second", func(t *testing.T) {
+		extr := &timedExtractor{count: 1}
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil)
+
+		run := newAgent(t, extr).Run(recipe.Recipe{
+			Name:                "sample",
+			Source:              recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:               []recipe.SinkRecipe{{Name: "test-sink"}},
+			MaxRecordsPerSecond: -1,
+		})
+		require.Error(t, run.Error)
+		assert.Contains(t, run.Error.Error(), "invalid max records per second -1")
+		assert.Empty(t, extr.emittedAt)
+	})
+}
+
+func TestRunnerRunExpectedRecords(t *testing.T) {
+	run := func(t *testing.T, recordCount, min, max int) agent.Run {
+		extr := &countingExtractor{count: recordCount}
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+		sink := &discardSink{}
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil)
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+		})
+		return r.Run(recipe.Recipe{
+			Name:               "sample",
+			Source:             recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:              []recipe.SinkRecipe{{Name: "test-sink"}},
+			ExpectedMinRecords: min,
+			ExpectedMaxRecords: max,
+		})
+	}
+
+	t.Run("should not check the record count if unset", func(t *testing.T) {
+		res := run(t, 0, 0, 0)
+		assert.NoError(t, res.Error)
+		assert.True(t, res.Success)
+	})
+
+	t.Run("should succeed if the record count is within the expected range", func(t *testing.T) {
+		for _, count := range []int{5, 7, 10} {
+			res := run(t, count, 5, 10)
+			assert.NoError(t, res.Error)
+			assert.True(t, res.Success)
+			assert.Equal(t, count, res.RecordCount)
+		}
+	})
+
+	t.Run("should fail the run if fewer records than expected are extracted", func(t *testing.T) {
+		res := run(t, 3, 5, 10)
+		require.Error(t, res.Error)
+		assert.EqualError(t, res.Error, "extracted 3 records, expected at least 5")
+		assert.False(t, res.Success)
+		assert.Equal(t, 3, res.RecordCount)
+		assert.Equal(t, 3, res.SinkedCount)
+	})
+
+	t.Run("should fail the run if more records than expected are extracted", func(t *testing.T) {
+		res := run(t, 12, 0, 10)
+		require.Error(t, res.Error)
+		assert.EqualError(t, res.Error, "extracted 12 records, expected at most 10")
+		assert.False(t, res.Success)
+		assert.Equal(t, 12, res.RecordCount)
+	})
+
+	t.Run("should fail the run on an invalid expected range", func(t *testing.T) {
+		for _, bounds := range [][2]int{{-1, 0}, {0, -1}, {10, 5}} {
+			res := run(t, 7, bounds[0], bounds[1])
+			require.Error(t, res.Error)
+			assert.Contains(t, res.Error.Error(), "invalid expected records")
+			assert.Zero(t, res.RecordCount)
+		}
+	})
+}
+
+func TestRunnerRunCircuitBreaker(t *testing.T) {
+	newAgent := func(t *testing.T, extr plugins.Extractor, sink plugins.Syncer, threshold int) *agent.Agent {
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		return agent.NewAgent(agent.Config{
+			ExtractorFactory:        ef,
+			ProcessorFactory:        registry.NewProcessorFactory(),
+			SinkFactory:             sf,
+			Logger:                  utils.Logger,
+			MaxRetries:              2,
+			RetryInitialInterval:    time.Millisecond,
+			CircuitBreakerThreshold: threshold,
+		})
+	}
+	rcp := recipe.Recipe{
+		Name:   "sample",
+		Source: recipe.SourceRecipe{Type: "test-extractor"},
+		Sinks:  []recipe.SinkRecipe{{Name: "test-sink", BatchSize: 1}},
+	}
+
+	t.Run("should stop sending batches to a persistently failing sink once tripped", func(t *testing.T) {
+		extr := &countingExtractor{count: 10}
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink.On("Sink", mock.Anything, mock.Anything).Return(plugins.NewRetryError(errors.New("backend down")))
+		sink.On("Close").Return(nil)
+		defer sink.AssertExpectations(t)
+
+		run := newAgent(t, extr, sink, 2).Run(rcp)
+		assert.NoError(t, run.Error)
+		assert.True(t, run.Success)
+		assert.Equal(t, []string{"test-sink"}, run.TrippedSinks)
+		assert.Zero(t, run.SinkedCount)
+		// each batch is reported, the ones after the breaker tripped without being sent
+		require.Len(t, run.SinkErrors, 10)
+		assert.Contains(t, run.SinkErrors[1].Error(), "backend down")
+		assert.Contains(t, run.SinkErrors[2].Error(), "circuit breaker open")
+		// two batches sent with their two retries
+		sink.AssertNumberOfCalls(t, "Sink", 6)
+	})
+
+	t.Run("should not trip on failures interleaved with successes", func(t *testing.T) {
+		extr := &countingExtractor{count: 10}
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink.On("Sink", mock.Anything, mock.Anything).Return(errors.New("invalid batch")).Once()
+		sink.On("Sink", mock.Anything, mock.Anything).Return(nil).Once()
+		sink.On("Sink", mock.Anything, mock.Anything).Return(errors.New("invalid batch")).Once()
+		sink.On("Sink", mock.Anything, mock.Anything).Return(nil)
+		sink.On("Close").Return(nil)
+		defer sink.AssertExpectations(t)
+
+		run := newAgent(t, extr, sink, 2).Run(rcp)
+		assert.NoError(t, run.Error)
+		assert.Empty(t, run.TrippedSinks)
+		assert.Len(t, run.SinkErrors, 2)
+		assert.Equal(t, 8, run.SinkedCount)
+	})
+
+	t.Run("should keep retrying every batch without threshold", func(t *testing.T) {
+		extr := &countingExtractor{count: 3}
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink.On("Sink", mock.Anything, mock.Anything).Return(plugins.NewRetryError(errors.New("backend down")))
+		sink.On("Close").Return(nil)
+		defer sink.AssertExpectations(t)
+
+		run := newAgent(t, extr, sink, 0).Run(rcp)
+		assert.Empty(t, run.TrippedSinks)
+		assert.Len(t, run.SinkErrors, 3)
+		sink.AssertNumberOfCalls(t, "Sink", 9)
+	})
+}
+
+func TestRunnerRunProcessConcurrency(t *testing.T) {
+	newAgent := func(t *testing.T, extr plugins.Extractor, proc plugins.Processor, sink plugins.Syncer, concurrency int) *agent.Agent {
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+		pf := registry.NewProcessorFactory()
+		if err := pf.Register("test-processor", newProcessor(proc)); err != nil {
+			t.Fatal(err)
+		}
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		return agent.NewAgent(agent.Config{
+			ExtractorFactory:   ef,
+			ProcessorFactory:   pf,
+			SinkFactory:        sf,
+			Logger:             utils.Logger,
+			ProcessConcurrency: concurrency,
+		})
+	}
+	rcp := recipe.Recipe{
+		Name:       "sample",
+		Source:     recipe.SourceRecipe{Type: "test-extractor"},
+		Processors: []recipe.ProcessorRecipe{{Name: "test-processor"}},
+		Sinks:      []recipe.SinkRecipe{{Name: "test-sink", BatchSize: 10}},
+	}
+
+	t.Run("should process records across workers keeping the record counts", func(t *testing.T) {
+		const (
+			recordCount = 100
+			concurrency = 4
+		)
+		extr := &countingExtractor{count: recordCount}
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		proc := &concurrentProcessor{delay: 2 * time.Millisecond, dropEvery: 4}
+		proc.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink := &discardSink{}
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+
+		run := newAgent(t, extr, proc, sink, concurrency).Run(rcp)
+		assert.NoError(t, run.Error)
+		assert.Equal(t, recordCount, run.ExtractedCount)
+		assert.Equal(t, recordCount*3/4, run.RecordCount)
+		assert.Equal(t, recordCount*3/4, run.ProcessedCount)
+		assert.Equal(t, recordCount*3/4, run.SinkedCount)
+		assert.Equal(t, int64(recordCount*3/4), atomic.LoadInt64(&sink.sinked))
+
+		assert.Greater(t, atomic.LoadInt64(&proc.maxInFlight), int64(1))
+		assert.LessOrEqual(t, atomic.LoadInt64(&proc.maxInFlight), int64(concurrency))
+	})
+
+	t.Run("should process records one at a time by default", func(t *testing.T) {
+		extr := &countingExtractor{count: 10}
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		proc := &concurrentProcessor{delay: time.Millisecond}
+		proc.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink := &discardSink{}
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+
+		run := newAgent(t, extr, proc, sink, 0).Run(rcp)
+		assert.NoError(t, run.Error)
+		assert.Equal(t, 10, run.RecordCount)
+		assert.Equal(t, int64(1), atomic.LoadInt64(&proc.maxInFlight))
+	})
+
+	t.Run("should fail the run when a processor panics in a worker", func(t *testing.T) {
+		extr := &countingExtractor{count: 10}
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		proc := new(panicProcessor)
+		proc.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink := &discardSink{}
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+
+		run := newAgent(t, extr, proc, sink, 4).Run(rcp)
+		assert.Error(t, run.Error)
+		assert.False(t, run.Success)
+	})
+}
+
+// BenchmarkRunnerRunProcessConcurrency runs a CPU-bound processor across an increasing number of workers
+func BenchmarkRunnerRunProcessConcurrency(b *testing.B) {
+	const recordCount = 1000
+	for _, concurrency := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", concurrency), func(b *testing.B) {
+			extr := &countingExtractor{count: recordCount}
+			extr.On("Init", mock.Anything, mock.Anything).Return(nil)
+			ef := registry.NewExtractorFactory()
+			if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+				b.Fatal(err)
+			}
+			proc := &hashProcessor{rounds: 2000}
+			proc.On("Init", mock.Anything, mock.Anything).Return(nil)
+			pf := registry.NewProcessorFactory()
+			if err := pf.Register("test-processor", newProcessor(proc)); err != nil {
+				b.Fatal(err)
+			}
+			sink := &discardSink{}
+			sink.On("Init", mock.Anything, mock.Anything).Return(nil)
+			sf := registry.NewSinkFactory()
+			if err := sf.Register("test-sink", newSink(sink)); err != nil {
+				b.Fatal(err)
+			}
+			r := agent.NewAgent(agent.Config{
+				ExtractorFactory:   ef,
+				ProcessorFactory:   pf,
+				SinkFactory:        sf,
+				Logger:             log.NewNoop(),
+				ProcessConcurrency: concurrency,
+			})
+			rcp := recipe.Recipe{
+				Name:       "sample",
+				Source:     recipe.SourceRecipe{Type: "test-extractor"},
+				Processors: []recipe.ProcessorRecipe{{Name: "test-processor"}},
+				Sinks:      []recipe.SinkRecipe{{Name: "test-sink", BatchSize: 100}},
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if run := r.Run(rcp); run.Error != nil {
+					b.Fatal(run.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestRunnerRunProcessorFlush(t *testing.T) {
+	newAgent := func(t *testing.T, data []models.Record, proc *flushProcessor, sink *mocks.Sink) *agent.Agent {
+		extr := mocks.NewExtractor()
+		extr.SetEmit(data)
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		extr.On("Extract", mock.Anything, mock.AnythingOfType("plugins.Emit")).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		// the next processor receives the flushed records
+		next := mocks.NewProcessor()
+		next.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		for _, d := range data {
+			next.On("Process", mock.Anything, d).Return(d, nil).Once()
+		}
+		t.Cleanup(func() { next.AssertExpectations(t) })
+		pf := registry.NewProcessorFactory()
+		if err := pf.Register("flush-processor", newProcessor(proc)); err != nil {
+			t.Fatal(err)
+		}
+		if err := pf.Register("next-processor", newProcessor(next)); err != nil {
+			t.Fatal(err)
+		}
+
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		return agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: pf,
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+		})
+	}
+	rcp := recipe.Recipe{
+		Name:       "sample",
+		Source:     recipe.SourceRecipe{Type: "test-extractor"},
+		Processors: []recipe.ProcessorRecipe{{Name: "flush-processor"}, {Name: "next-processor"}},
+		Sinks:      []recipe.SinkRecipe{{Name: "test-sink"}},
+	}
+
+	t.Run("should send flushed records through the next processors to sinks", func(t *testing.T) {
+		data := []models.Record{
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "orders"}}),
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "users"}}),
+		}
+		proc := new(flushProcessor)
+		proc.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		defer proc.AssertExpectations(t)
+
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink.On("Sink", mock.Anything, data[:1]).Return(nil).Once()
+		sink.On("Sink", mock.Anything, data[1:]).Return(nil).Once()
+		sink.On("Close").Return(nil)
+		defer sink.AssertExpectations(t)
+
+		run := newAgent(t, data, proc, sink).Run(rcp)
+		assert.NoError(t, run.Error)
+		assert.True(t, run.Success)
+		assert.Equal(t, 2, run.RecordCount)
+	})
+
+	t.Run("should fail run when flush fails", func(t *testing.T) {
+		proc := &flushProcessor{flushErr: errors.New("some error")}
+		proc.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink.On("Close").Return(nil)
+		defer sink.AssertExpectations(t)
+
+		run := newAgent(t, nil, proc, sink).Run(rcp)
+		assert.False(t, run.Success)
+		assert.Error(t, run.Error)
+	})
+}
+
+func TestRunnerRunBackpressure(t *testing.T) {
+	t.Run("should block extractor when a slow sink falls behind", func(t *testing.T) {
+		const (
+			recordCount = 50
+			bufferSize  = 5
+			batchSize   = 2
+		)
+
+		extr := &countingExtractor{count: recordCount}
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		sink := &slowSink{emitted: &extr.emitted}
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+			BufferSize:       bufferSize,
+		})
+		run := r.Run(recipe.Recipe{
+			Name:   "sample",
+			Source: recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:  []recipe.SinkRecipe{{Name: "test-sink", BatchSize: batchSize}},
+		})
+		assert.NoError(t, run.Error)
+		assert.Equal(t, recordCount, run.RecordCount)
+		assert.Equal(t, recordCount, sink.sinked)
+		// records emitted but not sinked yet are either buffered or in the batch being sinked
+		assert.LessOrEqual(t, sink.maxAhead, bufferSize)
+	})
+}
+
+func TestRunnerRunHooks(t *testing.T) {
+	data := []models.Record{
+		models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}),
+	}
+	rcp := recipe.Recipe{
+		Name:   "sample",
+		Source: recipe.SourceRecipe{Type: "test-extractor"},
+		Sinks:  []recipe.SinkRecipe{{Name: "test-sink"}},
+	}
+
+	newAgent := func(t *testing.T, hook agent.Hook, strict bool) *agent.Agent {
+		extr := mocks.NewExtractor()
+		extr.SetEmit(data)
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil)
+		extr.On("Extract", mock.Anything, mock.AnythingOfType("plugins.Emit")).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil)
+		sink.On("Sink", mock.Anything, data).Return(nil)
+		sink.On("Close").Return(nil)
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		return agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+			TimerFn: func() func() int {
+				return func() int { return 42 }
+			},
+			Hooks:       []agent.Hook{hook},
+			StrictHooks: strict,
+		})
+	}
+
+	t.Run("should call hooks with the started and finished run", func(t *testing.T) {
+		var started, finished []agent.Run
+		hook := agent.HookFuncs{
+			Start: func(run agent.Run) error {
+				started = append(started, run)
+				return nil
+			},
+			Finish: func(run agent.Run) error {
+				finished = append(finished, run)
+				return nil
+			},
+		}
+
+		run := newAgent(t, hook, false).Run(rcp)
+		assert.NoError(t, run.Error)
+
+		assert.Equal(t, []agent.Run{{RunID: run.RunID, Recipe: rcp}}, started)
+		require.Len(t, finished, 1)
+		assert.True(t, finished[0].Success)
+		assert.Equal(t, 1, finished[0].RecordCount)
+		assert.Equal(t, 42, finished[0].DurationInMs)
+	})
+
+	t.Run("should only log hook errors", func(t *testing.T) {
+		hook := agent.HookFuncs{
+			Start:  func(run agent.Run) error { return errors.New("start error") },
+			Finish: func(run agent.Run) error { return errors.New("finish error") },
+		}
+
+		run := newAgent(t, hook, false).Run(rcp)
+		assert.NoError(t, run.Error)
+		assert.True(t, run.Success)
+	})
+
+	t.Run("should fail run on start hook error if strict", func(t *testing.T) {
+		var finished []agent.Run
+		hook := agent.HookFuncs{
+			Start: func(run agent.Run) error { return errors.New("start error") },
+			Finish: func(run agent.Run) error {
+				finished = append(finished, run)
+				return nil
+			},
+		}
+
+		run := newAgent(t, hook, true).Run(rcp)
+		assert.False(t, run.Success)
+		assert.EqualError(t, run.Error, "failed to run start hook: start error")
+		assert.Equal(t, 0, run.RecordCount)
+		require.Len(t, finished, 1)
+		assert.Equal(t, run.Error, finished[0].Error)
+	})
+
+	t.Run("should fail run on finish hook error if strict", func(t *testing.T) {
+		hook := agent.HookFuncs{
+			Finish: func(run agent.Run) error { return errors.New("finish error") },
+		}
+
+		run := newAgent(t, hook, true).Run(rcp)
+		assert.False(t, run.Success)
+		assert.EqualError(t, run.Error, "failed to run finish hook: finish error")
+		assert.Equal(t, 1, run.RecordCount)
+	})
+}
+
+func TestRunnerRunMultiple(t *testing.T) {
+	t.Run("should return list of runs when finished", func(t *testing.T) {
+		validRecipe2 := validRecipe
+		validRecipe2.Name = "sample-2"
+		recipeList := []recipe.Recipe{validRecipe, validRecipe2}
+		data := []models.Record{
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}),
+		}
+		extr := mocks.NewExtractor()
+		extr.SetEmit(data)
+		extr.On("Init", mockCtx, validRecipe.Source.Config).Return(nil)
+		extr.On("Extract", mockCtx, mock.AnythingOfType("plugins.Emit")).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		proc := mocks.NewProcessor()
+		proc.On("Init", mockCtx, validRecipe.Processors[0].Config).Return(nil)
+		proc.On("Process", mockCtx, data[0]).Return(data[0], nil)
+		defer proc.AssertExpectations(t)
+		pf := registry.NewProcessorFactory()
+		if err := pf.Register("test-processor", newProcessor(proc)); err != nil {
+			t.Fatal(err)
+		}
+
+		sink := mocks.NewSink()
+		sink.On("Init", mockCtx, validRecipe.Sinks[0].Config).Return(nil)
+		sink.On("Sink", mockCtx, data).Return(nil)
+		sink.On("Close").Return(nil)
+		defer sink.AssertExpectations(t)
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		monitor := newMockMonitor()
+		monitor.On("RecordRun", mock.AnythingOfType("agent.Run"))
+		defer monitor.AssertExpectations(t)
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: pf,
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+			Monitor:          monitor,
+		})
+		runs := r.RunMultiple(recipeList)
+
+		require.Len(t, runs, len(recipeList))
+		assert.NotEqual(t, runs[0].RunID, runs[1].RunID)
+		assert.Equal(t, []agent.Run{
+			{RunID: runs[0].RunID, Recipe: validRecipe, RecordCount: len(data), ExtractedCount: len(data), ProcessedCount: len(data), SinkedCount: len(data), Success: true},
+			{RunID: runs[1].RunID, Recipe: validRecipe2, RecordCount: len(data), ExtractedCount: len(data), ProcessedCount: len(data), SinkedCount: len(data), Success: true},
+		}, runs)
+	})
+}
+
+func TestRunnerRunMultiplePanic(t *testing.T) {
+	t.Run("should return a failed run for a recipe panicking outside of the extractor", func(t *testing.T) {
+		data := []models.Record{
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}),
+		}
+		extr := mocks.NewExtractor()
+		extr.SetEmit(data)
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil)
+		extr.On("Extract", mock.Anything, mock.AnythingOfType("plugins.Emit")).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink.On("Sink", mock.Anything, data).Return(nil).Once()
+		sink.On("Close").Return(nil).Once()
+		defer sink.AssertExpectations(t)
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+		if err := sf.Register("panic-sink", newSink(&panicSink{})); err != nil {
+			t.Fatal(err)
+		}
+
+		panicRecipe := recipe.Recipe{
+			Name:   "panicking",
+			Source: recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:  []recipe.SinkRecipe{{Name: "panic-sink"}},
+		}
+		healthyRecipe := recipe.Recipe{
+			Name:   "healthy",
+			Source: recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:  []recipe.SinkRecipe{{Name: "test-sink"}},
+		}
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+		})
+		runs := r.RunMultiple([]recipe.Recipe{panicRecipe, healthyRecipe})
+
+		require.Len(t, runs, 2)
+		assert.Equal(t, panicRecipe, runs[0].Recipe)
+		assert.False(t, runs[0].Success)
+		require.Error(t, runs[0].Error)
+		assert.Contains(t, runs[0].Error.Error(), "panicking in init")
+		assert.Equal(t, healthyRecipe, runs[1].Recipe)
+		assert.True(t, runs[1].Success)
+		assert.Equal(t, len(data), runs[1].SinkedCount)
+	})
+}
+
+func TestRunnerRunWithContext(t *testing.T) {
+	t.Run("should drain current batches and close sinks when context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		data := []models.Record{
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "table-1"}}),
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "table-2"}}),
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "table-3"}}),
+		}
+		late := models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "table-4"}})
+		release := make(chan struct{})
+		extr := &cancellingExtractor{records: data, late: late, cancel: cancel, release: release, done: make(chan struct{})}
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		// any unexpected call panics the mock and fails the run
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink.On("Sink", mock.Anything, data[:2]).Return(nil).Once()
+		sink.On("Sink", mock.Anything, data[2:]).Return(nil).Once()
+		sink.On("Close").Return(nil).Once()
+		defer sink.AssertExpectations(t)
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+		})
+		run := r.RunWithContext(ctx, recipe.Recipe{
+			Name:   "sample",
+			Source: recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:  []recipe.SinkRecipe{{Name: "test-sink", BatchSize: 2}},
+		})
+		// records emitted after the run was interrupted are not sent
+		close(release)
+		<-extr.done
+
+		assert.False(t, run.Success)
+		assert.True(t, run.Incomplete)
+		assert.True(t, errors.Is(run.Error, context.Canceled))
+		assert.Equal(t, len(data), run.RecordCount)
+	})
+}
+
+func TestRunnerRunTimeout(t *testing.T) {
+	t.Run("should fail the run when the recipe timeout is exceeded", func(t *testing.T) {
+		data := []models.Record{
+			models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "table-1"}}),
+		}
+		extr := &blockingExtractor{records: data}
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink.On("Sink", mock.Anything, data).Return(nil).Once()
+		sink.On("Close").Return(nil).Once()
+		defer sink.AssertExpectations(t)
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+		})
+		run := r.Run(recipe.Recipe{
+			Name:    "sample",
+			Source:  recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:   []recipe.SinkRecipe{{Name: "test-sink"}},
+			Timeout: 10 * time.Millisecond,
+		})
+
+		assert.False(t, run.Success)
+		assert.True(t, run.Incomplete)
+		assert.True(t, errors.Is(run.Error, context.DeadlineExceeded))
+		assert.Contains(t, run.Error.Error(), "run timed out after 10ms")
+		assert.Equal(t, len(data), run.RecordCount)
+	})
+
+	t.Run("should not apply a timeout when unset", func(t *testing.T) {
+		extr := mocks.NewExtractor()
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		extr.On("Extract", mock.Anything, mock.AnythingOfType("plugins.Emit")).Run(func(args mock.Arguments) {
+			ctx := args.Get(0).(context.Context)
+			_, hasDeadline := ctx.Deadline()
+			assert.False(t, hasDeadline)
+		}).Return(nil).Once()
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		sink.On("Close").Return(nil).Once()
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+		})
+		run := r.Run(recipe.Recipe{
+			Name:   "sample",
+			Source: recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:  []recipe.SinkRecipe{{Name: "test-sink"}},
+		})
+
+		assert.True(t, run.Success)
+		assert.NoError(t, run.Error)
+		extr.AssertExpectations(t)
+	})
+}
+
+func TestRunnerRunLogFields(t *testing.T) {
+	t.Run("should log every line of a run with the recipe and run id", func(t *testing.T) {
+		extr := mocks.NewExtractor()
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil)
+		extr.On("Extract", mock.Anything, mock.AnythingOfType("plugins.Emit")).Return(nil)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil)
+		sink.On("Close").Return(errors.New("some error"))
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           log.NewLogrus(log.LogrusWithWriter(&buf), log.LogrusWithFormatter(&logrus.JSONFormatter{})),
+		})
+		rcp := recipe.Recipe{
+			Name:   "sample",
+			Source: recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:  []recipe.SinkRecipe{{Name: "test-sink"}},
+		}
+		first := r.Run(rcp)
+		firstLines := buf.String()
+		buf.Reset()
+		second := r.Run(rcp)
+
+		uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+		assert.Regexp(t, uuidPattern, first.RunID)
+		assert.Regexp(t, uuidPattern, second.RunID)
+		assert.NotEqual(t, first.RunID, second.RunID)
+
+		for runID, lines := range map[string]string{first.RunID: firstLines, second.RunID: buf.String()} {
+			scanner := bufio.NewScanner(strings.NewReader(lines))
+			var count int
+			for scanner.Scan() {
+				var entry map[string]interface{}
+				require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+				assert.Equal(t, "sample", entry["recipe"], entry["msg"])
+				assert.Equal(t, runID, entry["run_id"], entry["msg"])
+				count++
+			}
+			// running recipe, error closing sink and done running recipe
+			assert.Equal(t, 3, count)
+		}
+	})
+}
+
+func TestRunnerRunPluginLogLevel(t *testing.T) {
+	newAgent := func(t *testing.T, extr plugins.Extractor, buf *bytes.Buffer) *agent.Agent {
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil)
+		sink.On("Close").Return(nil)
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		newLogger := func(level string) log.Logger {
+			return log.NewLogrus(log.LogrusWithLevel(level), log.LogrusWithWriter(buf), log.LogrusWithFormatter(&logrus.JSONFormatter{}))
+		}
+		return agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           newLogger("info"),
+			NewLogger:        newLogger,
+		})
+	}
+	rcp := func(config map[string]interface{}) recipe.Recipe {
+		return recipe.Recipe{
+			Name:   "sample",
+			Source: recipe.SourceRecipe{Type: "test-extractor", Config: config},
+			Sinks:  []recipe.SinkRecipe{{Name: "test-sink"}},
+		}
+	}
+
+	t.Run("should log the plugin at the level of its config", func(t *testing.T) {
+		extr := &loggingExtractor{}
+		// the log level is read by the agent, the plugin is initiated without it
+		extr.On("Init", mock.Anything, map[string]interface{}{}).Return(nil).Once()
+		var buf bytes.Buffer
+
+		run := newAgent(t, extr, &buf).Run(rcp(map[string]interface{}{"log_level": "debug"}))
+		require.NoError(t, run.Error)
+
+		var found bool
+		scanner := bufio.NewScanner(strings.NewReader(buf.String()))
+		for scanner.Scan() {
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+			if entry["level"] != "debug" {
+				continue
+			}
+			found = true
+			assert.Equal(t, "extracting", entry["msg"])
+			assert.Equal(t, "sample", entry["recipe"])
+			assert.Equal(t, run.RunID, entry["run_id"])
+			assert.Equal(t, "test-extractor", entry["plugin"])
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("should log the plugin at the agent level without log level", func(t *testing.T) {
+		extr := &loggingExtractor{}
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
+		var buf bytes.Buffer
+
+		run := newAgent(t, extr, &buf).Run(rcp(nil))
+		require.NoError(t, run.Error)
+		assert.Nil(t, extr.logger)
+	})
+
+	t.Run("should fail the run on invalid log level", func(t *testing.T) {
+		extr := &loggingExtractor{}
+		var buf bytes.Buffer
+
+		run := newAgent(t, extr, &buf).Run(rcp(map[string]interface{}{"log_level": "verbose"}))
+		assert.False(t, run.Success)
+		assert.EqualError(t, run.Error, "failed to setup extractor: could not setup logger of extractor \"test-extractor\": invalid log level \"verbose\"")
+		extr.AssertNotCalled(t, "Init", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRunnerRunRecipeName(t *testing.T) {
+	t.Run("should pass the recipe name to plugins on their context", func(t *testing.T) {
+		hasRecipeName := mock.MatchedBy(func(ctx context.Context) bool {
+			return plugins.RecipeName(ctx) == "sample"
+		})
+		extr := mocks.NewExtractor()
+		extr.On("Init", hasRecipeName, mock.Anything).Return(nil).Once()
+		extr.On("Extract", hasRecipeName, mock.AnythingOfType("plugins.Emit")).Return(nil).Once()
+		defer extr.AssertExpectations(t)
+		ef := registry.NewExtractorFactory()
+		if err := ef.Register("test-extractor", newExtractor(extr)); err != nil {
+			t.Fatal(err)
+		}
+
+		sink := mocks.NewSink()
+		sink.On("Init", hasRecipeName, mock.Anything).Return(nil).Once()
+		sink.On("Close").Return(nil)
+		defer sink.AssertExpectations(t)
+		sf := registry.NewSinkFactory()
+		if err := sf.Register("test-sink", newSink(sink)); err != nil {
+			t.Fatal(err)
+		}
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+		})
+		run := r.Run(recipe.Recipe{
+			Name:    "sample",
+			Source:  recipe.SourceRecipe{Type: "test-extractor"},
+			Sinks:   []recipe.SinkRecipe{{Name: "test-sink"}},
+			Timeout: time.Minute,
+		})
+		assert.NoError(t, run.Error)
+	})
+}
+
+func TestRunnerRunMultipleWithContext(t *testing.T) {
+	t.Run("should not start recipes when context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: registry.NewExtractorFactory(),
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      registry.NewSinkFactory(),
+			Logger:           utils.Logger,
+		})
+		runs := r.RunMultipleWithContext(ctx, []recipe.Recipe{validRecipe})
+		assert.Empty(t, runs)
+	})
+}
+
+func newExtractor(extr plugins.Extractor) func() plugins.Extractor {
+	return func() plugins.Extractor {
+		return extr
+	}
+}
+
+func newProcessor(proc plugins.Processor) func() plugins.Processor {
+	return func() plugins.Processor {
+		return proc
+	}
+}
+
+func newSink(sink plugins.Syncer) func() plugins.Syncer {
+	return func() plugins.Syncer {
+		return sink
+	}
+}
+
+type mockMonitor struct {
+	mock.Mock
+}
+
+func newMockMonitor() *mockMonitor {
+	return &mockMonitor{}
+}
+
+func (m *mockMonitor) RecordRun(run agent.Run) {
+	m.Called(run)
+}
+
+type panicExtractor struct {
+	mocks.Extractor
+}
+
+func (e *panicExtractor) Extract(_ context.Context, _ plugins.Emit) (err error) {
 	panic("panicking")
 }
 
@@ -800,3 +2720,240 @@ type panicProcessor struct {
 func (p *panicProcessor) Process(_ context.Context, _ models.Record) (dst models.Record, err error) {
 	panic("panicking")
 }
+
+type panicSink struct {
+	mocks.Plugin
+}
+
+func (s *panicSink) Init(_ context.Context, _ map[string]interface{}) error {
+	panic("panicking in init")
+}
+
+func (s *panicSink) Sink(_ context.Context, _ []models.Record) error {
+	return nil
+}
+
+func (s *panicSink) Close() error {
+	return nil
+}
+
+type hookProcessor struct {
+	mocks.Processor
+	startErr   error
+	startCount int
+	endCount   int
+}
+
+func (p *hookProcessor) OnRunStart(_ context.Context) error {
+	p.startCount++
+	return p.startErr
+}
+
+func (p *hookProcessor) OnRunEnd(_ context.Context) error {
+	p.endCount++
+	return nil
+}
+
+// flushProcessor holds back all records until flushed
+type flushProcessor struct {
+	mocks.Processor
+	held     []models.Record
+	flushErr error
+}
+
+func (p *flushProcessor) Process(_ context.Context, src models.Record) (models.Record, error) {
+	p.held = append(p.held, src)
+	return src, plugins.NewDropRecordError("held back")
+}
+
+func (p *flushProcessor) Flush(_ context.Context) ([]models.Record, error) {
+	return p.held, p.flushErr
+}
+
+// concurrentProcessor tracks the records processed at once, dropping every dropEvery-th record if set
+type concurrentProcessor struct {
+	mocks.Processor
+	delay       time.Duration
+	dropEvery   int64
+	calls       int64
+	inFlight    int64
+	maxInFlight int64
+}
+
+func (p *concurrentProcessor) Process(_ context.Context, src models.Record) (models.Record, error) {
+	inFlight := atomic.AddInt64(&p.inFlight, 1)
+	defer atomic.AddInt64(&p.inFlight, -1)
+	for {
+		max := atomic.LoadInt64(&p.maxInFlight)
+		if inFlight <= max || atomic.CompareAndSwapInt64(&p.maxInFlight, max, inFlight) {
+			break
+		}
+	}
+	time.Sleep(p.delay)
+
+	if calls := atomic.AddInt64(&p.calls, 1); p.dropEvery > 0 && calls%p.dropEvery == 0 {
+		return src, plugins.NewDropRecordError("dropped")
+	}
+	return src, nil
+}
+
+// hashProcessor is CPU-bound, hashing the urn of each record rounds times
+type hashProcessor struct {
+	mocks.Processor
+	rounds int
+}
+
+func (p *hashProcessor) Process(_ context.Context, src models.Record) (models.Record, error) {
+	sum := sha256.Sum256([]byte(src.Data().GetResource().GetUrn()))
+	for i := 1; i < p.rounds; i++ {
+		sum = sha256.Sum256(sum[:])
+	}
+	return src, nil
+}
+
+// discardSink counts the records sinked
+type discardSink struct {
+	mocks.Plugin
+	sinked int64
+}
+
+func (s *discardSink) Sink(_ context.Context, batch []models.Record) error {
+	atomic.AddInt64(&s.sinked, int64(len(batch)))
+	return nil
+}
+
+func (s *discardSink) Close() error {
+	return nil
+}
+
+// countingExtractor emits count records, counting those emitted
+type countingExtractor struct {
+	mocks.Extractor
+	count   int
+	emitted int64
+}
+
+func (e *countingExtractor) Extract(_ context.Context, emit plugins.Emit) error {
+	for i := 0; i < e.count; i++ {
+		emit(models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}))
+		atomic.AddInt64(&e.emitted, 1)
+	}
+
+	return nil
+}
+
+// slowSink records how far the extractor got ahead of it
+// timedExtractor records when each of its records was emitted
+type timedExtractor struct {
+	mocks.Extractor
+	count     int
+	emittedAt []time.Time
+}
+
+func (e *timedExtractor) Extract(_ context.Context, emit plugins.Emit) error {
+	for i := 0; i < e.count; i++ {
+		emit(models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sample-urn"}}))
+		e.emittedAt = append(e.emittedAt, time.Now())
+	}
+
+	return nil
+}
+
+type slowSink struct {
+	mocks.Plugin
+	emitted  *int64
+	sinked   int
+	maxAhead int
+}
+
+func (s *slowSink) Sink(_ context.Context, batch []models.Record) error {
+	// give the extractor time to fill the buffer
+	time.Sleep(time.Millisecond)
+	s.sinked += len(batch)
+	if ahead := int(atomic.LoadInt64(s.emitted)) - s.sinked; ahead > s.maxAhead {
+		s.maxAhead = ahead
+	}
+
+	return nil
+}
+
+func (s *slowSink) Close() error {
+	return nil
+}
+
+// cancellingExtractor cancels the run after emitting its records
+// and ignores the cancellation until released, emitting one late record.
+type cancellingExtractor struct {
+	mocks.Extractor
+	records []models.Record
+	late    models.Record
+	cancel  context.CancelFunc
+	release chan struct{}
+	done    chan struct{}
+}
+
+func (e *cancellingExtractor) Extract(_ context.Context, emit plugins.Emit) error {
+	defer close(e.done)
+
+	for _, r := range e.records {
+		emit(r)
+	}
+	e.cancel()
+	<-e.release
+	emit(e.late)
+
+	return nil
+}
+
+// blockingExtractor emits its records and blocks until the run context is done
+type blockingExtractor struct {
+	mocks.Extractor
+	records []models.Record
+}
+
+func (e *blockingExtractor) Extract(ctx context.Context, emit plugins.Emit) error {
+	for _, r := range e.records {
+		emit(r)
+	}
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+// healthCheckExtractor is an extractor implementing plugins.HealthChecker
+type healthCheckExtractor struct {
+	mocks.Extractor
+}
+
+func (e *healthCheckExtractor) HealthCheck(ctx context.Context) error {
+	args := e.Called(ctx)
+	return args.Error(0)
+}
+
+// closingHealthCheckExtractor is a health checking extractor implementing io.Closer
+type closingHealthCheckExtractor struct {
+	healthCheckExtractor
+}
+
+func (e *closingHealthCheckExtractor) Close() error {
+	args := e.Called()
+	return args.Error(0)
+}
+
+// loggingExtractor is an extractor implementing plugins.LoggerSetter, logging at debug level
+type loggingExtractor struct {
+	mocks.Extractor
+	logger log.Logger
+}
+
+func (e *loggingExtractor) SetLogger(logger log.Logger) {
+	e.logger = logger
+}
+
+func (e *loggingExtractor) Extract(_ context.Context, _ plugins.Emit) error {
+	if e.logger != nil {
+		e.logger.Debug("extracting")
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package agent
+
+// circuitBreaker trips once a sink failed threshold consecutive batches, each after exhausted retries.
+// A tripped breaker stays open for the rest of the run, and a non-positive threshold never trips.
+type circuitBreaker struct {
+	threshold int
+	failures  int
+	tripped   bool
+}
+
+func newCircuitBreaker(threshold int) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold}
+}
+
+// open tells if batches must fail without being sent
+func (b *circuitBreaker) open() bool {
+	return b.tripped
+}
+
+// record counts the outcome of a batch, a success resetting the consecutive failures.
+// It returns true on the failure tripping the breaker.
+func (b *circuitBreaker) record(err error) bool {
+	if err == nil {
+		b.failures = 0
+		return false
+	}
+
+	b.failures++
+	if b.threshold > 0 && !b.tripped && b.failures >= b.threshold {
+		b.tripped = true
+		return true
+	}
+
+	return false
+}
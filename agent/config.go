@@ -17,4 +17,33 @@ type Config struct {
 	RetryInitialInterval time.Duration
 	StopOnSinkError      bool
 	TimerFn              TimerFn
+	// DefaultBatchSize is used for sinks without their own batch size,
+	// non-positive values fall back to 1.
+	DefaultBatchSize int
+	// BufferSize is the number of records buffered for each sink, the extractor
+	// is blocked once a buffer is full. Non-positive values fall back to 100.
+	BufferSize int
+	// Hooks are notified when each run starts and finishes
+	Hooks []Hook
+	// StrictHooks fails runs on hook errors, which are only logged otherwise
+	StrictHooks bool
+	// Include keeps only the assets with an urn matching one of its glob patterns, if it is not empty.
+	// Exclude drops the assets with an urn matching one of its glob patterns.
+	// Patterns follow path.Match, so "*" does not match "/". Lineage edges are never filtered.
+	Include []string
+	Exclude []string
+	// OnInvalidRecord is what happens to records without asset or urn emitted by extractors,
+	// either InvalidRecordSkip, the default, or InvalidRecordFail.
+	OnInvalidRecord InvalidRecordPolicy
+	// ProcessConcurrency is the number of workers running extracted records through the processors,
+	// non-positive values fall back to 1. With more than one worker, records may reach sinks in a
+	// different order than they were extracted, and processors must be safe for concurrent use.
+	ProcessConcurrency int
+	// CircuitBreakerThreshold is the number of consecutive batches failing a sink, after exhausted
+	// retries, tripping its circuit breaker. The remaining batches of the run then fail without being
+	// sent to the sink. Non-positive values disable the circuit breaker.
+	CircuitBreakerThreshold int
+	// NewLogger builds the logger of plugins with a log_level in their recipe config, the plugin
+	// then logs at this level instead of the level of Logger. Defaults to a logrus logger.
+	NewLogger func(level string) log.Logger
 }
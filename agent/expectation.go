@@ -0,0 +1,30 @@
+package agent
+
+import (
+	"github.com/odpf/meteor/recipe"
+	"github.com/pkg/errors"
+)
+
+// validateExpectation checks the expected record count range of the recipe, zero bounds being unset.
+func validateExpectation(rcp recipe.Recipe) error {
+	if rcp.ExpectedMinRecords < 0 || rcp.ExpectedMaxRecords < 0 {
+		return errors.Errorf("invalid expected records [%d, %d], bounds must not be negative", rcp.ExpectedMinRecords, rcp.ExpectedMaxRecords)
+	}
+	if rcp.ExpectedMaxRecords > 0 && rcp.ExpectedMinRecords > rcp.ExpectedMaxRecords {
+		return errors.Errorf("invalid expected records [%d, %d], minimum is greater than maximum", rcp.ExpectedMinRecords, rcp.ExpectedMaxRecords)
+	}
+
+	return nil
+}
+
+// checkExpectation returns an error if the number of records is out of the expected range of the recipe.
+func checkExpectation(rcp recipe.Recipe, recordCount int) error {
+	if recordCount < rcp.ExpectedMinRecords {
+		return errors.Errorf("extracted %d records, expected at least %d", recordCount, rcp.ExpectedMinRecords)
+	}
+	if rcp.ExpectedMaxRecords > 0 && recordCount > rcp.ExpectedMaxRecords {
+		return errors.Errorf("extracted %d records, expected at most %d", recordCount, rcp.ExpectedMaxRecords)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"path"
+
+	"github.com/odpf/meteor/models"
+	"github.com/odpf/meteor/plugins"
+	"github.com/pkg/errors"
+)
+
+// urnFilter drops the assets with an urn not matching any of the include patterns,
+// when there are some, or matching any of the exclude patterns.
+// Patterns are globs as in path.Match, lineage edges are not filtered.
+type urnFilter struct {
+	include []string
+	exclude []string
+}
+
+func newURNFilter(include, exclude []string) (*urnFilter, error) {
+	for _, pattern := range append(append([]string{}, include...), exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid urn pattern \"%s\"", pattern)
+		}
+	}
+
+	return &urnFilter{
+		include: include,
+		exclude: exclude,
+	}, nil
+}
+
+// enabled reports whether the filter may drop any record
+func (f *urnFilter) enabled() bool {
+	return len(f.include) > 0 || len(f.exclude) > 0
+}
+
+func (f *urnFilter) accepts(urn string) bool {
+	if len(f.include) > 0 && !matchAny(f.include, urn) {
+		return false
+	}
+
+	return !matchAny(f.exclude, urn)
+}
+
+func (f *urnFilter) middleware(src models.Record) (models.Record, error) {
+	if models.IsLineageRecord(src) {
+		return src, nil
+	}
+	urn := src.Data().GetResource().GetUrn()
+	if !f.accepts(urn) {
+		return src, plugins.NewDropRecordError("urn \"" + urn + "\" filtered out")
+	}
+
+	return src, nil
+}
+
+func matchAny(patterns []string, urn string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, urn); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"reflect"
+
+	"github.com/odpf/meteor/models"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+)
+
+// InvalidRecordPolicy is what happens to the invalid records emitted by extractors,
+// i.e. records without asset or without urn.
+type InvalidRecordPolicy string
+
+const (
+	// InvalidRecordSkip drops invalid records, logging them.
+	InvalidRecordSkip InvalidRecordPolicy = "skip"
+	// InvalidRecordFail fails the run on the first invalid record.
+	InvalidRecordFail InvalidRecordPolicy = "fail"
+)
+
+// recordGuard keeps invalid records emitted by the extractor from reaching processors and sinks
+type recordGuard struct {
+	policy InvalidRecordPolicy
+	logger log.Logger
+}
+
+// newRecordGuard returns a guard applying the policy, records are skipped if it is empty
+func newRecordGuard(policy InvalidRecordPolicy, logger log.Logger) (*recordGuard, error) {
+	switch policy {
+	case "":
+		policy = InvalidRecordSkip
+	case InvalidRecordSkip, InvalidRecordFail:
+	default:
+		return nil, errors.Errorf("invalid record policy \"%s\", supported policies are %s and %s",
+			policy, InvalidRecordSkip, InvalidRecordFail)
+	}
+
+	return &recordGuard{
+		policy: policy,
+		logger: logger,
+	}, nil
+}
+
+func (g *recordGuard) middleware(src models.Record) (models.Record, error) {
+	err := validateRecord(src)
+	if err == nil {
+		return src, nil
+	}
+	if g.policy == InvalidRecordFail {
+		return src, err
+	}
+
+	g.logger.Warn("skipping invalid record", "error", err)
+	return src, plugins.NewDropRecordError(err.Error())
+}
+
+// validateRecord checks the record holds an asset with an urn,
+// or a lineage edge with the urns of its source and target
+func validateRecord(record models.Record) error {
+	data := record.Data()
+	// records may hold typed nil pointers, e.g. a nil *Table
+	if data == nil || reflect.ValueOf(data).IsNil() {
+		return errors.New("invalid record: no asset")
+	}
+	if edge, ok := data.(*models.LineageEdge); ok {
+		if edge.Source.GetUrn() == "" || edge.Target.GetUrn() == "" {
+			return errors.Errorf("invalid record: lineage edge \"%s\" without source or target urn", edge.GetResource().GetUrn())
+		}
+		return nil
+	}
+	if data.GetResource().GetUrn() == "" {
+		return errors.Errorf("invalid record: %s without urn", assetTypeName(data))
+	}
+
+	return nil
+}
+
+// assetTypeName returns the asset type of the metadata for messages, "asset" if it is unknown
+func assetTypeName(data models.Metadata) string {
+	if assetType := models.AssetType(data); assetType != "" {
+		return assetType
+	}
+
+	return "asset"
+}
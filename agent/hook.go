@@ -0,0 +1,34 @@
+package agent
+
+// Hook is notified around each run of a recipe, e.g. to send a notification.
+type Hook interface {
+	// OnStart is called before the plugins of the run are set up,
+	// the run only holds its recipe.
+	OnStart(run Run) error
+
+	// OnFinish is called once the run is done with its final status,
+	// including its duration.
+	OnFinish(run Run) error
+}
+
+// HookFuncs adapts functions to a Hook, nil functions are skipped.
+type HookFuncs struct {
+	Start  func(run Run) error
+	Finish func(run Run) error
+}
+
+// OnStart calls Start
+func (h HookFuncs) OnStart(run Run) error {
+	if h.Start == nil {
+		return nil
+	}
+	return h.Start(run)
+}
+
+// OnFinish calls Finish
+func (h HookFuncs) OnFinish(run Run) error {
+	if h.Finish == nil {
+		return nil
+	}
+	return h.Finish(run)
+}
@@ -0,0 +1,85 @@
+package agent
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	"github.com/odpf/salt/log"
+)
+
+// fieldLogger adds its key/value pairs to every line logged with the wrapped logger
+type fieldLogger struct {
+	logger log.Logger
+	fields []interface{}
+}
+
+// withFields returns a logger adding the alternating key/value pairs to every log line
+func withFields(logger log.Logger, fields ...interface{}) log.Logger {
+	if l, ok := logger.(*fieldLogger); ok {
+		return &fieldLogger{
+			logger: l.logger,
+			fields: append(append([]interface{}{}, l.fields...), fields...),
+		}
+	}
+
+	return &fieldLogger{logger: logger, fields: fields}
+}
+
+// withLevel returns leveled, a logger built at the level of a plugin,
+// adding the fields of logger and then the given ones to every log line.
+func withLevel(logger log.Logger, leveled log.Logger, fields ...interface{}) log.Logger {
+	if l, ok := logger.(*fieldLogger); ok {
+		fields = append(append([]interface{}{}, l.fields...), fields...)
+	}
+
+	return withFields(leveled, fields...)
+}
+
+func newLogrusLogger(level string) log.Logger {
+	return log.NewLogrus(log.LogrusWithLevel(level))
+}
+
+func (l *fieldLogger) Debug(msg string, args ...interface{}) {
+	l.logger.Debug(msg, l.args(args)...)
+}
+
+func (l *fieldLogger) Info(msg string, args ...interface{}) {
+	l.logger.Info(msg, l.args(args)...)
+}
+
+func (l *fieldLogger) Warn(msg string, args ...interface{}) {
+	l.logger.Warn(msg, l.args(args)...)
+}
+
+func (l *fieldLogger) Error(msg string, args ...interface{}) {
+	l.logger.Error(msg, l.args(args)...)
+}
+
+func (l *fieldLogger) Fatal(msg string, args ...interface{}) {
+	l.logger.Fatal(msg, l.args(args)...)
+}
+
+func (l *fieldLogger) Level() string {
+	return l.logger.Level()
+}
+
+func (l *fieldLogger) Writer() io.Writer {
+	return l.logger.Writer()
+}
+
+func (l *fieldLogger) args(args []interface{}) []interface{} {
+	return append(append(make([]interface{}, 0, len(l.fields)+len(args)), l.fields...), args...)
+}
+
+// newRunID returns a random (version 4) UUID identifying a run
+func newRunID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
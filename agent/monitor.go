@@ -2,6 +2,7 @@ package agent
 
 import (
 	"reflect"
+	"sync"
 )
 
 // Monitor is the interface for monitoring the agent.
@@ -15,6 +16,47 @@ type defaultMonitor struct{}
 func (m *defaultMonitor) RecordRun(run Run) {
 }
 
+// MemoryMonitor keeps the recorded runs in memory, e.g. to inspect them in tests
+// or to report them once all recipes are run. It is safe for concurrent use.
+type MemoryMonitor struct {
+	mu   sync.Mutex
+	runs []Run
+}
+
+// NewMemoryMonitor creates a new MemoryMonitor without any run
+func NewMemoryMonitor() *MemoryMonitor {
+	return &MemoryMonitor{}
+}
+
+// RecordRun keeps the run
+func (m *MemoryMonitor) RecordRun(run Run) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.runs = append(m.runs, run)
+}
+
+// Runs returns a copy of the recorded runs, in the order they were recorded
+func (m *MemoryMonitor) Runs() []Run {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	runs := make([]Run, len(m.runs))
+	copy(runs, m.runs)
+	return runs
+}
+
+// Last returns the last recorded run, ok is false if no run was recorded
+func (m *MemoryMonitor) Last() (run Run, ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.runs) == 0 {
+		return run, false
+	}
+	return m.runs[len(m.runs)-1], true
+}
+
 func isNilMonitor(monitor Monitor) bool {
 	v := reflect.ValueOf(monitor)
 	return !v.IsValid() || reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
@@ -0,0 +1,114 @@
+package agent_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/odpf/meteor/agent"
+	"github.com/odpf/meteor/models"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/recipe"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/test/mocks"
+	"github.com/odpf/meteor/test/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryMonitor(t *testing.T) {
+	newRun := func(name string) agent.Run {
+		return agent.Run{Recipe: recipe.Recipe{Name: name}, Success: true}
+	}
+
+	t.Run("should return no run before any is recorded", func(t *testing.T) {
+		monitor := agent.NewMemoryMonitor()
+
+		_, ok := monitor.Last()
+		assert.False(t, ok)
+		assert.Empty(t, monitor.Runs())
+	})
+
+	t.Run("should return runs in the order they were recorded", func(t *testing.T) {
+		monitor := agent.NewMemoryMonitor()
+		monitor.RecordRun(newRun("first"))
+		monitor.RecordRun(newRun("second"))
+
+		assert.Equal(t, []agent.Run{newRun("first"), newRun("second")}, monitor.Runs())
+		last, ok := monitor.Last()
+		assert.True(t, ok)
+		assert.Equal(t, newRun("second"), last)
+	})
+
+	t.Run("should return a copy of the runs", func(t *testing.T) {
+		monitor := agent.NewMemoryMonitor()
+		monitor.RecordRun(newRun("first"))
+
+		runs := monitor.Runs()
+		runs[0] = newRun("changed")
+
+		assert.Equal(t, []agent.Run{newRun("first")}, monitor.Runs())
+	})
+
+	t.Run("should record runs of multiple recipes run concurrently", func(t *testing.T) {
+		data := []models.Record{models.NewRecord(&assetsv1beta1.Table{})}
+
+		extr := mocks.NewExtractor()
+		extr.SetEmit(data)
+		extr.On("Init", mock.Anything, mock.Anything).Return(nil)
+		extr.On("Extract", mock.Anything, mock.AnythingOfType("plugins.Emit")).Return(nil)
+		ef := registry.NewExtractorFactory()
+		require.NoError(t, ef.Register("test-extractor", newExtractor(extr)))
+
+		sink := mocks.NewSink()
+		sink.On("Init", mock.Anything, mock.Anything).Return(nil)
+		sink.On("Sink", mock.Anything, data).Return(nil)
+		sink.On("Close").Return(nil)
+		sf := registry.NewSinkFactory()
+		require.NoError(t, sf.Register("test-sink", newSink(sink)))
+
+		monitor := agent.NewMemoryMonitor()
+		r := agent.NewAgent(agent.Config{
+			ExtractorFactory: ef,
+			ProcessorFactory: registry.NewProcessorFactory(),
+			SinkFactory:      sf,
+			Logger:           utils.Logger,
+			Monitor:          monitor,
+		})
+
+		var recipes []recipe.Recipe
+		for _, name := range []string{"a", "b", "c", "d"} {
+			recipes = append(recipes, recipe.Recipe{
+				Name:   name,
+				Source: recipe.SourceRecipe{Type: "test-extractor"},
+				Sinks:  []recipe.SinkRecipe{{Name: "test-sink"}},
+			})
+		}
+		r.RunMultiple(recipes)
+
+		var names []string
+		for _, run := range monitor.Runs() {
+			assert.True(t, run.Success)
+			names = append(names, run.Recipe.Name)
+		}
+		assert.ElementsMatch(t, []string{"a", "b", "c", "d"}, names)
+	})
+
+	t.Run("should be safe for concurrent use", func(t *testing.T) {
+		monitor := agent.NewMemoryMonitor()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				monitor.RecordRun(newRun("run"))
+				monitor.Runs()
+				monitor.Last()
+			}()
+		}
+		wg.Wait()
+
+		assert.Len(t, monitor.Runs(), 50)
+	})
+}
@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/odpf/meteor/models"
+)
+
+// rateLimiter is a token bucket holding a single token, refilled rate times per second,
+// so consecutive records are spaced by at least 1/rate seconds.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	// next is when the next token is available
+	next time.Time
+}
+
+func newRateLimiter(rate float64) *rateLimiter {
+	return &rateLimiter{
+		interval: time.Duration(float64(time.Second) / rate),
+	}
+}
+
+// wait blocks until a token is available or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// middleware throttles the records going through the stream
+func (l *rateLimiter) middleware(ctx context.Context) streamMiddleware {
+	return func(src models.Record) (models.Record, error) {
+		// records pushed once ctx is done are not emitted by the stopped stream
+		l.wait(ctx)
+		return src, nil
+	}
+}
@@ -16,9 +16,25 @@ const (
 
 // Run contains the json data
 type Run struct {
+	// RunID is a UUID generated at the start of the run, it is logged with every line of the run
+	RunID        string        `json:"run_id"`
 	Recipe       recipe.Recipe `json:"recipe"`
 	Error        error         `json:"error"`
 	DurationInMs int           `json:"duration_in_ms"`
 	RecordCount  int           `json:"record_count"`
-	Success      bool          `json:"success"`
+	LineageCount int           `json:"lineage_count"`
+	// ExtractedCount is the number of records emitted by the extractor
+	ExtractedCount int `json:"extracted_count"`
+	// ProcessedCount is the number of records, assets and lineage edges, not dropped by processors
+	ProcessedCount int `json:"processed_count"`
+	// SinkedCount is the number of records successfully sent to sinks, summed over the sinks
+	SinkedCount int  `json:"sinked_count"`
+	Success     bool `json:"success"`
+	// Incomplete is set when the run was interrupted by a cancelled context or the recipe timeout
+	Incomplete bool `json:"incomplete"`
+	// SinkErrors holds the errors of batches not sent to a sink after exhausted retries,
+	// they do not fail the run unless the agent stops on sink errors.
+	SinkErrors []error `json:"sink_errors,omitempty"`
+	// TrippedSinks are the names of the sinks whose circuit breaker tripped during the run
+	TrippedSinks []string `json:"tripped_sinks,omitempty"`
 }
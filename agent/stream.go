@@ -5,47 +5,85 @@ import (
 	"sync"
 
 	"github.com/odpf/meteor/models"
+	"github.com/odpf/meteor/plugins"
 	"github.com/pkg/errors"
 )
 
 type streamMiddleware func(src models.Record) (dst models.Record, err error)
+type streamFlusher struct {
+	flush func() ([]models.Record, error)
+	// next is the index of the first middleware flushed records go through
+	next int
+}
 type subscriber struct {
-	callback  func([]models.Record) error
-	channel   chan models.Record
-	batchSize int
+	callback   func([]models.Record) error
+	channel    chan models.Record
+	batchSize  int
+	assetTypes map[string]bool
+}
+
+// accepts checks if the record holds one of the subscribed asset types, any if none
+func (l *subscriber) accepts(data models.Record) bool {
+	return len(l.assetTypes) == 0 || l.assetTypes[models.AssetType(data.Data())]
 }
 
 type stream struct {
+	bufferSize  int
 	middlewares []streamMiddleware
+	flushers    []streamFlusher
 	subscribers []*subscriber
 	onCloses    []func()
+	done        chan struct{}
+	mu          sync.Mutex
 	closed      bool
 	err         error
 }
 
-func newStream() *stream {
-	return &stream{}
+// newStream returns a stream buffering up to bufferSize records for each subscriber,
+// push() blocks once a subscriber's buffer is full.
+func newStream(bufferSize int) *stream {
+	return &stream{
+		bufferSize: bufferSize,
+		done:       make(chan struct{}),
+	}
 }
 
-// subscribe() will register callback with a batch size to the emitter.
+// subscribe() will register callback with a batch size to the emitter,
+// the callback only receives records of the given asset types if any.
 // Calling this will not start listening yet, use broadcast() to start sending data to subscriber.
-func (s *stream) subscribe(callback func(batchedData []models.Record) error, batchSize int) *stream {
-	s.subscribers = append(s.subscribers, &subscriber{
+func (s *stream) subscribe(callback func(batchedData []models.Record) error, batchSize int, assetTypes ...string) *stream {
+	l := &subscriber{
 		callback:  callback,
 		batchSize: batchSize,
-		channel:   make(chan models.Record),
-	})
+		channel:   make(chan models.Record, s.bufferSize),
+	}
+	if len(assetTypes) > 0 {
+		l.assetTypes = make(map[string]bool, len(assetTypes))
+		for _, assetType := range assetTypes {
+			l.assetTypes[assetType] = true
+		}
+	}
+	s.subscribers = append(s.subscribers, l)
 
 	return s
 }
 
-// onClose() is used to register callback for after stream is closed.
+// onClose() is used to register callback for after stream is closed
+// and all subscribers have received their leftover data.
 func (s *stream) onClose(callback func()) *stream {
 	s.onCloses = append(s.onCloses, callback)
 
 	return s
 }
 
+// runOnCloses() calls the registered callbacks, it is also used
+// when the stream is never broadcast, e.g. when a plugin failed to setup.
+func (s *stream) runOnCloses() {
+	for _, onClose := range s.onCloses {
+		onClose()
+	}
+}
+
 // broadcast() will start listening to emitter for any pushed data.
 // This process is blocking, so most times you would want to call this inside a goroutine.
 func (s *stream) broadcast() error {
@@ -61,8 +99,7 @@ func (s *stream) broadcast() error {
 			}()
 
 			batch := newBatch(l.batchSize)
-			// listen to channel and emit data to subscriber callback if batch is full
-			for d := range l.channel {
+			receive := func(d models.Record) {
 				if err := batch.add(d); err != nil {
 					s.closeWithError(err)
 				}
@@ -72,33 +109,124 @@ func (s *stream) broadcast() error {
 					}
 				}
 			}
-
-			// emit leftover data in the batch if any after channel is closed
-			if !batch.isEmpty() {
-				if err := l.callback(batch.flush()); err != nil {
-					s.closeWithError(err)
+			// listen to channel and emit data to subscriber callback if batch is full
+			for {
+				select {
+				case d := <-l.channel:
+					receive(d)
+				case <-s.done:
+					// receive data still buffered in the channel after stream is closed
+					for buffered := true; buffered; {
+						select {
+						case d := <-l.channel:
+							receive(d)
+						default:
+							buffered = false
+						}
+					}
+					// emit leftover data in the batch if any after stream is closed
+					if !batch.isEmpty() {
+						if err := l.callback(batch.flush()); err != nil {
+							s.closeWithError(err)
+						}
+					}
+					return
 				}
 			}
 		}(l)
 	}
 
 	wg.Wait()
+	s.runOnCloses()
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.err
 }
 
 // push() will run the record through all the registered middleware
 // and emit the record to all registered subscribers.
+// Records dropped by a middleware or pushed after the stream is closed are not emitted.
 func (s *stream) push(data models.Record) {
-	data, err := s.runMiddlewares(data)
+	s.pushFrom(0, data)
+}
+
+// concurrentPush() returns a push function handing records to n workers, each running records
+// through the middlewares and emitting them like push() does, so records may reach subscribers
+// in a different order than they were pushed. wait blocks until the workers are done with the
+// records pushed, records pushed afterwards are not emitted.
+func (s *stream) concurrentPush(n int) (push func(models.Record), wait func()) {
+	var (
+		records = make(chan models.Record)
+		wg      sync.WaitGroup
+		mu      sync.RWMutex
+		closed  bool
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					s.closeWithError(fmt.Errorf("%s", r))
+				}
+				wg.Done()
+			}()
+			for record := range records {
+				s.push(record)
+			}
+		}()
+	}
+
+	push = func(data models.Record) {
+		mu.RLock()
+		defer mu.RUnlock()
+		if closed {
+			return
+		}
+		select {
+		case records <- data:
+		case <-s.done:
+		}
+	}
+	wait = func() {
+		mu.Lock()
+		if !closed {
+			closed = true
+			close(records)
+		}
+		mu.Unlock()
+		wg.Wait()
+	}
+
+	return push, wait
+}
+
+// pushFrom() pushes the record skipping the middlewares registered before index.
+func (s *stream) pushFrom(index int, data models.Record) {
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+
+	data, err := s.runMiddlewares(index, data)
+	if errors.Is(err, plugins.DropRecordError{}) {
+		return
+	}
 	if err != nil {
-		s.err = errors.Wrap(err, "emitter: error running middleware")
-		s.Close()
+		s.closeWithError(errors.Wrap(err, "emitter: error running middleware"))
 		return
 	}
 
 	for _, l := range s.subscribers {
-		l.channel <- data
+		if !l.accepts(data) {
+			continue
+		}
+		select {
+		case l.channel <- data:
+		case <-s.done:
+			return
+		}
 	}
 }
 
@@ -109,31 +237,73 @@ func (s *stream) setMiddleware(m streamMiddleware) *stream {
 	return s
 }
 
+// setAssetMiddleware registers a middleware that will only be used to
+// process records holding the given asset type, other records are passed as is.
+func (s *stream) setAssetMiddleware(assetType string, m streamMiddleware) *stream {
+	return s.setMiddleware(func(src models.Record) (models.Record, error) {
+		if models.AssetType(src.Data()) != assetType {
+			return src, nil
+		}
+
+		return m(src)
+	})
+}
+
+// setFlusher registers a function releasing records held back by the last registered middleware,
+// the records go through the middlewares registered after it.
+func (s *stream) setFlusher(flush func() ([]models.Record, error)) *stream {
+	s.flushers = append(s.flushers, streamFlusher{
+		flush: flush,
+		next:  len(s.middlewares),
+	})
+	return s
+}
+
+// flush() pushes the records released by the flushers in the order they were registered,
+// so records released by a flusher can be held back again by the following ones.
+func (s *stream) flush() error {
+	for _, f := range s.flushers {
+		records, err := f.flush()
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			s.pushFrom(f.next, record)
+		}
+	}
+
+	return nil
+}
+
 func (s *stream) closeWithError(err error) {
+	s.mu.Lock()
 	s.err = err
+	s.mu.Unlock()
 	s.Close()
 }
 
 // Close the emitter and signalling all subscriber of the event.
 func (s *stream) Close() {
+	s.stop()
+}
+
+// stop closes the stream and reports whether it was still open.
+func (s *stream) stop() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.closed {
-		return
+		return false
 	}
 
-	for _, l := range s.subscribers {
-		close(l.channel)
-	}
+	close(s.done)
 	s.closed = true
-
-	for _, onClose := range s.onCloses {
-		onClose()
-	}
+	return true
 }
 
-func (s *stream) runMiddlewares(d models.Record) (res models.Record, err error) {
+func (s *stream) runMiddlewares(from int, d models.Record) (res models.Record, err error) {
 	res = d
-	for _, middleware := range s.middlewares {
-		res, err = middleware(d)
+	for _, middleware := range s.middlewares[from:] {
+		res, err = middleware(res)
 		if err != nil {
 			return
 		}
@@ -0,0 +1,52 @@
+package agent
+
+// Summary aggregates the results of multiple runs
+type Summary struct {
+	Success      int                      `json:"success"`
+	Failure      int                      `json:"failure"`
+	RecordCount  int                      `json:"record_count"`
+	LineageCount int                      `json:"lineage_count"`
+	DurationInMs int                      `json:"duration_in_ms"`
+	Sources      map[string]SourceSummary `json:"sources"`
+}
+
+// SourceSummary aggregates the results of runs sharing the same source type
+type SourceSummary struct {
+	Success      int `json:"success"`
+	Failure      int `json:"failure"`
+	RecordCount  int `json:"record_count"`
+	LineageCount int `json:"lineage_count"`
+	DurationInMs int `json:"duration_in_ms"`
+}
+
+// Summarize aggregates the runs, e.g. as returned by RunMultiple.
+// DurationInMs is the sum of the run durations, regardless of runs being concurrent.
+func Summarize(runs []Run) Summary {
+	summary := Summary{
+		Sources: make(map[string]SourceSummary),
+	}
+	for _, run := range runs {
+		source := summary.Sources[run.Recipe.Source.Type]
+		if run.Success {
+			summary.Success++
+			source.Success++
+		} else {
+			summary.Failure++
+			source.Failure++
+		}
+		summary.RecordCount += run.RecordCount
+		summary.LineageCount += run.LineageCount
+		summary.DurationInMs += run.DurationInMs
+		source.RecordCount += run.RecordCount
+		source.LineageCount += run.LineageCount
+		source.DurationInMs += run.DurationInMs
+		summary.Sources[run.Recipe.Source.Type] = source
+	}
+
+	return summary
+}
+
+// HasFailure checks if any of the summarized runs failed
+func (s Summary) HasFailure() bool {
+	return s.Failure > 0
+}
@@ -0,0 +1,71 @@
+package agent_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/odpf/meteor/agent"
+	"github.com/odpf/meteor/recipe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarize(t *testing.T) {
+	newRun := func(source string, success bool, records, duration int) agent.Run {
+		run := agent.Run{
+			Recipe:       recipe.Recipe{Name: source + "-recipe", Source: recipe.SourceRecipe{Type: source}},
+			Success:      success,
+			RecordCount:  records,
+			DurationInMs: duration,
+		}
+		if !success {
+			run.Error = errors.New("failed")
+		}
+		return run
+	}
+
+	t.Run("should aggregate runs in total and per source", func(t *testing.T) {
+		runs := []agent.Run{
+			newRun("mysql", true, 10, 100),
+			newRun("mysql", false, 2, 50),
+			newRun("kafka", true, 5, 20),
+		}
+		runs[2].LineageCount = 3
+
+		summary := agent.Summarize(runs)
+		assert.Equal(t, agent.Summary{
+			Success:      2,
+			Failure:      1,
+			RecordCount:  17,
+			LineageCount: 3,
+			DurationInMs: 170,
+			Sources: map[string]agent.SourceSummary{
+				"mysql": {Success: 1, Failure: 1, RecordCount: 12, DurationInMs: 150},
+				"kafka": {Success: 1, RecordCount: 5, LineageCount: 3, DurationInMs: 20},
+			},
+		}, summary)
+		assert.True(t, summary.HasFailure())
+	})
+
+	t.Run("should return empty summary without runs", func(t *testing.T) {
+		summary := agent.Summarize(nil)
+		assert.Equal(t, 0, summary.Success)
+		assert.False(t, summary.HasFailure())
+	})
+
+	t.Run("should be serializable to JSON", func(t *testing.T) {
+		out, err := json.Marshal(agent.Summarize([]agent.Run{newRun("mysql", true, 1, 10)}))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"success": 1,
+			"failure": 0,
+			"record_count": 1,
+			"lineage_count": 0,
+			"duration_in_ms": 10,
+			"sources": {
+				"mysql": {"success": 1, "failure": 0, "record_count": 1, "lineage_count": 0, "duration_in_ms": 10}
+			}
+		}`, string(out))
+	})
+}
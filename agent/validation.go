@@ -0,0 +1,170 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/odpf/meteor/models"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/recipe"
+	"github.com/pkg/errors"
+)
+
+// ValidationError is a linting error of a single plugin in a recipe.
+// Field is the config key the error belongs to, empty if it is not specific to one.
+type ValidationError struct {
+	PluginName string             `json:"plugin_name"`
+	PluginType plugins.PluginType `json:"plugin_type"`
+	Field      string             `json:"field,omitempty"`
+	Message    string             `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("invalid config for %s (%s): %s", e.PluginName, e.PluginType, e.Message)
+	}
+
+	return fmt.Sprintf("invalid config for %s (%s): %s: %s", e.PluginName, e.PluginType, e.Field, e.Message)
+}
+
+// ValidationResult holds the linting errors of a recipe.
+type ValidationResult struct {
+	Recipe string            `json:"recipe"`
+	Errors []ValidationError `json:"errors"`
+}
+
+// Valid returns true if the recipe has no linting errors.
+func (v ValidationResult) Valid() bool {
+	return len(v.Errors) == 0
+}
+
+// Errs returns the linting errors as errors.
+func (v ValidationResult) Errs() (errs []error) {
+	for _, e := range v.Errors {
+		errs = append(errs, e)
+	}
+
+	return
+}
+
+// ValidateDetailed checks the recipe for linting errors,
+// listing them per plugin and per config field.
+func (r *Agent) ValidateDetailed(rcp recipe.Recipe) (result ValidationResult) {
+	result.Recipe = rcp.Name
+	add := func(name string, typ plugins.PluginType, err error) {
+		result.Errors = append(result.Errors, toValidationErrors(name, typ, err)...)
+	}
+	validateLogLevel := func(name string, typ plugins.PluginType, config map[string]interface{}) {
+		if _, err := plugins.LogLevel(config); err != nil {
+			result.Errors = append(result.Errors, ValidationError{
+				PluginName: name,
+				PluginType: typ,
+				Field:      plugins.LogLevelKey,
+				Message:    err.Error(),
+			})
+		}
+	}
+
+	if ext, err := r.extractorFactory.Get(rcp.Source.Type); err != nil {
+		add(rcp.Source.Type, plugins.PluginTypeExtractor, err)
+	} else if err = ext.Validate(plugins.WithoutLogLevel(rcp.Source.Config)); err != nil {
+		add(rcp.Source.Type, plugins.PluginTypeExtractor, err)
+	}
+	validateLogLevel(rcp.Source.Type, plugins.PluginTypeExtractor, rcp.Source.Config)
+
+	for _, s := range rcp.Sinks {
+		if s.BatchSize < 0 {
+			result.Errors = append(result.Errors, ValidationError{
+				PluginName: s.Name,
+				PluginType: plugins.PluginTypeSink,
+				Field:      "batch_size",
+				Message:    fmt.Sprintf("invalid batch size %d", s.BatchSize),
+			})
+		}
+		for _, assetType := range s.Types {
+			if !models.IsAssetType(assetType) {
+				result.Errors = append(result.Errors, ValidationError{
+					PluginName: s.Name,
+					PluginType: plugins.PluginTypeSink,
+					Field:      "types",
+					Message:    fmt.Sprintf("invalid asset type \"%s\"", assetType),
+				})
+			}
+		}
+		validateLogLevel(s.Name, plugins.PluginTypeSink, s.Config)
+		sink, err := r.sinkFactory.Get(s.Name)
+		if err != nil {
+			add(s.Name, plugins.PluginTypeSink, err)
+			continue
+		}
+		if err = sink.Validate(plugins.WithoutLogLevel(s.Config)); err != nil {
+			add(s.Name, plugins.PluginTypeSink, err)
+		}
+	}
+
+	validateProcessors := func(processors []recipe.ProcessorRecipe) {
+		for _, p := range processors {
+			validateLogLevel(p.Name, plugins.PluginTypeProcessor, p.Config)
+			procc, err := r.processorFactory.Get(p.Name)
+			if err != nil {
+				add(p.Name, plugins.PluginTypeProcessor, err)
+				continue
+			}
+			if err = procc.Validate(plugins.WithoutLogLevel(p.Config)); err != nil {
+				add(p.Name, plugins.PluginTypeProcessor, err)
+			}
+		}
+	}
+	validateProcessors(rcp.Processors)
+	for assetType, processors := range rcp.AssetProcessors {
+		if !models.IsAssetType(assetType) {
+			result.Errors = append(result.Errors, ValidationError{
+				PluginType: plugins.PluginTypeProcessor,
+				Field:      "asset_processors",
+				Message:    fmt.Sprintf("invalid asset type \"%s\"", assetType),
+			})
+		}
+		validateProcessors(processors)
+	}
+
+	return
+}
+
+// toValidationErrors splits config validation errors per field.
+func toValidationErrors(name string, typ plugins.PluginType, err error) (errs []ValidationError) {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return []ValidationError{{PluginName: name, PluginType: typ, Message: err.Error()}}
+	}
+
+	for _, fe := range fieldErrs {
+		errs = append(errs, ValidationError{
+			PluginName: name,
+			PluginType: typ,
+			Field:      fieldPath(fe.Namespace()),
+			Message:    fieldMessage(fe),
+		})
+	}
+	return
+}
+
+// fieldPath removes the config struct name from the namespace, e.g. "Config.host" becomes "host".
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+
+	return namespace
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch {
+	case fe.Tag() == "required":
+		return "is required"
+	case fe.Param() != "":
+		return fmt.Sprintf("failed on \"%s=%s\" validation", fe.Tag(), fe.Param())
+	default:
+		return fmt.Sprintf("failed on \"%s\" validation", fe.Tag())
+	}
+}
@@ -51,7 +51,8 @@ func LintCmd(lg log.Logger, mt *metrics.StatsdMonitor) *cobra.Command {
 				Logger:           lg,
 			})
 
-			recipes, err := recipe.NewReader().Read(args[0])
+			// secrets are not needed, vault references are kept as is
+			recipes, err := recipe.NewReader().WithSecretResolver("vault", recipe.NoopResolver{}).Read(args[0])
 			if err != nil {
 				return err
 			}
@@ -67,17 +68,32 @@ func LintCmd(lg log.Logger, mt *metrics.StatsdMonitor) *cobra.Command {
 			var failures = 0
 
 			// Run linters and generate report
-			for _, recipe := range recipes {
-				errs := runner.Validate(recipe)
-				var row []string
-				if len(errs) > 0 {
-					for _, err := range errs {
-						lg.Error(err.Error())
+			for _, rcp := range recipes {
+				result := runner.ValidateDetailed(rcp)
+				errs := result.Errors
+				for _, err := range errs {
+					lg.Error(err.Message, "recipe", rcp.Name, "plugin", err.PluginName, "type", err.PluginType, "field", err.Field)
+				}
+
+				// unknown plugins are already reported by the agent
+				var lintErrs, lintWarns int
+				for _, issue := range recipe.Lint(rcp, recipe.Registries{}) {
+					if issue.Severity == recipe.LintSeverityWarning {
+						lg.Warn(issue.Message, "recipe", rcp.Name, "field", issue.Field)
+						lintWarns++
+						continue
 					}
-					row = []string{fmt.Sprintf("%s  %s", cs.FailureIcon(), recipe.Name), cs.Greyf("(%d errors, 0 warnings)", len(errs))}
+					lg.Error(issue.Message, "recipe", rcp.Name, "field", issue.Field)
+					lintErrs++
+				}
+
+				var row []string
+				count := cs.Greyf("(%d errors, %d warnings)", len(errs)+lintErrs, lintWarns)
+				if len(errs)+lintErrs > 0 {
+					row = []string{fmt.Sprintf("%s  %s", cs.FailureIcon(), rcp.Name), count}
 					failures++
 				} else {
-					row = []string{fmt.Sprintf("%s  %s", cs.SuccessIcon(), recipe.Name), cs.Greyf("(%d errors, 0 warnings)", len(errs))}
+					row = []string{fmt.Sprintf("%s  %s", cs.SuccessIcon(), rcp.Name), count}
 					success++
 				}
 				report = append(report, row)
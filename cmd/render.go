@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/odpf/meteor/recipe"
+	"github.com/odpf/salt/log"
+	"github.com/odpf/salt/term"
+	"github.com/spf13/cobra"
+)
+
+// RenderCmd creates a command object for printing recipes after variable substitution
+func RenderCmd(lg log.Logger) *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "render <path>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Print recipes as received by the plugins",
+		Long: heredoc.Doc(`
+			Print recipes after variable substitution.
+
+			Secrets such as passwords and tokens are masked in the output.`),
+		Example: heredoc.Doc(`
+			$ meteor render recipe.yml
+
+			# render all recipes in the specified directory as json
+			$ meteor render _recipes/ --format json
+		`),
+		Annotations: map[string]string{
+			"group:core": "true",
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cs := term.NewColorScheme()
+
+			// secrets are not needed, vault references are kept as is
+			recipes, err := recipe.NewReader().WithSecretResolver("vault", recipe.NoopResolver{}).Read(args[0])
+			if err != nil {
+				return err
+			}
+
+			if len(recipes) == 0 {
+				fmt.Println(cs.Yellowf("No recipe found in [%s]", args[0]))
+				return nil
+			}
+
+			for _, rcp := range recipes {
+				out, err := recipe.Render(rcp, format)
+				if err != nil {
+					return err
+				}
+
+				fmt.Println(string(out))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "yaml", "Output format, one of yaml or json")
+
+	return cmd
+}
@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/MakeNowJust/heredoc"
@@ -15,12 +18,18 @@ import (
 	"github.com/odpf/salt/log"
 	"github.com/odpf/salt/printer"
 	"github.com/odpf/salt/term"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
 // RunCmd creates a command object for the "run" action.
 func RunCmd(lg log.Logger, mt *metrics.StatsdMonitor, cfg config.Config) *cobra.Command {
-	return &cobra.Command{
+	var (
+		include []string
+		exclude []string
+	)
+
+	cmd := &cobra.Command{
 		Use:   "run <path>|<name>",
 		Short: "Execute recipes for metadata extraction",
 		Long: heredoc.Doc(`
@@ -39,6 +48,9 @@ func RunCmd(lg log.Logger, mt *metrics.StatsdMonitor, cfg config.Config) *cobra.
 
 			# run all recipes in the current directory
 			$ meteor run .
+
+			# only send the assets of the sales database, except temporary tables, to the sinks
+			$ meteor run recipe.yml --select "sales.*" --reject "sales.tmp_*"
 		`),
 		Args: cobra.ExactArgs(1),
 		Annotations: map[string]string{
@@ -48,17 +60,25 @@ func RunCmd(lg log.Logger, mt *metrics.StatsdMonitor, cfg config.Config) *cobra.
 
 			cs := term.NewColorScheme()
 			runner := agent.NewAgent(agent.Config{
-				ExtractorFactory:     registry.Extractors,
-				ProcessorFactory:     registry.Processors,
-				SinkFactory:          registry.Sinks,
-				Monitor:              mt,
-				Logger:               lg,
-				MaxRetries:           cfg.MaxRetries,
-				RetryInitialInterval: time.Duration(cfg.RetryInitialIntervalSeconds) * time.Second,
-				StopOnSinkError:      cfg.StopOnSinkError,
+				ExtractorFactory:        registry.Extractors,
+				ProcessorFactory:        registry.Processors,
+				SinkFactory:             registry.Sinks,
+				Monitor:                 mt,
+				Logger:                  lg,
+				MaxRetries:              cfg.MaxRetries,
+				RetryInitialInterval:    time.Duration(cfg.RetryInitialIntervalSeconds) * time.Second,
+				StopOnSinkError:         cfg.StopOnSinkError,
+				CircuitBreakerThreshold: cfg.CircuitBreakerThreshold,
+				OnInvalidRecord:         agent.InvalidRecordPolicy(cfg.OnInvalidRecord),
+				ProcessConcurrency:      cfg.ProcessConcurrency,
+				Include:                 include,
+				Exclude:                 exclude,
+				NewLogger:               pluginLogger(cfg.LogFormat),
 			})
 
-			recipes, err := recipe.NewReader().Read(args[0])
+			recipes, err := recipe.NewReader().
+				WithSecretResolver("vault", recipe.NewVaultResolver(cfg.VaultAddress, cfg.VaultToken)).
+				Read(args[0])
 			if err != nil {
 				return err
 			}
@@ -69,35 +89,63 @@ func RunCmd(lg log.Logger, mt *metrics.StatsdMonitor, cfg config.Config) *cobra.
 			}
 
 			report := [][]string{}
-			var success = 0
-			var failures = 0
 			report = append(report, []string{"Status", "Recipe", "Source", "Duration(ms)", "Records"})
 
-			// Run recipes and collect results
-			runs := runner.RunMultiple(recipes)
+			// Run recipes and collect results,
+			// interrupted runs still flush their current batches to the sinks
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			runs := runner.RunMultipleWithContext(ctx, recipes)
 			for _, run := range runs {
-				lg.Debug("recipe details", "recipe", run.Recipe)
+				lg.Debug("recipe details", "recipe", run.Recipe, "run_id", run.RunID)
 				row := []string{}
-				if run.Error != nil {
-					lg.Error(run.Error.Error(), "recipe")
-					failures++
+				if run.Incomplete {
+					lg.Warn(run.Error.Error(), "recipe", run.Recipe.Name, "run_id", run.RunID)
+					row = append(row, cs.WarningIcon(), run.Recipe.Name, cs.Grey(run.Recipe.Source.Type), cs.Greyf("%v ms", strconv.Itoa(run.DurationInMs)), cs.Greyf(strconv.Itoa(run.RecordCount)))
+				} else if run.Error != nil {
+					lg.Error(run.Error.Error(), "recipe", run.Recipe.Name, "run_id", run.RunID)
 					row = append(row, cs.FailureIcon(), run.Recipe.Name, cs.Grey(run.Recipe.Source.Type), cs.Greyf("%v ms", strconv.Itoa(run.DurationInMs)), cs.Greyf(strconv.Itoa(run.RecordCount)))
+				} else if len(run.SinkErrors) > 0 {
+					// the run succeeded but some records did not land in a sink
+					for _, err := range run.SinkErrors {
+						lg.Warn(err.Error(), "recipe", run.Recipe.Name, "run_id", run.RunID)
+					}
+					for _, sink := range run.TrippedSinks {
+						lg.Warn("sink circuit breaker tripped", "sink", sink, "recipe", run.Recipe.Name, "run_id", run.RunID)
+					}
+					row = append(row, cs.WarningIcon(), run.Recipe.Name, cs.Grey(run.Recipe.Source.Type), cs.Greyf("%v ms", strconv.Itoa(run.DurationInMs)), cs.Greyf(strconv.Itoa(run.RecordCount)))
 				} else {
-					success++
 					row = append(row, cs.SuccessIcon(), run.Recipe.Name, cs.Grey(run.Recipe.Source.Type), cs.Greyf("%v ms", strconv.Itoa(run.DurationInMs)), cs.Greyf(strconv.Itoa(run.RecordCount)))
 				}
 				report = append(report, row)
 			}
 
 			// Print the report
-			if failures > 0 {
+			summary := agent.Summarize(runs)
+			if summary.HasFailure() {
 				fmt.Println("\nSome recipes were not successful")
 			} else {
 				fmt.Println("\nAll recipes ran successful")
 			}
-			fmt.Printf("%d failing, %d successful, and %d total\n\n", failures, success, len(recipes))
+			fmt.Printf("%d failing, %d successful, and %d total\n\n", summary.Failure, summary.Success, len(recipes))
 			printer.Table(os.Stdout, report)
 			return nil
 		},
 	}
+
+	cmd.Flags().StringSliceVar(&include, "select", nil, "Only sink assets with an urn matching one of these glob patterns")
+	cmd.Flags().StringSliceVar(&exclude, "reject", nil, "Do not sink assets with an urn matching one of these glob patterns")
+
+	return cmd
+}
+
+// pluginLogger builds the loggers of plugins with a log_level in their config, in the format of the agent logs
+func pluginLogger(format string) func(level string) log.Logger {
+	return func(level string) log.Logger {
+		opts := []log.Option{log.LogrusWithLevel(level)}
+		if format == "json" {
+			opts = append(opts, log.LogrusWithFormatter(&logrus.JSONFormatter{}))
+		}
+		return log.NewLogrus(opts...)
+	}
 }
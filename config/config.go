@@ -7,12 +7,18 @@ import (
 // Config contains the configuration for meteor.
 type Config struct {
 	LogLevel                    string `mapstructure:"LOG_LEVEL" default:"info"`
+	LogFormat                   string `mapstructure:"LOG_FORMAT" default:"text"`
 	StatsdEnabled               bool   `mapstructure:"STATSD_ENABLED" default:"false"`
 	StatsdHost                  string `mapstructure:"STATSD_HOST" default:"localhost:8125"`
 	StatsdPrefix                string `mapstructure:"STATSD_PREFIX" default:"meteor"`
 	MaxRetries                  int    `mapstructure:"MAX_RETRIES" default:"5"`
 	RetryInitialIntervalSeconds int    `mapstructure:"RETRY_INITIAL_INTERVAL_SECONDS" default:"5"`
 	StopOnSinkError             bool   `mapstructure:"STOP_ON_SINK_ERROR" default:"false"`
+	CircuitBreakerThreshold     int    `mapstructure:"CIRCUIT_BREAKER_THRESHOLD" default:"0"`
+	OnInvalidRecord             string `mapstructure:"ON_INVALID_RECORD" default:"skip"`
+	ProcessConcurrency          int    `mapstructure:"PROCESS_CONCURRENCY" default:"1"`
+	VaultAddress                string `mapstructure:"VAULT_ADDR"`
+	VaultToken                  string `mapstructure:"VAULT_TOKEN"`
 }
 
 func Load() (cfg Config, err error) {
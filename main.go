@@ -14,6 +14,7 @@ import (
 	_ "github.com/odpf/meteor/plugins/processors"
 	_ "github.com/odpf/meteor/plugins/sinks"
 	"github.com/odpf/salt/log"
+	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -28,7 +29,16 @@ func main() {
 		os.Exit(1)
 	}
 
-	lg := log.NewLogrus(log.LogrusWithLevel(cfg.LogLevel))
+	logOpts := []log.Option{log.LogrusWithLevel(cfg.LogLevel)}
+	switch cfg.LogFormat {
+	case "text":
+	case "json":
+		logOpts = append(logOpts, log.LogrusWithFormatter(&logrus.JSONFormatter{}))
+	default:
+		fmt.Printf("ERROR: invalid log format \"%s\", expected text or json\n", cfg.LogFormat)
+		os.Exit(exitError)
+	}
+	lg := log.NewLogrus(logOpts...)
 	plugins.SetLog(lg)
 
 	// Setup statsd monitor to collect monitoring metrics
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/odpf/meteor/agent"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var runLabels = []string{"recipe", "source", "success"}
+
+// PrometheusMonitor records runs as prometheus metrics.
+// It is a prometheus.Collector, register it to a prometheus.Registerer
+// and serve it with your own http.Handler, e.g. promhttp.HandlerFor.
+type PrometheusMonitor struct {
+	runs         *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	recordCount  *prometheus.CounterVec
+	lineageCount *prometheus.CounterVec
+}
+
+// NewPrometheusMonitor creates a new PrometheusMonitor with metric names prefixed by namespace
+func NewPrometheusMonitor(namespace string) *PrometheusMonitor {
+	return &PrometheusMonitor{
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "runs_total",
+			Help:      "Number of recipe runs.",
+		}, runLabels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "run_duration_seconds",
+			Help:      "Duration of recipe runs in seconds.",
+			Buckets:   prometheus.ExponentialBuckets(0.1, 4, 8),
+		}, runLabels),
+		recordCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "run_records_total",
+			Help:      "Number of records extracted by recipe runs.",
+		}, runLabels),
+		lineageCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "run_lineage_records_total",
+			Help:      "Number of lineage records extracted by recipe runs.",
+		}, runLabels),
+	}
+}
+
+// RecordRun records a run behavior
+func (m *PrometheusMonitor) RecordRun(run agent.Run) {
+	labels := prometheus.Labels{
+		"recipe":  run.Recipe.Name,
+		"source":  run.Recipe.Source.Type,
+		"success": strconv.FormatBool(run.Success),
+	}
+
+	m.runs.With(labels).Inc()
+	m.duration.With(labels).Observe(float64(run.DurationInMs) / 1000)
+	m.recordCount.With(labels).Add(float64(run.RecordCount))
+	m.lineageCount.With(labels).Add(float64(run.LineageCount))
+}
+
+// Register registers the metrics of the monitor to the registerer
+func (m *PrometheusMonitor) Register(registerer prometheus.Registerer) error {
+	return registerer.Register(m)
+}
+
+// Describe implements prometheus.Collector
+func (m *PrometheusMonitor) Describe(ch chan<- *prometheus.Desc) {
+	m.runs.Describe(ch)
+	m.duration.Describe(ch)
+	m.recordCount.Describe(ch)
+	m.lineageCount.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (m *PrometheusMonitor) Collect(ch chan<- prometheus.Metric) {
+	m.runs.Collect(ch)
+	m.duration.Collect(ch)
+	m.recordCount.Collect(ch)
+	m.lineageCount.Collect(ch)
+}
@@ -0,0 +1,80 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/odpf/meteor/agent"
+	"github.com/odpf/meteor/metrics"
+	"github.com/odpf/meteor/recipe"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusMonitorRecordRun(t *testing.T) {
+	rcp := recipe.Recipe{
+		Name:   "test-recipe",
+		Source: recipe.SourceRecipe{Type: "mysql"},
+	}
+
+	t.Run("should record runs labeled by recipe, source and success", func(t *testing.T) {
+		monitor := metrics.NewPrometheusMonitor("meteor")
+		registry := prometheus.NewRegistry()
+		require.NoError(t, monitor.Register(registry))
+
+		monitor.RecordRun(agent.Run{Recipe: rcp, Success: true, DurationInMs: 150, RecordCount: 2, LineageCount: 1})
+		monitor.RecordRun(agent.Run{Recipe: rcp, Success: true, DurationInMs: 50, RecordCount: 3})
+		monitor.RecordRun(agent.Run{Recipe: rcp, Success: false, DurationInMs: 10})
+
+		expected := `
+# HELP meteor_runs_total Number of recipe runs.
+# TYPE meteor_runs_total counter
+meteor_runs_total{recipe="test-recipe",source="mysql",success="false"} 1
+meteor_runs_total{recipe="test-recipe",source="mysql",success="true"} 2
+# HELP meteor_run_records_total Number of records extracted by recipe runs.
+# TYPE meteor_run_records_total counter
+meteor_run_records_total{recipe="test-recipe",source="mysql",success="false"} 0
+meteor_run_records_total{recipe="test-recipe",source="mysql",success="true"} 5
+# HELP meteor_run_lineage_records_total Number of lineage records extracted by recipe runs.
+# TYPE meteor_run_lineage_records_total counter
+meteor_run_lineage_records_total{recipe="test-recipe",source="mysql",success="false"} 0
+meteor_run_lineage_records_total{recipe="test-recipe",source="mysql",success="true"} 1
+`
+		err := testutil.GatherAndCompare(registry, strings.NewReader(expected),
+			"meteor_runs_total", "meteor_run_records_total", "meteor_run_lineage_records_total")
+		assert.NoError(t, err)
+
+		// one histogram series per label set
+		count, err := testutil.GatherAndCount(registry, "meteor_run_duration_seconds")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() != "meteor_run_duration_seconds" {
+				continue
+			}
+			for _, metric := range family.GetMetric() {
+				if metric.GetLabel()[2].GetValue() == "true" {
+					assert.InDelta(t, 0.2, metric.GetHistogram().GetSampleSum(), 1e-9)
+				}
+			}
+		}
+	})
+
+	t.Run("should return error when registered twice", func(t *testing.T) {
+		monitor := metrics.NewPrometheusMonitor("meteor")
+		registry := prometheus.NewRegistry()
+		require.NoError(t, monitor.Register(registry))
+
+		assert.Error(t, monitor.Register(registry))
+	})
+
+	t.Run("should implement agent monitor", func(t *testing.T) {
+		var monitor agent.Monitor = metrics.NewPrometheusMonitor("meteor")
+		assert.NotNil(t, monitor)
+	})
+}
@@ -13,19 +13,21 @@ import (
 )
 
 var (
-	runDurationMetricName    = "runDuration"
-	runRecordCountMetricName = "runRecordCount"
-	runMetricName            = "run"
+	runDurationMetricName     = "runDuration"
+	runRecordCountMetricName  = "runRecordCount"
+	runLineageCountMetricName = "runLineageCount"
+	runMetricName             = "run"
 )
 
 // StatsdMonitor represents the statsd monitor.
+// Metrics are tagged by recipe name, source type and success.
 type StatsdMonitor struct {
-	client statsdClient
+	client StatsdClient
 	prefix string
 }
 
 // NewStatsdMonitor creates a new StatsdMonitor
-func NewStatsdMonitor(client statsdClient, prefix string) *StatsdMonitor {
+func NewStatsdMonitor(client StatsdClient, prefix string) *StatsdMonitor {
 	return &StatsdMonitor{
 		client: client,
 		prefix: prefix,
@@ -45,9 +47,13 @@ func (m *StatsdMonitor) RecordRun(run agent.Run) {
 		m.createMetricName(runRecordCountMetricName, run.Recipe, run.Success, run.RecordCount),
 		run.RecordCount,
 	)
+	m.client.IncrementByValue(
+		m.createMetricName(runLineageCountMetricName, run.Recipe, run.Success, run.RecordCount),
+		run.LineageCount,
+	)
 }
 
-// createMetricName creates a metric name for a given recipe and success
+// createMetricName creates a metric name tagged by recipe name, source type and success
 func (m *StatsdMonitor) createMetricName(metricName string, recipe recipe.Recipe, success bool, recordCount int) string {
 	var successText = "false"
 	if success {
@@ -55,16 +61,18 @@ func (m *StatsdMonitor) createMetricName(metricName string, recipe recipe.Recipe
 	}
 
 	return fmt.Sprintf(
-		"%s.%s,name=%s,success=%s,records=%d",
+		"%s.%s,name=%s,source=%s,success=%s,records=%d",
 		m.prefix,
 		metricName,
 		recipe.Name,
+		recipe.Source.Type,
 		successText,
 		recordCount,
 	)
 }
 
-type statsdClient interface {
+// StatsdClient sends metrics to statsd, it can be replaced to send metrics elsewhere or to assert them in tests
+type StatsdClient interface {
 	Timing(string, int64)
 	Increment(string)
 	IncrementByValue(string, int)
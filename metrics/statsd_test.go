@@ -31,28 +31,41 @@ func TestStatsdMonitorRecordRun(t *testing.T) {
 
 	t.Run("should create metrics with the correct name and value", func(t *testing.T) {
 		recipe := recipe.Recipe{
-			Name: "test-recipe",
+			Name:   "test-recipe",
+			Source: recipe.SourceRecipe{Type: "mysql"},
 		}
 		duration := 100
 		recordCount := 2
+		lineageCount := 1
 		timingMetric := fmt.Sprintf(
-			"%s.runDuration,name=%s,success=%s,records=%d",
+			"%s.runDuration,name=%s,source=%s,success=%s,records=%d",
 			statsdPrefix,
 			recipe.Name,
+			recipe.Source.Type,
 			"false",
 			recordCount,
 		)
 		incrementMetric := fmt.Sprintf(
-			"%s.run,name=%s,success=%s,records=%d",
+			"%s.run,name=%s,source=%s,success=%s,records=%d",
 			statsdPrefix,
 			recipe.Name,
+			recipe.Source.Type,
 			"false",
 			recordCount,
 		)
 		recordIncrementMetric := fmt.Sprintf(
-			"%s.runRecordCount,name=%s,success=%s,records=%d",
+			"%s.runRecordCount,name=%s,source=%s,success=%s,records=%d",
 			statsdPrefix,
 			recipe.Name,
+			recipe.Source.Type,
+			"false",
+			recordCount,
+		)
+		lineageIncrementMetric := fmt.Sprintf(
+			"%s.runLineageCount,name=%s,source=%s,success=%s,records=%d",
+			statsdPrefix,
+			recipe.Name,
+			recipe.Source.Type,
 			"false",
 			recordCount,
 		)
@@ -61,36 +74,50 @@ func TestStatsdMonitorRecordRun(t *testing.T) {
 		client.On("Timing", timingMetric, int64(duration))
 		client.On("Increment", incrementMetric)
 		client.On("IncrementByValue", recordIncrementMetric, recordCount)
+		client.On("IncrementByValue", lineageIncrementMetric, lineageCount)
 		defer client.AssertExpectations(t)
 
 		monitor := metrics.NewStatsdMonitor(client, statsdPrefix)
-		monitor.RecordRun(agent.Run{Recipe: recipe, DurationInMs: duration, RecordCount: 2, Success: false})
+		monitor.RecordRun(agent.Run{Recipe: recipe, DurationInMs: duration, RecordCount: 2, LineageCount: 1, Success: false})
 	})
 
 	t.Run("should set success field to true on success", func(t *testing.T) {
 		recipe := recipe.Recipe{
-			Name: "test-recipe",
+			Name:   "test-recipe",
+			Source: recipe.SourceRecipe{Type: "mysql"},
 		}
 		duration := 100
 		recordCount := 2
+		lineageCount := 1
 		timingMetric := fmt.Sprintf(
-			"%s.runDuration,name=%s,success=%s,records=%d",
+			"%s.runDuration,name=%s,source=%s,success=%s,records=%d",
 			statsdPrefix,
 			recipe.Name,
+			recipe.Source.Type,
 			"true",
 			recordCount,
 		)
 		incrementMetric := fmt.Sprintf(
-			"%s.run,name=%s,success=%s,records=%d",
+			"%s.run,name=%s,source=%s,success=%s,records=%d",
 			statsdPrefix,
 			recipe.Name,
+			recipe.Source.Type,
 			"true",
 			recordCount,
 		)
 		recordIncrementMetric := fmt.Sprintf(
-			"%s.runRecordCount,name=%s,success=%s,records=%d",
+			"%s.runRecordCount,name=%s,source=%s,success=%s,records=%d",
+			statsdPrefix,
+			recipe.Name,
+			recipe.Source.Type,
+			"true",
+			recordCount,
+		)
+		lineageIncrementMetric := fmt.Sprintf(
+			"%s.runLineageCount,name=%s,source=%s,success=%s,records=%d",
 			statsdPrefix,
 			recipe.Name,
+			recipe.Source.Type,
 			"true",
 			recordCount,
 		)
@@ -99,9 +126,10 @@ func TestStatsdMonitorRecordRun(t *testing.T) {
 		client.On("Timing", timingMetric, int64(duration))
 		client.On("Increment", incrementMetric)
 		client.On("IncrementByValue", recordIncrementMetric, recordCount)
+		client.On("IncrementByValue", lineageIncrementMetric, lineageCount)
 		defer client.AssertExpectations(t)
 
 		monitor := metrics.NewStatsdMonitor(client, statsdPrefix)
-		monitor.RecordRun(agent.Run{Recipe: recipe, DurationInMs: duration, RecordCount: 2, Success: true})
+		monitor.RecordRun(agent.Run{Recipe: recipe, DurationInMs: duration, RecordCount: 2, LineageCount: 1, Success: true})
 	})
 }
@@ -0,0 +1,50 @@
+package models
+
+import (
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+)
+
+// LineageEdge is a lineage-only record stating that data flows from Source to Target.
+// It is emitted independently of the assets it references, e.g. when lineage is
+// discovered from query logs instead of from the assets themselves.
+type LineageEdge struct {
+	Source     *commonv1beta1.Resource   `json:"source"`
+	Target     *commonv1beta1.Resource   `json:"target"`
+	Properties *facetsv1beta1.Properties `json:"properties,omitempty"`
+}
+
+// NewLineageRecord creates a new record holding a lineage edge
+func NewLineageRecord(source, target *commonv1beta1.Resource) Record {
+	return NewRecord(&LineageEdge{
+		Source: source,
+		Target: target,
+	})
+}
+
+// IsLineageRecord checks if the record holds a lineage edge instead of an asset
+func IsLineageRecord(record Record) bool {
+	_, ok := record.Data().(*LineageEdge)
+	return ok
+}
+
+// GetResource returns a resource identifying the edge itself
+func (e *LineageEdge) GetResource() *commonv1beta1.Resource {
+	return &commonv1beta1.Resource{
+		Urn:  LineageURN(e.Source.GetUrn(), e.Target.GetUrn()),
+		Type: "lineage",
+	}
+}
+
+// GetProperties returns the properties of the edge
+func (e *LineageEdge) GetProperties() *facetsv1beta1.Properties {
+	return e.Properties
+}
+
+// GetLineage returns the edge as lineage, source being upstream of target
+func (e *LineageEdge) GetLineage() *facetsv1beta1.Lineage {
+	return &facetsv1beta1.Lineage{
+		Upstreams:   []*commonv1beta1.Resource{e.Source},
+		Downstreams: []*commonv1beta1.Resource{e.Target},
+	}
+}
@@ -3,6 +3,7 @@ package models
 import (
 	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
 	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
 )
 
 // Metadata is a wrapper for the meta
@@ -18,3 +19,38 @@ type LineageMetadata interface {
 type OwnershipMetadata interface {
 	GetOwnership() *facetsv1beta1.Ownership
 }
+
+// AssetType returns the type of the asset held by the metadata, e.g. "table" or "dashboard".
+// It returns an empty string for unknown metadata.
+func AssetType(metadata Metadata) string {
+	switch metadata.(type) {
+	case *assetsv1beta1.Table:
+		return "table"
+	case *assetsv1beta1.Topic:
+		return "topic"
+	case *assetsv1beta1.Dashboard:
+		return "dashboard"
+	case *assetsv1beta1.Bucket:
+		return "bucket"
+	case *assetsv1beta1.Group:
+		return "group"
+	case *assetsv1beta1.Job:
+		return "job"
+	case *assetsv1beta1.User:
+		return "user"
+	case *LineageEdge:
+		return "lineage"
+	}
+
+	return ""
+}
+
+// IsAssetType checks if the given string is a type returned by AssetType
+func IsAssetType(assetType string) bool {
+	switch assetType {
+	case "table", "topic", "dashboard", "bucket", "group", "job", "user", "lineage":
+		return true
+	}
+
+	return false
+}
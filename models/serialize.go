@@ -0,0 +1,49 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// ToJSON returns the JSON serialization of the record data shared by sinks.
+// Fields are named after the json tags of the models, e.g. "resource" or "total_rows",
+// and empty fields are omitted.
+func ToJSON(record Record) ([]byte, error) {
+	return json.Marshal(record.Data())
+}
+
+// ToMap returns the record data as decoded from its ToJSON serialization.
+// Numbers are kept as json.Number, so that large integers are not rounded.
+func ToMap(record Record) (map[string]interface{}, error) {
+	jsonBytes, err := ToJSON(record)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.UseNumber()
+	var data map[string]interface{}
+	if err = decoder.Decode(&data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Lookup returns the value at the dot separated path of the fields returned by ToMap,
+// e.g. "resource.urn". Missing fields and fields under a value which is not an object are not found.
+func Lookup(fields map[string]interface{}, path string) (value interface{}, found bool) {
+	value = fields
+	for _, key := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
@@ -0,0 +1,101 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tableRecord = models.NewRecord(&assetsv1beta1.Table{
+	Resource: &commonv1beta1.Resource{
+		Urn:     "sales.orders",
+		Name:    "orders",
+		Service: "postgres",
+	},
+	Schema: &facetsv1beta1.Columns{
+		Columns: []*facetsv1beta1.Column{
+			{Name: "id", DataType: "bigint"},
+			{Name: "note", DataType: "text", IsNullable: true, Length: 255},
+		},
+	},
+	Profile: &assetsv1beta1.TableProfile{
+		TotalRows: 9007199254740993,
+	},
+})
+
+const tableJSON = `{
+	"resource": {"urn": "sales.orders", "name": "orders", "service": "postgres"},
+	"schema": {
+		"columns": [
+			{"name": "id", "data_type": "bigint"},
+			{"name": "note", "data_type": "text", "is_nullable": true, "length": 255}
+		]
+	},
+	"profile": {"total_rows": 9007199254740993}
+}`
+
+func TestToJSON(t *testing.T) {
+	t.Run("should serialize fields by their json names", func(t *testing.T) {
+		actual, err := models.ToJSON(tableRecord)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, tableJSON, string(actual))
+	})
+
+	t.Run("should round-trip to the same record data", func(t *testing.T) {
+		actual, err := models.ToJSON(tableRecord)
+		require.NoError(t, err)
+
+		var table assetsv1beta1.Table
+		require.NoError(t, json.Unmarshal(actual, &table))
+		again, err := models.ToJSON(models.NewRecord(&table))
+		require.NoError(t, err)
+		assert.Equal(t, string(actual), string(again))
+	})
+}
+
+func TestToMap(t *testing.T) {
+	t.Run("should return the fields of the json serialization", func(t *testing.T) {
+		actual, err := models.ToMap(tableRecord)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]interface{}{
+			"urn":     "sales.orders",
+			"name":    "orders",
+			"service": "postgres",
+		}, actual["resource"])
+		assert.Equal(t, json.Number("9007199254740993"), actual["profile"].(map[string]interface{})["total_rows"])
+
+		jsonBytes, err := json.Marshal(actual)
+		require.NoError(t, err)
+		assert.JSONEq(t, tableJSON, string(jsonBytes))
+	})
+}
+
+func TestLookup(t *testing.T) {
+	fields, err := models.ToMap(tableRecord)
+	require.NoError(t, err)
+
+	t.Run("should return the value at the dot separated path", func(t *testing.T) {
+		value, found := models.Lookup(fields, "resource.urn")
+		assert.True(t, found)
+		assert.Equal(t, "sales.orders", value)
+
+		value, found = models.Lookup(fields, "profile.total_rows")
+		assert.True(t, found)
+		assert.Equal(t, json.Number("9007199254740993"), value)
+	})
+
+	t.Run("should not find missing fields", func(t *testing.T) {
+		for _, path := range []string{"resource.description", "lineage.upstreams", "resource.urn.value"} {
+			_, found := models.Lookup(fields, path)
+			assert.False(t, found, path)
+		}
+	})
+}
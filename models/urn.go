@@ -13,3 +13,7 @@ func DashboardURN(service, host, id string) string {
 func JobURN(service, host, id string) string {
 	return fmt.Sprintf("%s::%s/%s", service, host, id)
 }
+
+func LineageURN(source, target string) string {
+	return fmt.Sprintf("%s->%s", source, target)
+}
@@ -0,0 +1,17 @@
+package plugins
+
+import "context"
+
+type recipeNameKey struct{}
+
+// WithRecipeName returns a copy of ctx holding the name of the recipe being run
+func WithRecipeName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, recipeNameKey{}, name)
+}
+
+// RecipeName returns the name of the recipe being run, set by the agent on the context
+// passed to plugins. It is empty if the plugin is not run by the agent.
+func RecipeName(ctx context.Context) string {
+	name, _ := ctx.Value(recipeNameKey{}).(string)
+	return name
+}
@@ -0,0 +1,216 @@
+package plugins
+
+import "strings"
+
+// NormalizedDataTypeAttribute is the column attribute holding the normalized data type.
+const NormalizedDataTypeAttribute = "normalized_data_type"
+
+// Canonical data types returned by NormalizeDataType
+const (
+	DataTypeString    = "string"
+	DataTypeInteger   = "integer"
+	DataTypeFloat     = "float"
+	DataTypeDecimal   = "decimal"
+	DataTypeBoolean   = "boolean"
+	DataTypeDate      = "date"
+	DataTypeTime      = "time"
+	DataTypeTimestamp = "timestamp"
+	DataTypeInterval  = "interval"
+	DataTypeBinary    = "binary"
+	DataTypeJSON      = "json"
+	DataTypeUUID      = "uuid"
+	DataTypeArray     = "array"
+	DataTypeMap       = "map"
+	DataTypeStruct    = "struct"
+	DataTypeUnknown   = "unknown"
+)
+
+// commonDataTypes holds the types named the same way by most databases
+var commonDataTypes = map[string]string{
+	"char":                        DataTypeString,
+	"character":                   DataTypeString,
+	"varchar":                     DataTypeString,
+	"character varying":           DataTypeString,
+	"nchar":                       DataTypeString,
+	"nvarchar":                    DataTypeString,
+	"text":                        DataTypeString,
+	"tinytext":                    DataTypeString,
+	"mediumtext":                  DataTypeString,
+	"longtext":                    DataTypeString,
+	"string":                      DataTypeString,
+	"enum":                        DataTypeString,
+	"set":                         DataTypeString,
+	"xml":                         DataTypeString,
+	"int":                         DataTypeInteger,
+	"integer":                     DataTypeInteger,
+	"tinyint":                     DataTypeInteger,
+	"smallint":                    DataTypeInteger,
+	"mediumint":                   DataTypeInteger,
+	"bigint":                      DataTypeInteger,
+	"float":                       DataTypeFloat,
+	"real":                        DataTypeFloat,
+	"double":                      DataTypeFloat,
+	"double precision":            DataTypeFloat,
+	"decimal":                     DataTypeDecimal,
+	"numeric":                     DataTypeDecimal,
+	"bool":                        DataTypeBoolean,
+	"boolean":                     DataTypeBoolean,
+	"date":                        DataTypeDate,
+	"time":                        DataTypeTime,
+	"time with time zone":         DataTypeTime,
+	"time without time zone":      DataTypeTime,
+	"datetime":                    DataTypeTimestamp,
+	"timestamp":                   DataTypeTimestamp,
+	"timestamp with time zone":    DataTypeTimestamp,
+	"timestamp without time zone": DataTypeTimestamp,
+	"interval":                    DataTypeInterval,
+	"binary":                      DataTypeBinary,
+	"varbinary":                   DataTypeBinary,
+	"blob":                        DataTypeBinary,
+	"tinyblob":                    DataTypeBinary,
+	"mediumblob":                  DataTypeBinary,
+	"longblob":                    DataTypeBinary,
+	"json":                        DataTypeJSON,
+	"uuid":                        DataTypeUUID,
+	"array":                       DataTypeArray,
+}
+
+// serviceDataTypes holds the vendor types, they take precedence over commonDataTypes
+var serviceDataTypes = map[string]map[string]string{
+	"mysql": {
+		"bit":  DataTypeBinary,
+		"year": DataTypeInteger,
+	},
+	"postgres": {
+		"bit":         DataTypeBinary,
+		"bit varying": DataTypeBinary,
+		"bytea":       DataTypeBinary,
+		"jsonb":       DataTypeJSON,
+		"money":       DataTypeDecimal,
+		"smallserial": DataTypeInteger,
+		"serial":      DataTypeInteger,
+		"bigserial":   DataTypeInteger,
+		"inet":        DataTypeString,
+		"cidr":        DataTypeString,
+		"macaddr":     DataTypeString,
+		"name":        DataTypeString,
+		"hstore":      DataTypeMap,
+	},
+	"mssql": {
+		"bit":              DataTypeBoolean,
+		"ntext":            DataTypeString,
+		"uniqueidentifier": DataTypeUUID,
+		"money":            DataTypeDecimal,
+		"smallmoney":       DataTypeDecimal,
+		"datetime2":        DataTypeTimestamp,
+		"smalldatetime":    DataTypeTimestamp,
+		"datetimeoffset":   DataTypeTimestamp,
+		// timestamp is a synonym of rowversion in sql server, not a date
+		"timestamp":  DataTypeBinary,
+		"rowversion": DataTypeBinary,
+		"image":      DataTypeBinary,
+	},
+	"oracle": {
+		"varchar2":      DataTypeString,
+		"nvarchar2":     DataTypeString,
+		"clob":          DataTypeString,
+		"nclob":         DataTypeString,
+		"long":          DataTypeString,
+		"rowid":         DataTypeString,
+		"urowid":        DataTypeString,
+		"xmltype":       DataTypeString,
+		"number":        DataTypeDecimal,
+		"binary_float":  DataTypeFloat,
+		"binary_double": DataTypeFloat,
+		// oracle dates hold the time of the day as well
+		"date":                           DataTypeTimestamp,
+		"timestamp with local time zone": DataTypeTimestamp,
+		"interval year to month":         DataTypeInterval,
+		"interval day to second":         DataTypeInterval,
+		"raw":                            DataTypeBinary,
+		"long raw":                       DataTypeBinary,
+		"bfile":                          DataTypeBinary,
+	},
+	"clickhouse": {
+		"fixedstring": DataTypeString,
+		"enum8":       DataTypeString,
+		"enum16":      DataTypeString,
+		"ipv4":        DataTypeString,
+		"ipv6":        DataTypeString,
+		"int8":        DataTypeInteger,
+		"int16":       DataTypeInteger,
+		"int32":       DataTypeInteger,
+		"int64":       DataTypeInteger,
+		"int128":      DataTypeInteger,
+		"int256":      DataTypeInteger,
+		"uint8":       DataTypeInteger,
+		"uint16":      DataTypeInteger,
+		"uint32":      DataTypeInteger,
+		"uint64":      DataTypeInteger,
+		"uint128":     DataTypeInteger,
+		"uint256":     DataTypeInteger,
+		"float32":     DataTypeFloat,
+		"float64":     DataTypeFloat,
+		"decimal32":   DataTypeDecimal,
+		"decimal64":   DataTypeDecimal,
+		"decimal128":  DataTypeDecimal,
+		"decimal256":  DataTypeDecimal,
+		"date32":      DataTypeDate,
+		"datetime64":  DataTypeTimestamp,
+		"object":      DataTypeJSON,
+		"map":         DataTypeMap,
+		"tuple":       DataTypeStruct,
+		"nested":      DataTypeArray,
+	},
+}
+
+// NormalizeDataType maps the data type of a column as reported by the service,
+// e.g. "VARCHAR2(255)" for oracle, to one of the canonical data types, e.g. "string".
+// Type parameters and modifiers are ignored, unknown types return DataTypeUnknown.
+func NormalizeDataType(service, raw string) string {
+	typ := strings.ToLower(strings.TrimSpace(raw))
+	// clickhouse wraps the type of nullable and dictionary encoded columns
+	for _, wrapper := range []string{"nullable(", "lowcardinality("} {
+		if strings.HasPrefix(typ, wrapper) && strings.HasSuffix(typ, ")") {
+			typ = strings.TrimSpace(typ[len(wrapper) : len(typ)-1])
+		}
+	}
+	if strings.HasSuffix(typ, "[]") {
+		return DataTypeArray
+	}
+	typ = stripTypeParams(typ)
+	typ = strings.TrimSuffix(typ, " zerofill")
+	typ = strings.TrimSuffix(typ, " unsigned")
+
+	if normalized, ok := serviceDataTypes[strings.ToLower(service)][typ]; ok {
+		return normalized
+	}
+	if normalized, ok := commonDataTypes[typ]; ok {
+		return normalized
+	}
+
+	return DataTypeUnknown
+}
+
+// stripTypeParams removes the parameters of a type, e.g. "timestamp(6) with time zone"
+// becomes "timestamp with time zone" and "array(int32)" becomes "array"
+func stripTypeParams(typ string) string {
+	var (
+		b     strings.Builder
+		depth int
+	)
+	for _, c := range typ {
+		switch {
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth > 0 {
+				depth--
+			}
+		case depth == 0:
+			b.WriteRune(c)
+		}
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
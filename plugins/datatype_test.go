@@ -0,0 +1,78 @@
+package plugins_test
+
+import (
+	"testing"
+
+	"github.com/odpf/meteor/plugins"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeDataType(t *testing.T) {
+	tests := []struct {
+		service  string
+		raw      string
+		expected string
+	}{
+		{"mysql", "varchar", plugins.DataTypeString},
+		{"mysql", "longtext", plugins.DataTypeString},
+		{"mysql", "int", plugins.DataTypeInteger},
+		{"mysql", "bigint unsigned", plugins.DataTypeInteger},
+		{"mysql", "double", plugins.DataTypeFloat},
+		{"mysql", "decimal(10,2)", plugins.DataTypeDecimal},
+		{"mysql", "tinyint(1)", plugins.DataTypeInteger},
+		{"mysql", "datetime", plugins.DataTypeTimestamp},
+		{"mysql", "year", plugins.DataTypeInteger},
+		{"mysql", "bit", plugins.DataTypeBinary},
+		{"mysql", "json", plugins.DataTypeJSON},
+
+		{"postgres", "character varying", plugins.DataTypeString},
+		{"postgres", "integer", plugins.DataTypeInteger},
+		{"postgres", "double precision", plugins.DataTypeFloat},
+		{"postgres", "numeric", plugins.DataTypeDecimal},
+		{"postgres", "boolean", plugins.DataTypeBoolean},
+		{"postgres", "timestamp with time zone", plugins.DataTypeTimestamp},
+		{"postgres", "time without time zone", plugins.DataTypeTime},
+		{"postgres", "bytea", plugins.DataTypeBinary},
+		{"postgres", "jsonb", plugins.DataTypeJSON},
+		{"postgres", "uuid", plugins.DataTypeUUID},
+		{"postgres", "ARRAY", plugins.DataTypeArray},
+		{"postgres", "integer[]", plugins.DataTypeArray},
+
+		{"mssql", "nvarchar", plugins.DataTypeString},
+		{"mssql", "bit", plugins.DataTypeBoolean},
+		{"mssql", "money", plugins.DataTypeDecimal},
+		{"mssql", "datetime2", plugins.DataTypeTimestamp},
+		{"mssql", "timestamp", plugins.DataTypeBinary},
+		{"mssql", "uniqueidentifier", plugins.DataTypeUUID},
+
+		{"oracle", "VARCHAR2", plugins.DataTypeString},
+		{"oracle", "CLOB", plugins.DataTypeString},
+		{"oracle", "NUMBER", plugins.DataTypeDecimal},
+		{"oracle", "BINARY_DOUBLE", plugins.DataTypeFloat},
+		{"oracle", "DATE", plugins.DataTypeTimestamp},
+		{"oracle", "TIMESTAMP(6) WITH TIME ZONE", plugins.DataTypeTimestamp},
+		{"oracle", "INTERVAL DAY(2) TO SECOND(6)", plugins.DataTypeInterval},
+		{"oracle", "RAW", plugins.DataTypeBinary},
+
+		{"clickhouse", "String", plugins.DataTypeString},
+		{"clickhouse", "LowCardinality(String)", plugins.DataTypeString},
+		{"clickhouse", "Nullable(UInt64)", plugins.DataTypeInteger},
+		{"clickhouse", "Float64", plugins.DataTypeFloat},
+		{"clickhouse", "Decimal(18, 4)", plugins.DataTypeDecimal},
+		{"clickhouse", "Bool", plugins.DataTypeBoolean},
+		{"clickhouse", "Date32", plugins.DataTypeDate},
+		{"clickhouse", "DateTime64(3, 'UTC')", plugins.DataTypeTimestamp},
+		{"clickhouse", "Enum8('a' = 1, 'b' = 2)", plugins.DataTypeString},
+		{"clickhouse", "Array(Int32)", plugins.DataTypeArray},
+		{"clickhouse", "Map(String, UInt64)", plugins.DataTypeMap},
+		{"clickhouse", "Tuple(String, Int32)", plugins.DataTypeStruct},
+
+		{"mysql", "geometry", plugins.DataTypeUnknown},
+		{"unknown-service", "varchar", plugins.DataTypeString},
+	}
+	for _, tt := range tests {
+		t.Run(tt.service+"/"+tt.raw, func(t *testing.T) {
+			assert.Equal(t, tt.expected, plugins.NormalizeDataType(tt.service, tt.raw))
+		})
+	}
+}
@@ -45,3 +45,22 @@ func NewRetryError(err error) error {
 	}
 	return RetryError{Err: err}
 }
+
+// DropRecordError is returned by a processor to drop the record from the stream.
+// A dropped record does not reach the following processors and sinks, and does not fail the run.
+type DropRecordError struct {
+	Reason string
+}
+
+func (e DropRecordError) Error() string {
+	return fmt.Sprintf("record dropped: %s", e.Reason)
+}
+
+func (e DropRecordError) Is(target error) bool {
+	_, ok := target.(DropRecordError)
+	return ok
+}
+
+func NewDropRecordError(reason string) error {
+	return DropRecordError{Reason: reason}
+}
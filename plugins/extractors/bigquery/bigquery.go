@@ -21,7 +21,6 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
-	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -38,6 +37,8 @@ type Config struct {
 	IsCollectTableUsage  bool     `mapstructure:"collect_table_usage" default:"false"`
 	UsagePeriodInDay     int64    `mapstructure:"usage_period_in_day" default:"7"`
 	UsageProjectIDs      []string `mapstructure:"usage_project_ids"`
+
+	plugins.SamplingConfig `mapstructure:",squash"`
 }
 
 var sampleConfig = `
@@ -57,7 +58,9 @@ service_account_json: |-
     "client_x509_cert_url": "xxxxxxx"
   }
 collect_table_usage: false
-usage_period_in_day: 7`
+usage_period_in_day: 7
+# leave the values of preview rows and column profiles out, only keeping field names and counts
+redact_samples: true`
 
 // Extractor manages the communication with the bigquery service
 type Extractor struct {
@@ -66,6 +69,7 @@ type Extractor struct {
 	config     Config
 	galClient  *auditlog.AuditLog
 	tableStats *auditlog.TableStats
+	sampler    *plugins.Sampler
 }
 
 func New(logger log.Logger) *Extractor {
@@ -76,11 +80,18 @@ func New(logger log.Logger) *Extractor {
 	}
 }
 
+// SetLogger sets the logger of the extractor
+func (e *Extractor) SetLogger(logger log.Logger) {
+	e.logger = logger
+	e.galClient = auditlog.New(logger)
+}
+
 // Info returns the detailed information about the extractor
 func (e *Extractor) Info() plugins.Info {
 	return plugins.Info{
 		Description:  "Big Query table metadata and metrics",
 		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
 		Summary:      summary,
 		Tags:         []string{"gcp", "table", "extractor"},
 	}
@@ -97,6 +108,7 @@ func (e *Extractor) Init(ctx context.Context, configMap map[string]interface{})
 	if err != nil {
 		return plugins.InvalidConfigError{}
 	}
+	e.sampler = plugins.NewSampler(e.config.SamplingConfig, e.logger)
 
 	e.client, err = e.createClient(ctx)
 	if err != nil {
@@ -194,7 +206,7 @@ func (e *Extractor) buildTable(ctx context.Context, t *bigquery.Table, md *bigqu
 	var preview *facetsv1beta1.Preview
 	if md.Type == bigquery.RegularTable {
 		var err error
-		preview, err = e.buildPreview(ctx, t)
+		preview, err = e.buildPreview(ctx, t, md)
 		if err != nil {
 			e.logger.Warn("error building preview", "err", err, "table", tableFQN)
 		}
@@ -267,19 +279,27 @@ func (e *Extractor) buildColumn(ctx context.Context, field *bigquery.FieldSchema
 		if err != nil {
 			e.logger.Error("error fetching column's profile", "error", err)
 		}
-		col.Profile = profile
+		col.Profile = e.sampler.ColumnProfile(profile)
 	}
 
 	return
 }
 
-func (e *Extractor) buildPreview(ctx context.Context, t *bigquery.Table) (preview *facetsv1beta1.Preview, err error) {
+// buildPreview reads the first rows of the table, only the field names being kept when redacting samples
+func (e *Extractor) buildPreview(ctx context.Context, t *bigquery.Table, md *bigquery.TableMetadata) (preview *facetsv1beta1.Preview, err error) {
 	preview = &facetsv1beta1.Preview{
 		Fields: []string{},
 	}
 	if e.config.MaxPreviewRows == 0 {
 		return
 	}
+	if e.sampler.Redacting() {
+		// the rows are not read at all
+		for _, field := range md.Schema {
+			preview.Fields = append(preview.Fields, field.Name)
+		}
+		return e.sampler.Preview(preview.Fields, nil)
+	}
 
 	rows := []interface{}{}
 	totalRows := 0
@@ -319,13 +339,7 @@ func (e *Extractor) buildPreview(ctx context.Context, t *bigquery.Table) (previe
 		totalRows++
 	}
 
-	preview.Rows, err = structpb.NewList(rows)
-	if err != nil {
-		err = errors.Wrap(err, "error creating preview list")
-		return
-	}
-
-	return
+	return e.sampler.Preview(preview.Fields, rows)
 }
 
 func (e *Extractor) getColumnProfile(ctx context.Context, col *bigquery.FieldSchema, tm *bigquery.TableMetadata) (cp *facetsv1beta1.ColumnProfile, err error) {
@@ -376,19 +390,23 @@ func (e *Extractor) getColumnProfile(ctx context.Context, col *bigquery.FieldSch
 }
 
 func (e *Extractor) buildColumnProfileQuery(col *bigquery.FieldSchema, tm *bigquery.TableMetadata) (query *bigquery.Query, err error) {
+	// the sampled values are only queried when they are kept
 	queryTemplate := `SELECT
+		{{- if not .Redacting }}
 		COALESCE(CAST(MIN({{ .ColumnName }}) AS STRING), "") AS min,
 		COALESCE(CAST(MAX({{ .ColumnName }}) AS STRING), "") AS max,
 		COALESCE(AVG(SAFE_CAST(SAFE_CAST({{ .ColumnName }} AS STRING) AS FLOAT64)), 0.0) AS avg,
 		COALESCE(SAFE_CAST(CAST(APPROX_QUANTILES({{ .ColumnName }}, 2)[OFFSET(1)] AS STRING) AS FLOAT64), 0.0) AS med,
+		COALESCE(CAST(APPROX_TOP_COUNT({{ .ColumnName }}, 1)[OFFSET(0)].value AS STRING), "") AS top,
+		{{- end }}
 		COALESCE(APPROX_COUNT_DISTINCT({{ .ColumnName }}),0) AS unique,
-		COALESCE(COUNT({{ .ColumnName }}), 0) AS count,
-		COALESCE(CAST(APPROX_TOP_COUNT({{ .ColumnName }}, 1)[OFFSET(0)].value AS STRING), "") AS top
+		COALESCE(COUNT({{ .ColumnName }}), 0) AS count
 	FROM
 		{{ .TableName }}`
 	data := map[string]interface{}{
 		"ColumnName": col.Name,
 		"TableName":  strings.ReplaceAll(tm.FullID, ":", "."),
+		"Redacting":  e.sampler.Redacting(),
 	}
 	temp := template.Must(template.New("query").Parse(queryTemplate))
 	builder := &strings.Builder{}
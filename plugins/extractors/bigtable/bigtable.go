@@ -53,11 +53,17 @@ func New(logger log.Logger) *Extractor {
 	}
 }
 
+// SetLogger sets the logger of the extractor
+func (e *Extractor) SetLogger(logger log.Logger) {
+	e.logger = logger
+}
+
 // Info returns the brief information about the extractor
 func (e *Extractor) Info() plugins.Info {
 	return plugins.Info{
 		Description:  "Compressed, high-performance, proprietary data storage system.",
 		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
 		Summary:      summary,
 		Tags:         []string{"gcp", "extractor"},
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	_ "embed" // used to print the embedded assets
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -14,6 +15,7 @@ import (
 	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
 	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
 	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/retry"
 	"github.com/odpf/meteor/registry"
 	"github.com/odpf/meteor/utils"
 	"github.com/odpf/salt/log"
@@ -33,10 +35,14 @@ var defaultKeyspaceList = []string{
 
 // Config holds the set of configuration for the cassandra extractor
 type Config struct {
-	UserID   string `mapstructure:"user_id" validate:"required"`
-	Password string `mapstructure:"password" validate:"required"`
-	Host     string `mapstructure:"host" validate:"required"`
-	Port     int    `mapstructure:"port" validate:"required"`
+	UserID              string `mapstructure:"user_id" validate:"required"`
+	Password            string `mapstructure:"password" validate:"required"`
+	Host                string `mapstructure:"host" validate:"required"`
+	Port                int    `mapstructure:"port" validate:"required"`
+	FlattenUDTs         bool   `mapstructure:"flatten_udts"`
+	IncludeTableOptions bool   `mapstructure:"include_table_options"`
+
+	retry.ConnectionConfig `mapstructure:",squash"`
 }
 
 var sampleConfig = `
@@ -44,8 +50,25 @@ user_id: admin
 password: "1234"
 host: localhost
 port: 9042
+# expand columns of user-defined types into their fields, e.g. address.city
+flatten_udts: false
+# attach compaction, caching, compression, ttl and gc grace options to tables
+include_table_options: false
+# retries when connecting to the cluster, authentication failures are not retried
+connection_retries: 3
+# backoff before the first connection retry, doubled on each retry
+connection_retry_interval: 1s
 `
 
+// maxUDTDepth limits the expansion of user-defined types nested in each other
+const maxUDTDepth = 5
+
+// udt holds the fields of a user-defined type
+type udt struct {
+	fieldNames []string
+	fieldTypes []string
+}
+
 // Extractor manages the extraction of data from cassandra
 type Extractor struct {
 	excludedKeyspaces map[string]bool
@@ -62,11 +85,17 @@ func New(logger log.Logger) *Extractor {
 	}
 }
 
+// SetLogger sets the logger of the extractor
+func (e *Extractor) SetLogger(logger log.Logger) {
+	e.logger = logger
+}
+
 // Info returns the brief information about the extractor
 func (e *Extractor) Info() plugins.Info {
 	return plugins.Info{
 		Description:  "Table metadata from cassandra server.",
 		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
 		Summary:      summary,
 		Tags:         []string{"oss", "extractor"},
 	}
@@ -84,6 +113,11 @@ func (e *Extractor) Init(ctx context.Context, configMap map[string]interface{})
 		return plugins.InvalidConfigError{}
 	}
 
+	connectPolicy, err := e.config.ConnectPolicy()
+	if err != nil {
+		return plugins.InvalidConfigError{}
+	}
+
 	// build excluded database list
 	e.buildExcludedKeyspaces()
 
@@ -96,7 +130,11 @@ func (e *Extractor) Init(ctx context.Context, configMap map[string]interface{})
 	cluster.Consistency = gocql.Quorum
 	cluster.ProtoVersion = 4
 	cluster.Port = e.config.Port
-	if e.session, err = cluster.CreateSession(); err != nil {
+	retrier := &retry.Retrier{Connect: connectPolicy}
+	if err = retrier.RetryConnect(ctx, func() (err error) {
+		e.session, err = cluster.CreateSession()
+		return
+	}); err != nil {
 		return errors.Wrap(err, "failed to create session")
 	}
 
@@ -133,8 +171,15 @@ func (e *Extractor) Extract(ctx context.Context, emit plugins.Emit) (err error)
 	return
 }
 
-// extractTables extract tables from a given keyspace
+// extractTables extract tables and materialized views from a given keyspace
 func (e *Extractor) extractTables(keyspace string) (err error) {
+	var types map[string]udt
+	if e.config.FlattenUDTs {
+		if types, err = e.extractTypes(keyspace); err != nil {
+			return errors.Wrap(err, "failed to extract user-defined types")
+		}
+	}
+
 	scanner := e.session.
 		Query(`SELECT table_name FROM system_schema.tables WHERE keyspace_name = ?`, keyspace).
 		Iter().
@@ -145,24 +190,66 @@ func (e *Extractor) extractTables(keyspace string) (err error) {
 		if err = scanner.Scan(&tableName); err != nil {
 			return errors.Wrapf(err, "failed to iterate over %s", tableName)
 		}
-		if err = e.processTable(keyspace, tableName); err != nil {
+		if err = e.processTable(keyspace, tableName, types); err != nil {
 			return errors.Wrap(err, "failed to process table")
 		}
 	}
+	if err = scanner.Err(); err != nil {
+		return errors.Wrap(err, "failed to fetch tables")
+	}
 
-	return
+	return e.extractViews(keyspace, types)
+}
+
+// extractViews extract materialized views from a given keyspace, emitted as tables
+func (e *Extractor) extractViews(keyspace string, types map[string]udt) (err error) {
+	scanner := e.session.
+		Query(`SELECT view_name, base_table_name FROM system_schema.views WHERE keyspace_name = ?`, keyspace).
+		Iter().
+		Scanner()
+
+	for scanner.Next() {
+		var viewName, baseTable string
+		if err = scanner.Scan(&viewName, &baseTable); err != nil {
+			return errors.Wrapf(err, "failed to iterate over %s", viewName)
+		}
+		if err = e.processView(keyspace, viewName, baseTable, types); err != nil {
+			return errors.Wrap(err, "failed to process materialized view")
+		}
+	}
+
+	return scanner.Err()
+}
+
+// extractTypes fetches the user-defined types of a keyspace by name
+func (e *Extractor) extractTypes(keyspace string) (types map[string]udt, err error) {
+	scanner := e.session.
+		Query(`SELECT type_name, field_names, field_types FROM system_schema.types WHERE keyspace_name = ?`, keyspace).
+		Iter().
+		Scanner()
+
+	types = make(map[string]udt)
+	for scanner.Next() {
+		var typeName string
+		var t udt
+		if err = scanner.Scan(&typeName, &t.fieldNames, &t.fieldTypes); err != nil {
+			return nil, errors.Wrapf(err, "failed to iterate over %s", typeName)
+		}
+		types[typeName] = t
+	}
+
+	return types, scanner.Err()
 }
 
 // processTable build and push table to out channel
-func (e *Extractor) processTable(keyspace string, tableName string) (err error) {
+func (e *Extractor) processTable(keyspace string, tableName string, types map[string]udt) (err error) {
 	var columns []*facetsv1beta1.Column
-	columns, err = e.extractColumns(keyspace, tableName)
+	columns, err = e.extractColumns(keyspace, tableName, types)
 	if err != nil {
 		return errors.Wrap(err, "failed to extract columns")
 	}
 
-	// push table to channel
-	e.emit(models.NewRecord(&assetsv1beta1.Table{
+	table := &assetsv1beta1.Table{
 		Resource: &commonv1beta1.Resource{
 			Urn:  fmt.Sprintf("%s.%s", keyspace, tableName),
 			Name: tableName,
@@ -170,15 +257,109 @@ func (e *Extractor) processTable(keyspace string, tableName string) (err error)
 		Schema: &facetsv1beta1.Columns{
 			Columns: columns,
 		},
+	}
+	if e.config.IncludeTableOptions {
+		options, err := e.extractTableOptions("tables", "table_name", keyspace, tableName)
+		if err != nil {
+			return errors.Wrap(err, "failed to extract table options")
+		}
+		table.Properties = &facetsv1beta1.Properties{
+			Attributes: utils.TryParseMapToProto(options),
+		}
+	}
+
+	// push table to channel
+	e.emit(models.NewRecord(table))
+
+	return
+}
+
+// processView build and push materialized view to out channel, its base table being its upstream
+func (e *Extractor) processView(keyspace, viewName, baseTable string, types map[string]udt) (err error) {
+	var columns []*facetsv1beta1.Column
+	columns, err = e.extractColumns(keyspace, viewName, types)
+	if err != nil {
+		return errors.Wrap(err, "failed to extract columns")
+	}
+
+	baseURN := fmt.Sprintf("%s.%s", keyspace, baseTable)
+	attributes := map[string]interface{}{
+		"object_type": "materialized_view",
+		"base_table":  baseURN,
+	}
+	if e.config.IncludeTableOptions {
+		options, err := e.extractTableOptions("views", "view_name", keyspace, viewName)
+		if err != nil {
+			return errors.Wrap(err, "failed to extract table options")
+		}
+		for k, v := range options {
+			attributes[k] = v
+		}
+	}
+
+	e.emit(models.NewRecord(&assetsv1beta1.Table{
+		Resource: &commonv1beta1.Resource{
+			Urn:  fmt.Sprintf("%s.%s", keyspace, viewName),
+			Name: viewName,
+		},
+		Schema: &facetsv1beta1.Columns{
+			Columns: columns,
+		},
+		Lineage: &facetsv1beta1.Lineage{
+			Upstreams: []*commonv1beta1.Resource{
+				{Urn: baseURN, Name: baseTable, Type: "table"},
+			},
+		},
+		Properties: &facetsv1beta1.Properties{
+			Attributes: utils.TryParseMapToProto(attributes),
+		},
 	}))
 
 	return
 }
 
+// extractTableOptions fetches the storage options of a table, or a materialized view
+// from system_schema.views, as attributes
+func (e *Extractor) extractTableOptions(schemaTable, nameColumn, keyspace, name string) (map[string]interface{}, error) {
+	query := fmt.Sprintf(`SELECT compaction, caching, compression, default_time_to_live, gc_grace_seconds, bloom_filter_fp_chance
+              FROM system_schema.%s
+              WHERE keyspace_name = ?
+              AND %s = ?`, schemaTable, nameColumn)
+
+	var compaction, caching, compression map[string]string
+	var defaultTTL, gcGraceSeconds int
+	var bloomFilterFPChance float64
+	if err := e.session.
+		Query(query, keyspace, name).
+		Scan(&compaction, &caching, &compression, &defaultTTL, &gcGraceSeconds, &bloomFilterFPChance); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"compaction_strategy":    compaction["class"],
+		"compaction":             optionsMap(compaction),
+		"caching":                optionsMap(caching),
+		"compression":            optionsMap(compression),
+		"default_time_to_live":   defaultTTL,
+		"gc_grace_seconds":       gcGraceSeconds,
+		"bloom_filter_fp_chance": bloomFilterFPChance,
+	}, nil
+}
+
+// optionsMap converts a map of options to a map the attributes can be parsed from
+func optionsMap(options map[string]string) map[string]interface{} {
+	m := make(map[string]interface{}, len(options))
+	for k, v := range options {
+		m[k] = v
+	}
+
+	return m
+}
+
 // extractColumns extract columns from a given table
-func (e *Extractor) extractColumns(keyspace string, tableName string) (columns []*facetsv1beta1.Column, err error) {
-	query := `SELECT column_name, type 
-              FROM system_schema.columns 
+func (e *Extractor) extractColumns(keyspace string, tableName string, types map[string]udt) (columns []*facetsv1beta1.Column, err error) {
+	query := `SELECT column_name, type, kind, position, clustering_order
+              FROM system_schema.columns
               WHERE keyspace_name = ?
               AND table_name = ?`
 	scanner := e.session.
@@ -187,8 +368,9 @@ func (e *Extractor) extractColumns(keyspace string, tableName string) (columns [
 		Scanner()
 
 	for scanner.Next() {
-		var fieldName, dataType string
-		if err = scanner.Scan(&fieldName, &dataType); err != nil {
+		var fieldName, dataType, kind, clusteringOrder string
+		var position int
+		if err = scanner.Scan(&fieldName, &dataType, &kind, &position, &clusteringOrder); err != nil {
 			e.logger.Error("failed to get fields", "error", err)
 			continue
 		}
@@ -196,12 +378,66 @@ func (e *Extractor) extractColumns(keyspace string, tableName string) (columns [
 		columns = append(columns, &facetsv1beta1.Column{
 			Name:     fieldName,
 			DataType: dataType,
+			Properties: &facetsv1beta1.Properties{
+				Attributes: utils.TryParseMapToProto(columnAttributes(kind, position, clusteringOrder)),
+			},
 		})
+		columns = append(columns, udtColumns(types, fieldName+".", dataType, 0)...)
 	}
 
 	return
 }
 
+// columnAttributes describes the role of a column in the primary key.
+// kind is one of partition_key, clustering, regular or static, key columns
+// also get their position in the key and clustering columns their order.
+func columnAttributes(kind string, position int, clusteringOrder string) map[string]interface{} {
+	attributes := map[string]interface{}{
+		"kind": kind,
+	}
+	switch kind {
+	case "partition_key":
+		attributes["position"] = position
+	case "clustering":
+		attributes["position"] = position
+		attributes["clustering_order"] = clusteringOrder
+	}
+
+	return attributes
+}
+
+// udtColumns expands a column of a user-defined type into columns of its fields,
+// prefixed with the name of the column, e.g. "address.city".
+// Collections of user-defined types are not expanded.
+func udtColumns(types map[string]udt, prefix, dataType string, depth int) (columns []*facetsv1beta1.Column) {
+	t, ok := types[unfrozen(dataType)]
+	if !ok || depth >= maxUDTDepth {
+		return
+	}
+
+	for i, name := range t.fieldNames {
+		if i >= len(t.fieldTypes) {
+			break
+		}
+		columns = append(columns, &facetsv1beta1.Column{
+			Name:     prefix + name,
+			DataType: t.fieldTypes[i],
+		})
+		columns = append(columns, udtColumns(types, prefix+name+".", t.fieldTypes[i], depth+1)...)
+	}
+
+	return
+}
+
+// unfrozen strips the frozen<> wrapper off a type, e.g. frozen<address> is address
+func unfrozen(dataType string) string {
+	if strings.HasPrefix(dataType, "frozen<") && strings.HasSuffix(dataType, ">") {
+		return strings.TrimSuffix(strings.TrimPrefix(dataType, "frozen<"), ">")
+	}
+
+	return dataType
+}
+
 // buildExcludedKeyspaces builds the list of excluded keyspaces
 func (e *Extractor) buildExcludedKeyspaces() {
 	excludedMap := make(map[string]bool)
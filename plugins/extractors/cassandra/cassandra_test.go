@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/odpf/meteor/test/utils"
+	meteorutils "github.com/odpf/meteor/utils"
 
 	"github.com/gocql/gocql"
 	"github.com/odpf/meteor/models"
@@ -138,6 +139,200 @@ func TestExtract(t *testing.T) {
 	})
 }
 
+// TestExtractUDTsAndViews tests the extraction of user-defined types and materialized views
+func TestExtractUDTsAndViews(t *testing.T) {
+	udtKeyspace := "cassandra_meteor_udt_test"
+	err := execute([]string{
+		fmt.Sprintf(`CREATE KEYSPACE %s WITH REPLICATION={'class':'SimpleStrategy','replication_factor':1}`, udtKeyspace),
+		fmt.Sprintf(`CREATE TYPE %s.geo (lat double, lng double)`, udtKeyspace),
+		fmt.Sprintf(`CREATE TYPE %s.address (city text, location frozen<geo>)`, udtKeyspace),
+		fmt.Sprintf(`CREATE TABLE %s.customers (id int PRIMARY KEY, email text, address frozen<address>, previous list<frozen<address>>)`, udtKeyspace),
+		fmt.Sprintf(`CREATE MATERIALIZED VIEW %s.customers_by_email AS SELECT id, email FROM %s.customers
+			WHERE email IS NOT NULL AND id IS NOT NULL PRIMARY KEY (email, id)`, udtKeyspace, udtKeyspace),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer execute([]string{fmt.Sprintf(`DROP KEYSPACE %s`, udtKeyspace)})
+
+	extract := func(t *testing.T, config map[string]interface{}) map[string]*assetsv1beta1.Table {
+		ctx := context.TODO()
+		extr := cassandra.New(utils.Logger)
+		config["user_id"] = user
+		config["password"] = pass
+		config["host"] = host
+		config["port"] = port
+		if err := extr.Init(ctx, config); err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		assert.NoError(t, extr.Extract(ctx, emitter.Push))
+
+		tables := map[string]*assetsv1beta1.Table{}
+		for _, d := range emitter.GetAllData() {
+			tables[d.GetResource().Urn] = d.(*assetsv1beta1.Table)
+		}
+		return tables
+	}
+	columnTypes := func(table *assetsv1beta1.Table) map[string]string {
+		types := map[string]string{}
+		for _, c := range table.Schema.Columns {
+			types[c.Name] = c.DataType
+		}
+		return types
+	}
+
+	t.Run("should keep type names of user-defined types by default", func(t *testing.T) {
+		tables := extract(t, map[string]interface{}{})
+
+		assert.Equal(t, map[string]string{
+			"id":       "int",
+			"email":    "text",
+			"address":  "frozen<address>",
+			"previous": "list<frozen<address>>",
+		}, columnTypes(tables[udtKeyspace+".customers"]))
+	})
+
+	t.Run("should flatten user-defined types into their fields", func(t *testing.T) {
+		tables := extract(t, map[string]interface{}{"flatten_udts": true})
+
+		assert.Equal(t, map[string]string{
+			"id":                   "int",
+			"email":                "text",
+			"address":              "frozen<address>",
+			"address.city":         "text",
+			"address.location":     "frozen<geo>",
+			"address.location.lat": "double",
+			"address.location.lng": "double",
+			"previous":             "list<frozen<address>>",
+		}, columnTypes(tables[udtKeyspace+".customers"]))
+	})
+
+	t.Run("should emit materialized views with their base table as upstream", func(t *testing.T) {
+		tables := extract(t, map[string]interface{}{})
+
+		view, ok := tables[udtKeyspace+".customers_by_email"]
+		if !assert.True(t, ok) {
+			return
+		}
+		assert.Equal(t, map[string]string{"id": "int", "email": "text"}, columnTypes(view))
+		assert.Equal(t, []*commonv1beta1.Resource{
+			{Urn: udtKeyspace + ".customers", Name: "customers", Type: "table"},
+		}, view.Lineage.Upstreams)
+		assert.Equal(t, map[string]interface{}{
+			"object_type": "materialized_view",
+			"base_table":  udtKeyspace + ".customers",
+		}, view.Properties.Attributes.AsMap())
+	})
+}
+
+// TestExtractTableOptions tests the attributes holding the storage options of tables
+func TestExtractTableOptions(t *testing.T) {
+	optionsKeyspace := "cassandra_meteor_options_test"
+	err := execute([]string{
+		fmt.Sprintf(`CREATE KEYSPACE %s WITH REPLICATION={'class':'SimpleStrategy','replication_factor':1}`, optionsKeyspace),
+		fmt.Sprintf(`CREATE TABLE %s.sessions (id int PRIMARY KEY, token text)
+			WITH compaction = {'class': 'LeveledCompactionStrategy'}
+			AND caching = {'keys': 'ALL', 'rows_per_partition': '10'}
+			AND default_time_to_live = 3600
+			AND gc_grace_seconds = 7200`, optionsKeyspace),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer execute([]string{fmt.Sprintf(`DROP KEYSPACE %s`, optionsKeyspace)})
+
+	extract := func(t *testing.T, config map[string]interface{}) *assetsv1beta1.Table {
+		ctx := context.TODO()
+		extr := cassandra.New(utils.Logger)
+		config["user_id"] = user
+		config["password"] = pass
+		config["host"] = host
+		config["port"] = port
+		if err := extr.Init(ctx, config); err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		assert.NoError(t, extr.Extract(ctx, emitter.Push))
+
+		for _, d := range emitter.GetAllData() {
+			if d.GetResource().Urn == optionsKeyspace+".sessions" {
+				return d.(*assetsv1beta1.Table)
+			}
+		}
+		t.Fatal("table not emitted")
+		return nil
+	}
+
+	t.Run("should not attach table options by default", func(t *testing.T) {
+		table := extract(t, map[string]interface{}{})
+
+		assert.Nil(t, table.Properties)
+	})
+
+	t.Run("should attach table options to attributes", func(t *testing.T) {
+		table := extract(t, map[string]interface{}{"include_table_options": true})
+
+		attributes := table.Properties.Attributes.AsMap()
+		assert.Equal(t, "org.apache.cassandra.db.compaction.LeveledCompactionStrategy", attributes["compaction_strategy"])
+		assert.Equal(t, map[string]interface{}{"keys": "ALL", "rows_per_partition": "10"}, attributes["caching"])
+		assert.Equal(t, float64(3600), attributes["default_time_to_live"])
+		assert.Equal(t, float64(7200), attributes["gc_grace_seconds"])
+		assert.Contains(t, attributes, "compression")
+		assert.Contains(t, attributes, "bloom_filter_fp_chance")
+	})
+}
+
+// TestExtractKeyColumns tests that columns are labeled with their role in the primary key
+func TestExtractKeyColumns(t *testing.T) {
+	keysKeyspace := "cassandra_meteor_keys_test"
+	err := execute([]string{
+		fmt.Sprintf(`CREATE KEYSPACE %s WITH REPLICATION={'class':'SimpleStrategy','replication_factor':1}`, keysKeyspace),
+		fmt.Sprintf(`CREATE TABLE %s.events (tenant text, day date, ts timestamp, id uuid, payload text, source text static,
+			PRIMARY KEY ((tenant, day), ts, id)) WITH CLUSTERING ORDER BY (ts DESC, id ASC)`, keysKeyspace),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer execute([]string{fmt.Sprintf(`DROP KEYSPACE %s`, keysKeyspace)})
+
+	ctx := context.TODO()
+	extr := cassandra.New(utils.Logger)
+	err = extr.Init(ctx, map[string]interface{}{
+		"user_id":  user,
+		"password": pass,
+		"host":     host,
+		"port":     port,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	emitter := mocks.NewEmitter()
+	assert.NoError(t, extr.Extract(ctx, emitter.Push))
+
+	attributes := map[string]map[string]interface{}{}
+	for _, d := range emitter.GetAllData() {
+		if d.GetResource().Urn != keysKeyspace+".events" {
+			continue
+		}
+		for _, c := range d.(*assetsv1beta1.Table).Schema.Columns {
+			attributes[c.Name] = c.Properties.Attributes.AsMap()
+		}
+	}
+
+	assert.Equal(t, map[string]map[string]interface{}{
+		"tenant":  {"kind": "partition_key", "position": float64(0)},
+		"day":     {"kind": "partition_key", "position": float64(1)},
+		"ts":      {"kind": "clustering", "position": float64(0), "clustering_order": "desc"},
+		"id":      {"kind": "clustering", "position": float64(1), "clustering_order": "asc"},
+		"payload": {"kind": "regular"},
+		"source":  {"kind": "static"},
+	}, attributes)
+}
+
 // setup is a helper function to setup the test keyspace
 func setup() (err error) {
 	// create database, user and grant access
@@ -193,14 +388,23 @@ func getExpected() []models.Record {
 					{
 						Name:     "applicantid",
 						DataType: "int",
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{"kind": "partition_key", "position": 0}),
+						},
 					},
 					{
 						Name:     "first_name",
 						DataType: "text",
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{"kind": "regular"}),
+						},
 					},
 					{
 						Name:     "last_name",
 						DataType: "text",
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{"kind": "regular"}),
+						},
 					},
 				},
 			},
@@ -215,14 +419,23 @@ func getExpected() []models.Record {
 					{
 						Name:     "department",
 						DataType: "text",
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{"kind": "regular"}),
+						},
 					},
 					{
 						Name:     "job",
 						DataType: "text",
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{"kind": "regular"}),
+						},
 					},
 					{
 						Name:     "jobid",
 						DataType: "int",
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{"kind": "partition_key", "position": 0}),
+						},
 					},
 				},
 			},
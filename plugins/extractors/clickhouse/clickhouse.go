@@ -14,6 +14,7 @@ import (
 	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
 	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
 	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/sqlutil"
 	"github.com/odpf/meteor/registry"
 	"github.com/odpf/meteor/utils"
 	"github.com/odpf/salt/log"
@@ -25,10 +26,14 @@ var summary string
 // Config holds the connection URL for the extractor
 type Config struct {
 	ConnectionURL string `mapstructure:"connection_url" validate:"required"`
+
+	sqlutil.URNConfig `mapstructure:",squash"`
 }
 
 var sampleConfig = `
-connection_url: "tcp://localhost:3306?username=admin&password=pass123&debug=true"`
+connection_url: "tcp://localhost:3306?username=admin&password=pass123&debug=true"
+# template of table urns with {service}, {host}, {database} and {table} placeholders
+urn_template: "{database}.{table}"`
 
 // Extractor manages the output stream
 // and logger interface for the extractor
@@ -36,6 +41,7 @@ type Extractor struct {
 	config Config
 	logger log.Logger
 	db     *sql.DB
+	urns   *sqlutil.URNBuilder
 }
 
 // New returns a pointer to an initialized Extractor Object
@@ -45,11 +51,17 @@ func New(logger log.Logger) *Extractor {
 	}
 }
 
+// SetLogger sets the logger of the extractor
+func (e *Extractor) SetLogger(logger log.Logger) {
+	e.logger = logger
+}
+
 // Info returns the brief information about the extractor
 func (e *Extractor) Info() plugins.Info {
 	return plugins.Info{
 		Description:  "Column-oriented DBMS for online analytical processing.",
 		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
 		Summary:      summary,
 		Tags:         []string{"oss", "extractor"},
 	}
@@ -65,6 +77,9 @@ func (e *Extractor) Init(ctx context.Context, configMap map[string]interface{})
 	if err = utils.BuildConfig(configMap, &e.config); err != nil {
 		return plugins.InvalidConfigError{}
 	}
+	if e.urns, err = sqlutil.NewURNBuilder(e.config.URNConfig, defaultTableURN); err != nil {
+		return plugins.InvalidConfigError{}
+	}
 
 	if e.db, err = sql.Open("clickhouse", e.config.ConnectionURL); err != nil {
 		return errors.Wrap(err, "failed to create a client")
@@ -106,7 +121,12 @@ func (e *Extractor) extractTables(emit plugins.Emit) (err error) {
 
 		emit(models.NewRecord(&assetsv1beta1.Table{
 			Resource: &commonv1beta1.Resource{
-				Urn:  fmt.Sprintf("%s.%s", dbName, tableName),
+				Urn: e.urns.URN(sqlutil.URNFields{
+					Service:  "clickhouse",
+					Host:     sqlutil.URLHost(e.config.ConnectionURL),
+					Database: dbName,
+					Table:    tableName,
+				}),
 				Name: tableName,
 			}, Schema: &facetsv1beta1.Columns{
 				Columns: columns,
@@ -135,11 +155,21 @@ func (e *Extractor) getColumnsInfo(dbName string, tableName string) (result []*f
 			Name:        colName,
 			DataType:    dataType,
 			Description: colDesc,
+			Properties: &facetsv1beta1.Properties{
+				Attributes: utils.TryParseMapToProto(map[string]interface{}{
+					plugins.NormalizedDataTypeAttribute: plugins.NormalizeDataType("clickhouse", dataType),
+				}),
+			},
 		})
 	}
 	return result, nil
 }
 
+// defaultTableURN prefixes the table name by its database
+func defaultTableURN(f sqlutil.URNFields) string {
+	return fmt.Sprintf("%s.%s", f.Database, f.Table)
+}
+
 // Register the extractor to catalog
 func init() {
 	if err := registry.Extractors.Register("clickhouse", func() plugins.Extractor {
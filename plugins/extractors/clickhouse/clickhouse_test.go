@@ -22,6 +22,7 @@ import (
 	"github.com/odpf/meteor/plugins"
 	"github.com/odpf/meteor/plugins/extractors/clickhouse"
 	"github.com/odpf/meteor/test/mocks"
+	meteorutils "github.com/odpf/meteor/utils"
 	"github.com/ory/dockertest/v3"
 	"github.com/ory/dockertest/v3/docker"
 	"github.com/stretchr/testify/assert"
@@ -130,16 +131,31 @@ func getExpected() []models.Record {
 						Name:        "applicant_id",
 						DataType:    "Int32",
 						Description: "",
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "integer",
+							}),
+						},
 					},
 					{
 						Name:        "last_name",
 						DataType:    "String",
 						Description: "",
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "string",
+							}),
+						},
 					},
 					{
 						Name:        "first_name",
 						DataType:    "String",
 						Description: "",
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "string",
+							}),
+						},
 					},
 				},
 			},
@@ -155,16 +171,31 @@ func getExpected() []models.Record {
 						Name:        "job_id",
 						DataType:    "Int32",
 						Description: "",
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "integer",
+							}),
+						},
 					},
 					{
 						Name:        "job",
 						DataType:    "String",
 						Description: "",
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "string",
+							}),
+						},
 					},
 					{
 						Name:        "department",
 						DataType:    "String",
 						Description: "",
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "string",
+							}),
+						},
 					},
 				},
 			},
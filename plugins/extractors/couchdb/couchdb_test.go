@@ -15,6 +15,8 @@ import (
 
 	_ "github.com/go-kivik/couchdb"
 	"github.com/go-kivik/kivik"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
 	"github.com/odpf/meteor/plugins"
 	"github.com/odpf/meteor/plugins/extractors/couchdb"
 	"github.com/odpf/meteor/test/mocks"
@@ -89,6 +91,15 @@ func TestInit(t *testing.T) {
 
 		assert.Equal(t, plugins.InvalidConfigError{}, err)
 	})
+
+	t.Run("should return error for a sample size above the bound", func(t *testing.T) {
+		err := couchdb.New(utils.Logger).Init(context.TODO(), map[string]interface{}{
+			"connection_url": fmt.Sprintf("http://%s:%s@%s/", user, pass, host),
+			"sample_size":    10001,
+		})
+
+		assert.Equal(t, plugins.InvalidConfigError{}, err)
+	})
 }
 
 func TestExtract(t *testing.T) {
@@ -107,7 +118,198 @@ func TestExtract(t *testing.T) {
 		err = extr.Extract(ctx, emitter.Push)
 
 		assert.NoError(t, err)
-		assert.Equal(t, docCount*len(dbs), len(emitter.Get()))
+		records := emitter.GetAllData()
+		assert.Equal(t, len(dbs), len(records))
+		for i, record := range records {
+			table := record.(*assetsv1beta1.Table)
+			assert.Equal(t, dbs[i], table.Resource.Name)
+			assert.Equal(t, int64(docCount), table.Profile.TotalRows)
+			assert.Equal(t, []*facetsv1beta1.Column{
+				{Name: "field1", DataType: "number"},
+				{Name: "field2", DataType: "string"},
+			}, table.Schema.Columns)
+		}
+	})
+
+	t.Run("should infer nullable columns from sampled documents", func(t *testing.T) {
+		ctx := context.TODO()
+		dbName := "sampled_docs"
+		if err := client.CreateDB(ctx, dbName); err != nil {
+			t.Fatal(err)
+		}
+		defer client.DestroyDB(ctx, dbName)
+		err := execute([]map[string]interface{}{
+			{"_id": "doc1", "name": "a", "tags": []string{"x"}, "score": 1},
+			{"_id": "doc2", "name": nil, "score": "high"},
+		}, client.DB(ctx, dbName))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		extr := couchdb.New(utils.Logger)
+		err = extr.Init(ctx, map[string]interface{}{
+			"connection_url": fmt.Sprintf("http://%s:%s@%s/", user, pass, host),
+			"sample_size":    10,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+		assert.NoError(t, err)
+
+		var table *assetsv1beta1.Table
+		for _, record := range emitter.GetAllData() {
+			if record.GetResource().Urn == dbName {
+				table = record.(*assetsv1beta1.Table)
+			}
+		}
+		if assert.NotNil(t, table) {
+			assert.Equal(t, []*facetsv1beta1.Column{
+				{Name: "name", DataType: "string", IsNullable: true},
+				{Name: "score", DataType: "mixed"},
+				{Name: "tags", DataType: "array", IsNullable: true},
+			}, table.Schema.Columns)
+		}
+	})
+}
+
+func TestExtractSampling(t *testing.T) {
+	ctx := context.TODO()
+	dbName := "paged_docs"
+	if err := client.CreateDB(ctx, dbName); err != nil {
+		t.Fatal(err)
+	}
+	defer client.DestroyDB(ctx, dbName)
+	err := execute([]map[string]interface{}{
+		{"_id": "doc1", "name": "a", "address": map[string]interface{}{"city": "Jakarta", "zip": "10110"}},
+		{"_id": "doc2", "name": "b", "score": 2},
+		{"_id": "_design/users", "language": "javascript"},
+		{"_id": "doc3", "name": "c", "score": 3, "tags": []string{"x"}},
+	}, client.DB(ctx, dbName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	extractTable := func(t *testing.T, config map[string]interface{}) *assetsv1beta1.Table {
+		config["connection_url"] = fmt.Sprintf("http://%s:%s@%s/", user, pass, host)
+		extr := couchdb.New(utils.Logger)
+		if err := extr.Init(ctx, config); err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err := extr.Extract(ctx, emitter.Push)
+		assert.NoError(t, err)
+
+		for _, record := range emitter.GetAllData() {
+			if record.GetResource().Urn == dbName {
+				return record.(*assetsv1beta1.Table)
+			}
+		}
+		t.Fatalf("table %s not extracted", dbName)
+		return nil
+	}
+
+	t.Run("should sample documents of every page", func(t *testing.T) {
+		table := extractTable(t, map[string]interface{}{
+			"sample_size":      10,
+			"sample_page_size": 1,
+		})
+
+		assert.Equal(t, []*facetsv1beta1.Column{
+			{Name: "address", DataType: "object", IsNullable: true},
+			{Name: "name", DataType: "string"},
+			{Name: "score", DataType: "number", IsNullable: true},
+			{Name: "tags", DataType: "array", IsNullable: true},
+		}, table.Schema.Columns)
+	})
+
+	t.Run("should stop sampling at sample_size", func(t *testing.T) {
+		table := extractTable(t, map[string]interface{}{
+			"sample_size":      2,
+			"sample_page_size": 1,
+		})
+
+		assert.Equal(t, []*facetsv1beta1.Column{
+			{Name: "address", DataType: "object", IsNullable: true},
+			{Name: "name", DataType: "string"},
+			{Name: "score", DataType: "number", IsNullable: true},
+		}, table.Schema.Columns)
+	})
+
+	t.Run("should only sample the projected fields", func(t *testing.T) {
+		table := extractTable(t, map[string]interface{}{
+			"sample_size":      10,
+			"sample_page_size": 2,
+			"fields":           []interface{}{"name", "address.city"},
+		})
+
+		assert.Equal(t, []*facetsv1beta1.Column{
+			{Name: "address", DataType: "object", IsNullable: true},
+			{Name: "name", DataType: "string"},
+		}, table.Schema.Columns)
+	})
+}
+
+func TestExtractDesignDocs(t *testing.T) {
+	t.Run("should extract design documents with their views", func(t *testing.T) {
+		ctx := context.TODO()
+		dbName := "design_docs"
+		if err := client.CreateDB(ctx, dbName); err != nil {
+			t.Fatal(err)
+		}
+		defer client.DestroyDB(ctx, dbName)
+		db := client.DB(ctx, dbName)
+		err := execute([]map[string]interface{}{
+			{"_id": "doc1", "field1": 1},
+			{
+				"_id":      "_design/users",
+				"language": "javascript",
+				"views": map[string]interface{}{
+					"by_name": map[string]interface{}{
+						"map": "function (doc) { emit(doc.name, 1); }",
+					},
+					"count": map[string]interface{}{
+						"map":    "function (doc) { emit(doc._id, 1); }",
+						"reduce": "_count",
+					},
+				},
+			},
+		}, db)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		extr := couchdb.New(utils.Logger)
+		err = extr.Init(ctx, map[string]interface{}{
+			"connection_url":      fmt.Sprintf("http://%s:%s@%s/", user, pass, host),
+			"include_design_docs": true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+		assert.NoError(t, err)
+
+		var designDoc *assetsv1beta1.Table
+		for _, record := range emitter.Get() {
+			if record.Data().GetResource().Urn == dbName+"._design/users" {
+				designDoc = record.Data().(*assetsv1beta1.Table)
+			}
+		}
+		if assert.NotNil(t, designDoc) {
+			assert.Equal(t, []*facetsv1beta1.Column{
+				{Name: "by_name", DataType: "view", Description: "function (doc) { emit(doc.name, 1); }"},
+				{Name: "count", DataType: "view", Description: "function (doc) { emit(doc._id, 1); }"},
+			}, designDoc.Schema.Columns)
+			attributes := designDoc.Properties.Attributes.AsMap()
+			assert.Equal(t, "design_document", attributes["type"])
+			assert.Equal(t, "javascript", attributes["language"])
+		}
 	})
 }
 
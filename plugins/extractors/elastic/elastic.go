@@ -2,10 +2,15 @@ package elastic
 
 import (
 	"context"
+	"crypto/tls"
 	_ "embed"
 	"encoding/json"
 	"fmt"
-	"reflect"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -23,16 +28,39 @@ import (
 //go:embed README.md
 var summary string
 
+// StoreSizeAttribute is the attribute holding the store size of the primary shards of an index
+const StoreSizeAttribute = "store_size_bytes"
+
+// Config holds the set of configuration for the elastic extractor
 type Config struct {
-	User     string `mapstructure:"user"`
-	Password string `mapstructure:"password"`
-	Host     string `mapstructure:"host" validate:"required"`
+	User     string    `mapstructure:"user"`
+	Password string    `mapstructure:"password"`
+	Host     string    `mapstructure:"host" validate:"required_without=Hosts"`
+	Hosts    []string  `mapstructure:"hosts" validate:"required_without=Host"`
+	TLS      TLSConfig `mapstructure:"tls"`
+	// IncludeSystemIndices extracts indices with names starting with a "."
+	IncludeSystemIndices bool `mapstructure:"include_system_indices"`
+	// Include and Exclude filter indices by name glob patterns, e.g. "logs-*"
+	Include []string `mapstructure:"include"`
+	Exclude []string `mapstructure:"exclude"`
+}
+
+// TLSConfig holds the TLS settings to connect to the elastic server
+type TLSConfig struct {
+	CACertPath         string `mapstructure:"ca_cert_path"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
 }
 
 var sampleConfig = `
  user: "elastic"
  password: "changeme"
- host: elastic_server`
+ hosts:
+   - https://elastic_server_1:9200
+   - https://elastic_server_2:9200
+ tls:
+   ca_cert_path: /etc/elastic/ca.pem
+ exclude:
+   - logs-*`
 
 // Extractor manages the extraction of data from elastic
 type Extractor struct {
@@ -48,11 +76,17 @@ func New(logger log.Logger) *Extractor {
 	}
 }
 
+// SetLogger sets the logger of the extractor
+func (e *Extractor) SetLogger(logger log.Logger) {
+	e.logger = logger
+}
+
 // Info returns the brief information about the extractor
 func (e *Extractor) Info() plugins.Info {
 	return plugins.Info{
 		Description:  "Search engine based on the Lucene library.",
 		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
 		Summary:      summary,
 		Tags:         []string{"oss", "extractor"},
 	}
@@ -60,7 +94,11 @@ func (e *Extractor) Info() plugins.Info {
 
 // Validate validates the configuration of the extractor
 func (e *Extractor) Validate(configMap map[string]interface{}) (err error) {
-	return utils.BuildConfig(configMap, &Config{})
+	var config Config
+	if err = utils.BuildConfig(configMap, &config); err != nil {
+		return err
+	}
+	return validatePatterns(config)
 }
 
 // Init initializes the extractor
@@ -70,14 +108,29 @@ func (e *Extractor) Init(ctx context.Context, configMap map[string]interface{})
 	if err != nil {
 		return plugins.InvalidConfigError{}
 	}
+	if err = validatePatterns(e.config); err != nil {
+		return err
+	}
 
 	//build elasticsearch client
+	addresses := e.config.Hosts
+	if e.config.Host != "" {
+		addresses = append([]string{e.config.Host}, addresses...)
+	}
 	cfg := elasticsearch.Config{
-		Addresses: []string{
-			e.config.Host,
-		},
-		Username: e.config.User,
-		Password: e.config.Password,
+		Addresses: addresses,
+		Username:  e.config.User,
+		Password:  e.config.Password,
+	}
+	if e.config.TLS.CACertPath != "" {
+		if cfg.CACert, err = ioutil.ReadFile(e.config.TLS.CACertPath); err != nil {
+			return errors.Wrap(err, "failed to read CA certificate")
+		}
+	}
+	if e.config.TLS.InsecureSkipVerify {
+		cfg.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
 	}
 	if e.client, err = elasticsearch.NewClient(cfg); err != nil {
 		return errors.Wrap(err, "failed to create client")
@@ -89,46 +142,25 @@ func (e *Extractor) Init(ctx context.Context, configMap map[string]interface{})
 // Extract extracts the data from the elastic server
 // and collected through the emitter
 func (e *Extractor) Extract(ctx context.Context, emit plugins.Emit) (err error) {
-	res, err := e.client.Cluster.Health(
-		e.client.Cluster.Health.WithLevel("indices"),
-	)
-	if err != nil {
-		return errors.Wrap(err, "failed to fetch cluster information")
-	}
-	var r map[string]interface{}
-	err = json.NewDecoder(res.Body).Decode(&r)
+	stats, err := e.getIndexStats(ctx)
 	if err != nil {
-		return
+		return errors.Wrap(err, "failed to fetch index stats")
 	}
-	x := reflect.ValueOf(r["indices"]).MapRange()
-	for x.Next() {
-		indexName := x.Key().String()
-		docProperties, err1 := e.listIndexInfo(x.Key().String())
-		if err1 != nil {
-			err = err1
-			return
-		}
-		var columns []*facetsv1beta1.Column
-		for i := range docProperties {
-			columns = append(columns, &facetsv1beta1.Column{
-				Name:     i,
-				DataType: docProperties[i].(map[string]interface{})["type"].(string),
-			})
-		}
-		countRes, err1 := e.client.Search(
-			e.client.Search.WithIndex(x.Key().String()),
-		)
-		if err1 != nil {
-			err = err1
-			return
+
+	indexNames := make([]string, 0, len(stats))
+	for indexName := range stats {
+		if e.shouldExtract(indexName) {
+			indexNames = append(indexNames, indexName)
 		}
-		var t map[string]interface{}
-		err = json.NewDecoder(countRes.Body).Decode(&t)
+	}
+	sort.Strings(indexNames)
+
+	for _, indexName := range indexNames {
+		docProperties, err := e.listIndexInfo(ctx, indexName)
 		if err != nil {
-			res.Body.Close()
-			return
+			return errors.Wrapf(err, "failed to fetch mappings of index \"%s\"", indexName)
 		}
-		docCount := len(t["hits"].(map[string]interface{})["hits"].([]interface{}))
+		indexStats := stats[indexName].Primaries
 
 		emit(models.NewRecord(&assetsv1beta1.Table{
 			Resource: &commonv1beta1.Resource{
@@ -136,36 +168,147 @@ func (e *Extractor) Extract(ctx context.Context, emit plugins.Emit) (err error)
 				Name: indexName,
 			},
 			Schema: &facetsv1beta1.Columns{
-				Columns: columns,
+				Columns: buildColumns(docProperties, ""),
 			},
 			Profile: &assetsv1beta1.TableProfile{
-				TotalRows: int64(docCount),
+				TotalRows: indexStats.Docs.Count,
+			},
+			Properties: &facetsv1beta1.Properties{
+				Attributes: utils.TryParseMapToProto(map[string]interface{}{
+					StoreSizeAttribute: indexStats.Store.SizeInBytes,
+				}),
 			},
 		}))
 	}
 	return
 }
 
+type indexStats struct {
+	Primaries struct {
+		Docs struct {
+			Count int64 `json:"count"`
+		} `json:"docs"`
+		Store struct {
+			SizeInBytes int64 `json:"size_in_bytes"`
+		} `json:"store"`
+	} `json:"primaries"`
+}
+
+// getIndexStats returns the document count and store size of all indices
+func (e *Extractor) getIndexStats(ctx context.Context) (map[string]indexStats, error) {
+	res, err := e.client.Indices.Stats(
+		e.client.Indices.Stats.WithContext(ctx),
+		e.client.Indices.Stats.WithMetric("docs", "store"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, errors.Errorf("received %s", res.Status())
+	}
+
+	var r struct {
+		Indices map[string]indexStats `json:"indices"`
+	}
+	if err = json.NewDecoder(res.Body).Decode(&r); err != nil {
+		return nil, err
+	}
+
+	return r.Indices, nil
+}
+
 // listIndexInfo returns the properties of the index
-func (e *Extractor) listIndexInfo(index string) (result map[string]interface{}, err error) {
-	var r map[string]interface{}
+func (e *Extractor) listIndexInfo(ctx context.Context, index string) (result map[string]interface{}, err error) {
 	res, err := e.client.Indices.GetMapping(
+		e.client.Indices.GetMapping.WithContext(ctx),
 		e.client.Indices.GetMapping.WithIndex(index),
 	)
 	if err != nil {
 		err = errors.Wrap(err, "failed to retrieve index")
 		return
 	}
-	err = json.NewDecoder(res.Body).Decode(&r)
-	if err != nil {
-		res.Body.Close()
+	defer res.Body.Close()
+	if res.IsError() {
+		return nil, errors.Errorf("received %s", res.Status())
+	}
+
+	var r map[string]struct {
+		Mappings struct {
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"mappings"`
+	}
+	if err = json.NewDecoder(res.Body).Decode(&r); err != nil {
 		return
 	}
-	result = r[index].(map[string]interface{})["mappings"].(map[string]interface{})["properties"].(map[string]interface{})
-	res.Body.Close()
+
+	return r[index].Mappings.Properties, nil
+}
+
+// shouldExtract checks the index name against the system index setting and the patterns
+func (e *Extractor) shouldExtract(index string) bool {
+	if strings.HasPrefix(index, ".") && !e.config.IncludeSystemIndices {
+		return false
+	}
+	if len(e.config.Include) > 0 && !matchAny(e.config.Include, index) {
+		return false
+	}
+
+	return !matchAny(e.config.Exclude, index)
+}
+
+// buildColumns flattens the mapping properties into columns,
+// fields of object and nested fields are prefixed with their parent name, e.g. "user.name"
+func buildColumns(properties map[string]interface{}, prefix string) (columns []*facetsv1beta1.Column) {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		field, ok := properties[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		dataType, _ := field["type"].(string)
+		subProperties, hasSubProperties := field["properties"].(map[string]interface{})
+		if dataType == "" && hasSubProperties {
+			dataType = "object"
+		}
+
+		columns = append(columns, &facetsv1beta1.Column{
+			Name:     prefix + name,
+			DataType: dataType,
+		})
+		if hasSubProperties {
+			columns = append(columns, buildColumns(subProperties, prefix+name+".")...)
+		}
+	}
+
 	return
 }
 
+func validatePatterns(config Config) error {
+	for _, pattern := range append(config.Include, config.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.Wrapf(err, "invalid index pattern \"%s\"", pattern)
+		}
+	}
+
+	return nil
+}
+
+func matchAny(patterns []string, index string) bool {
+	for _, pattern := range patterns {
+		if match, _ := path.Match(pattern, index); match {
+			return true
+		}
+	}
+
+	return false
+}
+
 // init registers the extractor to catalog
 func init() {
 	if err := registry.Extractors.Register("elastic", func() plugins.Extractor {
@@ -127,8 +127,37 @@ func TestExtract(t *testing.T) {
 		emitter := mocks.NewEmitter()
 		err = extr.Extract(ctx, emitter.Push)
 		assert.NoError(t, err)
-		assert.Equal(t, getExpectedVal(), emitter.Get())
+		assert.Equal(t, getExpectedVal(), withoutStoreSize(t, emitter.Get()))
 	})
+
+	t.Run("should skip system indices and indices matching exclude patterns", func(t *testing.T) {
+		extr := newExtractor()
+		err := extr.Init(ctx, map[string]interface{}{
+			"hosts":    []string{host},
+			"user":     user,
+			"password": pass,
+			"exclude":  []string{"index2"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+		assert.NoError(t, err)
+		assert.Equal(t, getExpectedVal()[:1], withoutStoreSize(t, emitter.Get()))
+	})
+}
+
+// withoutStoreSize checks and removes the store size attributes as they vary between runs
+func withoutStoreSize(t *testing.T, records []models.Record) []models.Record {
+	for _, r := range records {
+		table := r.Data().(*assetsv1beta1.Table)
+		assert.Greater(t, table.Properties.Attributes.AsMap()[elastic.StoreSizeAttribute], float64(0))
+		table.Properties = nil
+	}
+
+	return records
 }
 
 type MeteorMockElasticDocs struct {
@@ -154,6 +183,10 @@ func setup() (err error) {
 	if err != nil {
 		return
 	}
+	err = populateElasticSearch(".meteor-system", "1", docStr2)
+	if err != nil {
+		return
+	}
 	return
 }
 
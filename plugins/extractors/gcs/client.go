@@ -0,0 +1,54 @@
+package gcs
+
+import (
+	"context"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// Client lists the buckets and objects of a google cloud storage project
+type Client interface {
+	ListBuckets(ctx context.Context, projectID string) ([]*storage.BucketAttrs, error)
+	// ListObjects lists the objects of a bucket, all of them if query is nil
+	ListObjects(ctx context.Context, bucket string, query *storage.Query) ([]*storage.ObjectAttrs, error)
+	// NewRangeReader reads length bytes of the object starting from offset
+	NewRangeReader(ctx context.Context, bucket, object string, offset, length int64) (io.ReadCloser, error)
+}
+
+type client struct {
+	*storage.Client
+}
+
+func (c *client) ListBuckets(ctx context.Context, projectID string) (buckets []*storage.BucketAttrs, err error) {
+	it := c.Buckets(ctx, projectID)
+	bucket, err := it.Next()
+	for err == nil {
+		buckets = append(buckets, bucket)
+		bucket, err = it.Next()
+	}
+	if err != iterator.Done {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+func (c *client) ListObjects(ctx context.Context, bucket string, query *storage.Query) (objects []*storage.ObjectAttrs, err error) {
+	it := c.Bucket(bucket).Objects(ctx, query)
+	object, err := it.Next()
+	for err == nil {
+		objects = append(objects, object)
+		object, err = it.Next()
+	}
+	if err != iterator.Done {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func (c *client) NewRangeReader(ctx context.Context, bucket, object string, offset, length int64) (io.ReadCloser, error) {
+	return c.Bucket(bucket).Object(object).NewRangeReader(ctx, offset, length)
+}
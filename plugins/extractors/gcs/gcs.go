@@ -4,6 +4,7 @@ import (
 	"context"
 	_ "embed" // used to print the embedded assets
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -16,27 +17,44 @@ import (
 
 	"cloud.google.com/go/storage"
 	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/storageutil"
 	"github.com/odpf/meteor/utils"
 	"github.com/odpf/salt/log"
-	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 )
 
 //go:embed README.md
 var summary string
 
-const metadataSource = "googlecloudstorage"
+const (
+	metadataSource = "googlecloudstorage"
+	// sampleSize is the number of bytes read from a sample object to infer its schema
+	sampleSize = 64 * 1024
+)
 
 // Config holds the set of configuration for the extractor
 type Config struct {
-	ProjectID          string `mapstructure:"project_id" validate:"required"`
-	ServiceAccountJSON string `mapstructure:"service_account_json"`
-	ExtractBlob        bool   `mapstructure:"extract_blob"`
+	ProjectID              string `mapstructure:"project_id" validate:"required"`
+	ServiceAccountJSON     string `mapstructure:"service_account_json"`
+	ServiceAccountJSONPath string `mapstructure:"service_account_json_path"`
+	ExtractBlob            bool   `mapstructure:"extract_blob"`
+	// Buckets limits the extraction to the given buckets, all buckets of the project are listed if empty
+	Buckets []string `mapstructure:"buckets"`
+	// ExtractDatasets emits a table for each group of objects sharing a common prefix
+	ExtractDatasets bool `mapstructure:"extract_datasets"`
+	InferSchema     bool `mapstructure:"infer_schema"`
+
+	storageutil.DatasetConfig `mapstructure:",squash"`
 }
 
 var sampleConfig = `
 project_id: google-project-id
 extract_blob: true
+# emit objects grouped by common prefix as tables
+extract_datasets: true
+prefix: warehouse/
+depth: 1
+infer_schema: true
 service_account_json: |-
   {
     "type": "service_account",
@@ -53,16 +71,37 @@ service_account_json: |-
 // Extractor manages the extraction of data
 // from the google cloud storage
 type Extractor struct {
-	client *storage.Client
+	client Client
 	logger log.Logger
 	config Config
 }
 
+// Option provides extension abstraction to Extractor constructor
+type Option func(*Extractor)
+
+// WithClient assigns the storage client to the Extractor constructor,
+// the client is otherwise created from the config on Init
+func WithClient(client Client) Option {
+	return func(e *Extractor) {
+		e.client = client
+	}
+}
+
 // New returns a pointer to an initialized Extractor Object
-func New(logger log.Logger) *Extractor {
-	return &Extractor{
+func New(logger log.Logger, opts ...Option) *Extractor {
+	e := &Extractor{
 		logger: logger,
 	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// SetLogger sets the logger of the extractor
+func (e *Extractor) SetLogger(logger log.Logger) {
+	e.logger = logger
 }
 
 // Info returns the brief information about the extractor
@@ -70,6 +109,7 @@ func (e *Extractor) Info() plugins.Info {
 	return plugins.Info{
 		Description:  "Online file storage web service for storing and accessing data.",
 		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
 		Summary:      summary,
 		Tags:         []string{"gcp", "extractor"},
 	}
@@ -77,7 +117,12 @@ func (e *Extractor) Info() plugins.Info {
 
 // Validate validates the configuration of the extractor
 func (e *Extractor) Validate(configMap map[string]interface{}) (err error) {
-	return utils.BuildConfig(configMap, &Config{})
+	var config Config
+	if err = utils.BuildConfig(configMap, &config); err != nil {
+		return err
+	}
+	_, err = storageutil.NewGrouper(config.DatasetConfig)
+	return err
 }
 
 // Init initializes the extractor
@@ -87,6 +132,13 @@ func (e *Extractor) Init(ctx context.Context, configMap map[string]interface{})
 	if err != nil {
 		return plugins.InvalidConfigError{}
 	}
+	if _, err = storageutil.NewGrouper(e.config.DatasetConfig); err != nil {
+		return err
+	}
+
+	if e.client != nil {
+		return
+	}
 
 	// create client
 	e.client, err = e.createClient(ctx)
@@ -98,14 +150,13 @@ func (e *Extractor) Init(ctx context.Context, configMap map[string]interface{})
 }
 
 func (e *Extractor) Extract(ctx context.Context, emit plugins.Emit) (err error) {
-	it := e.client.Buckets(ctx, e.config.ProjectID)
-	for {
-		bucket, err := it.Next()
-		if err == iterator.Done {
-			break
-		}
-		if err != nil {
-			return errors.Wrapf(err, "failed to iterate over %s", bucket.Name)
+	buckets, err := e.client.ListBuckets(ctx, e.config.ProjectID)
+	if err != nil {
+		return errors.Wrap(err, "failed to iterate over buckets")
+	}
+	for _, bucket := range buckets {
+		if !e.shouldExtract(bucket.Name) {
+			continue
 		}
 
 		var blobs []*assetsv1beta1.Blob
@@ -117,21 +168,105 @@ func (e *Extractor) Extract(ctx context.Context, emit plugins.Emit) (err error)
 		}
 
 		emit(models.NewRecord(e.buildBucket(bucket, e.config.ProjectID, blobs)))
+
+		if e.config.ExtractDatasets {
+			datasets, err := e.extractDatasets(ctx, bucket.Name)
+			if err != nil {
+				return errors.Wrapf(err, "failed to extract datasets from %s", bucket.Name)
+			}
+			for _, d := range datasets {
+				emit(models.NewRecord(e.buildTable(ctx, bucket.Name, d)))
+			}
+		}
 	}
 
 	return
 }
 
-func (e *Extractor) extractBlobs(ctx context.Context, bucketName string, projectID string) (blobs []*assetsv1beta1.Blob, err error) {
-	it := e.client.Bucket(bucketName).Objects(ctx, nil)
+func (e *Extractor) shouldExtract(bucket string) bool {
+	if len(e.config.Buckets) == 0 {
+		return true
+	}
+	for _, b := range e.config.Buckets {
+		if b == bucket {
+			return true
+		}
+	}
 
-	object, err := it.Next()
-	for err == nil {
-		blobs = append(blobs, e.buildBlob(object, projectID))
-		object, err = it.Next()
+	return false
+}
+
+func (e *Extractor) extractDatasets(ctx context.Context, bucketName string) ([]storageutil.Dataset, error) {
+	grouper, err := storageutil.NewGrouper(e.config.DatasetConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := e.client.ListObjects(ctx, bucketName, &storage.Query{Prefix: e.config.Prefix})
+	if err != nil {
+		return nil, err
 	}
-	if err == iterator.Done {
-		err = nil
+	for _, object := range objects {
+		grouper.Add(storageutil.Object{
+			Key:          object.Name,
+			Size:         object.Size,
+			LastModified: object.Updated,
+		})
+	}
+
+	return grouper.Datasets(), nil
+}
+
+func (e *Extractor) buildTable(ctx context.Context, bucketName string, d storageutil.Dataset) *assetsv1beta1.Table {
+	name := strings.TrimSuffix(d.Prefix, "/")
+	if name == "" {
+		name = bucketName
+	}
+
+	table := &assetsv1beta1.Table{
+		Resource: &commonv1beta1.Resource{
+			Urn:     fmt.Sprintf("gs://%s/%s", bucketName, d.Prefix),
+			Name:    name,
+			Service: metadataSource,
+		},
+		Properties: &facetsv1beta1.Properties{
+			Attributes: utils.TryParseMapToProto(d.Attributes(bucketName)),
+		},
+		Timestamps: &commonv1beta1.Timestamp{
+			UpdateTime: timestamppb.New(d.LastModified),
+		},
+	}
+
+	if e.config.InferSchema && d.Format == storageutil.FormatCSV {
+		columns, err := e.inferCSVColumns(ctx, bucketName, d.SampleKey)
+		if err != nil {
+			e.logger.Warn("failed to infer schema", "bucket", bucketName, "object", d.SampleKey, "error", err)
+			return table
+		}
+		table.Schema = &facetsv1beta1.Columns{Columns: columns}
+	}
+
+	return table
+}
+
+// inferCSVColumns reads the header of the first bytes of the object
+func (e *Extractor) inferCSVColumns(ctx context.Context, bucketName, objectName string) ([]*facetsv1beta1.Column, error) {
+	reader, err := e.client.NewRangeReader(ctx, bucketName, objectName, 0, sampleSize)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return storageutil.InferCSVColumns(reader)
+}
+
+func (e *Extractor) extractBlobs(ctx context.Context, bucketName string, projectID string) (blobs []*assetsv1beta1.Blob, err error) {
+	objects, err := e.client.ListObjects(ctx, bucketName, nil)
+	if err != nil {
+		return
+	}
+	for _, object := range objects {
+		blobs = append(blobs, e.buildBlob(object, projectID))
 	}
 
 	return
@@ -179,13 +314,23 @@ func (e *Extractor) buildBlob(blob *storage.ObjectAttrs, projectID string) *asse
 	}
 }
 
-func (e *Extractor) createClient(ctx context.Context) (*storage.Client, error) {
-	if e.config.ServiceAccountJSON == "" {
+func (e *Extractor) createClient(ctx context.Context) (Client, error) {
+	var opts []option.ClientOption
+	switch {
+	case e.config.ServiceAccountJSONPath != "":
+		opts = append(opts, option.WithCredentialsFile(e.config.ServiceAccountJSONPath))
+	case e.config.ServiceAccountJSON != "":
+		opts = append(opts, option.WithCredentialsJSON([]byte(e.config.ServiceAccountJSON)))
+	default:
 		e.logger.Info("credentials are not specified, creating google cloud storage client using Default Credentials...")
-		return storage.NewClient(ctx)
 	}
 
-	return storage.NewClient(ctx, option.WithCredentialsJSON([]byte(e.config.ServiceAccountJSON)))
+	storageClient, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client{storageClient}, nil
 }
 
 // Register the extractor to catalog
@@ -5,13 +5,25 @@ package gcs_test
 
 import (
 	"context"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/odpf/meteor/test/utils"
-
+	"cloud.google.com/go/storage"
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
 	"github.com/odpf/meteor/plugins"
 	"github.com/odpf/meteor/plugins/extractors/gcs"
+	"github.com/odpf/meteor/test/mocks"
+	"github.com/odpf/meteor/test/utils"
+	meteorutils "github.com/odpf/meteor/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestInit(t *testing.T) {
@@ -22,4 +34,122 @@ func TestInit(t *testing.T) {
 
 		assert.Equal(t, plugins.InvalidConfigError{}, err)
 	})
+
+	t.Run("should return error for invalid exclude pattern", func(t *testing.T) {
+		err := gcs.New(utils.Logger).Init(context.TODO(), map[string]interface{}{
+			"project_id": "sample-project",
+			"exclude":    []string{"tmp["},
+		})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestExtract(t *testing.T) {
+	t.Run("should emit a table for each dataset", func(t *testing.T) {
+		created := time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC)
+		client := new(mockClient)
+		client.On("ListBuckets", mock.Anything, "sample-project").Return([]*storage.BucketAttrs{
+			{Name: "data-lake", Location: "ASIA", StorageClass: "STANDARD", Created: created},
+			{Name: "scratch"},
+		}, nil).Once()
+		client.On("ListObjects", mock.Anything, "data-lake", &storage.Query{Prefix: "warehouse/"}).Return([]*storage.ObjectAttrs{
+			{Name: "warehouse/orders/dt=2021-01-01/part-0.parquet", Size: 100, Updated: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{Name: "warehouse/orders/dt=2021-01-02/part-0.parquet", Size: 200, Updated: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)},
+			{Name: "warehouse/users/users.csv", Size: 50, Updated: time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC)},
+		}, nil).Once()
+		client.On("NewRangeReader", mock.Anything, "data-lake", "warehouse/users/users.csv", int64(0), mock.Anything).
+			Return(io.NopCloser(strings.NewReader("id,name,email\n1,a,a@example.com\n")), nil).Once()
+		defer client.AssertExpectations(t)
+
+		extr := gcs.New(utils.Logger, gcs.WithClient(client))
+		err := extr.Init(context.TODO(), map[string]interface{}{
+			"project_id":       "sample-project",
+			"buckets":          []string{"data-lake"},
+			"extract_datasets": true,
+			"prefix":           "warehouse/",
+			"infer_schema":     true,
+		})
+		require.NoError(t, err)
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(context.TODO(), emitter.Push)
+		require.NoError(t, err)
+
+		assert.Equal(t, []models.Record{
+			models.NewRecord(&assetsv1beta1.Bucket{
+				Resource: &commonv1beta1.Resource{
+					Urn:     "sample-project/data-lake",
+					Name:    "data-lake",
+					Service: "googlecloudstorage",
+				},
+				Location:    "ASIA",
+				StorageType: "STANDARD",
+				Timestamps: &commonv1beta1.Timestamp{
+					CreateTime: timestamppb.New(created),
+				},
+				Properties: &facetsv1beta1.Properties{},
+			}),
+			models.NewRecord(&assetsv1beta1.Table{
+				Resource: &commonv1beta1.Resource{
+					Urn:     "gs://data-lake/warehouse/orders/",
+					Name:    "warehouse/orders",
+					Service: "googlecloudstorage",
+				},
+				Properties: &facetsv1beta1.Properties{
+					Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+						"bucket":           "data-lake",
+						"prefix":           "warehouse/orders/",
+						"object_count":     2,
+						"total_size_bytes": 300,
+						"format":           "parquet",
+					}),
+				},
+				Timestamps: &commonv1beta1.Timestamp{
+					UpdateTime: timestamppb.New(time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)),
+				},
+			}),
+			models.NewRecord(&assetsv1beta1.Table{
+				Resource: &commonv1beta1.Resource{
+					Urn:     "gs://data-lake/warehouse/users/",
+					Name:    "warehouse/users",
+					Service: "googlecloudstorage",
+				},
+				Schema: &facetsv1beta1.Columns{
+					Columns: []*facetsv1beta1.Column{{Name: "id"}, {Name: "name"}, {Name: "email"}},
+				},
+				Properties: &facetsv1beta1.Properties{
+					Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+						"bucket":           "data-lake",
+						"prefix":           "warehouse/users/",
+						"object_count":     1,
+						"total_size_bytes": 50,
+						"format":           "csv",
+					}),
+				},
+				Timestamps: &commonv1beta1.Timestamp{
+					UpdateTime: timestamppb.New(time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC)),
+				},
+			}),
+		}, emitter.Get())
+	})
+}
+
+type mockClient struct {
+	mock.Mock
+}
+
+func (m *mockClient) ListBuckets(ctx context.Context, projectID string) ([]*storage.BucketAttrs, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]*storage.BucketAttrs), args.Error(1)
+}
+
+func (m *mockClient) ListObjects(ctx context.Context, bucket string, query *storage.Query) ([]*storage.ObjectAttrs, error) {
+	args := m.Called(ctx, bucket, query)
+	return args.Get(0).([]*storage.ObjectAttrs), args.Error(1)
+}
+
+func (m *mockClient) NewRangeReader(ctx context.Context, bucket, object string, offset, length int64) (io.ReadCloser, error) {
+	args := m.Called(ctx, bucket, object, offset, length)
+	return args.Get(0).(io.ReadCloser), args.Error(1)
 }
@@ -3,18 +3,21 @@ package github
 import (
 	"context"
 	_ "embed" // used to print the embedded assets
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/google/go-github/v37/github"
 	"github.com/odpf/meteor/models"
 	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
 	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
 	"github.com/odpf/meteor/plugins"
 	"github.com/odpf/meteor/registry"
 	"github.com/odpf/meteor/utils"
 	"github.com/odpf/salt/log"
 	"golang.org/x/oauth2"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 //go:embed README.md
@@ -22,26 +25,52 @@ var summary string
 
 // Config holds the set of configuration for the extractor
 type Config struct {
-	Org   string `mapstructure:"org" validate:"required"`
-	Token string `mapstructure:"token" validate:"required"`
+	Org                 string `mapstructure:"org" validate:"required"`
+	Token               string `mapstructure:"token" validate:"required"`
+	IncludeRepositories bool   `mapstructure:"include_repositories"`
+	IncludeTeams        bool   `mapstructure:"include_teams"`
+	// IncludeCollaborators lists the collaborators of every repository, costing an api call per repository
+	IncludeCollaborators bool   `mapstructure:"include_collaborators"`
+	MaxRateLimitWait     string `mapstructure:"max_rate_limit_wait" default:"5m"`
+	BaseURL              string `mapstructure:"base_url" validate:"omitempty,url"`
+	UploadURL            string `mapstructure:"upload_url" validate:"omitempty,url"`
 }
 
 var sampleConfig = `
 org: odpf
-token: github_token`
+token: github_token
+# extract repositories in addition to users
+include_repositories: true
+# extract teams with their members in addition to users
+include_teams: true
+# extract the permissions of users on repositories, and the outside collaborators
+include_collaborators: false
+# maximum time to wait for a rate limit to reset
+max_rate_limit_wait: 5m
+# api url of a github enterprise server, defaults to github.com
+base_url: https://github.example.com/api/v3/`
+
+const pageSize = 100
 
 // Extractor manages the extraction of data from the extractor
 type Extractor struct {
-	logger log.Logger
-	config Config
-	client *github.Client
+	logger           log.Logger
+	config           Config
+	client           *github.Client
+	maxRateLimitWait time.Duration
+}
+
+// SetLogger sets the logger of the extractor
+func (e *Extractor) SetLogger(logger log.Logger) {
+	e.logger = logger
 }
 
 // Info returns the brief information about the extractor
 func (e *Extractor) Info() plugins.Info {
 	return plugins.Info{
-		Description:  "User list from Github organisation.",
+		Description:  "User, repository and team list from Github organisation.",
 		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
 		Summary:      summary,
 		Tags:         []string{"platform", "extractor"},
 	}
@@ -58,12 +87,38 @@ func (e *Extractor) Init(ctx context.Context, configMap map[string]interface{})
 	if err != nil {
 		return plugins.InvalidConfigError{}
 	}
+	if e.maxRateLimitWait, err = time.ParseDuration(e.config.MaxRateLimitWait); err != nil {
+		return plugins.InvalidConfigError{}
+	}
 
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: e.config.Token},
 	)
 	tc := oauth2.NewClient(ctx, ts)
-	e.client = github.NewClient(tc)
+	if e.config.BaseURL == "" {
+		e.client = github.NewClient(tc)
+		return
+	}
+
+	uploadURL := e.config.UploadURL
+	if uploadURL == "" {
+		uploadURL = e.config.BaseURL
+	}
+	if e.client, err = github.NewEnterpriseClient(e.config.BaseURL, uploadURL, tc); err != nil {
+		return errors.Wrap(err, "failed to create github enterprise client")
+	}
+
+	return
+}
+
+// HealthCheck verifies the token by fetching the authenticated user and its access to the organisation
+func (e *Extractor) HealthCheck(ctx context.Context) (err error) {
+	if _, _, err = e.client.Users.Get(ctx, ""); err != nil {
+		return errors.Wrap(err, "failed to authenticate")
+	}
+	if _, _, err = e.client.Organizations.Get(ctx, e.config.Org); err != nil {
+		return errors.Wrapf(err, "failed to get organisation \"%s\"", e.config.Org)
+	}
 
 	return
 }
@@ -71,31 +126,407 @@ func (e *Extractor) Init(ctx context.Context, configMap map[string]interface{})
 // Extract extracts the data from the extractor
 // The data is returned as a list of assets.Asset
 func (e *Extractor) Extract(ctx context.Context, emit plugins.Emit) (err error) {
-	users, _, err := e.client.Organizations.ListMembers(ctx, e.config.Org, nil)
+	var repos []*github.Repository
+	if e.config.IncludeRepositories || e.config.IncludeCollaborators {
+		if repos, err = e.listRepositories(ctx); err != nil {
+			return errors.Wrap(err, "failed to fetch repositories")
+		}
+	}
+
+	var collaborators []*collaborator
+	if e.config.IncludeCollaborators {
+		if collaborators, err = e.listCollaborators(ctx, repos); err != nil {
+			return
+		}
+	}
+
+	if err = e.extractUsers(ctx, emit, collaborators); err != nil {
+		return
+	}
+
+	if e.config.IncludeRepositories {
+		for _, repo := range repos {
+			emit(models.NewRecord(e.buildRepository(repo)))
+		}
+	}
+
+	if e.config.IncludeTeams {
+		if err = e.extractTeams(ctx, emit); err != nil {
+			return
+		}
+	}
 
+	return nil
+}
+
+// extractUsers emits the members of the organisation as users, with their permissions on repositories
+// as memberships. The collaborators who are not members are then emitted as outside collaborators.
+func (e *Extractor) extractUsers(ctx context.Context, emit plugins.Emit, collaborators []*collaborator) (err error) {
+	users, err := e.listMembers(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to fetch organizations")
 	}
+	access := make(map[string]*collaborator, len(collaborators))
+	for _, c := range collaborators {
+		access[c.user.GetLogin()] = c
+	}
+	members := make(map[string]bool, len(users))
 	for _, user := range users {
-		usr, _, err := e.client.Users.Get(ctx, *user.Login)
+		members[user.GetLogin()] = true
+	}
+
+	for _, user := range users {
+		var usr *github.User
+		err := e.withRateLimitRetry(ctx, func() (resp *github.Response, err error) {
+			usr, resp, err = e.client.Users.Get(ctx, user.GetLogin())
+			return
+		})
 		if err != nil {
-			e.logger.Error("failed to fetch user", "error", err)
+			if isNotFound(err) {
+				e.logger.Warn("user not found, skipping", "user", user.GetLogin())
+				continue
+			}
+			if _, limited := rateLimitWait(err); limited {
+				return errors.Wrapf(err, "rate limited while fetching user \"%s\"", user.GetLogin())
+			}
+			e.logger.Error("failed to fetch user", "user", user.GetLogin(), "error", err)
 			continue
 		}
+		var memberships []*assetsv1beta1.Membership
+		if c, ok := access[user.GetLogin()]; ok {
+			memberships = c.memberships
+		}
 		emit(models.NewRecord(&assetsv1beta1.User{
 			Resource: &commonv1beta1.Resource{
 				Urn: usr.GetURL(),
 			},
-			Email:    usr.GetEmail(),
-			Username: usr.GetLogin(),
-			FullName: usr.GetName(),
-			Status:   "active",
+			Email:       usr.GetEmail(),
+			Username:    usr.GetLogin(),
+			FullName:    usr.GetName(),
+			Status:      "active",
+			Memberships: memberships,
+		}))
+	}
+
+	for _, c := range collaborators {
+		if members[c.user.GetLogin()] {
+			continue
+		}
+		// outside collaborators are not fetched one by one, sparing an api call each
+		emit(models.NewRecord(&assetsv1beta1.User{
+			Resource: &commonv1beta1.Resource{
+				Urn: c.user.GetURL(),
+			},
+			Username:    c.user.GetLogin(),
+			Status:      "active",
+			Memberships: c.memberships,
+			Properties: &facetsv1beta1.Properties{
+				Attributes: utils.TryParseMapToProto(map[string]interface{}{
+					"outside_collaborator": true,
+				}),
+			},
 		}))
 	}
 
 	return nil
 }
 
+// listMembers fetches every page of the organisation members
+func (e *Extractor) listMembers(ctx context.Context) (members []*github.User, err error) {
+	opts := &github.ListMembersOptions{
+		ListOptions: github.ListOptions{PerPage: pageSize},
+	}
+	for {
+		var (
+			users []*github.User
+			resp  *github.Response
+		)
+		err := e.withRateLimitRetry(ctx, func() (*github.Response, error) {
+			var callErr error
+			users, resp, callErr = e.client.Organizations.ListMembers(ctx, e.config.Org, opts)
+			return resp, callErr
+		})
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, users...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return members, nil
+}
+
+// listRepositories fetches every page of the organisation repositories
+func (e *Extractor) listRepositories(ctx context.Context) (repos []*github.Repository, err error) {
+	opts := &github.RepositoryListByOrgOptions{
+		ListOptions: github.ListOptions{PerPage: pageSize},
+	}
+	for {
+		var (
+			page []*github.Repository
+			resp *github.Response
+		)
+		err := e.withRateLimitRetry(ctx, func() (*github.Response, error) {
+			var callErr error
+			page, resp, callErr = e.client.Repositories.ListByOrg(ctx, e.config.Org, opts)
+			return resp, callErr
+		})
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, page...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return repos, nil
+}
+
+func (e *Extractor) buildRepository(repo *github.Repository) *assetsv1beta1.Table {
+	visibility := "public"
+	if repo.GetPrivate() {
+		visibility = "private"
+	}
+
+	return &assetsv1beta1.Table{
+		Resource: &commonv1beta1.Resource{
+			Urn:         repo.GetHTMLURL(),
+			Name:        repo.GetName(),
+			Service:     "github",
+			Type:        "repository",
+			Url:         repo.GetHTMLURL(),
+			Description: repo.GetDescription(),
+		},
+		Properties: &facetsv1beta1.Properties{
+			Tags: repo.Topics,
+			Attributes: utils.TryParseMapToProto(map[string]interface{}{
+				"full_name":      repo.GetFullName(),
+				"language":       repo.GetLanguage(),
+				"visibility":     visibility,
+				"default_branch": repo.GetDefaultBranch(),
+				"archived":       repo.GetArchived(),
+			}),
+		},
+		Timestamps: &commonv1beta1.Timestamp{
+			CreateTime: timestamppb.New(repo.GetCreatedAt().Time),
+			UpdateTime: timestamppb.New(repo.GetUpdatedAt().Time),
+		},
+	}
+}
+
+// collaborator is a user with access to repositories of the organisation,
+// each membership holding a repository urn and the permission of the user on it
+type collaborator struct {
+	user        *github.User
+	memberships []*assetsv1beta1.Membership
+}
+
+// permissions maps the permissions of collaborators, from the highest, to their names on github
+var permissions = []struct {
+	key  string
+	name string
+}{
+	{"admin", "admin"},
+	{"maintain", "maintain"},
+	{"push", "write"},
+	{"triage", "triage"},
+	{"pull", "read"},
+}
+
+// listCollaborators fetches the collaborators of every repository, in the order they are first seen.
+// Repositories whose collaborators can not be fetched are skipped.
+func (e *Extractor) listCollaborators(ctx context.Context, repos []*github.Repository) ([]*collaborator, error) {
+	var collaborators []*collaborator
+	byLogin := make(map[string]*collaborator)
+	for _, repo := range repos {
+		users, err := e.listRepositoryCollaborators(ctx, repo.GetName())
+		if err != nil {
+			if _, limited := rateLimitWait(err); limited {
+				return nil, errors.Wrapf(err, "rate limited while fetching collaborators of repository \"%s\"", repo.GetName())
+			}
+			e.logger.Error("failed to fetch collaborators, skipping repository", "repository", repo.GetName(), "error", err)
+			continue
+		}
+		for _, user := range users {
+			c, ok := byLogin[user.GetLogin()]
+			if !ok {
+				c = &collaborator{user: user}
+				byLogin[user.GetLogin()] = c
+				collaborators = append(collaborators, c)
+			}
+			c.memberships = append(c.memberships, &assetsv1beta1.Membership{
+				GroupUrn: repo.GetHTMLURL(),
+				Role:     []string{permission(user)},
+			})
+		}
+	}
+
+	return collaborators, nil
+}
+
+// listRepositoryCollaborators fetches every page of the repository collaborators
+func (e *Extractor) listRepositoryCollaborators(ctx context.Context, repo string) (collaborators []*github.User, err error) {
+	opts := &github.ListCollaboratorsOptions{
+		ListOptions: github.ListOptions{PerPage: pageSize},
+	}
+	for {
+		var (
+			users []*github.User
+			resp  *github.Response
+		)
+		err := e.withRateLimitRetry(ctx, func() (*github.Response, error) {
+			var callErr error
+			users, resp, callErr = e.client.Repositories.ListCollaborators(ctx, e.config.Org, repo, opts)
+			return resp, callErr
+		})
+		if err != nil {
+			return nil, err
+		}
+		collaborators = append(collaborators, users...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return collaborators, nil
+}
+
+// permission returns the highest permission of the collaborator
+func permission(user *github.User) string {
+	for _, p := range permissions {
+		if user.Permissions[p.key] {
+			return p.name
+		}
+	}
+
+	return ""
+}
+
+// teamRoles are the roles of team members, maintainers can manage the team
+var teamRoles = []string{"maintainer", "member"}
+
+// extractTeams emits the teams of the organisation as groups holding their members
+func (e *Extractor) extractTeams(ctx context.Context, emit plugins.Emit) (err error) {
+	teams, err := e.listTeams(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch teams")
+	}
+
+teams:
+	for _, team := range teams {
+		var members []*assetsv1beta1.Member
+		for _, role := range teamRoles {
+			users, err := e.listTeamMembers(ctx, team.GetSlug(), role)
+			if err != nil {
+				if _, limited := rateLimitWait(err); limited {
+					return errors.Wrapf(err, "rate limited while fetching members of team \"%s\"", team.GetSlug())
+				}
+				e.logger.Error("failed to fetch team members, skipping team", "team", team.GetSlug(), "error", err)
+				continue teams
+			}
+			for _, user := range users {
+				members = append(members, &assetsv1beta1.Member{
+					Urn:  user.GetURL(),
+					Role: role,
+				})
+			}
+		}
+		emit(models.NewRecord(e.buildTeam(team, members)))
+	}
+
+	return nil
+}
+
+// listTeams fetches every page of the organisation teams
+func (e *Extractor) listTeams(ctx context.Context) (teams []*github.Team, err error) {
+	opts := &github.ListOptions{PerPage: pageSize}
+	for {
+		var (
+			page []*github.Team
+			resp *github.Response
+		)
+		err := e.withRateLimitRetry(ctx, func() (*github.Response, error) {
+			var callErr error
+			page, resp, callErr = e.client.Teams.ListTeams(ctx, e.config.Org, opts)
+			return resp, callErr
+		})
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, page...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return teams, nil
+}
+
+// listTeamMembers fetches every page of the team members having the role
+func (e *Extractor) listTeamMembers(ctx context.Context, slug, role string) (members []*github.User, err error) {
+	opts := &github.TeamListTeamMembersOptions{
+		Role:        role,
+		ListOptions: github.ListOptions{PerPage: pageSize},
+	}
+	for {
+		var (
+			users []*github.User
+			resp  *github.Response
+		)
+		err := e.withRateLimitRetry(ctx, func() (*github.Response, error) {
+			var callErr error
+			users, resp, callErr = e.client.Teams.ListTeamMembersBySlug(ctx, e.config.Org, slug, opts)
+			return resp, callErr
+		})
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, users...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return members, nil
+}
+
+func (e *Extractor) buildTeam(team *github.Team, members []*assetsv1beta1.Member) *assetsv1beta1.Group {
+	attributes := map[string]interface{}{
+		"slug":    team.GetSlug(),
+		"privacy": team.GetPrivacy(),
+	}
+	if parent := team.GetParent(); parent != nil {
+		attributes["parent"] = parent.GetURL()
+	}
+
+	return &assetsv1beta1.Group{
+		Resource: &commonv1beta1.Resource{
+			Urn:         team.GetURL(),
+			Name:        team.GetName(),
+			Service:     "github",
+			Type:        "team",
+			Description: team.GetDescription(),
+		},
+		Members: members,
+		Properties: &facetsv1beta1.Properties{
+			Attributes: utils.TryParseMapToProto(attributes),
+		},
+	}
+}
+
 // init registers the extractor to catalog
 func init() {
 	if err := registry.Extractors.Register("github", func() plugins.Extractor {
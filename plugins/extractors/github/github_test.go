@@ -0,0 +1,387 @@
+//go:build plugins
+// +build plugins
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v37/github"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/test/mocks"
+	"github.com/odpf/meteor/test/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("should return error for malformed base_url", func(t *testing.T) {
+		err := (&Extractor{logger: utils.Logger}).Init(context.TODO(), map[string]interface{}{
+			"org":      "odpf",
+			"token":    "github_token",
+			"base_url": "not a url",
+		})
+
+		assert.Equal(t, plugins.InvalidConfigError{}, err)
+	})
+
+	t.Run("should use enterprise client when base_url is given", func(t *testing.T) {
+		extr := &Extractor{logger: utils.Logger}
+		err := extr.Init(context.TODO(), map[string]interface{}{
+			"org":      "odpf",
+			"token":    "github_token",
+			"base_url": "https://github.example.com",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://github.example.com/api/v3/", extr.client.BaseURL.String())
+		assert.Equal(t, "https://github.example.com/api/uploads/", extr.client.UploadURL.String())
+	})
+
+	t.Run("should use github.com when base_url is empty", func(t *testing.T) {
+		extr := &Extractor{logger: utils.Logger}
+		err := extr.Init(context.TODO(), map[string]interface{}{
+			"org":   "odpf",
+			"token": "github_token",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://api.github.com/", extr.client.BaseURL.String())
+	})
+}
+
+func TestExtract(t *testing.T) {
+	t.Run("should emit members from every page", func(t *testing.T) {
+		mux := http.NewServeMux()
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		mux.HandleFunc("/orgs/odpf/members", func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("page") {
+			case "", "1":
+				w.Header().Set("Link", fmt.Sprintf(`<%s/orgs/odpf/members?page=2>; rel="next"`, server.URL))
+				fmt.Fprint(w, `[{"login": "user-1"}, {"login": "user-2"}]`)
+			case "2":
+				fmt.Fprint(w, `[{"login": "user-3"}]`)
+			}
+		})
+		mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+			login := r.URL.Path[len("/users/"):]
+			fmt.Fprintf(w, `{"login": %q, "url": "https://api.github.com/users/%s"}`, login, login)
+		})
+
+		extr := newTestExtractor(t, server.URL)
+		emitter := mocks.NewEmitter()
+		err := extr.Extract(context.TODO(), emitter.Push)
+		assert.NoError(t, err)
+
+		var usernames []string
+		for _, d := range emitter.GetAllData() {
+			usernames = append(usernames, d.(*assetsv1beta1.User).Username)
+		}
+		assert.Equal(t, []string{"user-1", "user-2", "user-3"}, usernames)
+	})
+}
+
+func TestExtractTeams(t *testing.T) {
+	t.Run("should emit teams with members of every page when include_teams is true", func(t *testing.T) {
+		mux := http.NewServeMux()
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		mux.HandleFunc("/orgs/odpf/members", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[]`)
+		})
+		mux.HandleFunc("/orgs/odpf/teams", func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("page") {
+			case "", "1":
+				w.Header().Set("Link", fmt.Sprintf(`<%s/orgs/odpf/teams?page=2>; rel="next"`, server.URL))
+				fmt.Fprint(w, `[{"name": "Core", "slug": "core", "url": "https://api.github.com/teams/1", "privacy": "closed"}]`)
+			case "2":
+				fmt.Fprint(w, `[{"name": "Docs", "slug": "docs", "url": "https://api.github.com/teams/2", "privacy": "secret",
+					"parent": {"slug": "core", "url": "https://api.github.com/teams/1"}}]`)
+			}
+		})
+		mux.HandleFunc("/orgs/odpf/teams/core/members", func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("role") + "/" + r.URL.Query().Get("page") {
+			case "maintainer/":
+				fmt.Fprint(w, `[{"login": "user-1", "url": "https://api.github.com/users/user-1"}]`)
+			case "member/":
+				w.Header().Set("Link", fmt.Sprintf(`<%s/orgs/odpf/teams/core/members?role=member&page=2>; rel="next"`, server.URL))
+				fmt.Fprint(w, `[{"login": "user-2", "url": "https://api.github.com/users/user-2"}]`)
+			case "member/2":
+				fmt.Fprint(w, `[{"login": "user-3", "url": "https://api.github.com/users/user-3"}]`)
+			default:
+				fmt.Fprint(w, `[]`)
+			}
+		})
+		mux.HandleFunc("/orgs/odpf/teams/docs/members", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[]`)
+		})
+
+		extr := newTestExtractor(t, server.URL)
+		extr.config.IncludeTeams = true
+		emitter := mocks.NewEmitter()
+		err := extr.Extract(context.TODO(), emitter.Push)
+		assert.NoError(t, err)
+
+		data := emitter.GetAllData()
+		if assert.Len(t, data, 2) {
+			core := data[0].(*assetsv1beta1.Group)
+			assert.Equal(t, "https://api.github.com/teams/1", core.Resource.Urn)
+			assert.Equal(t, "team", core.Resource.Type)
+			assert.Equal(t, []*assetsv1beta1.Member{
+				{Urn: "https://api.github.com/users/user-1", Role: "maintainer"},
+				{Urn: "https://api.github.com/users/user-2", Role: "member"},
+				{Urn: "https://api.github.com/users/user-3", Role: "member"},
+			}, core.Members)
+			assert.Equal(t, map[string]interface{}{
+				"slug":    "core",
+				"privacy": "closed",
+			}, core.Properties.Attributes.AsMap())
+
+			docs := data[1].(*assetsv1beta1.Group)
+			assert.Empty(t, docs.Members)
+			assert.Equal(t, "https://api.github.com/teams/1", docs.Properties.Attributes.AsMap()["parent"])
+		}
+	})
+}
+
+func TestExtractCollaborators(t *testing.T) {
+	t.Run("should emit permissions of members and outside collaborators when include_collaborators is true", func(t *testing.T) {
+		mux := http.NewServeMux()
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		mux.HandleFunc("/orgs/odpf/members", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[{"login": "user-1"}]`)
+		})
+		mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+			login := r.URL.Path[len("/users/"):]
+			fmt.Fprintf(w, `{"login": %q, "url": "https://api.github.com/users/%s"}`, login, login)
+		})
+		mux.HandleFunc("/orgs/odpf/repos", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[{"name": "meteor", "html_url": "https://github.com/odpf/meteor"},
+				{"name": "optimus", "html_url": "https://github.com/odpf/optimus"},
+				{"name": "archive", "html_url": "https://github.com/odpf/archive"}]`)
+		})
+		mux.HandleFunc("/repos/odpf/meteor/collaborators", func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("page") {
+			case "", "1":
+				w.Header().Set("Link", fmt.Sprintf(`<%s/repos/odpf/meteor/collaborators?page=2>; rel="next"`, server.URL))
+				fmt.Fprint(w, `[{"login": "user-1", "url": "https://api.github.com/users/user-1",
+					"permissions": {"admin": true, "maintain": true, "push": true, "triage": true, "pull": true}}]`)
+			case "2":
+				fmt.Fprint(w, `[{"login": "user-2", "url": "https://api.github.com/users/user-2",
+					"permissions": {"admin": false, "maintain": false, "push": false, "triage": false, "pull": true}}]`)
+			}
+		})
+		mux.HandleFunc("/repos/odpf/optimus/collaborators", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[{"login": "user-1", "url": "https://api.github.com/users/user-1",
+				"permissions": {"admin": false, "maintain": false, "push": true, "triage": true, "pull": true}}]`)
+		})
+		mux.HandleFunc("/repos/odpf/archive/collaborators", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message": "Must have push access to view repository collaborators."}`)
+		})
+
+		extr := newTestExtractor(t, server.URL)
+		extr.config.IncludeCollaborators = true
+		emitter := mocks.NewEmitter()
+		err := extr.Extract(context.TODO(), emitter.Push)
+		assert.NoError(t, err)
+
+		data := emitter.GetAllData()
+		if assert.Len(t, data, 2) {
+			member := data[0].(*assetsv1beta1.User)
+			assert.Equal(t, "user-1", member.Username)
+			assert.Equal(t, []*assetsv1beta1.Membership{
+				{GroupUrn: "https://github.com/odpf/meteor", Role: []string{"admin"}},
+				{GroupUrn: "https://github.com/odpf/optimus", Role: []string{"write"}},
+			}, member.Memberships)
+			assert.Nil(t, member.Properties)
+
+			outside := data[1].(*assetsv1beta1.User)
+			assert.Equal(t, "user-2", outside.Username)
+			assert.Equal(t, "https://api.github.com/users/user-2", outside.Resource.Urn)
+			assert.Equal(t, []*assetsv1beta1.Membership{
+				{GroupUrn: "https://github.com/odpf/meteor", Role: []string{"read"}},
+			}, outside.Memberships)
+			assert.Equal(t, map[string]interface{}{"outside_collaborator": true}, outside.Properties.Attributes.AsMap())
+		}
+	})
+
+	t.Run("should not list collaborators by default", func(t *testing.T) {
+		mux := http.NewServeMux()
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		mux.HandleFunc("/orgs/odpf/members", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[]`)
+		})
+		mux.HandleFunc("/orgs/odpf/repos", func(w http.ResponseWriter, r *http.Request) {
+			t.Error("repositories should not be listed")
+		})
+
+		extr := newTestExtractor(t, server.URL)
+		emitter := mocks.NewEmitter()
+		assert.NoError(t, extr.Extract(context.TODO(), emitter.Push))
+		assert.Empty(t, emitter.GetAllData())
+	})
+}
+
+func TestHealthCheck(t *testing.T) {
+	t.Run("should return nil when the token can read the organisation", func(t *testing.T) {
+		mux := http.NewServeMux()
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"login": "user-1"}`)
+		})
+		mux.HandleFunc("/orgs/odpf", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"login": "odpf"}`)
+		})
+
+		assert.NoError(t, newTestExtractor(t, server.URL).HealthCheck(context.TODO()))
+	})
+
+	t.Run("should return error for a bad token", func(t *testing.T) {
+		mux := http.NewServeMux()
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message": "Bad credentials"}`)
+		})
+
+		err := newTestExtractor(t, server.URL).HealthCheck(context.TODO())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to authenticate")
+	})
+
+	t.Run("should return error when the organisation can not be read", func(t *testing.T) {
+		mux := http.NewServeMux()
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"login": "user-1"}`)
+		})
+		mux.HandleFunc("/orgs/odpf", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		})
+
+		err := newTestExtractor(t, server.URL).HealthCheck(context.TODO())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get organisation \"odpf\"")
+	})
+}
+
+func TestExtractRateLimit(t *testing.T) {
+	listMembers := func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"login": "user-1"}, {"login": "user-2"}]`)
+	}
+
+	t.Run("should wait and resume when rate limited", func(t *testing.T) {
+		mux := http.NewServeMux()
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		var calls int
+		mux.HandleFunc("/orgs/odpf/members", listMembers)
+		mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			if calls == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"message": "secondary rate limit", "documentation_url": "https://docs.github.com/rest#abuse-rate-limits"}`)
+				return
+			}
+			login := r.URL.Path[len("/users/"):]
+			fmt.Fprintf(w, `{"login": %q}`, login)
+		})
+
+		extr := newTestExtractor(t, server.URL)
+		extr.maxRateLimitWait = time.Second
+		emitter := mocks.NewEmitter()
+		err := extr.Extract(context.TODO(), emitter.Push)
+
+		assert.NoError(t, err)
+		assert.Len(t, emitter.Get(), 2)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("should return error when rate limit wait exceeds max_rate_limit_wait", func(t *testing.T) {
+		mux := http.NewServeMux()
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		mux.HandleFunc("/orgs/odpf/members", listMembers)
+		mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprint(time.Now().Add(time.Hour).Unix()))
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message": "API rate limit exceeded"}`)
+		})
+
+		extr := newTestExtractor(t, server.URL)
+		extr.maxRateLimitWait = time.Second
+		emitter := mocks.NewEmitter()
+		err := extr.Extract(context.TODO(), emitter.Push)
+
+		var rateLimitErr *github.RateLimitError
+		assert.ErrorAs(t, err, &rateLimitErr)
+		assert.Empty(t, emitter.Get())
+	})
+
+	t.Run("should skip users that are not found", func(t *testing.T) {
+		mux := http.NewServeMux()
+		server := httptest.NewServer(mux)
+		defer server.Close()
+
+		mux.HandleFunc("/orgs/odpf/members", listMembers)
+		mux.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+			login := r.URL.Path[len("/users/"):]
+			if login == "user-1" {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"message": "Not Found"}`)
+				return
+			}
+			fmt.Fprintf(w, `{"login": %q}`, login)
+		})
+
+		extr := newTestExtractor(t, server.URL)
+		emitter := mocks.NewEmitter()
+		err := extr.Extract(context.TODO(), emitter.Push)
+
+		assert.NoError(t, err)
+		if assert.Len(t, emitter.GetAllData(), 1) {
+			assert.Equal(t, "user-2", emitter.GetAllData()[0].(*assetsv1beta1.User).Username)
+		}
+	})
+}
+
+func newTestExtractor(t *testing.T, serverURL string) *Extractor {
+	baseURL, err := url.Parse(serverURL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := github.NewClient(nil)
+	client.BaseURL = baseURL
+
+	return &Extractor{
+		logger: utils.Logger,
+		config: Config{Org: "odpf"},
+		client: client,
+	}
+}
@@ -0,0 +1,63 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v37/github"
+)
+
+// defaultAbuseRetryAfter is used when a secondary rate limit response has no Retry-After header
+const defaultAbuseRetryAfter = 1 * time.Minute
+
+// withRateLimitRetry runs the call and, when it is rate limited, waits until the
+// limit resets before calling it again. It gives up when the wait would exceed max_rate_limit_wait.
+func (e *Extractor) withRateLimitRetry(ctx context.Context, call func() (*github.Response, error)) error {
+	for {
+		_, err := call()
+		wait, limited := rateLimitWait(err)
+		if !limited {
+			return err
+		}
+		if wait > e.maxRateLimitWait {
+			return err
+		}
+
+		e.logger.Warn("rate limited by github, waiting for reset", "wait", wait.String())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// rateLimitWait returns how long to wait before retrying if err is a rate limit error
+func rateLimitWait(err error) (wait time.Duration, limited bool) {
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		wait = time.Until(rateLimitErr.Rate.Reset.Time)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		if abuseErr.RetryAfter != nil {
+			return *abuseErr.RetryAfter, true
+		}
+		return defaultAbuseRetryAfter, true
+	}
+
+	return 0, false
+}
+
+// isNotFound checks if err is a github 404 response
+func isNotFound(err error) bool {
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
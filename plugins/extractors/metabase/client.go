@@ -2,20 +2,36 @@ package metabase
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
+const (
+	defaultRetryInterval = time.Second
+	maxRetryInterval     = time.Minute
+	defaultPageSize      = 50
+	// rootCollectionID identifies the root collection, holding the items outside of any collection
+	rootCollectionID = 0
+)
+
 type Client interface {
-	Authenticate(host, username, password, sessionID string) error
-	GetDatabase(int) (Database, error)
-	GetTable(int) (Table, error)
-	GetDashboard(int) (Dashboard, error)
-	GetDashboards() ([]Dashboard, error)
+	Authenticate(ctx context.Context, host, username, password, sessionID string) error
+	GetDatabase(context.Context, int) (Database, error)
+	GetTable(context.Context, int) (Table, error)
+	GetDashboard(context.Context, int) (Dashboard, error)
+	// GetCollections returns the collections other than the root collection
+	GetCollections(ctx context.Context) ([]Collection, error)
+	// GetCollectionDashboards returns the dashboards of every page of a collection listing
+	GetCollectionDashboards(ctx context.Context, collectionID int) ([]Dashboard, error)
+	GetCard(context.Context, int) (Card, error)
+	GetCards(ctx context.Context) ([]Card, error)
 }
 
 type client struct {
@@ -26,6 +42,10 @@ type client struct {
 	sessionID     string
 	databaseCache map[int]Database
 	tableCache    map[int]Table
+	// maxRetries is the number of retries for a request failing with 429 or 5xx
+	maxRetries    int
+	retryInterval time.Duration
+	pageSize      int
 }
 
 func newClient() *client {
@@ -33,10 +53,12 @@ func newClient() *client {
 		httpClient:    &http.Client{},
 		databaseCache: map[int]Database{},
 		tableCache:    map[int]Table{},
+		retryInterval: defaultRetryInterval,
+		pageSize:      defaultPageSize,
 	}
 }
 
-func (c *client) Authenticate(host, username, password, sessionID string) (err error) {
+func (c *client) Authenticate(ctx context.Context, host, username, password, sessionID string) (err error) {
 	c.host = host
 	c.username = username
 	c.password = password
@@ -45,7 +67,7 @@ func (c *client) Authenticate(host, username, password, sessionID string) (err e
 		return nil
 	}
 
-	c.sessionID, err = c.getSessionID()
+	c.sessionID, err = c.getSessionID(ctx)
 	if err != nil {
 		err = errors.Wrap(err, "error getting sessionID")
 		return
@@ -54,14 +76,14 @@ func (c *client) Authenticate(host, username, password, sessionID string) (err e
 	return
 }
 
-func (c *client) GetTable(id int) (table Table, err error) {
+func (c *client) GetTable(ctx context.Context, id int) (table Table, err error) {
 	table, ok := c.tableCache[id]
 	if ok {
 		return
 	}
 
 	url := fmt.Sprintf("%s/api/table/%d", c.host, id)
-	err = c.makeRequest("GET", url, nil, &table)
+	err = c.makeRequest(ctx, "GET", url, nil, &table)
 	if err != nil {
 		return
 	}
@@ -70,14 +92,14 @@ func (c *client) GetTable(id int) (table Table, err error) {
 	return
 }
 
-func (c *client) GetDatabase(id int) (database Database, err error) {
+func (c *client) GetDatabase(ctx context.Context, id int) (database Database, err error) {
 	database, ok := c.databaseCache[id]
 	if ok {
 		return
 	}
 
 	url := fmt.Sprintf("%s/api/database/%d", c.host, id)
-	err = c.makeRequest("GET", url, nil, &database)
+	err = c.makeRequest(ctx, "GET", url, nil, &database)
 	if err != nil {
 		return
 	}
@@ -86,20 +108,82 @@ func (c *client) GetDatabase(id int) (database Database, err error) {
 	return
 }
 
-func (c *client) GetDashboard(id int) (dashboard Dashboard, err error) {
+func (c *client) GetDashboard(ctx context.Context, id int) (dashboard Dashboard, err error) {
 	url := fmt.Sprintf("%s/api/dashboard/%d", c.host, id)
-	err = c.makeRequest("GET", url, nil, &dashboard)
+	err = c.makeRequest(ctx, "GET", url, nil, &dashboard)
+	return
+}
+
+func (c *client) GetCollections(ctx context.Context) (collections []Collection, err error) {
+	url := fmt.Sprintf("%s/api/collection", c.host)
+	var items []json.RawMessage
+	if err = c.makeRequest(ctx, "GET", url, nil, &items); err != nil {
+		return
+	}
+
+	for _, item := range items {
+		// the root collection is listed with "root" as id
+		var ref struct {
+			ID interface{} `json:"id"`
+		}
+		if err = json.Unmarshal(item, &ref); err != nil {
+			return nil, errors.Wrap(err, "failed to parse collection")
+		}
+		if _, ok := ref.ID.(string); ok {
+			continue
+		}
+
+		var collection Collection
+		if err = json.Unmarshal(item, &collection); err != nil {
+			return nil, errors.Wrap(err, "failed to parse collection")
+		}
+		collections = append(collections, collection)
+	}
+
+	return
+}
+
+func (c *client) GetCollectionDashboards(ctx context.Context, collectionID int) (dashboards []Dashboard, err error) {
+	id := strconv.Itoa(collectionID)
+	if collectionID == rootCollectionID {
+		id = "root"
+	}
+
+	for offset := 0; ; {
+		var page struct {
+			Data  []Dashboard `json:"data"`
+			Total int         `json:"total"`
+		}
+		url := fmt.Sprintf("%s/api/collection/%s/items?models=dashboard&limit=%d&offset=%d", c.host, id, c.pageSize, offset)
+		if err = c.makeRequest(ctx, "GET", url, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, d := range page.Data {
+			d.CollectionID = collectionID
+			dashboards = append(dashboards, d)
+		}
+
+		offset += len(page.Data)
+		if len(page.Data) == 0 || offset >= page.Total {
+			return dashboards, nil
+		}
+	}
+}
+
+func (c *client) GetCard(ctx context.Context, id int) (card Card, err error) {
+	url := fmt.Sprintf("%s/api/card/%d", c.host, id)
+	err = c.makeRequest(ctx, "GET", url, nil, &card)
 	return
 }
 
-func (c *client) GetDashboards() (dashboards []Dashboard, err error) {
-	url := fmt.Sprintf("%s/api/dashboard", c.host)
-	err = c.makeRequest("GET", url, nil, &dashboards)
+func (c *client) GetCards(ctx context.Context) (cards []Card, err error) {
+	url := fmt.Sprintf("%s/api/card", c.host)
+	err = c.makeRequest(ctx, "GET", url, nil, &cards)
 
 	return
 }
 
-func (c *client) getSessionID() (sessionID string, err error) {
+func (c *client) getSessionID(ctx context.Context) (sessionID string, err error) {
 	payload := map[string]interface{}{
 		"username": c.username,
 		"password": c.password,
@@ -108,7 +192,7 @@ func (c *client) getSessionID() (sessionID string, err error) {
 		ID string `json:"id"`
 	}
 	var data responseID
-	err = c.makeRequest("POST", c.host+"/api/session", payload, &data)
+	err = c.makeRequest(ctx, "POST", c.sessionURL(), payload, &data)
 	if err != nil {
 		return
 	}
@@ -116,23 +200,44 @@ func (c *client) getSessionID() (sessionID string, err error) {
 	return data.ID, nil
 }
 
-func (c *client) makeRequest(method, url string, payload interface{}, data interface{}) (err error) {
+func (c *client) makeRequest(ctx context.Context, method, url string, payload interface{}, data interface{}) (err error) {
 	jsonBytes, err := json.Marshal(payload)
 	if err != nil {
 		return errors.Wrap(err, "failed to encode the payload JSON")
 	}
 
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(jsonBytes))
-	if err != nil {
-		return errors.Wrap(err, "failed to create request")
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Metabase-Session", c.sessionID)
+	var res *http.Response
+	reauthenticated := false
+	for attempt := 0; ; attempt++ {
+		res, err = c.doRequest(ctx, method, url, jsonBytes)
+		if err != nil {
+			return errors.Wrap(err, "failed to generate response")
+		}
 
-	res, err := c.httpClient.Do(req)
-	if err != nil {
-		return errors.Wrap(err, "failed to generate response")
+		// session might expire during long extractions, renew it once and try again
+		if res.StatusCode == http.StatusUnauthorized && !reauthenticated && url != c.sessionURL() {
+			res.Body.Close()
+			if c.sessionID, err = c.getSessionID(ctx); err != nil {
+				return errors.Wrap(err, "error renewing sessionID")
+			}
+			reauthenticated = true
+			attempt--
+			continue
+		}
+
+		if !isRetryable(res.StatusCode) || attempt >= c.maxRetries {
+			break
+		}
+		wait := c.retryWait(res, attempt)
+		res.Body.Close()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
 	}
+	defer res.Body.Close()
+
 	if res.StatusCode >= 300 {
 		return fmt.Errorf("getting %d status code", res.StatusCode)
 	}
@@ -147,3 +252,48 @@ func (c *client) makeRequest(method, url string, payload interface{}, data inter
 
 	return
 }
+
+func (c *client) doRequest(ctx context.Context, method, url string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Metabase-Session", c.sessionID)
+
+	return c.httpClient.Do(req)
+}
+
+func (c *client) sessionURL() string {
+	return c.host + "/api/session"
+}
+
+// retryWait honors the Retry-After header if present,
+// otherwise it backs off exponentially from the retry interval
+func (c *client) retryWait(res *http.Response, attempt int) time.Duration {
+	if retryAfter := res.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			return capRetryWait(time.Duration(seconds) * time.Second)
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return capRetryWait(time.Until(date))
+		}
+	}
+
+	return capRetryWait(c.retryInterval << attempt)
+}
+
+func capRetryWait(wait time.Duration) time.Duration {
+	if wait < 0 {
+		return 0
+	}
+	if wait > maxRetryInterval {
+		return maxRetryInterval
+	}
+
+	return wait
+}
+
+func isRetryable(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
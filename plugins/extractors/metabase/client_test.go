@@ -0,0 +1,181 @@
+package metabase
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, maxRetries int) *client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c := newClient()
+	c.host = server.URL
+	c.username = "user"
+	c.password = "pass"
+	c.sessionID = "session-1"
+	c.maxRetries = maxRetries
+	c.retryInterval = time.Millisecond
+
+	return c
+}
+
+func TestClientRetry(t *testing.T) {
+	t.Run("should retry on 429 and 5xx until request succeeds", func(t *testing.T) {
+		statuses := []int{http.StatusTooManyRequests, http.StatusBadGateway}
+		calls := 0
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			defer func() { calls++ }()
+			if calls < len(statuses) {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(statuses[calls])
+				return
+			}
+			w.Write([]byte(`{"id": 2, "name": "ORDERS"}`))
+		}, 2)
+
+		table, err := c.GetTable(context.TODO(), 2)
+		require.NoError(t, err)
+		assert.Equal(t, "ORDERS", table.Name)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("should return error once retries are exhausted", func(t *testing.T) {
+		calls := 0
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}, 2)
+
+		_, err := c.GetDatabase(context.TODO(), 1)
+		assert.EqualError(t, err, "getting 503 status code")
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("should not retry on other client errors", func(t *testing.T) {
+		calls := 0
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.WriteHeader(http.StatusNotFound)
+		}, 2)
+
+		_, err := c.GetDashboard(context.TODO(), 1)
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("should stop waiting to retry once context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.TODO())
+		calls := 0
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			cancel()
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}, 2)
+
+		_, err := c.GetTable(ctx, 2)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("should renew session once on 401", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/api/session":
+				json.NewEncoder(w).Encode(map[string]string{"id": "session-2"})
+			case r.Header.Get("X-Metabase-Session") != "session-2":
+				w.WriteHeader(http.StatusUnauthorized)
+			default:
+				w.Write([]byte(`[{"id": 1}]`))
+			}
+		}, 0)
+
+		cards, err := c.GetCards(context.TODO())
+		require.NoError(t, err)
+		assert.Len(t, cards, 1)
+		assert.Equal(t, "session-2", c.sessionID)
+	})
+}
+
+func TestClientGetCollections(t *testing.T) {
+	t.Run("should skip the root collection", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/collection", r.URL.Path)
+			w.Write([]byte(`[
+				{"id": "root", "name": "Our analytics"},
+				{"id": 1, "name": "Sales", "location": "/"},
+				{"id": 2, "name": "Reports", "location": "/1/"}
+			]`))
+		}, 0)
+
+		collections, err := c.GetCollections(context.TODO())
+		require.NoError(t, err)
+		assert.Equal(t, []Collection{
+			{ID: 1, Name: "Sales", Location: "/"},
+			{ID: 2, Name: "Reports", Location: "/1/"},
+		}, collections)
+	})
+}
+
+func TestClientGetCollectionDashboards(t *testing.T) {
+	t.Run("should list dashboards of every page", func(t *testing.T) {
+		var offsets []string
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/collection/root/items", r.URL.Path)
+			assert.Equal(t, "dashboard", r.URL.Query().Get("models"))
+			assert.Equal(t, "2", r.URL.Query().Get("limit"))
+			offset := r.URL.Query().Get("offset")
+			offsets = append(offsets, offset)
+			switch offset {
+			case "0":
+				w.Write([]byte(`{"total": 3, "data": [{"id": 1, "name": "Main"}, {"id": 2, "name": "Sales"}]}`))
+			case "2":
+				w.Write([]byte(`{"total": 3, "data": [{"id": 3, "name": "Marketing"}]}`))
+			}
+		}, 0)
+		c.pageSize = 2
+
+		dashboards, err := c.GetCollectionDashboards(context.TODO(), rootCollectionID)
+		require.NoError(t, err)
+		assert.Equal(t, []Dashboard{
+			{ID: 1, Name: "Main"},
+			{ID: 2, Name: "Sales"},
+			{ID: 3, Name: "Marketing"},
+		}, dashboards)
+		assert.Equal(t, []string{"0", "2"}, offsets)
+	})
+
+	t.Run("should set the collection of the dashboards", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/collection/4/items", r.URL.Path)
+			w.Write([]byte(`{"total": 1, "data": [{"id": 1, "name": "Main"}]}`))
+		}, 0)
+
+		dashboards, err := c.GetCollectionDashboards(context.TODO(), 4)
+		require.NoError(t, err)
+		assert.Equal(t, []Dashboard{{ID: 1, Name: "Main", CollectionID: 4}}, dashboards)
+	})
+}
+
+func TestClientRetryWait(t *testing.T) {
+	c := newClient()
+
+	t.Run("should honor Retry-After in seconds", func(t *testing.T) {
+		res := &http.Response{Header: http.Header{"Retry-After": []string{"7"}}}
+		assert.Equal(t, 7*time.Second, c.retryWait(res, 0))
+	})
+
+	t.Run("should back off exponentially without Retry-After", func(t *testing.T) {
+		res := &http.Response{Header: http.Header{}}
+		assert.Equal(t, 4*time.Second, c.retryWait(res, 2))
+		assert.Equal(t, maxRetryInterval, c.retryWait(res, 10))
+	})
+}
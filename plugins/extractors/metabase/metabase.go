@@ -1,9 +1,12 @@
 package metabase
 
 import (
+	"bytes"
 	"context"
 	_ "embed" // used to print the embedded assets
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,14 +29,23 @@ var summary string
 var sampleConfig = `
 host: http://localhost:3000
 user_id: meteor_tester
-password: meteor_pass_1234`
+password: meteor_pass_1234
+# also extract questions (cards) that are not part of any dashboard
+include_cards: false
+# attach the native SQL or the MBQL of the cards to their charts
+include_query: false
+# retries for requests failing with 429 or 5xx
+max_retries: 3`
 
 // Config holds the set of configuration for the metabase extractor
 type Config struct {
-	Host      string `mapstructure:"host" validate:"required"`
-	Username  string `mapstructure:"username" validate:"required"`
-	Password  string `mapstructure:"password" validate:"required"`
-	SessionID string `mapstructure:"session_id"`
+	Host         string `mapstructure:"host" validate:"required"`
+	Username     string `mapstructure:"username" validate:"required"`
+	Password     string `mapstructure:"password" validate:"required"`
+	SessionID    string `mapstructure:"session_id"`
+	IncludeCards bool   `mapstructure:"include_cards"`
+	IncludeQuery bool   `mapstructure:"include_query"`
+	MaxRetries   int    `mapstructure:"max_retries" validate:"gte=0" default:"3"`
 }
 
 // Extractor manages the extraction of data
@@ -52,11 +64,17 @@ func New(client Client, logger log.Logger) *Extractor {
 	}
 }
 
+// SetLogger sets the logger of the extractor
+func (e *Extractor) SetLogger(logger log.Logger) {
+	e.logger = logger
+}
+
 // Info returns the brief information of the extractor
 func (e *Extractor) Info() plugins.Info {
 	return plugins.Info{
 		Description:  "Dashboard list from Metabase server.",
 		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
 		Summary:      summary,
 		Tags:         []string{"oss", "extractor"},
 	}
@@ -74,7 +92,12 @@ func (e *Extractor) Init(ctx context.Context, configMap map[string]interface{})
 		return plugins.InvalidConfigError{}
 	}
 
-	err = e.client.Authenticate(e.config.Host, e.config.Username, e.config.Password, e.config.SessionID)
+	// retries are only supported by the default http client
+	if c, ok := e.client.(*client); ok {
+		c.maxRetries = e.config.MaxRetries
+	}
+
+	err = e.client.Authenticate(ctx, e.config.Host, e.config.Username, e.config.Password, e.config.SessionID)
 	if err != nil {
 		return errors.Wrap(err, "error initiating client")
 	}
@@ -84,32 +107,143 @@ func (e *Extractor) Init(ctx context.Context, configMap map[string]interface{})
 
 // Extract collects the metadata from the source. The metadata is collected through the out channel
 func (e *Extractor) Extract(ctx context.Context, emit plugins.Emit) (err error) {
-	dashboards, err := e.client.GetDashboards()
+	collections, err := e.client.GetCollections(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to fetch collection list")
+	}
+	paths := collectionPaths(collections)
+
+	// dashboards outside of any collection are listed in the root collection
+	collectionIDs := []int{rootCollectionID}
+	for _, c := range collections {
+		if !c.Archived {
+			collectionIDs = append(collectionIDs, c.ID)
+		}
+	}
+
+	dashboardCards := map[int]bool{}
+	for _, collectionID := range collectionIDs {
+		dashboards, err := e.client.GetCollectionDashboards(ctx, collectionID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch dashboard list of collection %d", collectionID)
+		}
+		for _, d := range dashboards {
+			dashboard, err := e.buildDashboard(ctx, d, dashboardCards)
+			if err != nil {
+				e.logger.Error("failed to build dashboard with", "dashboard_id", d.ID, "err", err.Error())
+				continue
+			}
+			if path, ok := paths[collectionID]; ok {
+				dashboard.Properties.Labels = map[string]string{"collection_path": path}
+			}
+
+			emit(models.NewRecord(dashboard))
+		}
+	}
+
+	if !e.config.IncludeCards {
+		return nil
+	}
+
+	return e.extractCards(ctx, emit, dashboardCards)
+}
+
+// collectionPaths returns the path of each collection, the names of its ancestors and its own joined by "/".
+// Ancestors that could not be listed, e.g. for lack of permissions, are left out of the path.
+func collectionPaths(collections []Collection) map[int]string {
+	names := make(map[int]string, len(collections))
+	for _, c := range collections {
+		names[c.ID] = c.Name
+	}
+
+	paths := make(map[int]string, len(collections))
+	for _, c := range collections {
+		var path []string
+		for _, id := range strings.Split(strings.Trim(c.Location, "/"), "/") {
+			ancestorID, err := strconv.Atoi(id)
+			if err != nil {
+				continue
+			}
+			if name, ok := names[ancestorID]; ok {
+				path = append(path, name)
+			}
+		}
+		paths[c.ID] = strings.Join(append(path, c.Name), "/")
+	}
+
+	return paths
+}
+
+// extractCards emits the cards (questions) that are not part of any of the extracted dashboards
+func (e *Extractor) extractCards(ctx context.Context, emit plugins.Emit, dashboardCards map[int]bool) (err error) {
+	cards, err := e.client.GetCards(ctx)
 	if err != nil {
-		return errors.Wrap(err, "failed to fetch dashboard list")
+		return errors.Wrap(err, "failed to fetch card list")
 	}
-	for _, d := range dashboards {
-		dashboard, err := e.buildDashboard(d)
+	for _, c := range cards {
+		if c.Archived || dashboardCards[c.ID] {
+			continue
+		}
+
+		card, err := e.buildCard(ctx, c)
 		if err != nil {
-			e.logger.Error("failed to build dashboard with", "dashboard_id", d.ID, "err", err.Error())
+			e.logger.Error("failed to build card with", "card_id", c.ID, "err", err.Error())
 			continue
 		}
 
-		emit(models.NewRecord(dashboard))
+		emit(models.NewRecord(card))
 	}
+
 	return nil
 }
 
-func (e *Extractor) buildDashboard(d Dashboard) (data *assetsv1beta1.Dashboard, err error) {
+func (e *Extractor) buildCard(ctx context.Context, c Card) (data *assetsv1beta1.Dashboard, err error) {
+	// we fetch card again individually to get more fields
+	card, err := e.client.GetCard(ctx, c.ID)
+	if err != nil {
+		err = errors.Wrapf(err, "error fetching card")
+		return
+	}
+
+	cardUrn := models.DashboardURN("metabase", e.config.Host, fmt.Sprintf("card/%d", card.ID))
+	chart, err := e.buildChart(ctx, card, cardUrn)
+	if err != nil {
+		return
+	}
+
+	data = &assetsv1beta1.Dashboard{
+		Resource: &commonv1beta1.Resource{
+			Urn:         cardUrn,
+			Name:        card.Name,
+			Service:     "metabase",
+			Type:        "card",
+			Description: card.Description,
+		},
+		Charts:     []*assetsv1beta1.Chart{chart},
+		Properties: chart.Properties,
+		Timestamps: &commonv1beta1.Timestamp{
+			CreateTime: timestamppb.New(time.Time(card.CreatedAt)),
+			UpdateTime: timestamppb.New(time.Time(card.UpdatedAt)),
+		},
+		Lineage: chart.Lineage,
+	}
+	return
+}
+
+func (e *Extractor) buildDashboard(ctx context.Context, d Dashboard, dashboardCards map[int]bool) (data *assetsv1beta1.Dashboard, err error) {
 	// we fetch dashboard again individually to get more fields
-	dashboard, err := e.client.GetDashboard(d.ID)
+	dashboard, err := e.client.GetDashboard(ctx, d.ID)
 	if err != nil {
 		err = errors.Wrapf(err, "error fetching dashboard")
 		return
 	}
 
+	for _, oc := range dashboard.OrderedCards {
+		dashboardCards[oc.Card.ID] = true
+	}
+
 	dashboardUrn := models.DashboardURN("metabase", e.config.Host, fmt.Sprintf("dashboard/%d", dashboard.ID))
-	charts := e.buildCharts(dashboardUrn, dashboard)
+	charts := e.buildCharts(ctx, dashboardUrn, dashboard)
 	dashboardUpstreams := e.buildDashboardUpstreams(charts)
 
 	data = &assetsv1beta1.Dashboard{
@@ -138,9 +272,9 @@ func (e *Extractor) buildDashboard(d Dashboard) (data *assetsv1beta1.Dashboard,
 	return
 }
 
-func (e *Extractor) buildCharts(dashboardUrn string, dashboard Dashboard) (charts []*assetsv1beta1.Chart) {
+func (e *Extractor) buildCharts(ctx context.Context, dashboardUrn string, dashboard Dashboard) (charts []*assetsv1beta1.Chart) {
 	for _, oc := range dashboard.OrderedCards {
-		chart, err := e.buildChart(oc.Card, dashboardUrn)
+		chart, err := e.buildChart(ctx, oc.Card, dashboardUrn)
 		if err != nil {
 			e.logger.Error("error building upstreams for a card", "card_id", oc.Card.ID, "err", err)
 		} else {
@@ -151,13 +285,30 @@ func (e *Extractor) buildCharts(dashboardUrn string, dashboard Dashboard) (chart
 	return
 }
 
-func (e *Extractor) buildChart(card Card, dashboardUrn string) (chart *assetsv1beta1.Chart, err error) {
+func (e *Extractor) buildChart(ctx context.Context, card Card, dashboardUrn string) (chart *assetsv1beta1.Chart, err error) {
 	var upstreams []*commonv1beta1.Resource
-	upstreams, err = e.buildUpstreams(card)
+	upstreams, err = e.buildUpstreams(ctx, card)
 	if err != nil {
 		e.logger.Warn("error building upstreams for a card", "card_id", card.ID, "err", err)
 	}
 
+	attributes := map[string]interface{}{
+		"id":                     card.ID,
+		"collection_id":          card.CollectionID,
+		"creator_id":             card.CreatorID,
+		"database_id":            card.DatabaseID,
+		"table_id":               card.TableID,
+		"query_average_duration": card.QueryAverageDuration,
+		"display":                card.Display,
+		"archived":               card.Archived,
+	}
+	if e.config.IncludeQuery {
+		if query, ok := e.buildQuery(card.DatasetQuery); ok {
+			attributes["query_type"] = card.DatasetQuery.Type
+			attributes["query"] = query
+		}
+	}
+
 	return &assetsv1beta1.Chart{
 		Urn:          fmt.Sprintf("metabase::%s/card/%d", e.config.Host, card.ID),
 		DashboardUrn: dashboardUrn,
@@ -165,16 +316,7 @@ func (e *Extractor) buildChart(card Card, dashboardUrn string) (chart *assetsv1b
 		Name:         card.Name,
 		Description:  card.Description,
 		Properties: &facetsv1beta1.Properties{
-			Attributes: utils.TryParseMapToProto(map[string]interface{}{
-				"id":                     card.ID,
-				"collection_id":          card.CollectionID,
-				"creator_id":             card.CreatorID,
-				"database_id":            card.DatabaseID,
-				"table_id":               card.TableID,
-				"query_average_duration": card.QueryAverageDuration,
-				"display":                card.Display,
-				"archived":               card.Archived,
-			}),
+			Attributes: utils.TryParseMapToProto(attributes),
 		},
 		Lineage: &facetsv1beta1.Lineage{
 			Upstreams: upstreams,
@@ -182,16 +324,33 @@ func (e *Extractor) buildChart(card Card, dashboardUrn string) (chart *assetsv1b
 	}, nil
 }
 
-func (e *Extractor) buildUpstreams(card Card) (upstreams []*commonv1beta1.Resource, err error) {
+// buildQuery returns the SQL of native queries and the compacted MBQL of the others
+func (e *Extractor) buildQuery(datasetQuery CardDatasetQuery) (query string, ok bool) {
+	switch datasetQuery.Type {
+	case datasetQueryTypeNative:
+		return datasetQuery.Native.Query, datasetQuery.Native.Query != ""
+	case datasetQueryTypeQuery:
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, datasetQuery.RawQuery); err != nil {
+			e.logger.Warn("error reading query of a card", "err", err)
+			return "", false
+		}
+		return buf.String(), true
+	default:
+		return "", false
+	}
+}
+
+func (e *Extractor) buildUpstreams(ctx context.Context, card Card) (upstreams []*commonv1beta1.Resource, err error) {
 	switch card.DatasetQuery.Type {
 	case datasetQueryTypeQuery:
-		upstreams, err = e.buildUpstreamsFromQuery(card)
+		upstreams, err = e.buildUpstreamsFromQuery(ctx, card)
 		if err != nil {
 			err = errors.Wrap(err, "error building upstreams from query")
 		}
 		return
 	case datasetQueryTypeNative:
-		upstreams, err = e.buildUpstreamsFromNative(card)
+		upstreams, err = e.buildUpstreamsFromNative(ctx, card)
 		if err != nil {
 			err = errors.Wrap(err, "error building upstreams from native")
 		}
@@ -201,8 +360,8 @@ func (e *Extractor) buildUpstreams(card Card) (upstreams []*commonv1beta1.Resour
 	}
 }
 
-func (e *Extractor) buildUpstreamsFromQuery(card Card) (upstreams []*commonv1beta1.Resource, err error) {
-	table, err := e.client.GetTable(card.DatasetQuery.Query.SourceTable)
+func (e *Extractor) buildUpstreamsFromQuery(ctx context.Context, card Card) (upstreams []*commonv1beta1.Resource, err error) {
+	table, err := e.client.GetTable(ctx, card.DatasetQuery.Query.SourceTable)
 	if err != nil {
 		err = errors.Wrap(err, "error getting table")
 		return
@@ -218,8 +377,8 @@ func (e *Extractor) buildUpstreamsFromQuery(card Card) (upstreams []*commonv1bet
 	return
 }
 
-func (e *Extractor) buildUpstreamsFromNative(card Card) (upstreams []*commonv1beta1.Resource, err error) {
-	database, err := e.client.GetDatabase(card.DatasetQuery.Database)
+func (e *Extractor) buildUpstreamsFromNative(ctx context.Context, card Card) (upstreams []*commonv1beta1.Resource, err error) {
+	database, err := e.client.GetDatabase(ctx, card.DatasetQuery.Database)
 	if err != nil {
 		err = errors.Wrap(err, "error getting database")
 		return
@@ -13,6 +13,7 @@ import (
 	testutils "github.com/odpf/meteor/test/utils"
 	"github.com/pkg/errors"
 
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
 	"github.com/odpf/meteor/plugins"
 	"github.com/odpf/meteor/plugins/extractors/metabase"
 	"github.com/odpf/meteor/test/mocks"
@@ -58,7 +59,10 @@ func TestExtract(t *testing.T) {
 
 		client := new(mockClient)
 		client.On("Authenticate", host, "test-user", "test-pass", "").Return(nil)
-		client.On("GetDashboards").Return(dashboards, nil)
+		client.On("GetCollections").Return(getCollectionList(t), nil)
+		client.On("GetCollectionDashboards", 0).Return([]metabase.Dashboard{}, nil).Once()
+		client.On("GetCollectionDashboards", 3).Return([]metabase.Dashboard{}, nil).Once()
+		client.On("GetCollectionDashboards", 1).Return(dashboards, nil).Once()
 		client.On("GetDashboard", 1).Return(dashboard_1, nil)
 		client.On("GetTable", 2).Return(getTable(t, 2), nil).Once()
 		client.On("GetDatabase", 2).Return(getDatabase(t, 2), nil).Once()
@@ -85,6 +89,158 @@ func TestExtract(t *testing.T) {
 	})
 }
 
+func TestExtractCards(t *testing.T) {
+	t.Run("should not return cards if include_cards is not set", func(t *testing.T) {
+		client := new(mockClient)
+		client.On("Authenticate", host, "test-user", "test-pass", "").Return(nil)
+		client.On("GetCollections").Return([]metabase.Collection{}, nil)
+		client.On("GetCollectionDashboards", 0).Return([]metabase.Dashboard{}, nil)
+		defer client.AssertExpectations(t)
+
+		emitter := mocks.NewEmitter()
+		extr := metabase.New(client, plugins.GetLog())
+		err := extr.Init(context.TODO(), map[string]interface{}{
+			"host":     host,
+			"username": "test-user",
+			"password": "test-pass",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = extr.Extract(context.TODO(), emitter.Push)
+		assert.NoError(t, err)
+		assert.Empty(t, emitter.Get())
+	})
+	t.Run("should return cards that are not part of a dashboard", func(t *testing.T) {
+		client := new(mockClient)
+		client.On("Authenticate", host, "test-user", "test-pass", "").Return(nil)
+		client.On("GetCollections").Return([]metabase.Collection{}, nil)
+		client.On("GetCollectionDashboards", 0).Return(getDashboardList(t), nil)
+		client.On("GetDashboard", 1).Return(getDashboard(t, 1), nil)
+		client.On("GetTable", 2).Return(getTable(t, 2), nil)
+		client.On("GetDatabase", 2).Return(getDatabase(t, 2), nil)
+		client.On("GetTable", 5).Return(getTable(t, 5), nil)
+		client.On("GetDatabase", 3).Return(getDatabase(t, 3), nil)
+		client.On("GetCards").Return(getCardList(t), nil)
+		client.On("GetCard", 7).Return(getCard(t, 7), nil)
+		defer client.AssertExpectations(t)
+
+		emitter := mocks.NewEmitter()
+		extr := metabase.New(client, plugins.GetLog())
+		err := extr.Init(context.TODO(), map[string]interface{}{
+			"host":          host,
+			"username":      "test-user",
+			"password":      "test-pass",
+			"include_cards": true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = extr.Extract(context.TODO(), emitter.Push)
+		assert.NoError(t, err)
+
+		actuals := emitter.GetAllData()
+		assert.Len(t, actuals, 2)
+		testutils.AssertWithJSONFile(t, "./testdata/expected_cards.json", actuals[1:])
+	})
+}
+
+func TestExtractQuery(t *testing.T) {
+	newExtractor := func(t *testing.T, includeQuery bool) (*metabase.Extractor, *mockClient) {
+		client := new(mockClient)
+		client.On("Authenticate", host, "test-user", "test-pass", "").Return(nil)
+		client.On("GetCollections").Return([]metabase.Collection{}, nil)
+		client.On("GetCollectionDashboards", 0).Return(getDashboardList(t), nil)
+		client.On("GetDashboard", 1).Return(getDashboard(t, 1), nil)
+		client.On("GetTable", 2).Return(getTable(t, 2), nil)
+		client.On("GetDatabase", 2).Return(getDatabase(t, 2), nil)
+		client.On("GetTable", 5).Return(getTable(t, 5), nil)
+		client.On("GetDatabase", 3).Return(getDatabase(t, 3), nil)
+
+		extr := metabase.New(client, plugins.GetLog())
+		err := extr.Init(context.TODO(), map[string]interface{}{
+			"host":          host,
+			"username":      "test-user",
+			"password":      "test-pass",
+			"include_query": includeQuery,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return extr, client
+	}
+
+	t.Run("should attach the query of charts when include_query is true", func(t *testing.T) {
+		extr, client := newExtractor(t, true)
+		defer client.AssertExpectations(t)
+
+		emitter := mocks.NewEmitter()
+		err := extr.Extract(context.TODO(), emitter.Push)
+		assert.NoError(t, err)
+
+		dashboards := emitter.GetAllData()
+		assert.Len(t, dashboards, 1)
+		charts := dashboards[0].(*assetsv1beta1.Dashboard).Charts
+		assert.Len(t, charts, 4)
+
+		attributes := charts[0].Properties.Attributes.AsMap()
+		assert.Equal(t, "query", attributes["query_type"])
+		assert.Equal(t, `{"source-table":2,"filter":[">",["field",10,null],30]}`, attributes["query"])
+
+		attributes = charts[1].Properties.Attributes.AsMap()
+		assert.Equal(t, "native", attributes["query_type"])
+		assert.Equal(t, "SELECT name,total_followers,total_likes,created_at\nFROM public.user\nWHERE total_followers > {{followers}} AND created_at > {{start-time}} AND name = {{name}} AND {{dims}};", attributes["query"])
+	})
+
+	t.Run("should not attach the query of charts by default", func(t *testing.T) {
+		extr, client := newExtractor(t, false)
+		defer client.AssertExpectations(t)
+
+		emitter := mocks.NewEmitter()
+		err := extr.Extract(context.TODO(), emitter.Push)
+		assert.NoError(t, err)
+
+		for _, chart := range emitter.GetAllData()[0].(*assetsv1beta1.Dashboard).Charts {
+			assert.NotContains(t, chart.Properties.Attributes.AsMap(), "query")
+			assert.NotContains(t, chart.Properties.Attributes.AsMap(), "query_type")
+		}
+	})
+}
+
+func getCollectionList(t *testing.T) []metabase.Collection {
+	var collections []metabase.Collection
+	err := readFromFiles("./testdata/collections.json", &collections)
+	if err != nil {
+		t.Fatalf("error reading collections: %s", err.Error())
+	}
+
+	return collections
+}
+
+func getCardList(t *testing.T) []metabase.Card {
+	var cards []metabase.Card
+	err := readFromFiles("./testdata/cards.json", &cards)
+	if err != nil {
+		t.Fatalf("error reading cards: %s", err.Error())
+	}
+
+	return cards
+}
+
+func getCard(t *testing.T, id int) metabase.Card {
+	var card metabase.Card
+	filePath := fmt.Sprintf("./testdata/card_%d.json", id)
+	err := readFromFiles(filePath, &card)
+	if err != nil {
+		t.Fatalf("error reading %s: %s", filePath, err.Error())
+	}
+
+	return card
+}
+
 func getDashboardList(t *testing.T) []metabase.Dashboard {
 	var dashboards []metabase.Dashboard
 	err := readFromFiles("./testdata/dashboards.json", &dashboards)
@@ -145,27 +301,42 @@ type mockClient struct {
 	mock.Mock
 }
 
-func (m *mockClient) Authenticate(host, username, password, sessionID string) error {
+func (m *mockClient) Authenticate(ctx context.Context, host, username, password, sessionID string) error {
 	args := m.Called(host, username, password, sessionID)
 	return args.Error(0)
 }
 
-func (m *mockClient) GetDashboards() ([]metabase.Dashboard, error) {
+func (m *mockClient) GetCollections(ctx context.Context) ([]metabase.Collection, error) {
 	args := m.Called()
+	return args.Get(0).([]metabase.Collection), args.Error(1)
+}
+
+func (m *mockClient) GetCollectionDashboards(ctx context.Context, collectionID int) ([]metabase.Dashboard, error) {
+	args := m.Called(collectionID)
 	return args.Get(0).([]metabase.Dashboard), args.Error(1)
 }
 
-func (m *mockClient) GetDashboard(id int) (metabase.Dashboard, error) {
+func (m *mockClient) GetDashboard(ctx context.Context, id int) (metabase.Dashboard, error) {
 	args := m.Called(id)
 	return args.Get(0).(metabase.Dashboard), args.Error(1)
 }
 
-func (m *mockClient) GetDatabase(id int) (metabase.Database, error) {
+func (m *mockClient) GetDatabase(ctx context.Context, id int) (metabase.Database, error) {
 	args := m.Called(id)
 	return args.Get(0).(metabase.Database), args.Error(1)
 }
 
-func (m *mockClient) GetTable(id int) (metabase.Table, error) {
+func (m *mockClient) GetTable(ctx context.Context, id int) (metabase.Table, error) {
 	args := m.Called(id)
 	return args.Get(0).(metabase.Table), args.Error(1)
 }
+
+func (m *mockClient) GetCards(ctx context.Context) ([]metabase.Card, error) {
+	args := m.Called()
+	return args.Get(0).([]metabase.Card), args.Error(1)
+}
+
+func (m *mockClient) GetCard(ctx context.Context, id int) (metabase.Card, error) {
+	args := m.Called(id)
+	return args.Get(0).(metabase.Card), args.Error(1)
+}
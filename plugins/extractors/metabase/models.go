@@ -1,6 +1,7 @@
 package metabase
 
 import (
+	"encoding/json"
 	"strings"
 	"time"
 )
@@ -112,6 +113,9 @@ type Collection struct {
 	Description string       `json:"description"`
 	CreatedAt   MetabaseTime `json:"created_at"`
 	UpdatedAt   MetabaseTime `json:"updated_at"`
+	// Location holds the ids of the ancestors of the collection, e.g. "/1/4/"
+	Location string `json:"location"`
+	Archived bool   `json:"archived"`
 }
 
 type CardDatasetQuery struct {
@@ -122,6 +126,25 @@ type CardDatasetQuery struct {
 	} `json:"query"`
 	Native   NativeDatasetQuery `json:"native"`
 	Database int                `json:"database"`
+	// RawQuery holds the MBQL of the query as it was received
+	RawQuery json.RawMessage `json:"-"`
+}
+
+func (q *CardDatasetQuery) UnmarshalJSON(b []byte) error {
+	type datasetQuery CardDatasetQuery
+	if err := json.Unmarshal(b, (*datasetQuery)(q)); err != nil {
+		return err
+	}
+
+	var raw struct {
+		Query json.RawMessage `json:"query"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	q.RawQuery = raw.Query
+
+	return nil
 }
 
 type NativeDatasetQuery struct {
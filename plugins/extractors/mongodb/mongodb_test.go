@@ -100,6 +100,50 @@ func TestInit(t *testing.T) {
 
 		assert.Equal(t, plugins.InvalidConfigError{}, err)
 	})
+
+	t.Run("should return error for invalid count mode", func(t *testing.T) {
+		err := mongodb.New(utils.Logger).Init(context.TODO(), map[string]interface{}{
+			"connection_url": fmt.Sprintf("mongodb://%s:%s@%s", user, pass, host),
+			"count_mode":     "approximate",
+		})
+
+		assert.Equal(t, plugins.InvalidConfigError{}, err)
+	})
+
+	t.Run("should return error for a client certificate without key", func(t *testing.T) {
+		err := mongodb.New(utils.Logger).Init(context.TODO(), map[string]interface{}{
+			"connection_url": fmt.Sprintf("mongodb://%s:%s@%s", user, pass, host),
+			"ssl_cert_file":  "testdata/client.pem",
+		})
+
+		assert.Equal(t, plugins.InvalidConfigError{}, err)
+	})
+
+	t.Run("should return error for unreadable tls files", func(t *testing.T) {
+		err := mongodb.New(utils.Logger).Init(context.TODO(), map[string]interface{}{
+			"connection_url": fmt.Sprintf("mongodb://%s:%s@%s", user, pass, host),
+			"ssl_ca_file":    "testdata/missing-ca.pem",
+		})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to read ssl_ca_file")
+	})
+}
+
+func TestHealthCheck(t *testing.T) {
+	t.Run("should ping the server", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := mongodb.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url": fmt.Sprintf("mongodb://%s:%s@%s", user, pass, host),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NoError(t, extr.HealthCheck(ctx))
+	})
 }
 
 func TestExtract(t *testing.T) {
@@ -120,6 +164,140 @@ func TestExtract(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, getExpected(), emitter.Get())
 	})
+
+	t.Run("should count documents exactly with exact count mode", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := mongodb.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url": fmt.Sprintf("mongodb://%s:%s@%s", user, pass, host),
+			"count_mode":     "exact",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+
+		assert.NoError(t, err)
+		assert.Equal(t, getExpected(), emitter.Get())
+	})
+
+	t.Run("should attach size of collections when include_size is true", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := mongodb.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url": fmt.Sprintf("mongodb://%s:%s@%s", user, pass, host),
+			"include_size":   true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+
+		assert.NoError(t, err)
+		records := emitter.GetAllData()
+		assert.Len(t, records, len(getExpected()))
+		for _, record := range records {
+			size, ok := record.GetProperties().GetAttributes().AsMap()["size_bytes"].(float64)
+			assert.True(t, ok, record.GetResource().Urn)
+			assert.Greater(t, size, float64(0))
+		}
+	})
+
+	t.Run("should attach indexes of collections when include_indexes is true", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := mongodb.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url":   fmt.Sprintf("mongodb://%s:%s@%s", user, pass, host),
+			"include_indexes":  true,
+			"exclude_id_index": true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+
+		assert.NoError(t, err)
+		indexes := map[string]interface{}{}
+		for _, record := range emitter.GetAllData() {
+			indexes[record.GetResource().Name] = record.GetProperties().GetAttributes().AsMap()["indexes"]
+		}
+		assert.Equal(t, map[string]interface{}{
+			"connections": []interface{}{
+				map[string]interface{}{
+					"name":                 "created_at_1",
+					"keys":                 []interface{}{map[string]interface{}{"field": "created_at", "type": "1"}},
+					"unique":               false,
+					"expire_after_seconds": float64(3600),
+				},
+			},
+			"posts": []interface{}{
+				map[string]interface{}{
+					"name": "title_1_body_-1",
+					"keys": []interface{}{
+						map[string]interface{}{"field": "title", "type": "1"},
+						map[string]interface{}{"field": "body", "type": "-1"},
+					},
+					"unique": true,
+				},
+			},
+			"stats": nil,
+		}, indexes)
+	})
+
+	t.Run("should keep the _id_ index unless excluded", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := mongodb.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url":  fmt.Sprintf("mongodb://%s:%s@%s", user, pass, host),
+			"include_indexes": true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+
+		assert.NoError(t, err)
+		for _, record := range emitter.GetAllData() {
+			indexes, ok := record.GetProperties().GetAttributes().AsMap()["indexes"].([]interface{})
+			if assert.True(t, ok, record.GetResource().Urn) {
+				assert.Equal(t, "_id_", indexes[0].(map[string]interface{})["name"])
+			}
+		}
+	})
+
+	t.Run("should not count documents with none count mode", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := mongodb.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url": fmt.Sprintf("mongodb://%s:%s@%s", user, pass, host),
+			"count_mode":     "none",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+
+		assert.NoError(t, err)
+		for _, table := range emitter.GetAllData() {
+			assert.Nil(t, table.(*assetsv1beta1.Table).Profile)
+		}
+		assert.Len(t, emitter.GetAllData(), len(getExpected()))
+	})
 }
 
 func setup(ctx context.Context) (err error) {
@@ -142,6 +320,22 @@ func setup(ctx context.Context) (err error) {
 		return
 	}
 
+	// index posts and expire connections
+	_, err = client.Database(testDB).Collection("posts").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "title", Value: 1}, {Key: "body", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return
+	}
+	_, err = client.Database(testDB).Collection("connections").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(3600),
+	})
+	if err != nil {
+		return
+	}
+
 	// create and populate stats collection
 	err = createCollection(ctx, "stats", []interface{}{
 		bson.D{{Key: "views", Value: "500"}, {Key: "likes", Value: "200"}},
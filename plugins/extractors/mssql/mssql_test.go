@@ -21,6 +21,7 @@ import (
 	"github.com/odpf/meteor/plugins"
 	"github.com/odpf/meteor/plugins/extractors/mssql"
 	"github.com/odpf/meteor/test/mocks"
+	meteorutils "github.com/odpf/meteor/utils"
 	"github.com/ory/dockertest/v3"
 	"github.com/ory/dockertest/v3/docker"
 	"github.com/stretchr/testify/assert"
@@ -156,18 +157,33 @@ func getExpected() []models.Record {
 						Name:       "applicant_id",
 						IsNullable: true,
 						Length:     0,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "integer",
+							}),
+						},
 					},
 					{
 						DataType:   "varchar",
 						Name:       "first_name",
 						IsNullable: true,
 						Length:     255,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "string",
+							}),
+						},
 					},
 					{
 						DataType:   "varchar",
 						Name:       "last_name",
 						IsNullable: true,
 						Length:     255,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "string",
+							}),
+						},
 					},
 				},
 			},
@@ -184,18 +200,33 @@ func getExpected() []models.Record {
 						Name:       "department",
 						IsNullable: true,
 						Length:     255,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "string",
+							}),
+						},
 					},
 					{
 						DataType:   "varchar",
 						Name:       "job",
 						IsNullable: true,
 						Length:     255,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "string",
+							}),
+						},
 					},
 					{
 						DataType:   "int",
 						Name:       "job_id",
 						IsNullable: true,
 						Length:     0,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "integer",
+							}),
+						},
 					},
 				},
 			},
@@ -5,10 +5,12 @@ import (
 	"database/sql"
 	_ "embed" // used to print the embedded assets
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"github.com/odpf/meteor/models"
 
 	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
@@ -16,6 +18,8 @@ import (
 	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
 
 	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/retry"
+	"github.com/odpf/meteor/plugins/sqlutil"
 	"github.com/odpf/meteor/registry"
 	"github.com/odpf/meteor/utils"
 	"github.com/odpf/salt/log"
@@ -33,10 +37,62 @@ var defaultDBList = []string{
 
 // Config holds the connection URL for the extractor
 type Config struct {
-	ConnectionURL string `mapstructure:"connection_url" validate:"required"`
+	Mode                      string                       `mapstructure:"mode" validate:"oneof=schema profile" default:"schema"`
+	ConnectRetries            int                          `mapstructure:"connect_retries" validate:"gte=0"`
+	QueryRetries              int                          `mapstructure:"query_retries" validate:"gte=0"`
+	TemporaryTables           sqlutil.TemporaryTableConfig `mapstructure:"temporary_tables"`
+	Freshness                 []sqlutil.FreshnessColumn    `mapstructure:"freshness" validate:"dive"`
+	IncludeIndexes            bool                         `mapstructure:"include_indexes"`
+	IncludeForeignKeys        bool                         `mapstructure:"include_foreign_keys"`
+	IncludeSize               bool                         `mapstructure:"include_size"`
+	IncludeViewLineage        bool                         `mapstructure:"include_view_lineage"`
+	IncludeRoutines           bool                         `mapstructure:"include_routines"`
+	IncludeRoutineDefinitions bool                         `mapstructure:"include_routine_definitions"`
+	EmitContainers            bool                         `mapstructure:"emit_containers"`
+
+	sqlutil.EndpointConfig `mapstructure:",squash"`
+	retry.ConnectionConfig `mapstructure:",squash"`
+	sqlutil.URNConfig      `mapstructure:",squash"`
 }
 
-var sampleConfig = `connection_url: "admin:pass123@tcp(localhost:3306)/"`
+var sampleConfig = `
+connection_url: "admin:pass123@tcp(localhost:3306)/"
+# read replica all the extraction queries are run against, connection_url is optional when set
+read_connection_url: "admin:pass123@tcp(replica:3306)/"
+# schema skips counting the rows of tables, profile counts them
+mode: schema
+# retries when connecting to the server, authentication failures are not retried
+connection_retries: 5
+# backoff before the first connection retry, doubled on each retry
+connection_retry_interval: 1s
+# retries when a query fails
+query_retries: 2
+# tag tables matching these patterns as temporary, or skip them
+temporary_tables:
+  patterns:
+    - tmp_*
+    - stg_*
+  skip: false
+# attach data freshness, the latest value of a timestamp column, to matching tables
+freshness:
+  - table: orders*
+    column: updated_at
+# attach indexes of tables
+include_indexes: false
+# attach foreign keys of tables, referenced tables become upstream lineage
+include_foreign_keys: false
+# attach the size in bytes of tables, data and indexes included
+include_size: false
+# parse the definition of views, the tables they select from become upstream lineage
+include_view_lineage: false
+# emit the stored procedures and functions of databases as job assets
+include_routines: false
+# attach the body of routines, only readable by their definer or with the SHOW_ROUTINE privilege
+include_routine_definitions: false
+# emit each database as well, holding the urns of its tables
+emit_containers: false
+# template of table urns with {service}, {host}, {database} and {table} placeholders
+urn_template: "{database}.{table}"`
 
 // Extractor manages the extraction of data from MySQL
 type Extractor struct {
@@ -44,6 +100,11 @@ type Extractor struct {
 	logger      log.Logger
 	config      Config
 	db          *sql.DB
+	retrier     *retry.Retrier
+	classifier  *sqlutil.TableClassifier
+	freshness   *sqlutil.FreshnessMatcher
+	urns        *sqlutil.URNBuilder
+	host        string
 	emit        plugins.Emit
 }
 
@@ -54,11 +115,17 @@ func New(logger log.Logger) *Extractor {
 	}
 }
 
+// SetLogger sets the logger of the extractor
+func (e *Extractor) SetLogger(logger log.Logger) {
+	e.logger = logger
+}
+
 // Info returns the brief information about the extractor
 func (e *Extractor) Info() plugins.Info {
 	return plugins.Info{
 		Description:  "Table metadata from MySQL server.",
 		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
 		Summary:      summary,
 		Tags:         []string{"oss", "extractor"},
 	}
@@ -75,14 +142,41 @@ func (e *Extractor) Init(ctx context.Context, configMap map[string]interface{})
 		return plugins.InvalidConfigError{}
 	}
 
+	if e.classifier, err = sqlutil.NewTableClassifier(e.config.TemporaryTables); err != nil {
+		return plugins.InvalidConfigError{}
+	}
+	if e.freshness, err = sqlutil.NewFreshnessMatcher(e.config.Freshness); err != nil {
+		return plugins.InvalidConfigError{}
+	}
+	if e.urns, err = sqlutil.NewURNBuilder(e.config.URNConfig, defaultURN); err != nil {
+		return plugins.InvalidConfigError{}
+	}
+	if dsn, err := mysql.ParseDSN(e.config.PrimaryURL()); err == nil {
+		e.host = dsn.Addr
+	}
+	connectPolicy, err := e.config.ConnectPolicy()
+	if err != nil {
+		return plugins.InvalidConfigError{}
+	}
+	// connect_retries predates connection_retries, the larger of both is used
+	if e.config.ConnectRetries > connectPolicy.MaxRetries {
+		connectPolicy.MaxRetries = e.config.ConnectRetries
+	}
+	e.retrier = retry.NewRetrier(0, e.config.QueryRetries)
+	e.retrier.Connect = connectPolicy
+
 	// build excluded database list
 	e.buildExcludedDBs()
 
 	// create client
-	if e.db, err = sql.Open("mysql", e.config.ConnectionURL); err != nil {
+	if e.db, err = sql.Open("mysql", e.config.ReadURL()); err != nil {
 		return errors.Wrap(err, "failed to create client")
 	}
 
+	if err = e.retrier.PingContext(ctx, e.db); err != nil {
+		return errors.Wrap(err, "failed to connect")
+	}
+
 	return
 }
 
@@ -92,7 +186,7 @@ func (e *Extractor) Extract(ctx context.Context, emit plugins.Emit) (err error)
 	defer e.db.Close()
 	e.emit = emit
 
-	res, err := e.db.Query("SHOW DATABASES;")
+	res, err := e.retrier.QueryContext(ctx, e.db, "SHOW DATABASES;")
 	if err != nil {
 		return errors.Wrap(err, "failed to fetch databases")
 	}
@@ -103,7 +197,7 @@ func (e *Extractor) Extract(ctx context.Context, emit plugins.Emit) (err error)
 			continue
 		}
 
-		if err := e.extractTables(database); err != nil {
+		if err := e.extractTables(ctx, database); err != nil {
 			e.logger.Error("failed to get tables, skipping database", "error", err)
 			continue
 		}
@@ -113,66 +207,468 @@ func (e *Extractor) Extract(ctx context.Context, emit plugins.Emit) (err error)
 }
 
 // Extract tables from a given database
-func (e *Extractor) extractTables(database string) (err error) {
+func (e *Extractor) extractTables(ctx context.Context, database string) (err error) {
 	// skip if database is default
 	if e.isExcludedDB(database) {
 		return
 	}
 
+	charset, collation, err := e.extractCharset(ctx, database)
+	if err != nil {
+		return errors.Wrapf(err, "failed to fetch character set of %s", database)
+	}
+
 	// extract tables
 	_, err = e.db.Exec(fmt.Sprintf("USE %s;", database))
 	if err != nil {
 		return errors.Wrapf(err, "failed to iterate over %s", database)
 	}
-	rows, err := e.db.Query("SHOW TABLES;")
+	rows, err := e.retrier.QueryContext(ctx, e.db, "SHOW TABLES;")
 	if err != nil {
 		return errors.Wrapf(err, "failed to show tables of %s", database)
 	}
 
 	// process each rows
+	var tableURNs []interface{}
 	for rows.Next() {
 		var tableName string
 		if err := rows.Scan(&tableName); err != nil {
 			return errors.Wrapf(err, "failed to iterate over %s", tableName)
 		}
+		if e.classifier.ShouldSkip(tableName) {
+			e.logger.Debug("skipping temporary table", "database", database, "table", tableName)
+			continue
+		}
 
-		if err := e.processTable(database, tableName); err != nil {
+		if err := e.processTable(ctx, database, tableName, charset, collation); err != nil {
 			return errors.Wrap(err, "failed to process table")
 		}
+		tableURNs = append(tableURNs, e.tableURN(database, tableName))
+	}
+
+	if e.config.IncludeRoutines {
+		if err := e.extractRoutines(ctx, database); err != nil {
+			e.logger.Warn("failed to fetch routines", "database", database, "error", err)
+		}
+	}
+
+	if e.config.EmitContainers {
+		e.emit(models.NewRecord(&assetsv1beta1.Table{
+			Resource: &commonv1beta1.Resource{
+				Urn:     e.databaseURN(database),
+				Name:    database,
+				Service: "mysql",
+				Type:    "database",
+			},
+			Properties: &facetsv1beta1.Properties{
+				Attributes: utils.TryParseMapToProto(map[string]interface{}{
+					"database_charset":   charset,
+					"database_collation": collation,
+					"tables":             tableURNs,
+				}),
+			},
+		}))
 	}
 
 	return
 }
 
 // processTable builds and push table to emitter
-func (e *Extractor) processTable(database string, tableName string) (err error) {
+func (e *Extractor) processTable(ctx context.Context, database, tableName, charset, collation string) (err error) {
 	var columns []*facetsv1beta1.Column
-	if columns, err = e.extractColumns(tableName); err != nil {
+	if columns, err = e.extractColumns(ctx, database, tableName); err != nil {
 		return errors.Wrap(err, "failed to extract columns")
 	}
+	description, err := e.extractTableComment(ctx, database, tableName)
+	if err != nil {
+		return errors.Wrap(err, "failed to extract table comment")
+	}
+
+	attributes := map[string]interface{}{
+		"database_charset":   charset,
+		"database_collation": collation,
+	}
+	if freshness, ok := e.extractFreshness(ctx, database, tableName, columns); ok {
+		attributes[sqlutil.FreshnessAttribute] = freshness.UTC().Format(time.RFC3339)
+	}
+	if e.config.IncludeIndexes {
+		indexes, err := e.extractIndexes(ctx, database, tableName)
+		if err != nil {
+			e.logger.Warn("failed to fetch indexes", "table", tableName, "error", err)
+		} else if len(indexes) > 0 {
+			attributes["indexes"] = indexes
+		}
+	}
+	if e.config.IncludeSize {
+		size, ok, err := e.extractSize(ctx, database, tableName)
+		if err != nil {
+			e.logger.Warn("failed to fetch size", "table", tableName, "error", err)
+		} else if ok {
+			attributes[sqlutil.SizeAttribute] = size
+		}
+	}
+	var profile *assetsv1beta1.TableProfile
+	if e.config.Mode == sqlutil.ModeProfile {
+		rowCount, err := e.extractRowCount(ctx, database, tableName)
+		if err != nil {
+			e.logger.Warn("failed to count rows", "table", tableName, "error", err)
+		} else {
+			profile = &assetsv1beta1.TableProfile{TotalRows: rowCount}
+		}
+	}
+	var upstreams []*commonv1beta1.Resource
+	if e.config.IncludeForeignKeys {
+		foreignKeys, referenced, err := e.extractForeignKeys(ctx, database, tableName)
+		if err != nil {
+			e.logger.Warn("failed to fetch foreign keys", "table", tableName, "error", err)
+		} else if len(foreignKeys) > 0 {
+			attributes["foreign_keys"] = foreignKeys
+			upstreams = referenced
+		}
+	}
+	if e.config.IncludeViewLineage {
+		referenced, err := e.extractViewUpstreams(ctx, database, tableName)
+		if err != nil {
+			e.logger.Warn("failed to extract lineage from view definition", "table", tableName, "error", err)
+		}
+		upstreams = appendUpstreams(upstreams, referenced...)
+	}
+	var lineage *facetsv1beta1.Lineage
+	if len(upstreams) > 0 {
+		lineage = &facetsv1beta1.Lineage{Upstreams: upstreams}
+	}
 
 	// push table to channel
 	e.emit(models.NewRecord(&assetsv1beta1.Table{
 		Resource: &commonv1beta1.Resource{
-			Urn:  fmt.Sprintf("%s.%s", database, tableName),
-			Name: tableName,
+			Urn:         e.tableURN(database, tableName),
+			Name:        tableName,
+			Description: description,
 		},
 		Schema: &facetsv1beta1.Columns{
 			Columns: columns,
 		},
+		Profile: profile,
+		Lineage: lineage,
+		Properties: e.classifier.Tag(tableName, &facetsv1beta1.Properties{
+			Attributes: utils.TryParseMapToProto(attributes),
+		}),
 	}))
 
 	return
 }
 
+// extractFreshness fetches the latest value of the freshness column configured for the table.
+// Tables without such column are skipped, failures are only logged.
+func (e *Extractor) extractFreshness(ctx context.Context, database, tableName string, columns []*facetsv1beta1.Column) (freshness time.Time, ok bool) {
+	column, configured := e.freshness.Column(tableName)
+	if !configured {
+		return
+	}
+	if !hasColumn(columns, column) {
+		e.logger.Warn("freshness column not found, skipping freshness", "table", tableName, "column", column)
+		return
+	}
+
+	query := fmt.Sprintf("SELECT MAX(%s) FROM %s.%s", quoteIdentifier(column), quoteIdentifier(database), quoteIdentifier(tableName))
+	freshness, ok, err := sqlutil.QueryFreshness(ctx, e.db, query)
+	if err != nil {
+		e.logger.Warn("failed to fetch freshness", "table", tableName, "column", column, "error", err)
+		return freshness, false
+	}
+
+	return
+}
+
+// extractIndexes fetches the indexes of a table with their columns in index order
+func (e *Extractor) extractIndexes(ctx context.Context, database, tableName string) (indexes []interface{}, err error) {
+	query := `SELECT INDEX_NAME, NON_UNIQUE, INDEX_TYPE, COLUMN_NAME
+				FROM information_schema.STATISTICS
+				WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+				ORDER BY INDEX_NAME, SEQ_IN_INDEX`
+	rows, err := e.retrier.QueryContext(ctx, e.db, query, database, tableName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	byName := make(map[string]map[string]interface{})
+	for rows.Next() {
+		var name, indexType string
+		var nonUnique int
+		// functional indexes have no column
+		var column sql.NullString
+		if err = rows.Scan(&name, &nonUnique, &indexType, &column); err != nil {
+			return nil, errors.Wrap(err, "failed to scan index")
+		}
+
+		index, ok := byName[name]
+		if !ok {
+			index = map[string]interface{}{
+				"name":    name,
+				"unique":  nonUnique == 0,
+				"type":    indexType,
+				"columns": []interface{}{},
+			}
+			byName[name] = index
+			indexes = append(indexes, index)
+		}
+		if column.Valid {
+			index["columns"] = append(index["columns"].([]interface{}), column.String)
+		}
+	}
+
+	return indexes, rows.Err()
+}
+
+// extractSize fetches the size of the data and indexes of a table, views having no size
+func (e *Extractor) extractSize(ctx context.Context, database, tableName string) (size int64, ok bool, err error) {
+	query := `SELECT DATA_LENGTH + INDEX_LENGTH
+				FROM information_schema.TABLES
+				WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND TABLE_TYPE = 'BASE TABLE'`
+	rows, err := e.retrier.QueryContext(ctx, e.db, query, database, tableName)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	var length sql.NullInt64
+	if rows.Next() {
+		if err = rows.Scan(&length); err != nil {
+			return 0, false, errors.Wrap(err, "failed to scan size")
+		}
+	}
+
+	return length.Int64, length.Valid, rows.Err()
+}
+
+// extractRowCount counts the rows of a table
+func (e *Extractor) extractRowCount(ctx context.Context, database, tableName string) (rowCount int64, err error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.%s", quoteIdentifier(database), quoteIdentifier(tableName))
+	rows, err := e.retrier.QueryContext(ctx, e.db, query)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&rowCount); err != nil {
+			return 0, errors.Wrap(err, "failed to scan row count")
+		}
+	}
+
+	return rowCount, rows.Err()
+}
+
+// extractForeignKeys fetches the foreign keys of a table along with the referenced tables as upstreams.
+// Referenced tables are identified with the same URN as the tables emitted by the extractor.
+func (e *Extractor) extractForeignKeys(ctx context.Context, database, tableName string) (foreignKeys []interface{}, upstreams []*commonv1beta1.Resource, err error) {
+	query := `SELECT k.CONSTRAINT_NAME, k.COLUMN_NAME, k.REFERENCED_TABLE_SCHEMA,
+				k.REFERENCED_TABLE_NAME, k.REFERENCED_COLUMN_NAME, r.UPDATE_RULE, r.DELETE_RULE
+				FROM information_schema.KEY_COLUMN_USAGE k
+				INNER JOIN information_schema.REFERENTIAL_CONSTRAINTS r ON
+				r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA AND
+				r.CONSTRAINT_NAME = k.CONSTRAINT_NAME AND
+				r.TABLE_NAME = k.TABLE_NAME
+				WHERE k.TABLE_SCHEMA = ? AND k.TABLE_NAME = ?
+				ORDER BY k.CONSTRAINT_NAME, k.ORDINAL_POSITION`
+	rows, err := e.retrier.QueryContext(ctx, e.db, query, database, tableName)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	byName := make(map[string]map[string]interface{})
+	referenced := make(map[string]bool)
+	for rows.Next() {
+		var name, column, refDatabase, refTable, refColumn, updateRule, deleteRule string
+		if err = rows.Scan(&name, &column, &refDatabase, &refTable, &refColumn, &updateRule, &deleteRule); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to scan foreign key")
+		}
+
+		refURN := e.tableURN(refDatabase, refTable)
+		fk, ok := byName[name]
+		if !ok {
+			fk = map[string]interface{}{
+				"name":               name,
+				"columns":            []interface{}{},
+				"referenced_table":   refURN,
+				"referenced_columns": []interface{}{},
+				"on_update":          updateRule,
+				"on_delete":          deleteRule,
+			}
+			byName[name] = fk
+			foreignKeys = append(foreignKeys, fk)
+		}
+		fk["columns"] = append(fk["columns"].([]interface{}), column)
+		fk["referenced_columns"] = append(fk["referenced_columns"].([]interface{}), refColumn)
+
+		if !referenced[refURN] {
+			referenced[refURN] = true
+			upstreams = append(upstreams, &commonv1beta1.Resource{
+				Urn:  refURN,
+				Name: refTable,
+				Type: "table",
+			})
+		}
+	}
+
+	return foreignKeys, upstreams, rows.Err()
+}
+
+// extractViewUpstreams parses the definition of a view for the tables it selects from,
+// unqualified tables belonging to the database of the view. Tables have no definition.
+func (e *Extractor) extractViewUpstreams(ctx context.Context, database, tableName string) (upstreams []*commonv1beta1.Resource, err error) {
+	query := `SELECT VIEW_DEFINITION
+				FROM information_schema.VIEWS
+				WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?`
+	rows, err := e.retrier.QueryContext(ctx, e.db, query, database, tableName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	var definition string
+	if err = rows.Scan(&definition); err != nil {
+		return nil, errors.Wrap(err, "failed to scan view definition")
+	}
+	// the definition is only visible to the definer of the view or with the SHOW VIEW privilege
+	if definition == "" {
+		return nil, errors.New("view definition is not readable")
+	}
+
+	refs, err := sqlutil.ViewReferences(definition, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse view definition")
+	}
+	for _, ref := range refs {
+		refDatabase := ref.Schema
+		if refDatabase == "" {
+			refDatabase = database
+		}
+		upstreams = append(upstreams, &commonv1beta1.Resource{
+			Urn:  e.tableURN(refDatabase, ref.Name),
+			Name: ref.Name,
+			Type: "table",
+		})
+	}
+
+	return upstreams, nil
+}
+
+// extractRoutines emits the stored procedures and functions of a database, the ones
+// the connecting user has no privilege on being left out by information_schema.
+func (e *Extractor) extractRoutines(ctx context.Context, database string) (err error) {
+	query := `SELECT ROUTINE_NAME, ROUTINE_TYPE, IFNULL(DTD_IDENTIFIER, ''), IFNULL(ROUTINE_COMMENT, ''),
+				IFNULL(ROUTINE_DEFINITION, '')
+				FROM information_schema.ROUTINES
+				WHERE ROUTINE_SCHEMA = ?
+				ORDER BY ROUTINE_TYPE, ROUTINE_NAME`
+	rows, err := e.retrier.QueryContext(ctx, e.db, query, database)
+	if err != nil {
+		return errors.Wrap(err, "failed to execute query")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		routine := sqlutil.Routine{Schema: database}
+		var routineType string
+		if err = rows.Scan(&routine.Name, &routineType, &routine.ReturnType, &routine.Description, &routine.Definition); err != nil {
+			return errors.Wrap(err, "failed to scan routine")
+		}
+		routine.Type = sqlutil.RoutineType(routineType)
+		if !e.config.IncludeRoutineDefinitions {
+			routine.Definition = ""
+		}
+
+		urn := sqlutil.RoutineURN("mysql", e.host, database, routine.Type, routine.Name)
+		e.emit(models.NewRecord(routine.Asset(urn, "mysql")))
+	}
+
+	return rows.Err()
+}
+
+// appendUpstreams appends the upstreams whose urn is not in the list yet
+func appendUpstreams(upstreams []*commonv1beta1.Resource, more ...*commonv1beta1.Resource) []*commonv1beta1.Resource {
+	for _, upstream := range more {
+		found := false
+		for _, u := range upstreams {
+			if u.Urn == upstream.Urn {
+				found = true
+				break
+			}
+		}
+		if !found {
+			upstreams = append(upstreams, upstream)
+		}
+	}
+
+	return upstreams
+}
+
+// hasColumn checks if the column is in the list, ignoring case
+func hasColumn(columns []*facetsv1beta1.Column, name string) bool {
+	for _, column := range columns {
+		if strings.EqualFold(column.Name, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// quoteIdentifier quotes a MySQL identifier with backticks
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// extractCharset fetches the default character set and collation of a database
+func (e *Extractor) extractCharset(ctx context.Context, database string) (charset, collation string, err error) {
+	query := `SELECT DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME
+				FROM information_schema.SCHEMATA
+				WHERE SCHEMA_NAME = ?`
+	rows, err := e.retrier.QueryContext(ctx, e.db, query, database)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		err = rows.Scan(&charset, &collation)
+	}
+
+	return
+}
+
+// extractTableComment fetches the comment of a table, views having no comment
+func (e *Extractor) extractTableComment(ctx context.Context, database, tableName string) (comment string, err error) {
+	query := `SELECT IF(TABLE_TYPE = 'VIEW', '', IFNULL(TABLE_COMMENT, ''))
+				FROM information_schema.TABLES
+				WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?`
+	rows, err := e.retrier.QueryContext(ctx, e.db, query, database, tableName)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		err = rows.Scan(&comment)
+	}
+
+	return
+}
+
 // Extract columns from a given table
-func (e *Extractor) extractColumns(tableName string) (columns []*facetsv1beta1.Column, err error) {
-	query := `SELECT COLUMN_NAME,column_comment,DATA_TYPE,
-				IS_NULLABLE,IFNULL(CHARACTER_MAXIMUM_LENGTH,0)
+func (e *Extractor) extractColumns(ctx context.Context, database, tableName string) (columns []*facetsv1beta1.Column, err error) {
+	query := `SELECT COLUMN_NAME,IFNULL(COLUMN_COMMENT,''),DATA_TYPE,
+				IS_NULLABLE,CHARACTER_MAXIMUM_LENGTH
 				FROM information_schema.columns
-				WHERE table_name = ?
+				WHERE table_schema = ? AND table_name = ?
 				ORDER BY COLUMN_NAME ASC`
-	rows, err := e.db.Query(query, tableName)
+	rows, err := e.retrier.QueryContext(ctx, e.db, query, database, tableName)
 	if err != nil {
 		err = errors.Wrap(err, "failed to execute query")
 		return
@@ -180,24 +676,54 @@ func (e *Extractor) extractColumns(tableName string) (columns []*facetsv1beta1.C
 
 	for rows.Next() {
 		var fieldName, fieldDesc, dataType, isNullableString string
-		var length int
+		var length sql.NullInt64
 		if err = rows.Scan(&fieldName, &fieldDesc, &dataType, &isNullableString, &length); err != nil {
 			e.logger.Error("failed to get fields", "error", err)
 			continue
 		}
+		nullable, err := sqlutil.ParseNullable(isNullableString)
+		if err != nil {
+			e.logger.Warn("failed to parse nullable of column", "table", tableName, "column", fieldName, "error", err)
+		}
 
 		columns = append(columns, &facetsv1beta1.Column{
 			Name:        fieldName,
 			DataType:    dataType,
 			Description: fieldDesc,
-			IsNullable:  e.isNullable(isNullableString),
-			Length:      int64(length),
+			IsNullable:  nullable,
+			// lengths of information schemas are in characters
+			Length:     sqlutil.ParseLength(sqlutil.CharLengthSemantics, length, sql.NullInt64{}),
+			Properties: sqlutil.DataTypeProperties("mysql", dataType),
 		})
 	}
 
 	return
 }
 
+// tableURN builds the urn of a table from the urn template, if any
+func (e *Extractor) tableURN(database, tableName string) string {
+	return e.urns.URN(sqlutil.URNFields{
+		Service:  "mysql",
+		Host:     e.host,
+		Database: database,
+		Table:    tableName,
+	})
+}
+
+// databaseURN builds the urn of a database from the urn template, with an empty table
+func (e *Extractor) databaseURN(database string) string {
+	return e.tableURN(database, "")
+}
+
+// defaultURN prefixes the table name by the urn of its database, the database name
+func defaultURN(f sqlutil.URNFields) string {
+	if f.Table == "" {
+		return f.Database
+	}
+
+	return fmt.Sprintf("%s.%s", f.Database, f.Table)
+}
+
 // buildExcludedDBs builds the list of excluded databases
 func (e *Extractor) buildExcludedDBs() {
 	excludedMap := make(map[string]bool)
@@ -214,11 +740,6 @@ func (e *Extractor) isExcludedDB(database string) bool {
 	return ok
 }
 
-// isNullable checks if the given string is null or not
-func (e *Extractor) isNullable(value string) bool {
-	return value == "YES"
-}
-
 // init register the extractor to the catalog
 func init() {
 	if err := registry.Extractors.Register("mysql", func() plugins.Extractor {
@@ -9,8 +9,10 @@ import (
 	"log"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/odpf/meteor/test/utils"
+	meteorutils "github.com/odpf/meteor/utils"
 
 	"database/sql"
 
@@ -25,6 +27,7 @@ import (
 	"github.com/ory/dockertest/v3"
 	"github.com/ory/dockertest/v3/docker"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var db *sql.DB
@@ -87,6 +90,37 @@ func TestInit(t *testing.T) {
 
 		assert.Equal(t, plugins.InvalidConfigError{}, err)
 	})
+
+	t.Run("should return error for invalid connection_retry_interval", func(t *testing.T) {
+		err := mysql.New(utils.Logger).Init(context.TODO(), map[string]interface{}{
+			"connection_url":            fmt.Sprintf("%s:%s@tcp(%s)/", user, pass, host),
+			"connection_retry_interval": "soon",
+		})
+
+		assert.Equal(t, plugins.InvalidConfigError{}, err)
+	})
+
+	t.Run("should retry connecting with deprecated connect_retries", func(t *testing.T) {
+		start := time.Now()
+		err := mysql.New(utils.Logger).Init(context.TODO(), map[string]interface{}{
+			"connection_url":            fmt.Sprintf("%s:%s@tcp(localhost:1)/", user, pass),
+			"connect_retries":           2,
+			"connection_retry_interval": "50ms",
+		})
+
+		assert.Error(t, err)
+		// backoff of 50ms then 100ms before both retries
+		assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+	})
+
+	t.Run("should return error for unknown urn_template placeholder", func(t *testing.T) {
+		err := mysql.New(utils.Logger).Init(context.TODO(), map[string]interface{}{
+			"connection_url": fmt.Sprintf("%s:%s@tcp(%s)/", user, pass, host),
+			"urn_template":   "{database}/{tabel}",
+		})
+
+		assert.Equal(t, plugins.InvalidConfigError{}, err)
+	})
 }
 
 func TestExtract(t *testing.T) {
@@ -107,6 +141,401 @@ func TestExtract(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, getExpected(), emitter.Get())
 	})
+
+	t.Run("should tag tables matching temporary table patterns", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := mysql.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url": fmt.Sprintf("%s:%s@tcp(%s)/", user, pass, host),
+			"temporary_tables": map[string]interface{}{
+				"patterns": []string{"job*"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+
+		assert.NoError(t, err)
+		records := emitter.GetAllData()
+		assert.Len(t, records, 2)
+		assert.Empty(t, records[0].GetProperties().Tags)
+		assert.Equal(t, []string{"temporary"}, records[1].GetProperties().Tags)
+	})
+
+	t.Run("should attach size of tables when include_size is true", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := mysql.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url": fmt.Sprintf("%s:%s@tcp(%s)/", user, pass, host),
+			"include_size":   true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+
+		assert.NoError(t, err)
+		records := emitter.GetAllData()
+		assert.Len(t, records, 2)
+		for _, record := range records {
+			size, ok := meteorutils.GetCustomProperties(record)["size_bytes"].(float64)
+			assert.True(t, ok, record.GetResource().Urn)
+			assert.Greater(t, size, float64(0))
+		}
+	})
+
+	t.Run("should count rows of tables in profile mode", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := mysql.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url": fmt.Sprintf("%s:%s@tcp(%s)/", user, pass, host),
+			"mode":           "profile",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+
+		assert.NoError(t, err)
+		records := emitter.GetAllData()
+		assert.Len(t, records, 2)
+		for _, record := range records {
+			assert.Equal(t, int64(1), record.(*assetsv1beta1.Table).GetProfile().GetTotalRows(), record.GetResource().Urn)
+		}
+	})
+
+	t.Run("should return error for unknown mode", func(t *testing.T) {
+		err := mysql.New(utils.Logger).Init(context.TODO(), map[string]interface{}{
+			"connection_url": fmt.Sprintf("%s:%s@tcp(%s)/", user, pass, host),
+			"mode":           "full",
+		})
+		assert.Equal(t, plugins.InvalidConfigError{}, err)
+	})
+
+	t.Run("should build table urns from urn_template", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := mysql.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url": fmt.Sprintf("%s:%s@tcp(%s)/", user, pass, host),
+			"urn_template":   "{service}::{host}/{database}/{table}",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+
+		assert.NoError(t, err)
+		records := emitter.GetAllData()
+		assert.Len(t, records, 2)
+		assert.Equal(t, fmt.Sprintf("mysql::%s/mockdata_meteor_metadata_test/applicant", host), records[0].GetResource().Urn)
+		assert.Equal(t, fmt.Sprintf("mysql::%s/mockdata_meteor_metadata_test/jobs", host), records[1].GetResource().Urn)
+	})
+
+	t.Run("should build database urns from urn_template", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := mysql.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url":  fmt.Sprintf("%s:%s@tcp(%s)/", user, pass, host),
+			"urn_template":    "{service}::{host}/{database}/{table}",
+			"emit_containers": true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+
+		assert.NoError(t, err)
+		records := emitter.GetAllData()
+		if assert.Len(t, records, 3) {
+			database := records[2].(*assetsv1beta1.Table)
+			assert.Equal(t, fmt.Sprintf("mysql::%s/mockdata_meteor_metadata_test/", host), database.Resource.Urn)
+			assert.Equal(t, []interface{}{
+				fmt.Sprintf("mysql::%s/mockdata_meteor_metadata_test/applicant", host),
+				fmt.Sprintf("mysql::%s/mockdata_meteor_metadata_test/jobs", host),
+			}, database.Properties.Attributes.AsMap()["tables"])
+		}
+	})
+
+	t.Run("should skip tables matching temporary table patterns", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := mysql.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url": fmt.Sprintf("%s:%s@tcp(%s)/", user, pass, host),
+			"temporary_tables": map[string]interface{}{
+				"patterns": []string{"job*"},
+				"skip":     true,
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+
+		assert.NoError(t, err)
+		records := emitter.GetAllData()
+		assert.Len(t, records, 1)
+		assert.Equal(t, "applicant", records[0].GetResource().Name)
+	})
+
+	t.Run("should emit databases holding their tables when emit_containers is true", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := mysql.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url":  fmt.Sprintf("%s:%s@tcp(%s)/", user, pass, host),
+			"emit_containers": true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+
+		assert.NoError(t, err)
+		records := emitter.GetAllData()
+		if assert.Len(t, records, 3) {
+			database := records[2].(*assetsv1beta1.Table)
+			assert.Equal(t, &commonv1beta1.Resource{
+				Urn:     "mockdata_meteor_metadata_test",
+				Name:    "mockdata_meteor_metadata_test",
+				Service: "mysql",
+				Type:    "database",
+			}, database.Resource)
+			assert.Equal(t, map[string]interface{}{
+				"database_charset":   "utf8mb4",
+				"database_collation": "utf8mb4_unicode_ci",
+				"tables": []interface{}{
+					"mockdata_meteor_metadata_test.applicant",
+					"mockdata_meteor_metadata_test.jobs",
+				},
+			}, database.Properties.Attributes.AsMap())
+		}
+	})
+}
+
+func TestExtractFreshness(t *testing.T) {
+	t.Run("should attach freshness to tables with a configured freshness column", func(t *testing.T) {
+		ctx := context.TODO()
+		freshnessDB := "mockdata_meteor_freshness_test"
+		err := execute(db, []string{
+			fmt.Sprintf("CREATE DATABASE %s", freshnessDB),
+			fmt.Sprintf("CREATE TABLE %s.orders (order_id int, updated_at datetime);", freshnessDB),
+			fmt.Sprintf("INSERT INTO %s.orders VALUES (1, '2021-11-01 08:00:00'), (2, '2021-11-02 10:31:39');", freshnessDB),
+			fmt.Sprintf("CREATE TABLE %s.order_items (order_id int);", freshnessDB),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer execute(db, []string{fmt.Sprintf("DROP DATABASE %s", freshnessDB)})
+
+		extr := mysql.New(utils.Logger)
+		err = extr.Init(ctx, map[string]interface{}{
+			"connection_url": fmt.Sprintf("%s:%s@tcp(%s)/", user, pass, host),
+			"freshness": []map[string]interface{}{
+				{"table": "order*", "column": "updated_at"},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+		assert.NoError(t, err)
+
+		attributes := map[string]map[string]interface{}{}
+		for _, record := range emitter.GetAllData() {
+			attributes[record.GetResource().Urn] = record.GetProperties().Attributes.AsMap()
+		}
+		assert.Equal(t, "2021-11-02T10:31:39Z", attributes[freshnessDB+".orders"]["data_freshness"])
+		// missing freshness column is skipped
+		assert.NotContains(t, attributes[freshnessDB+".order_items"], "data_freshness")
+		// tables not matching any pattern are left untouched
+		assert.NotContains(t, attributes["mockdata_meteor_metadata_test.applicant"], "data_freshness")
+	})
+}
+
+func TestExtractKeys(t *testing.T) {
+	t.Run("should attach indexes and foreign keys as lineage when enabled", func(t *testing.T) {
+		ctx := context.TODO()
+		keysDB := "mockdata_meteor_keys_test"
+		err := execute(db, []string{
+			fmt.Sprintf("CREATE DATABASE %s", keysDB),
+			fmt.Sprintf("CREATE TABLE %s.customers (id int PRIMARY KEY, email varchar(255), UNIQUE KEY uq_email (email));", keysDB),
+			fmt.Sprintf(`CREATE TABLE %s.orders (order_id int PRIMARY KEY, customer_id int,
+				CONSTRAINT fk_orders_customer FOREIGN KEY (customer_id) REFERENCES %s.customers (id) ON DELETE CASCADE);`, keysDB, keysDB),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer execute(db, []string{fmt.Sprintf("DROP DATABASE %s", keysDB)})
+
+		extr := mysql.New(utils.Logger)
+		err = extr.Init(ctx, map[string]interface{}{
+			"connection_url":       fmt.Sprintf("%s:%s@tcp(%s)/", user, pass, host),
+			"include_indexes":      true,
+			"include_foreign_keys": true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+		assert.NoError(t, err)
+
+		tables := map[string]*assetsv1beta1.Table{}
+		for _, record := range emitter.GetAllData() {
+			tables[record.GetResource().Urn] = record.(*assetsv1beta1.Table)
+		}
+
+		customers := tables[keysDB+".customers"].Properties.Attributes.AsMap()
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"name": "PRIMARY", "unique": true, "type": "BTREE", "columns": []interface{}{"id"}},
+			map[string]interface{}{"name": "uq_email", "unique": true, "type": "BTREE", "columns": []interface{}{"email"}},
+		}, customers["indexes"])
+		assert.NotContains(t, customers, "foreign_keys")
+		assert.Nil(t, tables[keysDB+".customers"].Lineage)
+
+		orders := tables[keysDB+".orders"]
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{
+				"name":               "fk_orders_customer",
+				"columns":            []interface{}{"customer_id"},
+				"referenced_table":   keysDB + ".customers",
+				"referenced_columns": []interface{}{"id"},
+				"on_update":          "NO ACTION",
+				"on_delete":          "CASCADE",
+			},
+		}, orders.Properties.Attributes.AsMap()["foreign_keys"])
+		assert.Equal(t, []*commonv1beta1.Resource{
+			{Urn: keysDB + ".customers", Name: "customers", Type: "table"},
+		}, orders.Lineage.Upstreams)
+	})
+}
+
+func TestExtractViewLineage(t *testing.T) {
+	t.Run("should attach the tables selected by views as lineage when enabled", func(t *testing.T) {
+		ctx := context.TODO()
+		viewsDB := "mockdata_meteor_views_test"
+		err := execute(db, []string{
+			fmt.Sprintf("CREATE DATABASE %s", viewsDB),
+			fmt.Sprintf("CREATE TABLE %s.customers (id int PRIMARY KEY, name varchar(255));", viewsDB),
+			fmt.Sprintf("CREATE TABLE %s.orders (order_id int PRIMARY KEY, customer_id int);", viewsDB),
+			fmt.Sprintf(`CREATE VIEW %s.customer_orders AS SELECT c.name, o.order_id
+				FROM orders o JOIN customers c ON o.customer_id = c.id;`, viewsDB),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer execute(db, []string{fmt.Sprintf("DROP DATABASE %s", viewsDB)})
+
+		extr := mysql.New(utils.Logger)
+		err = extr.Init(ctx, map[string]interface{}{
+			"connection_url":       fmt.Sprintf("%s:%s@tcp(%s)/", user, pass, host),
+			"include_view_lineage": true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+		assert.NoError(t, err)
+
+		tables := map[string]*assetsv1beta1.Table{}
+		for _, record := range emitter.GetAllData() {
+			tables[record.GetResource().Urn] = record.(*assetsv1beta1.Table)
+		}
+
+		assert.Nil(t, tables[viewsDB+".orders"].Lineage)
+		assert.Equal(t, []*commonv1beta1.Resource{
+			{Urn: viewsDB + ".orders", Name: "orders", Type: "table"},
+			{Urn: viewsDB + ".customers", Name: "customers", Type: "table"},
+		}, tables[viewsDB+".customer_orders"].Lineage.Upstreams)
+	})
+}
+
+func TestExtractRoutines(t *testing.T) {
+	t.Run("should emit the procedures and functions of databases as jobs when enabled", func(t *testing.T) {
+		ctx := context.TODO()
+		routinesDB := "mockdata_meteor_routines_test"
+		err := execute(db, []string{
+			fmt.Sprintf("CREATE DATABASE %s", routinesDB),
+			fmt.Sprintf("CREATE TABLE %s.orders (order_id int PRIMARY KEY, total decimal(12,2));", routinesDB),
+			fmt.Sprintf(`CREATE PROCEDURE %s.purge_orders() COMMENT 'Delete all orders' DELETE FROM orders;`, routinesDB),
+			fmt.Sprintf(`CREATE FUNCTION %s.with_tax(total decimal(12,2)) RETURNS decimal(12,2) DETERMINISTIC RETURN total * 1.2;`, routinesDB),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer execute(db, []string{fmt.Sprintf("DROP DATABASE %s", routinesDB)})
+
+		extr := mysql.New(utils.Logger)
+		err = extr.Init(ctx, map[string]interface{}{
+			"connection_url":              fmt.Sprintf("%s:%s@tcp(%s)/", user, pass, host),
+			"include_routines":            true,
+			"include_routine_definitions": true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+		assert.NoError(t, err)
+
+		jobs := map[string]*assetsv1beta1.Job{}
+		for _, record := range emitter.GetAllData() {
+			if job, ok := record.(*assetsv1beta1.Job); ok {
+				jobs[job.Resource.Urn] = job
+			}
+		}
+		require.Len(t, jobs, 2)
+
+		procedure := jobs[fmt.Sprintf("mysql::%s/%s/procedure/purge_orders", host, routinesDB)]
+		require.NotNil(t, procedure)
+		assert.Equal(t, &commonv1beta1.Resource{
+			Urn:         procedure.Resource.Urn,
+			Name:        "purge_orders",
+			Service:     "mysql",
+			Type:        "procedure",
+			Description: "Delete all orders",
+		}, procedure.Resource)
+		assert.Equal(t, map[string]interface{}{
+			"schema":     routinesDB,
+			"definition": "DELETE FROM orders",
+		}, procedure.Properties.Attributes.AsMap())
+
+		function := jobs[fmt.Sprintf("mysql::%s/%s/function/with_tax", host, routinesDB)]
+		require.NotNil(t, function)
+		assert.Equal(t, "function", function.Resource.Type)
+		assert.Equal(t, map[string]interface{}{
+			"schema":      routinesDB,
+			"return_type": "decimal(12,2)",
+			"definition":  "RETURN total * 1.2",
+		}, function.Properties.Attributes.AsMap())
+	})
 }
 
 func setup() (err error) {
@@ -115,7 +544,7 @@ func setup() (err error) {
 	// create database, user and grant access
 	err = execute(db, []string{
 		fmt.Sprintf("DROP DATABASE IF EXISTS %s", testDB),
-		fmt.Sprintf("CREATE DATABASE %s", testDB),
+		fmt.Sprintf("CREATE DATABASE %s CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci", testDB),
 		fmt.Sprintf("USE %s;", testDB),
 		fmt.Sprintf(`CREATE USER IF NOT EXISTS '%s'@'%%' IDENTIFIED BY '%s';`, user, pass),
 		fmt.Sprintf(`GRANT ALL PRIVILEGES ON *.* TO '%s'@'%%';`, user),
@@ -126,7 +555,7 @@ func setup() (err error) {
 
 	// create and populate tables
 	err = execute(db, []string{
-		"CREATE TABLE applicant (applicant_id int, last_name varchar(255), first_name varchar(255));",
+		"CREATE TABLE applicant (applicant_id int, last_name varchar(255), first_name varchar(255) COMMENT 'Given name of the applicant') COMMENT='Job applicants';",
 		"INSERT INTO applicant VALUES (1, 'test1', 'test11');",
 		"CREATE TABLE jobs (job_id int, job varchar(255), department varchar(255));",
 		"INSERT INTO jobs VALUES (2, 'test2', 'test22');",
@@ -152,8 +581,15 @@ func getExpected() []models.Record {
 	return []models.Record{
 		models.NewRecord(&assetsv1beta1.Table{
 			Resource: &commonv1beta1.Resource{
-				Urn:  "mockdata_meteor_metadata_test.applicant",
-				Name: "applicant",
+				Urn:         "mockdata_meteor_metadata_test.applicant",
+				Name:        "applicant",
+				Description: "Job applicants",
+			},
+			Properties: &facetsv1beta1.Properties{
+				Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+					"database_charset":   "utf8mb4",
+					"database_collation": "utf8mb4_unicode_ci",
+				}),
 			},
 			Schema: &facetsv1beta1.Columns{
 				Columns: []*facetsv1beta1.Column{
@@ -163,13 +599,23 @@ func getExpected() []models.Record {
 						Description: "",
 						IsNullable:  true,
 						Length:      0,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "integer",
+							}),
+						},
 					},
 					{
 						Name:        "first_name",
 						DataType:    "varchar",
-						Description: "",
+						Description: "Given name of the applicant",
 						IsNullable:  true,
 						Length:      255,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "string",
+							}),
+						},
 					},
 					{
 						Name:        "last_name",
@@ -177,6 +623,11 @@ func getExpected() []models.Record {
 						Description: "",
 						IsNullable:  true,
 						Length:      255,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "string",
+							}),
+						},
 					},
 				},
 			},
@@ -186,6 +637,12 @@ func getExpected() []models.Record {
 				Urn:  "mockdata_meteor_metadata_test.jobs",
 				Name: "jobs",
 			},
+			Properties: &facetsv1beta1.Properties{
+				Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+					"database_charset":   "utf8mb4",
+					"database_collation": "utf8mb4_unicode_ci",
+				}),
+			},
 			Schema: &facetsv1beta1.Columns{
 				Columns: []*facetsv1beta1.Column{
 					{
@@ -194,6 +651,11 @@ func getExpected() []models.Record {
 						Description: "",
 						IsNullable:  true,
 						Length:      255,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "string",
+							}),
+						},
 					},
 					{
 						Name:        "job",
@@ -201,6 +663,11 @@ func getExpected() []models.Record {
 						Description: "",
 						IsNullable:  true,
 						Length:      255,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "string",
+							}),
+						},
 					},
 					{
 						Name:        "job_id",
@@ -208,6 +675,11 @@ func getExpected() []models.Record {
 						Description: "",
 						IsNullable:  true,
 						Length:      0,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "integer",
+							}),
+						},
 					},
 				},
 			},
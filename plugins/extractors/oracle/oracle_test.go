@@ -15,6 +15,7 @@ import (
 	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
 	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
 	"github.com/odpf/meteor/test/utils"
+	meteorutils "github.com/odpf/meteor/utils"
 
 	"database/sql"
 
@@ -30,6 +31,7 @@ import (
 var db *sql.DB
 
 const (
+	orderUser = "test_order_user"
 	user      = "test_user"
 	password  = "oracle"
 	port      = "1521"
@@ -86,6 +88,22 @@ func TestInit(t *testing.T) {
 	})
 }
 
+func TestHealthCheck(t *testing.T) {
+	t.Run("should ping the database", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := oracle.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url": fmt.Sprintf("oracle://%s:%s@%s/%s", user, password, host, defaultDB),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.NoError(t, extr.HealthCheck(ctx))
+	})
+}
+
 func TestExtract(t *testing.T) {
 	t.Run("should return mockdata we generated with oracle", func(t *testing.T) {
 		ctx := context.TODO()
@@ -104,6 +122,184 @@ func TestExtract(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, getExpected(), emitter.Get())
 	})
+
+	t.Run("should include views when include_views is true", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := oracle.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url": fmt.Sprintf("oracle://%s:%s@%s/%s", sysUser, password, host, defaultDB),
+			"schemas":        []string{user},
+			"include_views":  true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+		assert.NoError(t, err)
+
+		var urns []string
+		for _, d := range emitter.GetAllData() {
+			urns = append(urns, d.GetResource().Urn)
+		}
+		assert.ElementsMatch(t, []string{
+			"XE.TEST_USER.EMPLOYEE",
+			"XE.TEST_USER.DEPARTMENT",
+			"XE.TEST_USER.HIGH_EARNERS",
+		}, urns)
+	})
+
+	t.Run("should skip partitions of non-partitioned tables", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := oracle.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url":     fmt.Sprintf("oracle://%s:%s@%s/%s", user, password, host, defaultDB),
+			"include_partitions": true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+
+		assert.NoError(t, err)
+		assert.Equal(t, getExpected(), emitter.Get())
+	})
+
+	t.Run("should attach size of tables when include_size is true", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := oracle.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url": fmt.Sprintf("oracle://%s:%s@%s/%s", user, password, host, defaultDB),
+			"include_size":   true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+
+		assert.NoError(t, err)
+		records := emitter.GetAllData()
+		assert.NotEmpty(t, records)
+		for _, record := range records {
+			size, ok := record.GetProperties().GetAttributes().AsMap()["size_bytes"].(float64)
+			assert.True(t, ok, record.GetResource().Urn)
+			assert.Greater(t, size, float64(0))
+		}
+	})
+
+	t.Run("should quote mixed-case and reserved-word table names", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := oracle.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url": fmt.Sprintf("oracle://%s:%s@%s/%s", sysUser, password, host, defaultDB),
+			"schemas":        []string{orderUser},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+		assert.NoError(t, err)
+
+		data := emitter.GetAllData()
+		if assert.Len(t, data, 1) {
+			table := data[0].(*assetsv1beta1.Table)
+			assert.Equal(t, "XE.TEST_ORDER_USER.Order", table.Resource.Urn)
+			assert.Equal(t, int64(2), table.Profile.TotalRows)
+			assert.Len(t, table.Schema.Columns, 1)
+		}
+	})
+
+	t.Run("should emit procedures and functions as jobs when include_routines is true", func(t *testing.T) {
+		ctx := context.TODO()
+		for _, query := range []string{
+			fmt.Sprintf("CREATE OR REPLACE PROCEDURE %s.raise_salary AS BEGIN NULL; END;", user),
+			fmt.Sprintf("CREATE OR REPLACE FUNCTION %s.with_bonus(salary NUMBER) RETURN NUMBER AS BEGIN RETURN salary * 1.1; END;", user),
+		} {
+			if _, err := db.Exec(query); err != nil {
+				t.Fatal(err)
+			}
+		}
+		defer db.Exec(fmt.Sprintf("DROP PROCEDURE %s.raise_salary", user))
+		defer db.Exec(fmt.Sprintf("DROP FUNCTION %s.with_bonus", user))
+
+		extr := oracle.New(utils.Logger)
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url":              fmt.Sprintf("oracle://%s:%s@%s/%s", sysUser, password, host, defaultDB),
+			"schemas":                     []string{user},
+			"include_routines":            true,
+			"include_routine_definitions": true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+		assert.NoError(t, err)
+
+		var jobs []*assetsv1beta1.Job
+		for _, d := range emitter.GetAllData() {
+			if job, ok := d.(*assetsv1beta1.Job); ok {
+				jobs = append(jobs, job)
+			}
+		}
+		if assert.Len(t, jobs, 2) {
+			assert.Equal(t, "oracle::"+host+"/XE.TEST_USER/function/WITH_BONUS", jobs[0].Resource.Urn)
+			assert.Equal(t, "function", jobs[0].Resource.Type)
+			assert.Equal(t, "oracle::"+host+"/XE.TEST_USER/procedure/RAISE_SALARY", jobs[1].Resource.Urn)
+			assert.Equal(t, "procedure", jobs[1].Resource.Type)
+
+			attributes := jobs[1].Properties.Attributes.AsMap()
+			assert.Equal(t, "TEST_USER", attributes["schema"])
+			assert.Contains(t, attributes["definition"], "BEGIN NULL; END;")
+		}
+	})
+
+	t.Run("should emit database and schemas when emit_containers is true", func(t *testing.T) {
+		ctx := context.TODO()
+		extr := oracle.New(utils.Logger)
+
+		err := extr.Init(ctx, map[string]interface{}{
+			"connection_url":  fmt.Sprintf("oracle://%s:%s@%s/%s", user, password, host, defaultDB),
+			"emit_containers": true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(ctx, emitter.Push)
+		assert.NoError(t, err)
+
+		data := emitter.GetAllData()
+		if assert.Len(t, data, 4) {
+			schema := data[2].(*assetsv1beta1.Table)
+			assert.Equal(t, "XE.TEST_USER", schema.Resource.Urn)
+			assert.Equal(t, "schema", schema.Resource.Type)
+			assert.Equal(t, map[string]interface{}{
+				"database": "XE",
+				"tables":   []interface{}{"XE.TEST_USER.EMPLOYEE", "XE.TEST_USER.DEPARTMENT"},
+			}, schema.Properties.Attributes.AsMap())
+
+			database := data[3].(*assetsv1beta1.Table)
+			assert.Equal(t, "XE", database.Resource.Urn)
+			assert.Equal(t, "database", database.Resource.Type)
+			assert.Equal(t, map[string]interface{}{
+				"schemas": []interface{}{"XE.TEST_USER"},
+			}, database.Properties.Attributes.AsMap())
+		}
+	})
 }
 
 func setup() (err error) {
@@ -112,6 +308,11 @@ func setup() (err error) {
 		fmt.Sprintf("CREATE USER %s IDENTIFIED BY %s", user, password),
 		fmt.Sprintf("GRANT CREATE SESSION TO %s", user),
 		fmt.Sprintf("GRANT DBA TO %s", user),
+		fmt.Sprintf("CREATE USER %s IDENTIFIED BY %s", orderUser, password),
+		fmt.Sprintf("GRANT UNLIMITED TABLESPACE TO %s", orderUser),
+		fmt.Sprintf(`CREATE TABLE %s."Order" (id integer primary key)`, orderUser),
+		fmt.Sprintf(`INSERT INTO %s."Order" values(1)`, orderUser),
+		fmt.Sprintf(`INSERT INTO %s."Order" values(2)`, orderUser),
 	}
 	err = execute(db, queries)
 
@@ -125,6 +326,7 @@ func setup() (err error) {
 		"CREATE TABLE employee (empid integer primary key, name varchar2(30) NOT NULL, salary number(10, 2))",
 		"CREATE TABLE department (id integer primary key, title varchar(20) NOT NULL, budget float(26))",
 		"COMMENT ON column department.title IS 'Department Name'",
+		"CREATE VIEW high_earners AS SELECT empid, name FROM employee WHERE salary > 50000",
 	}
 
 	var populateTables = []string{
@@ -157,10 +359,16 @@ func getExpected() []models.Record {
 	return []models.Record{
 		models.NewRecord(&assetsv1beta1.Table{
 			Resource: &commonv1beta1.Resource{
-				Urn:     "XE.EMPLOYEE",
+				Urn:     "XE.TEST_USER.EMPLOYEE",
 				Name:    "EMPLOYEE",
 				Service: "Oracle",
 			},
+			Properties: &facetsv1beta1.Properties{
+				Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+					"schema":      "TEST_USER",
+					"object_type": "TABLE",
+				}),
+			},
 			Profile: &assetsv1beta1.TableProfile{
 				TotalRows: 3,
 			},
@@ -170,27 +378,48 @@ func getExpected() []models.Record {
 						Name:     "EMPID",
 						DataType: "NUMBER",
 						Length:   22,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "decimal",
+							}),
+						},
 					},
 					{
 						Name:     "NAME",
 						DataType: "VARCHAR2",
 						Length:   30,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "string",
+							}),
+						},
 					},
 					{
 						Name:       "SALARY",
 						DataType:   "NUMBER",
 						IsNullable: true,
 						Length:     22,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "decimal",
+							}),
+						},
 					},
 				},
 			},
 		}),
 		models.NewRecord(&assetsv1beta1.Table{
 			Resource: &commonv1beta1.Resource{
-				Urn:     "XE.DEPARTMENT",
+				Urn:     "XE.TEST_USER.DEPARTMENT",
 				Name:    "DEPARTMENT",
 				Service: "Oracle",
 			},
+			Properties: &facetsv1beta1.Properties{
+				Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+					"schema":      "TEST_USER",
+					"object_type": "TABLE",
+				}),
+			},
 			Profile: &assetsv1beta1.TableProfile{
 				TotalRows: 4,
 			},
@@ -200,18 +429,33 @@ func getExpected() []models.Record {
 						Name:     "ID",
 						DataType: "NUMBER",
 						Length:   22,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "decimal",
+							}),
+						},
 					},
 					{
 						Name:        "TITLE",
 						Description: "Department Name",
 						DataType:    "VARCHAR2",
 						Length:      20,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "string",
+							}),
+						},
 					},
 					{
 						Name:       "BUDGET",
 						DataType:   "FLOAT",
 						IsNullable: true,
 						Length:     22,
+						Properties: &facetsv1beta1.Properties{
+							Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+								"normalized_data_type": "float",
+							}),
+						},
 					},
 				},
 			},
@@ -18,6 +18,7 @@ import (
 	_ "github.com/odpf/meteor/plugins/extractors/mysql"
 	_ "github.com/odpf/meteor/plugins/extractors/optimus"
 	_ "github.com/odpf/meteor/plugins/extractors/postgres"
+	_ "github.com/odpf/meteor/plugins/extractors/s3"
 	_ "github.com/odpf/meteor/plugins/extractors/superset"
 	_ "github.com/odpf/meteor/plugins/extractors/tableau"
 	_ "github.com/odpf/meteor/plugins/extractors/oracle"
@@ -109,6 +109,43 @@ func TestExtract(t *testing.T) {
 	})
 }
 
+func TestExtractReplication(t *testing.T) {
+	t.Run("should attach publications to published tables when include_replication is true", func(t *testing.T) {
+		ctx := context.TODO()
+		userDB, err := sql.Open("postgres", fmt.Sprintf("postgres://%s:%s@%s/test_db?sslmode=disable", user, pass, host))
+		require.NoError(t, err)
+		defer userDB.Close()
+		require.NoError(t, execute(userDB, []string{
+			"CREATE PUBLICATION articles_cdc FOR TABLE article WITH (publish = 'insert, update');",
+		}))
+		defer execute(userDB, []string{"DROP PUBLICATION articles_cdc;"})
+
+		extr := postgres.New(utils.Logger)
+		err = extr.Init(ctx, map[string]interface{}{
+			"connection_url":      fmt.Sprintf("postgres://%s:%s@%s/postgres?sslmode=disable", user, pass, host),
+			"include_replication": true,
+		})
+		require.NoError(t, err)
+
+		emitter := mocks.NewEmitter()
+		require.NoError(t, extr.Extract(ctx, emitter.Push))
+
+		tables := map[string]*assetsv1beta1.Table{}
+		for _, record := range emitter.Get() {
+			table := record.Data().(*assetsv1beta1.Table)
+			tables[table.Resource.Name] = table
+		}
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{
+				"name":       "articles_cdc",
+				"all_tables": false,
+				"operations": []interface{}{"insert", "update"},
+			},
+		}, tables["article"].Properties.Attributes.AsMap()["publications"])
+		assert.Nil(t, tables["post"].Properties)
+	})
+}
+
 func setup() (err error) {
 	testDB := "test_db"
 
@@ -0,0 +1,134 @@
+//go:build plugins
+// +build plugins
+
+package s3_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/extractors/s3"
+	"github.com/odpf/meteor/test/mocks"
+	"github.com/odpf/meteor/test/utils"
+	meteorutils "github.com/odpf/meteor/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const listObjectsResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Name>data-lake</Name>
+  <Prefix>warehouse/</Prefix>
+  <KeyCount>3</KeyCount>
+  <IsTruncated>false</IsTruncated>
+  <Contents><Key>warehouse/orders/dt=2021-01-01/part-0.parquet</Key><LastModified>2021-01-01T00:00:00.000Z</LastModified><Size>100</Size></Contents>
+  <Contents><Key>warehouse/orders/dt=2021-01-02/part-0.parquet</Key><LastModified>2021-01-02T00:00:00.000Z</LastModified><Size>200</Size></Contents>
+  <Contents><Key>warehouse/users/users.csv</Key><LastModified>2021-01-03T00:00:00.000Z</LastModified><Size>50</Size></Contents>
+</ListBucketResult>`
+
+func TestInit(t *testing.T) {
+	t.Run("should return error if no region in config", func(t *testing.T) {
+		err := s3.New(utils.Logger).Init(context.TODO(), map[string]interface{}{
+			"buckets": []string{"data-lake"},
+		})
+
+		assert.Equal(t, plugins.InvalidConfigError{}, err)
+	})
+
+	t.Run("should return error if secret access key is missing", func(t *testing.T) {
+		err := s3.New(utils.Logger).Init(context.TODO(), map[string]interface{}{
+			"region":        "ap-southeast-1",
+			"access_key_id": "key",
+		})
+
+		assert.Equal(t, plugins.InvalidConfigError{}, err)
+	})
+}
+
+func TestExtract(t *testing.T) {
+	t.Run("should emit a table for each dataset", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.URL.Path == "/data-lake" || r.URL.Path == "/data-lake/":
+				assert.Equal(t, "warehouse/", r.URL.Query().Get("prefix"))
+				fmt.Fprint(w, listObjectsResponse)
+			case r.URL.Path == "/data-lake/warehouse/users/users.csv":
+				assert.NotEmpty(t, r.Header.Get("Range"))
+				fmt.Fprint(w, "id,name,email\n1,a,a@example.com\n")
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		extr := s3.New(utils.Logger)
+		err := extr.Init(context.TODO(), map[string]interface{}{
+			"region":            "ap-southeast-1",
+			"access_key_id":     "key",
+			"secret_access_key": "secret",
+			"endpoint":          server.URL,
+			"buckets":           []string{"data-lake"},
+			"prefix":            "warehouse/",
+			"infer_schema":      true,
+		})
+		require.NoError(t, err)
+
+		emitter := mocks.NewEmitter()
+		err = extr.Extract(context.TODO(), emitter.Push)
+		require.NoError(t, err)
+
+		assert.Equal(t, []models.Record{
+			models.NewRecord(&assetsv1beta1.Table{
+				Resource: &commonv1beta1.Resource{
+					Urn:     "s3://data-lake/warehouse/orders/",
+					Name:    "warehouse/orders",
+					Service: "s3",
+				},
+				Properties: &facetsv1beta1.Properties{
+					Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+						"bucket":           "data-lake",
+						"prefix":           "warehouse/orders/",
+						"object_count":     2,
+						"total_size_bytes": 300,
+						"format":           "parquet",
+					}),
+				},
+				Timestamps: &commonv1beta1.Timestamp{
+					UpdateTime: timestamppb.New(time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)),
+				},
+			}),
+			models.NewRecord(&assetsv1beta1.Table{
+				Resource: &commonv1beta1.Resource{
+					Urn:     "s3://data-lake/warehouse/users/",
+					Name:    "warehouse/users",
+					Service: "s3",
+				},
+				Schema: &facetsv1beta1.Columns{
+					Columns: []*facetsv1beta1.Column{{Name: "id"}, {Name: "name"}, {Name: "email"}},
+				},
+				Properties: &facetsv1beta1.Properties{
+					Attributes: meteorutils.TryParseMapToProto(map[string]interface{}{
+						"bucket":           "data-lake",
+						"prefix":           "warehouse/users/",
+						"object_count":     1,
+						"total_size_bytes": 50,
+						"format":           "csv",
+					}),
+				},
+				Timestamps: &commonv1beta1.Timestamp{
+					UpdateTime: timestamppb.New(time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC)),
+				},
+			}),
+		}, emitter.Get())
+	})
+}
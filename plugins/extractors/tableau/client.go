@@ -194,13 +194,7 @@ func (c *client) GetDetailedWorkbooksByProjectName(ctx context.Context, projectN
 
 func (c *client) getAuthToken() (authToken string, siteID string, err error) {
 	payload := map[string]interface{}{
-		"credentials": map[string]interface{}{
-			"name":     c.config.Username,
-			"password": c.config.Password,
-			"site": map[string]interface{}{
-				"contentUrl": c.config.Sitename,
-			},
-		},
+		"credentials": c.buildCredentials(),
 	}
 
 	var data responseSignIn
@@ -212,6 +206,26 @@ func (c *client) getAuthToken() (authToken string, siteID string, err error) {
 	return data.Credentials.Token, data.Credentials.Site.ID, nil
 }
 
+// buildCredentials prefers personal access token over username and password
+func (c *client) buildCredentials() map[string]interface{} {
+	site := map[string]interface{}{
+		"contentUrl": c.config.Sitename,
+	}
+	if c.config.AuthTokenName != "" {
+		return map[string]interface{}{
+			"personalAccessTokenName":   c.config.AuthTokenName,
+			"personalAccessTokenSecret": c.config.AuthTokenSecret,
+			"site":                      site,
+		}
+	}
+
+	return map[string]interface{}{
+		"name":     c.config.Username,
+		"password": c.config.Password,
+		"site":     site,
+	}
+}
+
 func (c *client) buildURL(path string) string {
 	return fmt.Sprintf("%s/api/%s/%s", c.config.Host, c.config.Version, path)
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/dnaeon/go-vcr/v2/recorder"
@@ -51,6 +52,30 @@ func TestInit(t *testing.T) {
 		assert.EqualError(t, err, "failed to fetch auth token: failed to generate response: Post \"invalidhost/api/3.12/auth/signin\": unsupported protocol scheme \"\"")
 	})
 
+	t.Run("initializing client with personal access token", func(t *testing.T) {
+		var payload map[string]map[string]interface{}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatal(err)
+			}
+			_, _ = w.Write([]byte(`{"credentials":{"site":{"id":"site-id"},"token":"auth-token"}}`))
+		}))
+		defer ts.Close()
+
+		cl := NewClient(nil)
+		err := cl.Init(context.TODO(), Config{
+			Host:            ts.URL,
+			Version:         "3.12",
+			AuthTokenName:   "meteor_token",
+			AuthTokenSecret: "xxxxxxxxxx",
+			Sitename:        "testdev550928",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "meteor_token", payload["credentials"]["personalAccessTokenName"])
+		assert.Equal(t, "xxxxxxxxxx", payload["credentials"]["personalAccessTokenSecret"])
+		assert.NotContains(t, payload["credentials"], "name")
+	})
+
 }
 
 func TestGetAllProjects(t *testing.T) {
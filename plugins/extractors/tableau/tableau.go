@@ -27,15 +27,20 @@ version: 3.12
 username: meteor_user
 password: xxxxxxxxxx
 sitename: testdev550928
+# personal access token can be used instead of username and password
+# auth_token_name: meteor_token
+# auth_token_secret: xxxxxxxxxx
 `
 
 // Config that holds a set of configuration for tableau extractor
 type Config struct {
-	Host     string `mapstructure:"host" validate:"required"`
-	Version  string `mapstructure:"version" validate:"required"` // float as string
-	Username string `mapstructure:"username" validate:"required"`
-	Password string `mapstructure:"password" validate:"required"`
-	Sitename string `mapstructure:"sitename"`
+	Host            string `mapstructure:"host" validate:"required"`
+	Version         string `mapstructure:"version" validate:"required"` // float as string
+	Username        string `mapstructure:"username" validate:"required_without=AuthTokenName"`
+	Password        string `mapstructure:"password" validate:"required_without=AuthTokenName"`
+	AuthTokenName   string `mapstructure:"auth_token_name"`
+	AuthTokenSecret string `mapstructure:"auth_token_secret" validate:"required_with=AuthTokenName"`
+	Sitename        string `mapstructure:"sitename"`
 }
 
 // Extractor manages the extraction of data
@@ -71,11 +76,17 @@ func New(logger log.Logger, opts ...Option) *Extractor {
 	return e
 }
 
+// SetLogger sets the logger of the extractor
+func (e *Extractor) SetLogger(logger log.Logger) {
+	e.logger = logger
+}
+
 // Info returns the brief information of the extractor
 func (e *Extractor) Info() plugins.Info {
 	return plugins.Info{
 		Description:  "Dashboard list from Tableau server",
 		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
 		Summary:      summary,
 		Tags:         []string{"oss", "extractor"},
 	}
@@ -1,11 +1,26 @@
 package plugins
 
-import "github.com/odpf/salt/log"
+import (
+	"fmt"
+
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// LogLevelKey is the key of plugin configs overriding the level of the plugin logger, e.g. "debug"
+const LogLevelKey = "log_level"
 
 var (
 	logger log.Logger = log.NewLogrus(log.LogrusWithLevel("INFO"))
 )
 
+// LoggerSetter is an optional interface a plugin can implement to log with a logger of its own,
+// e.g. one logging at the level of its config LogLevelKey. SetLogger will be called before Init.
+type LoggerSetter interface {
+	SetLogger(logger log.Logger)
+}
+
 // GetLog returns the logger
 func GetLog() log.Logger {
 	return logger
@@ -15,3 +30,33 @@ func GetLog() log.Logger {
 func SetLog(l log.Logger) {
 	logger = l
 }
+
+// LogLevel returns the level of the plugin config LogLevelKey, empty if it is not set.
+func LogLevel(config map[string]interface{}) (string, error) {
+	value, ok := config[LogLevelKey]
+	if !ok {
+		return "", nil
+	}
+	level := fmt.Sprint(value)
+	if _, err := logrus.ParseLevel(level); err != nil {
+		return "", errors.Errorf("invalid log level \"%s\"", level)
+	}
+
+	return level, nil
+}
+
+// WithoutLogLevel returns the plugin config without its LogLevelKey, which is read by the agent
+// and not by the plugin. The config is returned as is if it has no log level.
+func WithoutLogLevel(config map[string]interface{}) map[string]interface{} {
+	if _, ok := config[LogLevelKey]; !ok {
+		return config
+	}
+	stripped := make(map[string]interface{}, len(config)-1)
+	for key, value := range config {
+		if key != LogLevelKey {
+			stripped[key] = value
+		}
+	}
+
+	return stripped
+}
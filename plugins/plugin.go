@@ -25,6 +25,8 @@ type Info struct {
 	SampleConfig string   `yaml:"sample_config"`
 	Tags         []string `yaml:"tags"`
 	Summary      string   `yaml:"summary"`
+	// ConfigSchema describes the config options, see ConfigSchema
+	ConfigSchema JSONSchema `yaml:"config_schema,omitempty"`
 }
 
 type Plugin interface {
@@ -46,11 +48,40 @@ type Extractor interface {
 }
 
 // Processor are the functions that are executed on the extracted data.
+// Process may be called concurrently when the agent runs processors across several workers,
+// processors holding state across records must guard it.
 type Processor interface {
 	Plugin
 	Process(ctx context.Context, src models.Record) (dst models.Record, err error)
 }
 
+// RunHook is an optional interface a Processor can implement to be notified
+// once per run instead of once per record. This is useful for loading shared
+// data (e.g. a team directory) before any record is processed.
+type RunHook interface {
+	// OnRunStart will be called once after Init and before any record is processed.
+	OnRunStart(ctx context.Context) error
+
+	// OnRunEnd will be called once after all records have been processed.
+	OnRunEnd(ctx context.Context) error
+}
+
+// Flusher is an optional interface a Processor can implement to hold records
+// back, e.g. by dropping them in Process, and release them once the extractor is done.
+type Flusher interface {
+	// Flush will be called once after the extractor is done, the returned records
+	// are processed by the processors set up after this one and sent to the sinks.
+	Flush(ctx context.Context) ([]models.Record, error)
+}
+
+// HealthChecker is an optional interface an Extractor can implement to verify
+// it can reach its source, e.g. the network connection and the credentials,
+// without extracting anything. An extractor also implementing io.Closer is closed after its health check.
+type HealthChecker interface {
+	// HealthCheck will be called after Init, in place of Extract.
+	HealthCheck(ctx context.Context) error
+}
+
 // Syncer is a plugin that can be used to sync data from one source to another.
 type Syncer interface {
 	Plugin
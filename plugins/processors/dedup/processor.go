@@ -0,0 +1,166 @@
+package dedup
+
+import (
+	"container/list"
+	"context"
+	_ "embed"
+	"sync"
+
+	"github.com/odpf/meteor/models"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+)
+
+//go:embed README.md
+var summary string
+
+const (
+	// KeepFirst emits the first occurrence of an urn and drops the following ones
+	KeepFirst = "first"
+	// KeepLast holds records back and emits the last occurrence of an urn once the extractor is done
+	KeepLast = "last"
+)
+
+// Config holds the set of configuration for the dedup processor
+type Config struct {
+	Keep string `mapstructure:"keep" validate:"oneof=first last" default:"first"`
+	// Size limits the number of urns tracked, the least recently seen urn being evicted, unbounded if 0
+	Size int `mapstructure:"size" validate:"gte=0"`
+}
+
+var sampleConfig = `
+# which occurrence of an urn is kept, either first or last
+keep: first
+# maximum number of urns tracked, unbounded if 0
+size: 100000`
+
+type entry struct {
+	urn    string
+	record models.Record
+}
+
+// Processor drops records with an urn already seen in the run
+type Processor struct {
+	config  Config
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	logger  log.Logger
+}
+
+// New create a new processor
+func New(logger log.Logger) *Processor {
+	return &Processor{
+		logger: logger,
+	}
+}
+
+// SetLogger sets the logger of the plugin
+func (p *Processor) SetLogger(logger log.Logger) {
+	p.logger = logger
+}
+
+// Info returns the plugin information
+func (p *Processor) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Drop records with an already seen urn",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"processor", "dedup"},
+	}
+}
+
+// Validate validates the plugin configuration
+func (p *Processor) Validate(configMap map[string]interface{}) (err error) {
+	return utils.BuildConfig(configMap, &Config{})
+}
+
+// Init initializes the processor
+func (p *Processor) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &p.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}
+	}
+	p.entries = make(map[string]*list.Element)
+	p.order = list.New()
+
+	return
+}
+
+// Process drops the record if its urn was already seen when keeping the first occurrence.
+// When keeping the last occurrence, the record is held back and the record evicted to stay
+// within size, if any, is emitted instead.
+func (p *Processor) Process(ctx context.Context, src models.Record) (dst models.Record, err error) {
+	urn := src.Data().GetResource().GetUrn()
+	if urn == "" {
+		return src, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.config.Keep == KeepFirst {
+		if elem, ok := p.entries[urn]; ok {
+			p.order.MoveToBack(elem)
+			return src, plugins.NewDropRecordError("duplicate urn")
+		}
+		p.add(urn, models.Record{})
+		if evicted, ok := p.evict(); ok {
+			p.logger.Debug("urn evicted, its next occurrence will be emitted", "urn", evicted.urn)
+		}
+		return src, nil
+	}
+
+	if elem, ok := p.entries[urn]; ok {
+		elem.Value.(*entry).record = src
+		p.order.MoveToBack(elem)
+		return src, plugins.NewDropRecordError("held back until run ends")
+	}
+	p.add(urn, src)
+	if evicted, ok := p.evict(); ok {
+		return evicted.record, nil
+	}
+	return src, plugins.NewDropRecordError("held back until run ends")
+}
+
+// Flush returns the records held back when keeping the last occurrence
+func (p *Processor) Flush(ctx context.Context) ([]models.Record, error) {
+	if p.config.Keep != KeepLast {
+		return nil, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	records := make([]models.Record, 0, p.order.Len())
+	for elem := p.order.Front(); elem != nil; elem = elem.Next() {
+		records = append(records, elem.Value.(*entry).record)
+	}
+	p.entries = make(map[string]*list.Element)
+	p.order.Init()
+
+	return records, nil
+}
+
+func (p *Processor) add(urn string, record models.Record) {
+	p.entries[urn] = p.order.PushBack(&entry{urn: urn, record: record})
+}
+
+// evict removes the least recently seen entry if size is exceeded
+func (p *Processor) evict() (*entry, bool) {
+	if p.config.Size == 0 || p.order.Len() <= p.config.Size {
+		return nil, false
+	}
+
+	e := p.order.Remove(p.order.Front()).(*entry)
+	delete(p.entries, e.urn)
+	return e, true
+}
+
+func init() {
+	if err := registry.Processors.Register("dedup", func() plugins.Processor {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,105 @@
+package dedup_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/processors/dedup"
+	testutils "github.com/odpf/meteor/test/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTable(urn, name string) models.Record {
+	return models.NewRecord(&assetsv1beta1.Table{
+		Resource: &commonv1beta1.Resource{Urn: urn, Name: name},
+	})
+}
+
+// process runs the records through the processor and flushes it, returning the emitted names
+func process(t *testing.T, config map[string]interface{}, records ...models.Record) []string {
+	proc := dedup.New(testutils.Logger)
+	require.NoError(t, proc.Init(context.TODO(), config))
+
+	var names []string
+	for _, record := range records {
+		dst, err := proc.Process(context.TODO(), record)
+		if errors.Is(err, plugins.DropRecordError{}) {
+			continue
+		}
+		require.NoError(t, err)
+		names = append(names, dst.Data().GetResource().GetName())
+	}
+
+	flushed, err := proc.Flush(context.TODO())
+	require.NoError(t, err)
+	for _, record := range flushed {
+		names = append(names, record.Data().GetResource().GetName())
+	}
+
+	return names
+}
+
+func TestInit(t *testing.T) {
+	t.Run("should return error for invalid config", func(t *testing.T) {
+		invalidConfigs := []map[string]interface{}{
+			{"keep": "middle"},
+			{"size": -1},
+		}
+		for _, config := range invalidConfigs {
+			err := dedup.New(testutils.Logger).Init(context.TODO(), config)
+			assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}, err)
+		}
+	})
+}
+
+func TestProcess(t *testing.T) {
+	records := []models.Record{
+		newTable("urn-a", "a1"),
+		newTable("urn-b", "b1"),
+		newTable("urn-a", "a2"),
+		newTable("", "no-urn"),
+		newTable("urn-c", "c1"),
+		newTable("urn-b", "b2"),
+	}
+
+	t.Run("should keep first occurrence by default", func(t *testing.T) {
+		names := process(t, map[string]interface{}{}, records...)
+
+		assert.Equal(t, []string{"a1", "b1", "no-urn", "c1"}, names)
+	})
+
+	t.Run("should keep last occurrence once flushed", func(t *testing.T) {
+		names := process(t, map[string]interface{}{"keep": "last"}, records...)
+
+		assert.Equal(t, []string{"no-urn", "a2", "c1", "b2"}, names)
+	})
+
+	t.Run("should emit evicted urn again when keeping first occurrence", func(t *testing.T) {
+		names := process(t, map[string]interface{}{"size": 1}, records...)
+
+		assert.Equal(t, []string{"a1", "b1", "a2", "no-urn", "c1", "b2"}, names)
+	})
+
+	t.Run("should emit evicted record when keeping last occurrence", func(t *testing.T) {
+		names := process(t, map[string]interface{}{"keep": "last", "size": 2}, records...)
+
+		assert.Equal(t, []string{"no-urn", "b1", "a2", "c1", "b2"}, names)
+	})
+
+	t.Run("should not flush records when keeping first occurrence", func(t *testing.T) {
+		proc := dedup.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{}))
+		_, err := proc.Process(context.TODO(), newTable("urn-a", "a1"))
+		require.NoError(t, err)
+
+		flushed, err := proc.Flush(context.TODO())
+		require.NoError(t, err)
+		assert.Empty(t, flushed)
+	})
+}
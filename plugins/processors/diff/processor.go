@@ -0,0 +1,327 @@
+package diff
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/odpf/meteor/models"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+)
+
+//go:embed README.md
+var summary string
+
+// Config holds the set of configuration for the diff processor
+type Config struct {
+	// SnapshotPath is the file holding the assets of the previous run, updated once the extractor is done
+	SnapshotPath string `mapstructure:"snapshot_path" validate:"required"`
+	// OutputPath is the file the diff is written to, only a summary is logged if empty
+	OutputPath string `mapstructure:"output_path"`
+}
+
+var sampleConfig = `
+# file holding the assets of the previous run, created if it does not exist
+snapshot_path: ./snapshots/mysql.json
+# file the changes since the previous run are written to
+output_path: ./diffs/mysql.json`
+
+// Column is the part of a table column compared between runs
+type Column struct {
+	Name       string `json:"name"`
+	DataType   string `json:"data_type"`
+	IsNullable bool   `json:"is_nullable"`
+}
+
+// Asset is the part of an asset compared between runs, columns are only set for tables
+type Asset struct {
+	URN     string   `json:"urn"`
+	Type    string   `json:"type"`
+	Name    string   `json:"name"`
+	Columns []Column `json:"columns,omitempty"`
+}
+
+// Snapshot holds the assets of a run sorted by urn
+type Snapshot struct {
+	Assets []Asset `json:"assets"`
+}
+
+// ColumnChange is a column with a different data type or nullability than in the previous run
+type ColumnChange struct {
+	Name     string `json:"name"`
+	Previous Column `json:"previous"`
+	Current  Column `json:"current"`
+}
+
+// AssetChange is an asset of both runs with a different name or columns
+type AssetChange struct {
+	URN             string         `json:"urn"`
+	Type            string         `json:"type"`
+	Name            string         `json:"name"`
+	PreviousName    string         `json:"previous_name,omitempty"`
+	AddedColumns    []Column       `json:"added_columns,omitempty"`
+	RemovedColumns  []Column       `json:"removed_columns,omitempty"`
+	ModifiedColumns []ColumnChange `json:"modified_columns,omitempty"`
+}
+
+// Diff holds the changes between two runs, each list is sorted by urn
+type Diff struct {
+	Added    []Asset       `json:"added"`
+	Removed  []Asset       `json:"removed"`
+	Modified []AssetChange `json:"modified"`
+}
+
+// Processor compares the assets of the run with the ones of the previous run
+type Processor struct {
+	config   Config
+	previous map[string]Asset
+	mu       sync.Mutex
+	current  map[string]Asset
+	logger   log.Logger
+}
+
+// New create a new processor
+func New(logger log.Logger) *Processor {
+	return &Processor{
+		logger: logger,
+	}
+}
+
+// SetLogger sets the logger of the plugin
+func (p *Processor) SetLogger(logger log.Logger) {
+	p.logger = logger
+}
+
+// Info returns the plugin information
+func (p *Processor) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Detect assets and columns changed since the previous run",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"processor", "diff"},
+	}
+}
+
+// Validate validates the plugin configuration
+func (p *Processor) Validate(configMap map[string]interface{}) (err error) {
+	return utils.BuildConfig(configMap, &Config{})
+}
+
+// Init initializes the processor and reads the snapshot of the previous run, if any
+func (p *Processor) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &p.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}
+	}
+
+	snapshot, err := readSnapshot(p.config.SnapshotPath)
+	if err != nil {
+		return err
+	}
+	p.previous = make(map[string]Asset, len(snapshot.Assets))
+	for _, asset := range snapshot.Assets {
+		p.previous[asset.URN] = asset
+	}
+	p.current = make(map[string]Asset)
+
+	return
+}
+
+// Process records the asset and passes it as is, lineage edges and records without an urn are ignored
+func (p *Processor) Process(ctx context.Context, src models.Record) (dst models.Record, err error) {
+	urn := src.Data().GetResource().GetUrn()
+	if urn == "" || models.IsLineageRecord(src) {
+		return src, nil
+	}
+	asset := newAsset(src.Data())
+	p.mu.Lock()
+	p.current[urn] = asset
+	p.mu.Unlock()
+
+	return src, nil
+}
+
+// Flush writes the diff with the previous run and replaces the snapshot with the assets of this run.
+// It is only called once the extractor is done, the snapshot is left as is if the run was cancelled.
+func (p *Processor) Flush(ctx context.Context) ([]models.Record, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	diff := compare(p.previous, p.current)
+	p.logger.Info("compared assets with previous run", "snapshot_path", p.config.SnapshotPath,
+		"added", len(diff.Added), "removed", len(diff.Removed), "modified", len(diff.Modified))
+	if p.config.OutputPath != "" {
+		if err := writeJSON(p.config.OutputPath, diff); err != nil {
+			return nil, errors.Wrapf(err, "failed to write diff to \"%s\"", p.config.OutputPath)
+		}
+	}
+
+	snapshot := Snapshot{Assets: sortedAssets(p.current)}
+	if err := writeJSON(p.config.SnapshotPath, snapshot); err != nil {
+		return nil, errors.Wrapf(err, "failed to write snapshot to \"%s\"", p.config.SnapshotPath)
+	}
+
+	return nil, nil
+}
+
+func newAsset(data models.Metadata) Asset {
+	asset := Asset{
+		URN:  data.GetResource().GetUrn(),
+		Type: models.AssetType(data),
+		Name: data.GetResource().GetName(),
+	}
+	if table, ok := data.(*assetsv1beta1.Table); ok {
+		for _, column := range table.GetSchema().GetColumns() {
+			asset.Columns = append(asset.Columns, Column{
+				Name:       column.GetName(),
+				DataType:   column.GetDataType(),
+				IsNullable: column.GetIsNullable(),
+			})
+		}
+	}
+
+	return asset
+}
+
+// compare returns the assets added, removed and modified in current
+func compare(previous, current map[string]Asset) Diff {
+	diff := Diff{
+		Added:    []Asset{},
+		Removed:  []Asset{},
+		Modified: []AssetChange{},
+	}
+	for _, asset := range sortedAssets(current) {
+		prev, ok := previous[asset.URN]
+		if !ok {
+			diff.Added = append(diff.Added, asset)
+			continue
+		}
+		if change, changed := compareAsset(prev, asset); changed {
+			diff.Modified = append(diff.Modified, change)
+		}
+	}
+	for _, asset := range sortedAssets(previous) {
+		if _, ok := current[asset.URN]; !ok {
+			diff.Removed = append(diff.Removed, asset)
+		}
+	}
+
+	return diff
+}
+
+func compareAsset(previous, current Asset) (change AssetChange, changed bool) {
+	change = AssetChange{
+		URN:  current.URN,
+		Type: current.Type,
+		Name: current.Name,
+	}
+	if previous.Name != current.Name {
+		change.PreviousName = previous.Name
+	}
+
+	previousColumns := make(map[string]Column, len(previous.Columns))
+	for _, column := range previous.Columns {
+		previousColumns[column.Name] = column
+	}
+	currentColumns := make(map[string]bool, len(current.Columns))
+	for _, column := range current.Columns {
+		currentColumns[column.Name] = true
+		prev, ok := previousColumns[column.Name]
+		if !ok {
+			change.AddedColumns = append(change.AddedColumns, column)
+			continue
+		}
+		if prev != column {
+			change.ModifiedColumns = append(change.ModifiedColumns, ColumnChange{
+				Name:     column.Name,
+				Previous: prev,
+				Current:  column,
+			})
+		}
+	}
+	for _, column := range previous.Columns {
+		if !currentColumns[column.Name] {
+			change.RemovedColumns = append(change.RemovedColumns, column)
+		}
+	}
+
+	changed = change.PreviousName != "" || len(change.AddedColumns) > 0 ||
+		len(change.RemovedColumns) > 0 || len(change.ModifiedColumns) > 0
+	return
+}
+
+func sortedAssets(assets map[string]Asset) []Asset {
+	list := make([]Asset, 0, len(assets))
+	for _, asset := range assets {
+		list = append(list, asset)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].URN < list[j].URN
+	})
+
+	return list
+}
+
+// readSnapshot reads the snapshot at path, a missing file is an empty snapshot
+func readSnapshot(path string) (snapshot Snapshot, err error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Snapshot{}, nil
+	}
+	if err != nil {
+		return snapshot, errors.Wrap(err, "failed to read snapshot")
+	}
+	if err = json.Unmarshal(content, &snapshot); err != nil {
+		return snapshot, errors.Wrapf(err, "failed to parse snapshot \"%s\"", path)
+	}
+
+	return
+}
+
+// writeJSON replaces the file at path with value, writing a temporary file first
+// so the previous content is kept if writing fails
+func writeJSON(path string, value interface{}) error {
+	content, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func init() {
+	if err := registry.Processors.Register("diff", func() plugins.Processor {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,155 @@
+package diff_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/processors/diff"
+	testutils "github.com/odpf/meteor/test/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTable(urn, name string, columns ...*facetsv1beta1.Column) models.Record {
+	return models.NewRecord(&assetsv1beta1.Table{
+		Resource: &commonv1beta1.Resource{Urn: urn, Name: name},
+		Schema:   &facetsv1beta1.Columns{Columns: columns},
+	})
+}
+
+// run processes the records and flushes a processor reading and writing the snapshot in dir
+func run(t *testing.T, dir string, records ...models.Record) diff.Diff {
+	output := filepath.Join(dir, "diff.json")
+	proc := diff.New(testutils.Logger)
+	require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{
+		"snapshot_path": filepath.Join(dir, "snapshot.json"),
+		"output_path":   output,
+	}))
+
+	for _, record := range records {
+		dst, err := proc.Process(context.TODO(), record)
+		require.NoError(t, err)
+		assert.Equal(t, record, dst)
+	}
+	flushed, err := proc.Flush(context.TODO())
+	require.NoError(t, err)
+	assert.Empty(t, flushed)
+
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+	var result diff.Diff
+	require.NoError(t, json.Unmarshal(content, &result))
+	return result
+}
+
+func TestInit(t *testing.T) {
+	t.Run("should return error for invalid config", func(t *testing.T) {
+		err := diff.New(testutils.Logger).Init(context.TODO(), map[string]interface{}{})
+		assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}, err)
+	})
+
+	t.Run("should return error for invalid snapshot", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "snapshot.json")
+		require.NoError(t, os.WriteFile(path, []byte("not json"), 0600))
+
+		err := diff.New(testutils.Logger).Init(context.TODO(), map[string]interface{}{
+			"snapshot_path": path,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse snapshot")
+	})
+}
+
+func TestProcess(t *testing.T) {
+	id := &facetsv1beta1.Column{Name: "id", DataType: "int"}
+	amount := &facetsv1beta1.Column{Name: "amount", DataType: "int"}
+	topic := models.NewRecord(&assetsv1beta1.Topic{Resource: &commonv1beta1.Resource{Urn: "orders-topic", Name: "orders"}})
+
+	t.Run("should report every asset as added without snapshot", func(t *testing.T) {
+		result := run(t, t.TempDir(), newTable("sales.orders", "orders", id), topic)
+
+		assert.Equal(t, diff.Diff{
+			Added: []diff.Asset{
+				{URN: "orders-topic", Type: "topic", Name: "orders"},
+				{URN: "sales.orders", Type: "table", Name: "orders", Columns: []diff.Column{{Name: "id", DataType: "int"}}},
+			},
+			Removed:  []diff.Asset{},
+			Modified: []diff.AssetChange{},
+		}, result)
+	})
+
+	t.Run("should compare assets and columns with the previous run", func(t *testing.T) {
+		dir := t.TempDir()
+		run(t, dir,
+			newTable("sales.orders", "orders", id, amount, &facetsv1beta1.Column{Name: "note", DataType: "varchar"}),
+			newTable("sales.tmp_orders", "tmp_orders"),
+			newTable("sales.customers", "customers", id),
+			topic,
+			models.NewLineageRecord(
+				&commonv1beta1.Resource{Urn: "sales.orders", Type: "table"},
+				&commonv1beta1.Resource{Urn: "orders-topic", Type: "topic"},
+			),
+		)
+
+		result := run(t, dir,
+			newTable("sales.orders", "orders",
+				id,
+				&facetsv1beta1.Column{Name: "amount", DataType: "decimal", IsNullable: true},
+				&facetsv1beta1.Column{Name: "currency", DataType: "varchar"},
+			),
+			newTable("sales.customers", "clients", id),
+			newTable("sales.refunds", "refunds"),
+			topic,
+		)
+
+		assert.Equal(t, diff.Diff{
+			Added:   []diff.Asset{{URN: "sales.refunds", Type: "table", Name: "refunds"}},
+			Removed: []diff.Asset{{URN: "sales.tmp_orders", Type: "table", Name: "tmp_orders"}},
+			Modified: []diff.AssetChange{
+				{URN: "sales.customers", Type: "table", Name: "clients", PreviousName: "customers"},
+				{
+					URN:            "sales.orders",
+					Type:           "table",
+					Name:           "orders",
+					AddedColumns:   []diff.Column{{Name: "currency", DataType: "varchar"}},
+					RemovedColumns: []diff.Column{{Name: "note", DataType: "varchar"}},
+					ModifiedColumns: []diff.ColumnChange{{
+						Name:     "amount",
+						Previous: diff.Column{Name: "amount", DataType: "int"},
+						Current:  diff.Column{Name: "amount", DataType: "decimal", IsNullable: true},
+					}},
+				},
+			},
+		}, result)
+
+		// the snapshot now holds the second run
+		result = run(t, dir)
+		assert.Len(t, result.Removed, 4)
+	})
+
+	t.Run("should keep snapshot when the run is cancelled", func(t *testing.T) {
+		dir := t.TempDir()
+		run(t, dir, topic)
+
+		proc := diff.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{
+			"snapshot_path": filepath.Join(dir, "snapshot.json"),
+		}))
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+		_, err := proc.Flush(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		result := run(t, dir, topic)
+		assert.Empty(t, result.Added)
+		assert.Empty(t, result.Removed)
+	})
+}
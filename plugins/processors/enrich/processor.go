@@ -14,9 +14,16 @@ import (
 //go:embed README.md
 var summary string
 
+// Config holds the set of configuration for the enrich processor
+type Config struct {
+	Attributes map[string]interface{} `mapstructure:"attributes"`
+	Labels     map[string]string      `mapstructure:"labels"`
+	Overwrite  bool                   `mapstructure:"overwrite"`
+}
+
 // Processor work in a list of data
 type Processor struct {
-	config map[string]interface{}
+	config Config
 	logger log.Logger
 }
 
@@ -28,15 +35,25 @@ func New(logger log.Logger) *Processor {
 }
 
 var sampleConfig = `
- # Enrichment configuration
- # fieldA: valueA
- # fieldB: valueB`
+# Enrichment configuration
+attributes:
+  environment: production
+labels:
+  team: data-platform
+# overwrite existing values of the same keys
+overwrite: false`
+
+// SetLogger sets the logger of the plugin
+func (p *Processor) SetLogger(logger log.Logger) {
+	p.logger = logger
+}
 
 // Info returns the plugin information
 func (p *Processor) Info() plugins.Info {
 	return plugins.Info{
 		Description:  "Append custom fields to records",
 		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
 		Summary:      summary,
 		Tags:         []string{"processor", "transform"},
 	}
@@ -44,12 +61,22 @@ func (p *Processor) Info() plugins.Info {
 
 // Validate validates the plugin configuration
 func (p *Processor) Validate(configMap map[string]interface{}) (err error) {
-	return nil
+	if !isStructured(configMap) {
+		return nil
+	}
+	return utils.BuildConfig(configMap, &Config{})
 }
 
-// Process processes the data
+// Init initializes the processor
 func (p *Processor) Init(ctx context.Context, config map[string]interface{}) (err error) {
-	p.config = config
+	if !isStructured(config) {
+		p.config = legacyConfig(config)
+		return
+	}
+	if err = utils.BuildConfig(config, &p.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}
+	}
+
 	return
 }
 
@@ -69,11 +96,11 @@ func (p *Processor) process(record models.Record) (models.Metadata, error) {
 	customProps := utils.GetCustomProperties(data)
 
 	// update custom properties using value from config
-	for key, value := range p.config {
-		stringVal, ok := value.(string)
-		if ok {
-			customProps[key] = stringVal
+	for key, value := range p.config.Attributes {
+		if _, exists := customProps[key]; exists && !p.config.Overwrite {
+			continue
 		}
+		customProps[key] = value
 	}
 
 	// save custom properties
@@ -81,10 +108,55 @@ func (p *Processor) process(record models.Record) (models.Metadata, error) {
 	if err != nil {
 		return data, err
 	}
+	if len(p.config.Labels) == 0 {
+		return result, nil
+	}
+
+	properties := result.GetProperties()
+	if properties == nil {
+		return result, nil
+	}
+	if properties.Labels == nil {
+		properties.Labels = make(map[string]string)
+	}
+	for key, value := range p.config.Labels {
+		if _, exists := properties.Labels[key]; exists && !p.config.Overwrite {
+			continue
+		}
+		properties.Labels[key] = value
+	}
 
 	return result, nil
 }
 
+// isStructured checks if the config declares attributes or labels,
+// otherwise it is a flat map of attributes
+func isStructured(config map[string]interface{}) bool {
+	for _, key := range []string{"attributes", "labels"} {
+		if _, ok := config[key].(map[string]interface{}); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// legacyConfig maps the string values of a flat config to attributes overwriting existing ones,
+// the log level of the plugin is not an attribute
+func legacyConfig(config map[string]interface{}) Config {
+	attributes := make(map[string]interface{})
+	for key, value := range config {
+		if key == plugins.LogLevelKey {
+			continue
+		}
+		if stringVal, ok := value.(string); ok {
+			attributes[key] = stringVal
+		}
+	}
+
+	return Config{Attributes: attributes, Overwrite: true}
+}
+
 func init() {
 	if err := registry.Processors.Register("enrich", func() plugins.Processor {
 		return New(plugins.GetLog())
@@ -0,0 +1,108 @@
+package enrich_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins/processors/enrich"
+	testutils "github.com/odpf/meteor/test/utils"
+	"github.com/odpf/meteor/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcess(t *testing.T) {
+	newProperties := func() *facetsv1beta1.Properties {
+		return &facetsv1beta1.Properties{
+			Attributes: utils.TryParseMapToProto(map[string]interface{}{"environment": "staging"}),
+			Labels:     map[string]string{"team": "sales"},
+		}
+	}
+	config := func(overwrite bool) map[string]interface{} {
+		return map[string]interface{}{
+			"attributes": map[string]interface{}{"environment": "production", "tier": "gold"},
+			"labels":     map[string]interface{}{"team": "data-platform", "domain": "payments"},
+			"overwrite":  overwrite,
+		}
+	}
+
+	t.Run("should merge attributes and labels across asset types", func(t *testing.T) {
+		proc := enrich.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), config(false)))
+
+		for _, data := range []models.Metadata{
+			&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "table"}},
+			&assetsv1beta1.User{Resource: &commonv1beta1.Resource{Urn: "user"}},
+			&assetsv1beta1.Dashboard{Resource: &commonv1beta1.Resource{Urn: "dashboard"}, Properties: &facetsv1beta1.Properties{}},
+		} {
+			dst, err := proc.Process(context.TODO(), models.NewRecord(data))
+			require.NoError(t, err)
+
+			props := dst.Data().GetProperties()
+			assert.Equal(t, map[string]interface{}{"environment": "production", "tier": "gold"}, props.Attributes.AsMap())
+			assert.Equal(t, map[string]string{"team": "data-platform", "domain": "payments"}, props.Labels)
+		}
+	})
+
+	t.Run("should preserve existing values without overwrite", func(t *testing.T) {
+		proc := enrich.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), config(false)))
+
+		dst, err := proc.Process(context.TODO(), models.NewRecord(&assetsv1beta1.Table{
+			Resource:   &commonv1beta1.Resource{Urn: "table"},
+			Properties: newProperties(),
+		}))
+		require.NoError(t, err)
+
+		props := dst.Data().GetProperties()
+		assert.Equal(t, map[string]interface{}{"environment": "staging", "tier": "gold"}, props.Attributes.AsMap())
+		assert.Equal(t, map[string]string{"team": "sales", "domain": "payments"}, props.Labels)
+	})
+
+	t.Run("should overwrite existing values with overwrite", func(t *testing.T) {
+		proc := enrich.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), config(true)))
+
+		dst, err := proc.Process(context.TODO(), models.NewRecord(&assetsv1beta1.Table{
+			Resource:   &commonv1beta1.Resource{Urn: "table"},
+			Properties: newProperties(),
+		}))
+		require.NoError(t, err)
+
+		props := dst.Data().GetProperties()
+		assert.Equal(t, map[string]interface{}{"environment": "production", "tier": "gold"}, props.Attributes.AsMap())
+		assert.Equal(t, map[string]string{"team": "data-platform", "domain": "payments"}, props.Labels)
+	})
+
+	t.Run("should set flat config as attributes", func(t *testing.T) {
+		proc := enrich.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{"environment": "production"}))
+
+		dst, err := proc.Process(context.TODO(), models.NewRecord(&assetsv1beta1.Table{
+			Resource:   &commonv1beta1.Resource{Urn: "table"},
+			Properties: newProperties(),
+		}))
+		require.NoError(t, err)
+
+		props := dst.Data().GetProperties()
+		assert.Equal(t, map[string]interface{}{"environment": "production"}, props.Attributes.AsMap())
+		assert.Equal(t, map[string]string{"team": "sales"}, props.Labels)
+	})
+
+	t.Run("should not set log level of flat config as attribute", func(t *testing.T) {
+		proc := enrich.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{"environment": "production", "log_level": "debug"}))
+
+		dst, err := proc.Process(context.TODO(), models.NewRecord(&assetsv1beta1.Table{
+			Resource:   &commonv1beta1.Resource{Urn: "table"},
+			Properties: newProperties(),
+		}))
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]interface{}{"environment": "production"}, dst.Data().GetProperties().Attributes.AsMap())
+	})
+}
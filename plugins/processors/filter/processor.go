@@ -0,0 +1,177 @@
+package filter
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"regexp"
+
+	"github.com/odpf/meteor/models"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+)
+
+//go:embed README.md
+var summary string
+
+// Rule matches records by the value at Field, a dot separated path of the
+// record's JSON fields, e.g. "resource.name" or "properties.attributes.schema".
+// Exactly one of Matches, Equals or NotEquals has to be set.
+type Rule struct {
+	Field     string `mapstructure:"field" validate:"required"`
+	Matches   string `mapstructure:"matches"`
+	Equals    string `mapstructure:"equals"`
+	NotEquals string `mapstructure:"not_equals"`
+}
+
+// Config holds the set of configuration for the filter processor
+type Config struct {
+	Rules []Rule `mapstructure:"rules" validate:"required,min=1,dive"`
+}
+
+var sampleConfig = `
+# records matching any of the rules are dropped
+rules:
+  - field: resource.name
+    matches: ^tmp_
+  - field: properties.attributes.schema
+    equals: staging`
+
+type rule struct {
+	Rule
+	regex *regexp.Regexp
+}
+
+// Processor drops records matching any of the configured rules
+type Processor struct {
+	config Config
+	rules  []rule
+	logger log.Logger
+}
+
+// New create a new processor
+func New(logger log.Logger) *Processor {
+	return &Processor{
+		logger: logger,
+	}
+}
+
+// SetLogger sets the logger of the plugin
+func (p *Processor) SetLogger(logger log.Logger) {
+	p.logger = logger
+}
+
+// Info returns the plugin information
+func (p *Processor) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Drop records matching field predicates",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"processor", "filter"},
+	}
+}
+
+// Validate validates the plugin configuration
+func (p *Processor) Validate(configMap map[string]interface{}) (err error) {
+	var config Config
+	if err = utils.BuildConfig(configMap, &config); err != nil {
+		return err
+	}
+	_, err = buildRules(config.Rules)
+	return err
+}
+
+// Init initializes the processor
+func (p *Processor) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &p.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}
+	}
+	if p.rules, err = buildRules(p.config.Rules); err != nil {
+		return errors.Wrap(err, "invalid rules")
+	}
+
+	return
+}
+
+// Process drops the record if it matches any of the rules
+func (p *Processor) Process(ctx context.Context, src models.Record) (dst models.Record, err error) {
+	fields, err := models.ToMap(src)
+	if err != nil {
+		return src, errors.Wrap(err, "failed to read record fields")
+	}
+
+	for _, r := range p.rules {
+		value, found := lookup(fields, r.Field)
+		if r.match(value, found) {
+			p.logger.Debug("dropping record", "record", src.Data().GetResource().GetUrn(), "field", r.Field)
+			return src, plugins.NewDropRecordError(fmt.Sprintf("matched rule on \"%s\"", r.Field))
+		}
+	}
+
+	return src, nil
+}
+
+func buildRules(rules []Rule) (built []rule, err error) {
+	for _, r := range rules {
+		set := 0
+		for _, v := range []string{r.Matches, r.Equals, r.NotEquals} {
+			if v != "" {
+				set++
+			}
+		}
+		if set != 1 {
+			return nil, fmt.Errorf("rule on \"%s\" must have exactly one of matches, equals or not_equals", r.Field)
+		}
+
+		b := rule{Rule: r}
+		if r.Matches != "" {
+			if b.regex, err = regexp.Compile(r.Matches); err != nil {
+				return nil, errors.Wrapf(err, "invalid regex for rule on \"%s\"", r.Field)
+			}
+		}
+		built = append(built, b)
+	}
+
+	return
+}
+
+// match checks the value against the rule, a missing field only matches not_equals
+func (r rule) match(value string, found bool) bool {
+	switch {
+	case r.regex != nil:
+		return found && r.regex.MatchString(value)
+	case r.Equals != "":
+		return found && value == r.Equals
+	default:
+		return !found || value != r.NotEquals
+	}
+}
+
+// lookup returns the value at the dot separated path as a string
+func lookup(fields map[string]interface{}, path string) (string, bool) {
+	value, found := models.Lookup(fields, path)
+	if !found {
+		return "", false
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	default:
+		return fmt.Sprint(v), true
+	}
+}
+
+func init() {
+	if err := registry.Processors.Register("filter", func() plugins.Processor {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
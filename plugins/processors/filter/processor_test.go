@@ -0,0 +1,91 @@
+package filter_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/processors/filter"
+	testutils "github.com/odpf/meteor/test/utils"
+	"github.com/odpf/meteor/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("should return error for invalid config", func(t *testing.T) {
+		err := filter.New(testutils.Logger).Init(context.TODO(), map[string]interface{}{})
+		assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}, err)
+	})
+
+	t.Run("should return error for rule without predicate", func(t *testing.T) {
+		err := filter.New(testutils.Logger).Init(context.TODO(), map[string]interface{}{
+			"rules": []map[string]interface{}{{"field": "resource.name"}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("should return error for invalid regex", func(t *testing.T) {
+		err := filter.New(testutils.Logger).Init(context.TODO(), map[string]interface{}{
+			"rules": []map[string]interface{}{{"field": "resource.name", "matches": "tmp_["}},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestProcess(t *testing.T) {
+	newTable := func(name, schema string) models.Record {
+		return models.NewRecord(&assetsv1beta1.Table{
+			Resource: &commonv1beta1.Resource{Urn: "db." + name, Name: name},
+			Properties: &facetsv1beta1.Properties{
+				Attributes: utils.TryParseMapToProto(map[string]interface{}{"schema": schema}),
+			},
+		})
+	}
+
+	proc := filter.New(testutils.Logger)
+	err := proc.Init(context.TODO(), map[string]interface{}{
+		"rules": []map[string]interface{}{
+			{"field": "resource.name", "matches": "^tmp_"},
+			{"field": "properties.attributes.schema", "equals": "staging"},
+		},
+	})
+	require.NoError(t, err)
+
+	cases := []struct {
+		description string
+		record      models.Record
+		dropped     bool
+	}{
+		{"should drop record matching regex", newTable("tmp_orders", "public"), true},
+		{"should drop record matching equals", newTable("orders", "staging"), true},
+		{"should keep record matching no rule", newTable("orders", "public"), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			dst, err := proc.Process(context.TODO(), tc.record)
+			assert.Equal(t, tc.record, dst)
+			if tc.dropped {
+				assert.True(t, errors.Is(err, plugins.DropRecordError{}))
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("should match missing fields with not_equals", func(t *testing.T) {
+		proc := filter.New(testutils.Logger)
+		err := proc.Init(context.TODO(), map[string]interface{}{
+			"rules": []map[string]interface{}{{"field": "properties.attributes.owner", "not_equals": "data"}},
+		})
+		require.NoError(t, err)
+
+		_, err = proc.Process(context.TODO(), newTable("orders", "public"))
+		assert.True(t, errors.Is(err, plugins.DropRecordError{}))
+	})
+}
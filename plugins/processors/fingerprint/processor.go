@@ -0,0 +1,143 @@
+package fingerprint
+
+import (
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"github.com/odpf/meteor/models"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+)
+
+//go:embed README.md
+var summary string
+
+// Config holds the set of configuration for the fingerprint processor
+type Config struct {
+	// Attribute is the custom attribute the fingerprint is set to
+	Attribute string `mapstructure:"attribute" validate:"required" default:"schema_fingerprint"`
+}
+
+var sampleConfig = `
+# custom attribute holding the sha256 fingerprint of the table schema
+attribute: schema_fingerprint`
+
+// column holds the fields of a column the fingerprint is computed from
+type column struct {
+	Name       string `json:"name"`
+	DataType   string `json:"data_type"`
+	IsNullable bool   `json:"is_nullable"`
+}
+
+// Processor attaches a fingerprint of their schema to tables
+type Processor struct {
+	config Config
+	logger log.Logger
+}
+
+// New create a new processor
+func New(logger log.Logger) *Processor {
+	return &Processor{
+		logger: logger,
+	}
+}
+
+// SetLogger sets the logger of the plugin
+func (p *Processor) SetLogger(logger log.Logger) {
+	p.logger = logger
+}
+
+// Info returns the plugin information
+func (p *Processor) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Attach a fingerprint of their schema to tables",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"processor", "transform"},
+	}
+}
+
+// Validate validates the plugin configuration
+func (p *Processor) Validate(configMap map[string]interface{}) (err error) {
+	return utils.BuildConfig(configMap, &Config{})
+}
+
+// Init initializes the processor
+func (p *Processor) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &p.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}
+	}
+
+	return
+}
+
+// Process sets the fingerprint of the schema of tables, other records are passed as they are
+func (p *Processor) Process(ctx context.Context, src models.Record) (dst models.Record, err error) {
+	table, ok := src.Data().(*assetsv1beta1.Table)
+	if !ok {
+		return src, nil
+	}
+
+	fingerprint, err := Fingerprint(table)
+	if err != nil {
+		return src, errors.Wrapf(err, "failed to fingerprint table \"%s\"", table.GetResource().GetUrn())
+	}
+	customProps := utils.GetCustomProperties(table)
+	customProps[p.config.Attribute] = fingerprint
+	result, err := utils.SetCustomProperties(table, customProps)
+	if err != nil {
+		return src, err
+	}
+
+	return models.NewRecord(result), nil
+}
+
+// Fingerprint returns the hex encoded sha256 hash of the name, data type and nullability
+// of the columns of the table. Columns are sorted by name, so the order they were
+// extracted in does not change the fingerprint.
+func Fingerprint(table *assetsv1beta1.Table) (string, error) {
+	columns := make([]column, 0, len(table.GetSchema().GetColumns()))
+	for _, c := range table.GetSchema().GetColumns() {
+		columns = append(columns, column{
+			Name:       c.GetName(),
+			DataType:   c.GetDataType(),
+			IsNullable: c.GetIsNullable(),
+		})
+	}
+	// columns sharing a name, e.g. in nested schemas, are ordered by their other fields
+	sort.Slice(columns, func(i, j int) bool {
+		a, b := columns[i], columns[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.DataType != b.DataType {
+			return a.DataType < b.DataType
+		}
+		return !a.IsNullable && b.IsNullable
+	})
+
+	canonical, err := json.Marshal(columns)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(canonical)
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func init() {
+	if err := registry.Processors.Register("fingerprint", func() plugins.Processor {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
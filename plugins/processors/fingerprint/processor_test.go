@@ -0,0 +1,105 @@
+package fingerprint_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/processors/fingerprint"
+	testutils "github.com/odpf/meteor/test/utils"
+	"github.com/odpf/meteor/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTable(columns ...*facetsv1beta1.Column) *assetsv1beta1.Table {
+	return &assetsv1beta1.Table{
+		Resource: &commonv1beta1.Resource{Urn: "sales.orders"},
+		Schema:   &facetsv1beta1.Columns{Columns: columns},
+	}
+}
+
+func TestInit(t *testing.T) {
+	t.Run("should return error for invalid config", func(t *testing.T) {
+		err := fingerprint.New(testutils.Logger).Init(context.TODO(), map[string]interface{}{
+			"attribute": "",
+		})
+		assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}, err)
+	})
+}
+
+func TestFingerprint(t *testing.T) {
+	id := &facetsv1beta1.Column{Name: "id", DataType: "int"}
+	amount := &facetsv1beta1.Column{Name: "amount", DataType: "decimal", IsNullable: true}
+	expected, err := fingerprint.Fingerprint(newTable(id, amount))
+	require.NoError(t, err)
+	assert.Len(t, expected, 64)
+
+	t.Run("should not depend on the order of columns", func(t *testing.T) {
+		actual, err := fingerprint.Fingerprint(newTable(amount, id))
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("should ignore other fields of columns", func(t *testing.T) {
+		actual, err := fingerprint.Fingerprint(newTable(
+			&facetsv1beta1.Column{Name: "id", DataType: "int", Description: "order id", Length: 11},
+			amount,
+		))
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("should change with the name, data type or nullability of columns", func(t *testing.T) {
+		for _, changed := range []*facetsv1beta1.Column{
+			{Name: "order_id", DataType: "int"},
+			{Name: "id", DataType: "bigint"},
+			{Name: "id", DataType: "int", IsNullable: true},
+		} {
+			actual, err := fingerprint.Fingerprint(newTable(changed, amount))
+			require.NoError(t, err)
+			assert.NotEqual(t, expected, actual, changed.String())
+		}
+
+		actual, err := fingerprint.Fingerprint(newTable(id))
+		require.NoError(t, err)
+		assert.NotEqual(t, expected, actual)
+	})
+}
+
+func TestProcess(t *testing.T) {
+	t.Run("should set fingerprint to the configured attribute", func(t *testing.T) {
+		table := newTable(&facetsv1beta1.Column{Name: "id", DataType: "int"})
+		table.Properties = &facetsv1beta1.Properties{
+			Attributes: utils.TryParseMapToProto(map[string]interface{}{"owner": "sales"}),
+		}
+		expected, err := fingerprint.Fingerprint(table)
+		require.NoError(t, err)
+
+		proc := fingerprint.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{
+			"attribute": "schema_hash",
+		}))
+		dst, err := proc.Process(context.TODO(), models.NewRecord(table))
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]interface{}{
+			"owner":       "sales",
+			"schema_hash": expected,
+		}, utils.GetCustomProperties(dst.Data()))
+	})
+
+	t.Run("should pass records other than tables as they are", func(t *testing.T) {
+		proc := fingerprint.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{}))
+
+		topic := models.NewRecord(&assetsv1beta1.Topic{Resource: &commonv1beta1.Resource{Urn: "orders-topic"}})
+		dst, err := proc.Process(context.TODO(), topic)
+		require.NoError(t, err)
+		assert.Equal(t, topic, dst)
+	})
+}
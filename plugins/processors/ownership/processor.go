@@ -0,0 +1,229 @@
+package ownership
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/odpf/meteor/models"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed README.md
+var summary string
+
+// Owner is an owner attached to the assets of a mapping
+type Owner struct {
+	URN   string `mapstructure:"urn" validate:"required"`
+	Name  string `mapstructure:"name"`
+	Role  string `mapstructure:"role"`
+	Email string `mapstructure:"email"`
+}
+
+// Mapping assigns Owners to the assets with an urn starting with Prefix
+type Mapping struct {
+	Prefix string  `mapstructure:"prefix" validate:"required"`
+	Owners []Owner `mapstructure:"owners" validate:"required,min=1,dive"`
+}
+
+// Config holds the set of configuration for the ownership processor,
+// mappings read from Path are added to the inline Mappings.
+type Config struct {
+	Path     string    `mapstructure:"path" validate:"required_without=Mappings"`
+	Mappings []Mapping `mapstructure:"mappings" validate:"required_without=Path,dive"`
+	// Overwrite replaces the owners already set on assets instead of adding to them
+	Overwrite bool `mapstructure:"overwrite"`
+}
+
+// mappingFile is the content of the file at Path
+type mappingFile struct {
+	Mappings []Mapping `mapstructure:"mappings" validate:"required,dive"`
+}
+
+var sampleConfig = `
+# yaml file holding mappings, in the same format as the inline mappings
+path: ./ownership.yaml
+# owners of the assets with an urn starting with prefix, the longest matching prefix wins
+mappings:
+  - prefix: sales_db.
+    owners:
+      - urn: team:sales
+        name: Sales
+        role: owner
+        email: sales@example.com
+# replace owners set by the extractor instead of adding to them
+overwrite: false`
+
+// Processor sets the owners of assets from urn prefixes
+type Processor struct {
+	config Config
+	// mappings are sorted by descending prefix length
+	mappings []Mapping
+	logger   log.Logger
+}
+
+// New create a new processor
+func New(logger log.Logger) *Processor {
+	return &Processor{
+		logger: logger,
+	}
+}
+
+// SetLogger sets the logger of the plugin
+func (p *Processor) SetLogger(logger log.Logger) {
+	p.logger = logger
+}
+
+// Info returns the plugin information
+func (p *Processor) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Set owners of assets from a mapping of urn prefixes",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"processor", "transform"},
+	}
+}
+
+// Validate validates the plugin configuration
+func (p *Processor) Validate(configMap map[string]interface{}) (err error) {
+	var config Config
+	if err = utils.BuildConfig(configMap, &config); err != nil {
+		return err
+	}
+	_, err = loadMappings(config)
+
+	return
+}
+
+// Init initializes the processor
+func (p *Processor) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &p.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}
+	}
+	if p.mappings, err = loadMappings(p.config); err != nil {
+		return err
+	}
+
+	return
+}
+
+// Process sets the owners of the mapping with the longest prefix of the record urn.
+// Records without a matching mapping and assets without ownership, e.g. users, are passed as they are.
+func (p *Processor) Process(ctx context.Context, src models.Record) (dst models.Record, err error) {
+	mapping, ok := p.match(src.Data().GetResource().GetUrn())
+	if !ok {
+		return src, nil
+	}
+
+	switch asset := src.Data().(type) {
+	case *assetsv1beta1.Table:
+		asset.Ownership = p.assign(asset.Ownership, mapping.Owners)
+	case *assetsv1beta1.Topic:
+		asset.Ownership = p.assign(asset.Ownership, mapping.Owners)
+	case *assetsv1beta1.Dashboard:
+		asset.Ownership = p.assign(asset.Ownership, mapping.Owners)
+	case *assetsv1beta1.Bucket:
+		asset.Ownership = p.assign(asset.Ownership, mapping.Owners)
+	case *assetsv1beta1.Job:
+		asset.Ownership = p.assign(asset.Ownership, mapping.Owners)
+	}
+
+	return src, nil
+}
+
+func (p *Processor) match(urn string) (Mapping, bool) {
+	for _, m := range p.mappings {
+		if strings.HasPrefix(urn, m.Prefix) {
+			return m, true
+		}
+	}
+
+	return Mapping{}, false
+}
+
+// assign adds the owners missing from the ownership, matched by urn, or replaces them on overwrite
+func (p *Processor) assign(ownership *facetsv1beta1.Ownership, owners []Owner) *facetsv1beta1.Ownership {
+	if ownership == nil || p.config.Overwrite {
+		ownership = &facetsv1beta1.Ownership{}
+	}
+
+	existing := make(map[string]bool, len(ownership.Owners))
+	for _, o := range ownership.Owners {
+		existing[o.Urn] = true
+	}
+	for _, o := range owners {
+		if existing[o.URN] {
+			continue
+		}
+		ownership.Owners = append(ownership.Owners, &facetsv1beta1.Owner{
+			Urn:   o.URN,
+			Name:  o.Name,
+			Role:  o.Role,
+			Email: o.Email,
+		})
+	}
+
+	return ownership
+}
+
+// loadMappings merges the inline mappings with the ones of the file, sorted by descending prefix length
+func loadMappings(config Config) (mappings []Mapping, err error) {
+	mappings = append(mappings, config.Mappings...)
+	if config.Path != "" {
+		fileMappings, err := readMappingFile(config.Path)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, fileMappings...)
+	}
+
+	seen := make(map[string]bool, len(mappings))
+	for _, m := range mappings {
+		if seen[m.Prefix] {
+			return nil, fmt.Errorf("duplicate mapping for prefix \"%s\"", m.Prefix)
+		}
+		seen[m.Prefix] = true
+	}
+	sort.SliceStable(mappings, func(i, j int) bool {
+		return len(mappings[i].Prefix) > len(mappings[j].Prefix)
+	})
+
+	return mappings, nil
+}
+
+func readMappingFile(path string) ([]Mapping, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read mapping file")
+	}
+
+	var fileMap map[string]interface{}
+	if err = yaml.Unmarshal(content, &fileMap); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse mapping file \"%s\"", path)
+	}
+	var file mappingFile
+	if err = utils.BuildConfig(fileMap, &file); err != nil {
+		return nil, errors.Wrapf(err, "invalid mapping file \"%s\"", path)
+	}
+
+	return file.Mappings, nil
+}
+
+func init() {
+	if err := registry.Processors.Register("ownership", func() plugins.Processor {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,132 @@
+package ownership_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/processors/ownership"
+	testutils "github.com/odpf/meteor/test/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("should return error for invalid config", func(t *testing.T) {
+		err := ownership.New(testutils.Logger).Init(context.TODO(), map[string]interface{}{})
+		assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}, err)
+	})
+
+	t.Run("should return error when a prefix is mapped twice", func(t *testing.T) {
+		err := ownership.New(testutils.Logger).Init(context.TODO(), map[string]interface{}{
+			"mappings": []interface{}{
+				map[string]interface{}{"prefix": "sales.", "owners": []interface{}{map[string]interface{}{"urn": "team:a"}}},
+				map[string]interface{}{"prefix": "sales.", "owners": []interface{}{map[string]interface{}{"urn": "team:b"}}},
+			},
+		})
+		assert.EqualError(t, err, "duplicate mapping for prefix \"sales.\"")
+	})
+
+	t.Run("should return error for invalid mapping file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ownership.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("mappings:\n  - prefix: sales.\n"), 0600))
+
+		err := ownership.New(testutils.Logger).Init(context.TODO(), map[string]interface{}{
+			"path": path,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid mapping file")
+	})
+}
+
+func TestProcess(t *testing.T) {
+	newTable := func(urn string, owners ...*facetsv1beta1.Owner) *assetsv1beta1.Table {
+		table := &assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: urn}}
+		if len(owners) > 0 {
+			table.Ownership = &facetsv1beta1.Ownership{Owners: owners}
+		}
+		return table
+	}
+	newProcessor := func(t *testing.T, overwrite bool) *ownership.Processor {
+		path := filepath.Join(t.TempDir(), "ownership.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+mappings:
+  - prefix: sales.invoices
+    owners:
+      - urn: team:billing
+        role: owner
+`), 0600))
+
+		proc := ownership.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{
+			"path": path,
+			"mappings": []interface{}{
+				map[string]interface{}{
+					"prefix": "sales.",
+					"owners": []interface{}{
+						map[string]interface{}{"urn": "team:sales", "name": "Sales", "role": "owner", "email": "sales@example.com"},
+					},
+				},
+			},
+			"overwrite": overwrite,
+		}))
+		return proc
+	}
+
+	t.Run("should set owners of the longest matching prefix", func(t *testing.T) {
+		proc := newProcessor(t, false)
+
+		dst, err := proc.Process(context.TODO(), models.NewRecord(newTable("sales.orders")))
+		require.NoError(t, err)
+		assert.Equal(t, []*facetsv1beta1.Owner{
+			{Urn: "team:sales", Name: "Sales", Role: "owner", Email: "sales@example.com"},
+		}, dst.Data().(*assetsv1beta1.Table).Ownership.Owners)
+
+		dst, err = proc.Process(context.TODO(), models.NewRecord(newTable("sales.invoices")))
+		require.NoError(t, err)
+		assert.Equal(t, []*facetsv1beta1.Owner{
+			{Urn: "team:billing", Role: "owner"},
+		}, dst.Data().(*assetsv1beta1.Table).Ownership.Owners)
+	})
+
+	t.Run("should pass unmatched records and assets without ownership unchanged", func(t *testing.T) {
+		proc := newProcessor(t, false)
+
+		dst, err := proc.Process(context.TODO(), models.NewRecord(newTable("hr.employees")))
+		require.NoError(t, err)
+		assert.Nil(t, dst.Data().(*assetsv1beta1.Table).Ownership)
+
+		user := &assetsv1beta1.User{Resource: &commonv1beta1.Resource{Urn: "sales.user"}}
+		dst, err = proc.Process(context.TODO(), models.NewRecord(user))
+		require.NoError(t, err)
+		assert.Equal(t, user, dst.Data())
+	})
+
+	t.Run("should add missing owners to existing ones", func(t *testing.T) {
+		dst, err := newProcessor(t, false).Process(context.TODO(), models.NewRecord(newTable("sales.orders",
+			&facetsv1beta1.Owner{Urn: "user:jane"},
+			&facetsv1beta1.Owner{Urn: "team:sales"},
+		)))
+		require.NoError(t, err)
+		assert.Equal(t, []*facetsv1beta1.Owner{
+			{Urn: "user:jane"},
+			{Urn: "team:sales"},
+		}, dst.Data().(*assetsv1beta1.Table).Ownership.Owners)
+	})
+
+	t.Run("should replace existing owners on overwrite", func(t *testing.T) {
+		dst, err := newProcessor(t, true).Process(context.TODO(), models.NewRecord(newTable("sales.orders",
+			&facetsv1beta1.Owner{Urn: "user:jane"},
+		)))
+		require.NoError(t, err)
+		assert.Equal(t, []*facetsv1beta1.Owner{
+			{Urn: "team:sales", Name: "Sales", Role: "owner", Email: "sales@example.com"},
+		}, dst.Data().(*assetsv1beta1.Table).Ownership.Owners)
+	})
+}
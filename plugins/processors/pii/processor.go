@@ -0,0 +1,253 @@
+package pii
+
+import (
+	"context"
+	_ "embed"
+	"regexp"
+
+	"github.com/odpf/meteor/models"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+//go:embed README.md
+var summary string
+
+const (
+	// Tag is added to the tags of tables and columns containing PII
+	Tag = "pii"
+	// TypeLabel is the column label holding the name of the matched rule
+	TypeLabel = "pii_type"
+	// ColumnsAttribute is the table attribute listing the columns containing PII
+	ColumnsAttribute = "pii_columns"
+
+	maskValue = "****"
+)
+
+// Rule flags columns with a name or description matching Pattern as PII of type Name.
+// Patterns are matched case insensitively.
+type Rule struct {
+	Name    string `mapstructure:"name" validate:"required"`
+	Pattern string `mapstructure:"pattern" validate:"required"`
+}
+
+// DefaultRules are used when no rules are configured
+var DefaultRules = []Rule{
+	{Name: "email", Pattern: `e[-_ ]?mail`},
+	{Name: "ssn", Pattern: `(^|[^a-z])ssn([^a-z]|$)|social[-_ ]?security`},
+	{Name: "phone", Pattern: `phone|mobile|msisdn`},
+}
+
+// Config holds the set of configuration for the pii processor
+type Config struct {
+	Rules []Rule `mapstructure:"rules" validate:"dive"`
+	Mask  bool   `mapstructure:"mask"`
+}
+
+var sampleConfig = `
+# rules replace the default email, ssn and phone rules
+rules:
+  - name: email
+    pattern: e[-_ ]?mail
+  - name: credit_card
+    pattern: card[-_ ]?number
+# mask sample values of matching columns in previews and profiles
+mask: true`
+
+type rule struct {
+	name  string
+	regex *regexp.Regexp
+}
+
+// Processor flags and masks columns containing PII
+type Processor struct {
+	config Config
+	rules  []rule
+	logger log.Logger
+}
+
+// New create a new processor
+func New(logger log.Logger) *Processor {
+	return &Processor{
+		logger: logger,
+	}
+}
+
+// SetLogger sets the logger of the plugin
+func (p *Processor) SetLogger(logger log.Logger) {
+	p.logger = logger
+}
+
+// Info returns the plugin information
+func (p *Processor) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Flag and mask columns containing PII",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"processor", "pii"},
+	}
+}
+
+// Validate validates the plugin configuration
+func (p *Processor) Validate(configMap map[string]interface{}) (err error) {
+	var config Config
+	if err = utils.BuildConfig(configMap, &config); err != nil {
+		return err
+	}
+	_, err = buildRules(config.Rules)
+	return err
+}
+
+// Init initializes the processor
+func (p *Processor) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &p.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}
+	}
+	if p.rules, err = buildRules(p.config.Rules); err != nil {
+		return errors.Wrap(err, "invalid rules")
+	}
+
+	return
+}
+
+// Process flags the columns of tables matching any of the rules
+func (p *Processor) Process(ctx context.Context, src models.Record) (dst models.Record, err error) {
+	table, ok := src.Data().(*assetsv1beta1.Table)
+	if !ok || table.GetSchema() == nil {
+		return src, nil
+	}
+
+	var piiColumns []interface{}
+	for _, column := range table.Schema.Columns {
+		name, ok := p.match(column)
+		if !ok {
+			continue
+		}
+
+		p.logger.Debug("found pii column", "record", table.GetResource().GetUrn(), "column", column.Name, "type", name)
+		column.Properties = annotate(column.Properties, name)
+		piiColumns = append(piiColumns, column.Name)
+		if p.config.Mask {
+			maskProfile(column.Profile)
+			maskPreview(table.Preview, column.Name)
+		}
+	}
+	if len(piiColumns) == 0 {
+		return src, nil
+	}
+
+	customProps := utils.GetCustomProperties(table)
+	customProps[ColumnsAttribute] = piiColumns
+	result, err := utils.SetCustomProperties(table, customProps)
+	if err != nil {
+		return src, err
+	}
+	properties := result.GetProperties()
+	properties.Tags = appendTag(properties.Tags)
+
+	return models.NewRecord(result), nil
+}
+
+// match returns the name of the first rule matching the column name or description
+func (p *Processor) match(column *facetsv1beta1.Column) (string, bool) {
+	for _, r := range p.rules {
+		if r.regex.MatchString(column.Name) || r.regex.MatchString(column.Description) {
+			return r.name, true
+		}
+	}
+
+	return "", false
+}
+
+func buildRules(rules []Rule) (built []rule, err error) {
+	if len(rules) == 0 {
+		rules = DefaultRules
+	}
+	for _, r := range rules {
+		regex, err := regexp.Compile("(?i)" + r.Pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern for rule \"%s\"", r.Name)
+		}
+		built = append(built, rule{name: r.Name, regex: regex})
+	}
+
+	return
+}
+
+func annotate(properties *facetsv1beta1.Properties, name string) *facetsv1beta1.Properties {
+	if properties == nil {
+		properties = &facetsv1beta1.Properties{}
+	}
+	if properties.Labels == nil {
+		properties.Labels = make(map[string]string)
+	}
+	properties.Labels[TypeLabel] = name
+	properties.Tags = appendTag(properties.Tags)
+
+	return properties
+}
+
+func appendTag(tags []string) []string {
+	for _, t := range tags {
+		if t == Tag {
+			return tags
+		}
+	}
+
+	return append(tags, Tag)
+}
+
+func maskProfile(profile *facetsv1beta1.ColumnProfile) {
+	if profile == nil {
+		return
+	}
+	for _, v := range []*string{&profile.Min, &profile.Max, &profile.Top} {
+		if *v != "" {
+			*v = maskValue
+		}
+	}
+}
+
+func maskPreview(preview *facetsv1beta1.Preview, column string) {
+	if preview == nil || preview.Rows == nil {
+		return
+	}
+
+	index := -1
+	for i, field := range preview.Fields {
+		if field == column {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return
+	}
+
+	for _, row := range preview.Rows.Values {
+		values := row.GetListValue().GetValues()
+		if index >= len(values) {
+			continue
+		}
+		// keep nulls to not hide missing values
+		if _, isNull := values[index].GetKind().(*structpb.Value_NullValue); isNull {
+			continue
+		}
+		values[index] = structpb.NewStringValue(maskValue)
+	}
+}
+
+func init() {
+	if err := registry.Processors.Register("pii", func() plugins.Processor {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,115 @@
+package pii_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins/processors/pii"
+	testutils "github.com/odpf/meteor/test/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func newTable() *assetsv1beta1.Table {
+	rows, err := structpb.NewList([]interface{}{
+		[]interface{}{1, "a@example.com", "+62811"},
+		[]interface{}{2, nil, "+62812"},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return &assetsv1beta1.Table{
+		Resource: &commonv1beta1.Resource{Urn: "db.customers"},
+		Schema: &facetsv1beta1.Columns{
+			Columns: []*facetsv1beta1.Column{
+				{Name: "id"},
+				{Name: "Email_Address", Profile: &facetsv1beta1.ColumnProfile{Min: "a@example.com", Count: 2}},
+				{Name: "contact", Description: "mobile number of the customer"},
+				{Name: "customer_ssn"},
+			},
+		},
+		Preview: &facetsv1beta1.Preview{
+			Fields: []string{"id", "Email_Address", "contact"},
+			Rows:   rows,
+		},
+	}
+}
+
+func TestProcess(t *testing.T) {
+	t.Run("should flag columns matching default rules", func(t *testing.T) {
+		proc := pii.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{}))
+
+		dst, err := proc.Process(context.TODO(), models.NewRecord(newTable()))
+		require.NoError(t, err)
+
+		table := dst.Data().(*assetsv1beta1.Table)
+		columns := table.Schema.Columns
+		assert.Nil(t, columns[0].Properties)
+		assert.Equal(t, "email", columns[1].Properties.Labels[pii.TypeLabel])
+		assert.Equal(t, "phone", columns[2].Properties.Labels[pii.TypeLabel])
+		assert.Equal(t, "ssn", columns[3].Properties.Labels[pii.TypeLabel])
+		assert.Equal(t, []string{pii.Tag}, columns[3].Properties.Tags)
+
+		assert.Equal(t, []string{pii.Tag}, table.Properties.Tags)
+		assert.Equal(t, []interface{}{"Email_Address", "contact", "customer_ssn"},
+			table.Properties.Attributes.AsMap()[pii.ColumnsAttribute])
+
+		// values are masked only when configured
+		assert.Equal(t, "a@example.com", columns[1].Profile.Min)
+		assert.Equal(t, "a@example.com", table.Preview.Rows.Values[0].GetListValue().Values[1].GetStringValue())
+	})
+
+	t.Run("should mask sample values of matching columns", func(t *testing.T) {
+		proc := pii.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{"mask": true}))
+
+		dst, err := proc.Process(context.TODO(), models.NewRecord(newTable()))
+		require.NoError(t, err)
+
+		table := dst.Data().(*assetsv1beta1.Table)
+		assert.Equal(t, "****", table.Schema.Columns[1].Profile.Min)
+		assert.Equal(t, int64(2), table.Schema.Columns[1].Profile.Count)
+		assert.Equal(t, []interface{}{
+			[]interface{}{float64(1), "****", "****"},
+			[]interface{}{float64(2), nil, "****"},
+		}, table.Preview.Rows.AsSlice())
+	})
+
+	t.Run("should replace default rules with configured rules", func(t *testing.T) {
+		proc := pii.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{
+			"rules": []map[string]interface{}{{"name": "id", "pattern": "^id$"}},
+		}))
+
+		dst, err := proc.Process(context.TODO(), models.NewRecord(newTable()))
+		require.NoError(t, err)
+
+		columns := dst.Data().(*assetsv1beta1.Table).Schema.Columns
+		assert.Equal(t, "id", columns[0].Properties.Labels[pii.TypeLabel])
+		assert.Nil(t, columns[1].Properties)
+	})
+
+	t.Run("should ignore assets without schema", func(t *testing.T) {
+		proc := pii.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{}))
+
+		src := models.NewRecord(&assetsv1beta1.Dashboard{Resource: &commonv1beta1.Resource{Urn: "dashboard"}})
+		dst, err := proc.Process(context.TODO(), src)
+		require.NoError(t, err)
+		assert.Equal(t, src, dst)
+	})
+
+	t.Run("should return error for invalid pattern", func(t *testing.T) {
+		err := pii.New(testutils.Logger).Init(context.TODO(), map[string]interface{}{
+			"rules": []map[string]interface{}{{"name": "email", "pattern": "e[mail"}},
+		})
+		assert.Error(t, err)
+	})
+}
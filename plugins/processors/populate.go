@@ -1,5 +1,16 @@
 package processors
 
 import (
+	_ "github.com/odpf/meteor/plugins/processors/dedup"
+	_ "github.com/odpf/meteor/plugins/processors/diff"
 	_ "github.com/odpf/meteor/plugins/processors/enrich"
+	_ "github.com/odpf/meteor/plugins/processors/filter"
+	_ "github.com/odpf/meteor/plugins/processors/fingerprint"
+	_ "github.com/odpf/meteor/plugins/processors/ownership"
+	_ "github.com/odpf/meteor/plugins/processors/pii"
+	_ "github.com/odpf/meteor/plugins/processors/rename"
+	_ "github.com/odpf/meteor/plugins/processors/sample"
+	_ "github.com/odpf/meteor/plugins/processors/scope"
+	_ "github.com/odpf/meteor/plugins/processors/sortcolumns"
+	_ "github.com/odpf/meteor/plugins/processors/validate"
 )
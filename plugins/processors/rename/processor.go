@@ -0,0 +1,225 @@
+package rename
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"sort"
+
+	"github.com/odpf/meteor/models"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+//go:embed README.md
+var summary string
+
+// Config holds the set of configuration for the rename processor,
+// Attributes and Labels map the keys to rename to their new names.
+type Config struct {
+	Attributes map[string]string `mapstructure:"attributes" validate:"required_without=Labels"`
+	Labels     map[string]string `mapstructure:"labels" validate:"required_without=Attributes"`
+	// Overwrite replaces the value of a new name already in use instead of failing
+	Overwrite bool `mapstructure:"overwrite"`
+}
+
+var sampleConfig = `
+# keys of custom properties to rename, from -> to
+attributes:
+  db_name: database
+# keys of labels to rename, from -> to
+labels:
+  owner_team: team
+# overwrite values of keys already named as the new name
+overwrite: false`
+
+// rename moves the value at From to To
+type rename struct {
+	From string
+	To   string
+}
+
+// Processor renames keys of the custom properties and labels of records
+type Processor struct {
+	config     Config
+	attributes []rename
+	labels     []rename
+	logger     log.Logger
+}
+
+// New create a new processor
+func New(logger log.Logger) *Processor {
+	return &Processor{
+		logger: logger,
+	}
+}
+
+// SetLogger sets the logger of the plugin
+func (p *Processor) SetLogger(logger log.Logger) {
+	p.logger = logger
+}
+
+// Info returns the plugin information
+func (p *Processor) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Rename keys of custom properties and labels",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"processor", "transform"},
+	}
+}
+
+// Validate validates the plugin configuration
+func (p *Processor) Validate(configMap map[string]interface{}) (err error) {
+	var config Config
+	if err = utils.BuildConfig(configMap, &config); err != nil {
+		return err
+	}
+	if _, err = buildRenames(config.Attributes); err != nil {
+		return errors.Wrap(err, "invalid attributes")
+	}
+	if _, err = buildRenames(config.Labels); err != nil {
+		return errors.Wrap(err, "invalid labels")
+	}
+
+	return
+}
+
+// Init initializes the processor
+func (p *Processor) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &p.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}
+	}
+	if p.attributes, err = buildRenames(p.config.Attributes); err != nil {
+		return errors.Wrap(err, "invalid attributes")
+	}
+	if p.labels, err = buildRenames(p.config.Labels); err != nil {
+		return errors.Wrap(err, "invalid labels")
+	}
+
+	return
+}
+
+// Process renames the keys of the record's custom properties and labels, missing keys are ignored
+func (p *Processor) Process(ctx context.Context, src models.Record) (dst models.Record, err error) {
+	properties := src.Data().GetProperties()
+	if properties == nil {
+		return src, nil
+	}
+
+	if properties.Attributes != nil {
+		if err = p.renameAttributes(properties.Attributes); err != nil {
+			return src, errors.Wrapf(err, "failed to rename attributes of \"%s\"", src.Data().GetResource().GetUrn())
+		}
+	}
+	if properties.Labels != nil {
+		if err = p.renameLabels(properties.Labels); err != nil {
+			return src, errors.Wrapf(err, "failed to rename labels of \"%s\"", src.Data().GetResource().GetUrn())
+		}
+	}
+
+	return src, nil
+}
+
+func (p *Processor) renameAttributes(attributes *structpb.Struct) error {
+	moves, err := p.plan(p.attributes, func(key string) bool {
+		_, ok := attributes.Fields[key]
+		return ok
+	})
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]*structpb.Value, len(moves))
+	for _, m := range moves {
+		values[m.From] = attributes.Fields[m.From]
+		delete(attributes.Fields, m.From)
+	}
+	for _, m := range moves {
+		attributes.Fields[m.To] = values[m.From]
+	}
+
+	return nil
+}
+
+func (p *Processor) renameLabels(labels map[string]string) error {
+	moves, err := p.plan(p.labels, func(key string) bool {
+		_, ok := labels[key]
+		return ok
+	})
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]string, len(moves))
+	for _, m := range moves {
+		values[m.From] = labels[m.From]
+		delete(labels, m.From)
+	}
+	for _, m := range moves {
+		labels[m.To] = values[m.From]
+	}
+
+	return nil
+}
+
+// plan returns the renames of existing keys, failing if a new name is already
+// in use by a key that is not renamed itself, unless overwrite is set
+func (p *Processor) plan(renames []rename, exists func(key string) bool) (moves []rename, err error) {
+	renamed := make(map[string]bool)
+	for _, r := range renames {
+		if exists(r.From) {
+			moves = append(moves, r)
+			renamed[r.From] = true
+		}
+	}
+	if p.config.Overwrite {
+		return
+	}
+	for _, m := range moves {
+		if exists(m.To) && !renamed[m.To] {
+			return nil, fmt.Errorf("cannot rename \"%s\", \"%s\" already exists", m.From, m.To)
+		}
+	}
+
+	return
+}
+
+// buildRenames sorts the renames by key and checks that new names are unique
+func buildRenames(config map[string]string) (renames []rename, err error) {
+	keys := make([]string, 0, len(config))
+	for from := range config {
+		keys = append(keys, from)
+	}
+	sort.Strings(keys)
+
+	targets := make(map[string]string)
+	for _, from := range keys {
+		to := config[from]
+		if to == "" {
+			return nil, fmt.Errorf("empty new name for \"%s\"", from)
+		}
+		if other, ok := targets[to]; ok {
+			return nil, fmt.Errorf("\"%s\" and \"%s\" are both renamed to \"%s\"", other, from, to)
+		}
+		targets[to] = from
+		if from != to {
+			renames = append(renames, rename{From: from, To: to})
+		}
+	}
+
+	return
+}
+
+func init() {
+	if err := registry.Processors.Register("rename", func() plugins.Processor {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,102 @@
+package rename_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/processors/rename"
+	testutils "github.com/odpf/meteor/test/utils"
+	"github.com/odpf/meteor/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("should return error for invalid config", func(t *testing.T) {
+		err := rename.New(testutils.Logger).Init(context.TODO(), map[string]interface{}{})
+		assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}, err)
+	})
+
+	t.Run("should return error when keys are renamed to the same name", func(t *testing.T) {
+		err := rename.New(testutils.Logger).Init(context.TODO(), map[string]interface{}{
+			"labels": map[string]interface{}{"owner": "team", "owner_team": "team"},
+		})
+		assert.EqualError(t, err, "invalid labels: \"owner\" and \"owner_team\" are both renamed to \"team\"")
+	})
+}
+
+func TestProcess(t *testing.T) {
+	newRecord := func(attributes map[string]interface{}, labels map[string]string) models.Record {
+		return models.NewRecord(&assetsv1beta1.Table{
+			Resource: &commonv1beta1.Resource{Urn: "orders"},
+			Properties: &facetsv1beta1.Properties{
+				Attributes: utils.TryParseMapToProto(attributes),
+				Labels:     labels,
+			},
+		})
+	}
+	newProcessor := func(t *testing.T, overwrite bool) *rename.Processor {
+		proc := rename.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{
+			"attributes": map[string]interface{}{"db_name": "database", "schema": "namespace"},
+			"labels":     map[string]interface{}{"owner_team": "team"},
+			"overwrite":  overwrite,
+		}))
+		return proc
+	}
+
+	t.Run("should rename keys and ignore missing ones", func(t *testing.T) {
+		dst, err := newProcessor(t, false).Process(context.TODO(), newRecord(
+			map[string]interface{}{"db_name": "sales", "rows": 10},
+			map[string]string{"owner_team": "payments", "tier": "gold"},
+		))
+		require.NoError(t, err)
+
+		props := dst.Data().GetProperties()
+		assert.Equal(t, map[string]interface{}{"database": "sales", "rows": float64(10)}, props.Attributes.AsMap())
+		assert.Equal(t, map[string]string{"team": "payments", "tier": "gold"}, props.Labels)
+	})
+
+	t.Run("should return error on collision without overwrite", func(t *testing.T) {
+		_, err := newProcessor(t, false).Process(context.TODO(), newRecord(
+			map[string]interface{}{"db_name": "sales", "database": "orders_db"},
+			nil,
+		))
+		assert.EqualError(t, err, "failed to rename attributes of \"orders\": cannot rename \"db_name\", \"database\" already exists")
+	})
+
+	t.Run("should overwrite existing key with overwrite", func(t *testing.T) {
+		dst, err := newProcessor(t, true).Process(context.TODO(), newRecord(
+			nil,
+			map[string]string{"owner_team": "payments", "team": "sales"},
+		))
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"team": "payments"}, dst.Data().GetProperties().Labels)
+	})
+
+	t.Run("should swap keys renamed to each other", func(t *testing.T) {
+		proc := rename.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{
+			"labels": map[string]interface{}{"a": "b", "b": "a"},
+		}))
+
+		dst, err := proc.Process(context.TODO(), newRecord(nil, map[string]string{"a": "1", "b": "2"}))
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"a": "2", "b": "1"}, dst.Data().GetProperties().Labels)
+	})
+
+	t.Run("should pass records without properties", func(t *testing.T) {
+		src := models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "orders"}})
+
+		dst, err := newProcessor(t, false).Process(context.TODO(), src)
+		require.NoError(t, err)
+		assert.Equal(t, src, dst)
+	})
+}
@@ -0,0 +1,140 @@
+package sample
+
+import (
+	"context"
+	"crypto/md5"
+	_ "embed"
+	"encoding/binary"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/odpf/meteor/models"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+)
+
+//go:embed README.md
+var summary string
+
+const (
+	// MethodHash samples records by the hash of their urn, reruns keep the same records
+	MethodHash = "hash"
+	// MethodRandom samples records randomly
+	MethodRandom = "random"
+)
+
+// Config holds the set of configuration for the sample processor,
+// exactly one of Rate and EveryNth has to be set.
+type Config struct {
+	// Rate is the fraction of records kept
+	Rate float64 `mapstructure:"rate" validate:"required_without=EveryNth,excluded_with=EveryNth,gte=0,lte=1"`
+	// EveryNth keeps the first record and every nth record after it, in the order they are emitted
+	EveryNth uint64 `mapstructure:"every_nth" validate:"required_without=Rate,excluded_with=Rate"`
+	Method   string `mapstructure:"method" validate:"oneof=hash random" default:"hash"`
+	// Seed of the random method, the current time is used if 0
+	Seed int64 `mapstructure:"seed"`
+}
+
+var sampleConfig = `
+# fraction of records kept, or keep one out of every_nth records
+rate: 0.1
+# hash of the urn for reproducible runs, or random
+method: hash`
+
+// Processor drops records not in the sample
+type Processor struct {
+	config Config
+	mu     sync.Mutex
+	rand   *rand.Rand
+	// count is the number of records processed, for every_nth
+	count  uint64
+	logger log.Logger
+}
+
+// New create a new processor
+func New(logger log.Logger) *Processor {
+	return &Processor{
+		logger: logger,
+	}
+}
+
+// SetLogger sets the logger of the plugin
+func (p *Processor) SetLogger(logger log.Logger) {
+	p.logger = logger
+}
+
+// Info returns the plugin information
+func (p *Processor) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Keep a sample of the records",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"processor", "sample"},
+	}
+}
+
+// Validate validates the plugin configuration
+func (p *Processor) Validate(configMap map[string]interface{}) (err error) {
+	return utils.BuildConfig(configMap, &Config{})
+}
+
+// Init initializes the processor
+func (p *Processor) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &p.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}
+	}
+
+	seed := p.config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	p.rand = rand.New(rand.NewSource(seed))
+
+	return
+}
+
+// Process drops the record if it is not in the sample
+func (p *Processor) Process(ctx context.Context, src models.Record) (dst models.Record, err error) {
+	if p.keep(src) {
+		return src, nil
+	}
+
+	return src, plugins.NewDropRecordError("not sampled")
+}
+
+func (p *Processor) keep(record models.Record) bool {
+	if p.config.EveryNth > 0 {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		keep := p.count%p.config.EveryNth == 0
+		p.count++
+		return keep
+	}
+
+	var value uint64
+	if p.config.Method == MethodHash {
+		// fnv is not uniform enough for urns sharing long prefixes
+		sum := md5.Sum([]byte(record.Data().GetResource().GetUrn()))
+		value = binary.BigEndian.Uint64(sum[:8])
+	} else {
+		p.mu.Lock()
+		value = p.rand.Uint64()
+		p.mu.Unlock()
+	}
+
+	// compare the value as a fraction of the uint64 range to the rate
+	return float64(value) < p.config.Rate*math.MaxUint64
+}
+
+func init() {
+	if err := registry.Processors.Register("sample", func() plugins.Processor {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,90 @@
+package sample_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/processors/sample"
+	testutils "github.com/odpf/meteor/test/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const recordCount = 10000
+
+// sampled returns the urns of the records kept by a processor with the given config
+func sampled(t *testing.T, config map[string]interface{}) []string {
+	proc := sample.New(testutils.Logger)
+	require.NoError(t, proc.Init(context.TODO(), config))
+
+	var urns []string
+	for i := 0; i < recordCount; i++ {
+		urn := fmt.Sprintf("urn-%d", i)
+		_, err := proc.Process(context.TODO(), models.NewRecord(&assetsv1beta1.Table{
+			Resource: &commonv1beta1.Resource{Urn: urn},
+		}))
+		if errors.Is(err, plugins.DropRecordError{}) {
+			continue
+		}
+		require.NoError(t, err)
+		urns = append(urns, urn)
+	}
+
+	return urns
+}
+
+func TestInit(t *testing.T) {
+	t.Run("should return error for invalid config", func(t *testing.T) {
+		invalidConfigs := []map[string]interface{}{
+			{},
+			{"rate": 1.5},
+			{"rate": 0.1, "every_nth": 10},
+			{"rate": 0.1, "method": "sequential"},
+		}
+		for i, config := range invalidConfigs {
+			t.Run(fmt.Sprintf("test invalid config #%d", i+1), func(t *testing.T) {
+				err := sample.New(testutils.Logger).Init(context.TODO(), config)
+				assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}, err)
+			})
+		}
+	})
+}
+
+func TestProcess(t *testing.T) {
+	t.Run("should keep about the rate of records", func(t *testing.T) {
+		for _, method := range []string{sample.MethodHash, sample.MethodRandom} {
+			urns := sampled(t, map[string]interface{}{"rate": 0.1, "method": method, "seed": 1})
+
+			assert.InDelta(t, recordCount/10, len(urns), recordCount/50, method)
+		}
+	})
+
+	t.Run("should keep every nth record in emit order", func(t *testing.T) {
+		urns := sampled(t, map[string]interface{}{"every_nth": 4})
+
+		require.Len(t, urns, recordCount/4)
+		assert.Equal(t, []string{"urn-0", "urn-4", "urn-8"}, urns[:3])
+	})
+
+	t.Run("should keep all records with rate of one", func(t *testing.T) {
+		assert.Len(t, sampled(t, map[string]interface{}{"rate": 1}), recordCount)
+	})
+
+	t.Run("should keep the same records on reruns with hash method", func(t *testing.T) {
+		config := map[string]interface{}{"rate": 0.1}
+
+		assert.Equal(t, sampled(t, config), sampled(t, config))
+	})
+
+	t.Run("should keep the same records with the same seed", func(t *testing.T) {
+		config := map[string]interface{}{"rate": 0.1, "method": sample.MethodRandom, "seed": 42}
+
+		assert.Equal(t, sampled(t, config), sampled(t, config))
+	})
+}
@@ -0,0 +1,146 @@
+package scope
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+)
+
+//go:embed README.md
+var summary string
+
+// ServiceInstanceLabel is the label holding the service instance records are extracted from
+const ServiceInstanceLabel = "service_instance"
+
+// Config holds the set of configuration for the scope processor
+type Config struct {
+	// ServiceInstance identifies the instance of the service the records are extracted from, e.g. prod-eu
+	ServiceInstance string            `mapstructure:"service_instance" validate:"required"`
+	Labels          map[string]string `mapstructure:"labels"`
+	// URNPrefix is prepended to the urns of records and the lineage references of the same service
+	URNPrefix string `mapstructure:"urn_prefix"`
+}
+
+var sampleConfig = `
+# instance of the service records are extracted from, set as service_instance label
+service_instance: prod-eu
+# labels set on every record, overwriting existing ones
+labels:
+  environment: production
+# prepended to urns of records and lineage references of the same service
+urn_prefix: "prod-eu:"`
+
+// Processor stamps records with the identity of the service instance they are extracted from
+type Processor struct {
+	config Config
+	logger log.Logger
+}
+
+// New create a new processor
+func New(logger log.Logger) *Processor {
+	return &Processor{
+		logger: logger,
+	}
+}
+
+// SetLogger sets the logger of the plugin
+func (p *Processor) SetLogger(logger log.Logger) {
+	p.logger = logger
+}
+
+// Info returns the plugin information
+func (p *Processor) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Stamp records with the service instance they are extracted from",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"processor", "transform"},
+	}
+}
+
+// Validate validates the plugin configuration
+func (p *Processor) Validate(configMap map[string]interface{}) (err error) {
+	return utils.BuildConfig(configMap, &Config{})
+}
+
+// Init initializes the processor
+func (p *Processor) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &p.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}
+	}
+
+	return
+}
+
+// Process labels the record with the service instance, and prefixes its urns if configured
+func (p *Processor) Process(ctx context.Context, src models.Record) (dst models.Record, err error) {
+	data := src.Data()
+	// properties are created on records without any
+	result, err := utils.SetCustomProperties(data, utils.GetCustomProperties(data))
+	if err != nil {
+		return src, errors.Wrapf(err, "failed to scope \"%s\"", data.GetResource().GetUrn())
+	}
+
+	properties := result.GetProperties()
+	if properties.Labels == nil {
+		properties.Labels = make(map[string]string)
+	}
+	for key, value := range p.config.Labels {
+		properties.Labels[key] = value
+	}
+	properties.Labels[ServiceInstanceLabel] = p.config.ServiceInstance
+
+	if p.config.URNPrefix != "" {
+		p.prefixURNs(result)
+	}
+
+	return models.NewRecord(result), nil
+}
+
+// prefixURNs prefixes the urn of the record and its lineage references without service
+// or of the service of the record, references to other services being left as they are.
+// The urn of lineage edges is built from their source and target, only these are prefixed.
+func (p *Processor) prefixURNs(data models.Metadata) {
+	if edge, ok := data.(*models.LineageEdge); ok {
+		p.prefixReference(edge.Source, "")
+		p.prefixReference(edge.Target, "")
+		return
+	}
+
+	resource := data.GetResource()
+	if resource == nil {
+		return
+	}
+	if lm, ok := data.(models.LineageMetadata); ok {
+		for _, upstream := range lm.GetLineage().GetUpstreams() {
+			p.prefixReference(upstream, resource.Service)
+		}
+		for _, downstream := range lm.GetLineage().GetDownstreams() {
+			p.prefixReference(downstream, resource.Service)
+		}
+	}
+	resource.Urn = p.config.URNPrefix + resource.Urn
+}
+
+func (p *Processor) prefixReference(reference *commonv1beta1.Resource, service string) {
+	if reference == nil || (reference.Service != "" && reference.Service != service) {
+		return
+	}
+	reference.Urn = p.config.URNPrefix + reference.Urn
+}
+
+func init() {
+	if err := registry.Processors.Register("scope", func() plugins.Processor {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,125 @@
+package scope_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/processors/scope"
+	testutils "github.com/odpf/meteor/test/utils"
+	"github.com/odpf/meteor/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("should return error without service instance", func(t *testing.T) {
+		err := scope.New(testutils.Logger).Init(context.TODO(), map[string]interface{}{
+			"labels": map[string]interface{}{"environment": "production"},
+		})
+		assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}, err)
+	})
+}
+
+func TestProcess(t *testing.T) {
+	t.Run("should set service instance and labels", func(t *testing.T) {
+		proc := scope.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{
+			"service_instance": "prod-eu",
+			"labels":           map[string]interface{}{"environment": "production"},
+		}))
+
+		for _, data := range []models.Metadata{
+			&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sales.orders"}},
+			&assetsv1beta1.Topic{
+				Resource: &commonv1beta1.Resource{Urn: "orders"},
+				Properties: &facetsv1beta1.Properties{
+					Attributes: utils.TryParseMapToProto(map[string]interface{}{"partitions": 3}),
+					Labels:     map[string]string{"team": "sales", "environment": "dev"},
+				},
+			},
+		} {
+			dst, err := proc.Process(context.TODO(), models.NewRecord(data))
+			require.NoError(t, err)
+
+			labels := dst.Data().GetProperties().GetLabels()
+			assert.Equal(t, "prod-eu", labels[scope.ServiceInstanceLabel])
+			assert.Equal(t, "production", labels["environment"])
+			assert.Equal(t, data.GetResource().Urn, dst.Data().GetResource().Urn)
+		}
+	})
+
+	t.Run("should keep other labels and attributes", func(t *testing.T) {
+		proc := scope.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{
+			"service_instance": "prod-eu",
+		}))
+
+		dst, err := proc.Process(context.TODO(), models.NewRecord(&assetsv1beta1.Topic{
+			Resource: &commonv1beta1.Resource{Urn: "orders"},
+			Properties: &facetsv1beta1.Properties{
+				Attributes: utils.TryParseMapToProto(map[string]interface{}{"partitions": 3}),
+				Labels:     map[string]string{"team": "sales"},
+			},
+		}))
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"team": "sales", "service_instance": "prod-eu"}, dst.Data().GetProperties().GetLabels())
+		assert.Equal(t, map[string]interface{}{"partitions": float64(3)}, utils.GetCustomProperties(dst.Data()))
+	})
+
+	t.Run("should prefix urns of records and lineage references of the same service", func(t *testing.T) {
+		proc := scope.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{
+			"service_instance": "prod-eu",
+			"urn_prefix":       "prod-eu:",
+		}))
+
+		dst, err := proc.Process(context.TODO(), models.NewRecord(&assetsv1beta1.Table{
+			Resource: &commonv1beta1.Resource{Urn: "sales.order_totals", Service: "postgres"},
+			Lineage: &facetsv1beta1.Lineage{
+				Upstreams: []*commonv1beta1.Resource{
+					{Urn: "sales.orders"},
+					{Urn: "sales.customers", Service: "postgres"},
+					{Urn: "orders-topic", Service: "kafka"},
+				},
+				Downstreams: []*commonv1beta1.Resource{
+					{Urn: "sales.daily_totals", Service: "postgres"},
+				},
+			},
+		}))
+		require.NoError(t, err)
+
+		table := dst.Data().(*assetsv1beta1.Table)
+		assert.Equal(t, "prod-eu:sales.order_totals", table.Resource.Urn)
+		assert.Equal(t, []*commonv1beta1.Resource{
+			{Urn: "prod-eu:sales.orders"},
+			{Urn: "prod-eu:sales.customers", Service: "postgres"},
+			{Urn: "orders-topic", Service: "kafka"},
+		}, table.Lineage.Upstreams)
+		assert.Equal(t, "prod-eu:sales.daily_totals", table.Lineage.Downstreams[0].Urn)
+	})
+
+	t.Run("should prefix source and target of lineage records without service", func(t *testing.T) {
+		proc := scope.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{
+			"service_instance": "prod-eu",
+			"urn_prefix":       "prod-eu:",
+		}))
+
+		dst, err := proc.Process(context.TODO(), models.NewLineageRecord(
+			&commonv1beta1.Resource{Urn: "orders-topic", Service: "kafka"},
+			&commonv1beta1.Resource{Urn: "sales.orders"},
+		))
+		require.NoError(t, err)
+
+		edge := dst.Data().(*models.LineageEdge)
+		assert.Equal(t, "orders-topic", edge.Source.Urn)
+		assert.Equal(t, "prod-eu:sales.orders", edge.Target.Urn)
+		assert.Equal(t, "prod-eu", edge.Properties.Labels[scope.ServiceInstanceLabel])
+	})
+}
@@ -0,0 +1,128 @@
+package sortcolumns
+
+import (
+	"context"
+	_ "embed"
+	"sort"
+
+	"github.com/odpf/meteor/models"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"google.golang.org/protobuf/proto"
+)
+
+//go:embed README.md
+var summary string
+
+// Config holds the set of configuration for the sort_columns processor
+type Config struct {
+	// KeepDuplicates keeps columns equal to a previous column in every field
+	KeepDuplicates bool `mapstructure:"keep_duplicates"`
+}
+
+var sampleConfig = `
+# keep columns equal to a previous column in every field
+keep_duplicates: false`
+
+// Processor sorts the columns of tables by name, so that they are emitted in the same order across runs
+type Processor struct {
+	config Config
+	logger log.Logger
+}
+
+// New create a new processor
+func New(logger log.Logger) *Processor {
+	return &Processor{
+		logger: logger,
+	}
+}
+
+// SetLogger sets the logger of the plugin
+func (p *Processor) SetLogger(logger log.Logger) {
+	p.logger = logger
+}
+
+// Info returns the plugin information
+func (p *Processor) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Sort columns of tables by name and remove duplicate columns",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"processor", "transform"},
+	}
+}
+
+// Validate validates the plugin configuration
+func (p *Processor) Validate(configMap map[string]interface{}) (err error) {
+	return utils.BuildConfig(configMap, &Config{})
+}
+
+// Init initializes the processor
+func (p *Processor) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &p.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}
+	}
+
+	return
+}
+
+// Process sorts the columns of tables, other records are passed as they are
+func (p *Processor) Process(ctx context.Context, src models.Record) (dst models.Record, err error) {
+	table, ok := src.Data().(*assetsv1beta1.Table)
+	if !ok || len(table.GetSchema().GetColumns()) == 0 {
+		return src, nil
+	}
+
+	columns := table.Schema.Columns
+	if !p.config.KeepDuplicates {
+		columns = uniqueColumns(columns)
+		if removed := len(table.Schema.Columns) - len(columns); removed > 0 {
+			p.logger.Debug("removed duplicate columns", "table", table.GetResource().GetUrn(), "count", removed)
+		}
+	}
+	// columns sharing a name keep the order they were extracted in
+	sort.SliceStable(columns, func(i, j int) bool {
+		return columns[i].Name < columns[j].Name
+	})
+	table.Schema.Columns = columns
+
+	return models.NewRecord(table), nil
+}
+
+// uniqueColumns returns the columns without the ones equal to a previous column
+func uniqueColumns(columns []*facetsv1beta1.Column) []*facetsv1beta1.Column {
+	byName := make(map[string][]*facetsv1beta1.Column, len(columns))
+	unique := make([]*facetsv1beta1.Column, 0, len(columns))
+	for _, column := range columns {
+		if containsColumn(byName[column.Name], column) {
+			continue
+		}
+		byName[column.Name] = append(byName[column.Name], column)
+		unique = append(unique, column)
+	}
+
+	return unique
+}
+
+func containsColumn(columns []*facetsv1beta1.Column, column *facetsv1beta1.Column) bool {
+	for _, c := range columns {
+		if proto.Equal(c, column) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func init() {
+	if err := registry.Processors.Register("sort_columns", func() plugins.Processor {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,83 @@
+package sortcolumns_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins/processors/sortcolumns"
+	testutils "github.com/odpf/meteor/test/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTable() *assetsv1beta1.Table {
+	return &assetsv1beta1.Table{
+		Resource: &commonv1beta1.Resource{Urn: "sales.orders"},
+		Schema: &facetsv1beta1.Columns{
+			Columns: []*facetsv1beta1.Column{
+				{Name: "total", DataType: "decimal"},
+				{Name: "id", DataType: "int"},
+				{Name: "address", DataType: "text"},
+				{Name: "id", DataType: "int"},
+				{Name: "address", DataType: "varchar"},
+			},
+		},
+	}
+}
+
+func columnTypes(record models.Record) (types [][]string) {
+	for _, c := range record.Data().(*assetsv1beta1.Table).Schema.Columns {
+		types = append(types, []string{c.Name, c.DataType})
+	}
+
+	return
+}
+
+func TestProcess(t *testing.T) {
+	t.Run("should sort columns by name and remove duplicates", func(t *testing.T) {
+		proc := sortcolumns.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{}))
+
+		dst, err := proc.Process(context.TODO(), models.NewRecord(newTable()))
+		require.NoError(t, err)
+
+		assert.Equal(t, [][]string{
+			{"address", "text"},
+			{"address", "varchar"},
+			{"id", "int"},
+			{"total", "decimal"},
+		}, columnTypes(dst))
+	})
+
+	t.Run("should keep duplicates if configured", func(t *testing.T) {
+		proc := sortcolumns.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{
+			"keep_duplicates": true,
+		}))
+
+		dst, err := proc.Process(context.TODO(), models.NewRecord(newTable()))
+		require.NoError(t, err)
+
+		assert.Equal(t, [][]string{
+			{"address", "text"},
+			{"address", "varchar"},
+			{"id", "int"},
+			{"id", "int"},
+			{"total", "decimal"},
+		}, columnTypes(dst))
+	})
+
+	t.Run("should pass records other than tables as they are", func(t *testing.T) {
+		proc := sortcolumns.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{}))
+
+		topic := models.NewRecord(&assetsv1beta1.Topic{Resource: &commonv1beta1.Resource{Urn: "orders"}})
+		dst, err := proc.Process(context.TODO(), topic)
+		require.NoError(t, err)
+		assert.Equal(t, topic, dst)
+	})
+}
@@ -0,0 +1,143 @@
+package validate
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/odpf/meteor/models"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+)
+
+//go:embed README.md
+var summary string
+
+const (
+	modeDrop = "drop"
+	modeFail = "fail"
+)
+
+// Config holds the set of configuration for the validate processor
+type Config struct {
+	// Fields are dot separated paths of the record's JSON fields, e.g. "resource.urn"
+	Fields []string `mapstructure:"fields" validate:"required,min=1,dive,required"`
+	Mode   string   `mapstructure:"mode" validate:"oneof=drop fail" default:"drop"`
+}
+
+var sampleConfig = `
+# fields every record must have, as dot separated paths of the record's JSON fields
+fields:
+  - resource.urn
+  - resource.name
+# drop records missing a field or fail the run
+mode: drop`
+
+// Processor checks that records have all the required fields
+type Processor struct {
+	config Config
+	logger log.Logger
+}
+
+// New create a new processor
+func New(logger log.Logger) *Processor {
+	return &Processor{
+		logger: logger,
+	}
+}
+
+// SetLogger sets the logger of the plugin
+func (p *Processor) SetLogger(logger log.Logger) {
+	p.logger = logger
+}
+
+// Info returns the plugin information
+func (p *Processor) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Drop records or fail the run on missing required fields",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"processor", "validate"},
+	}
+}
+
+// Validate validates the plugin configuration
+func (p *Processor) Validate(configMap map[string]interface{}) (err error) {
+	return utils.BuildConfig(configMap, &Config{})
+}
+
+// Init initializes the processor
+func (p *Processor) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &p.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}
+	}
+
+	return
+}
+
+// Process drops the record or returns an error if any of the required fields is missing.
+// Lineage records are not assets and are passed as they are.
+func (p *Processor) Process(ctx context.Context, src models.Record) (dst models.Record, err error) {
+	if models.IsLineageRecord(src) {
+		return src, nil
+	}
+
+	fields, err := models.ToMap(src)
+	if err != nil {
+		return src, errors.Wrap(err, "failed to read record fields")
+	}
+
+	var missing []string
+	for _, field := range p.config.Fields {
+		if !present(fields, field) {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) == 0 {
+		return src, nil
+	}
+
+	urn := src.Data().GetResource().GetUrn()
+	reason := fmt.Sprintf("missing required fields %s", strings.Join(missing, ", "))
+	if p.config.Mode == modeFail {
+		return src, fmt.Errorf("invalid record \"%s\": %s", urn, reason)
+	}
+
+	p.logger.Warn("dropping invalid record", "record", urn, "missing", missing)
+	return src, plugins.NewDropRecordError(reason)
+}
+
+// present checks if the value at the dot separated path is set,
+// null values, empty strings, lists and objects count as missing
+func present(fields map[string]interface{}, path string) bool {
+	value, found := models.Lookup(fields, path)
+	if !found {
+		return false
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return false
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
+
+func init() {
+	if err := registry.Processors.Register("validate", func() plugins.Processor {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
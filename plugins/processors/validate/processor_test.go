@@ -0,0 +1,83 @@
+package validate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/processors/validate"
+	testutils "github.com/odpf/meteor/test/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("should return error for invalid config", func(t *testing.T) {
+		err := validate.New(testutils.Logger).Init(context.TODO(), map[string]interface{}{})
+		assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}, err)
+	})
+
+	t.Run("should return error for invalid mode", func(t *testing.T) {
+		err := validate.New(testutils.Logger).Init(context.TODO(), map[string]interface{}{
+			"fields": []string{"resource.urn"},
+			"mode":   "route",
+		})
+		assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeProcessor}, err)
+	})
+}
+
+func TestProcess(t *testing.T) {
+	fields := []string{"resource.urn", "resource.name", "schema.columns"}
+	valid := models.NewRecord(&assetsv1beta1.Table{
+		Resource: &commonv1beta1.Resource{Urn: "db.orders", Name: "orders"},
+		Schema: &facetsv1beta1.Columns{
+			Columns: []*facetsv1beta1.Column{{Name: "id"}},
+		},
+	})
+	invalid := models.NewRecord(&assetsv1beta1.Table{
+		Resource: &commonv1beta1.Resource{Urn: "db.orders"},
+		Schema:   &facetsv1beta1.Columns{},
+	})
+
+	t.Run("should keep records with all the required fields", func(t *testing.T) {
+		proc := validate.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{"fields": fields}))
+
+		dst, err := proc.Process(context.TODO(), valid)
+		assert.NoError(t, err)
+		assert.Equal(t, valid, dst)
+	})
+
+	t.Run("should drop records missing a field by default", func(t *testing.T) {
+		proc := validate.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{"fields": fields}))
+
+		_, err := proc.Process(context.TODO(), invalid)
+		assert.True(t, errors.Is(err, plugins.DropRecordError{}))
+		assert.EqualError(t, err, "record dropped: missing required fields resource.name, schema.columns")
+	})
+
+	t.Run("should return error for records missing a field in fail mode", func(t *testing.T) {
+		proc := validate.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{"fields": fields, "mode": "fail"}))
+
+		_, err := proc.Process(context.TODO(), invalid)
+		assert.False(t, errors.Is(err, plugins.DropRecordError{}))
+		assert.EqualError(t, err, "invalid record \"db.orders\": missing required fields resource.name, schema.columns")
+	})
+
+	t.Run("should not validate lineage records", func(t *testing.T) {
+		proc := validate.New(testutils.Logger)
+		require.NoError(t, proc.Init(context.TODO(), map[string]interface{}{"fields": fields, "mode": "fail"}))
+
+		edge := models.NewLineageRecord(&commonv1beta1.Resource{Urn: "a"}, &commonv1beta1.Resource{Urn: "b"})
+		dst, err := proc.Process(context.TODO(), edge)
+		assert.NoError(t, err)
+		assert.Equal(t, edge, dst)
+	})
+}
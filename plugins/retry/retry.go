@@ -0,0 +1,134 @@
+package retry
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultConnectInterval = 1 * time.Second
+	defaultQueryInterval   = 100 * time.Millisecond
+)
+
+// authErrorMessages are parts of the messages of authentication failures reported by the drivers,
+// connecting again would fail the same way.
+var authErrorMessages = []string{
+	"access denied",
+	"authentication failed",
+	"unauthorized",
+	"invalid username/password",
+	"password is incorrect",
+	"password are incorrect",
+}
+
+// ConnectionConfig holds the retries of the initial connection of an extractor,
+// to be squashed into the config of the extractor.
+type ConnectionConfig struct {
+	ConnectionRetries int `mapstructure:"connection_retries" validate:"gte=0"`
+	// ConnectionRetryInterval is the backoff before the first retry, doubled on each retry
+	ConnectionRetryInterval string `mapstructure:"connection_retry_interval" default:"1s"`
+}
+
+// ConnectPolicy returns the connection retry policy of the config.
+func (c ConnectionConfig) ConnectPolicy() (Policy, error) {
+	interval, err := time.ParseDuration(c.ConnectionRetryInterval)
+	if err != nil {
+		return Policy{}, errors.Wrap(err, "invalid connection_retry_interval")
+	}
+	if interval <= 0 {
+		return Policy{}, errors.Errorf("connection_retry_interval must be positive, got %s", interval)
+	}
+
+	return Policy{
+		MaxRetries:      c.ConnectionRetries,
+		InitialInterval: interval,
+	}, nil
+}
+
+// Policy holds the number of retries and the first backoff interval.
+type Policy struct {
+	MaxRetries      int
+	InitialInterval time.Duration
+}
+
+// Retrier retries connection and query failures with independent policies,
+// so a slow-to-start database gets patient connection retries while a
+// failing query is retried quickly.
+type Retrier struct {
+	Connect Policy
+	Query   Policy
+}
+
+// NewRetrier returns a Retrier using the default backoff intervals.
+func NewRetrier(connectRetries, queryRetries int) *Retrier {
+	return &Retrier{
+		Connect: Policy{
+			MaxRetries:      connectRetries,
+			InitialInterval: defaultConnectInterval,
+		},
+		Query: Policy{
+			MaxRetries:      queryRetries,
+			InitialInterval: defaultQueryInterval,
+		},
+	}
+}
+
+// RetryConnect runs the operation using the connection retry policy,
+// authentication failures are returned without retrying.
+func (r *Retrier) RetryConnect(ctx context.Context, operation func() error) error {
+	return run(ctx, r.Connect, func() error {
+		err := operation()
+		if err != nil && IsAuthError(err) {
+			return backoff.Permanent(err)
+		}
+		return err
+	})
+}
+
+// RetryQuery runs the operation using the query retry policy.
+func (r *Retrier) RetryQuery(ctx context.Context, operation func() error) error {
+	return run(ctx, r.Query, operation)
+}
+
+// PingContext verifies the connection to the database, retrying with the connection policy.
+func (r *Retrier) PingContext(ctx context.Context, db *sql.DB) error {
+	return r.RetryConnect(ctx, func() error {
+		return db.PingContext(ctx)
+	})
+}
+
+// QueryContext executes a query, retrying with the query policy.
+func (r *Retrier) QueryContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) (rows *sql.Rows, err error) {
+	err = r.RetryQuery(ctx, func() (err error) {
+		rows, err = db.QueryContext(ctx, query, args...)
+		return
+	})
+	return
+}
+
+// IsAuthError reports whether err is an authentication failure of the database driver.
+func IsAuthError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, m := range authErrorMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func run(ctx context.Context, policy Policy, operation func() error) error {
+	ebo := backoff.NewExponentialBackOff()
+	ebo.InitialInterval = policy.InitialInterval
+	ebo.RandomizationFactor = 0
+	ebo.Multiplier = 2
+
+	bo := backoff.WithContext(backoff.WithMaxRetries(ebo, uint64(policy.MaxRetries)), ctx)
+	return backoff.Retry(operation, bo)
+}
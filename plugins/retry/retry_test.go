@@ -0,0 +1,125 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/odpf/meteor/plugins/retry"
+	"github.com/odpf/meteor/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetrier(t *testing.T) {
+	newRetrier := func(connectRetries, queryRetries int) *retry.Retrier {
+		r := retry.NewRetrier(connectRetries, queryRetries)
+		r.Connect.InitialInterval = time.Millisecond
+		r.Query.InitialInterval = time.Millisecond
+		return r
+	}
+
+	t.Run("should retry connection errors using connect retries", func(t *testing.T) {
+		var calls int
+		err := newRetrier(3, 1).RetryConnect(context.TODO(), func() error {
+			calls++
+			return errors.New("connection refused")
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("should retry query errors using query retries", func(t *testing.T) {
+		var calls int
+		err := newRetrier(3, 1).RetryQuery(context.TODO(), func() error {
+			calls++
+			return errors.New("deadlock found")
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should stop retrying once the operation succeeds", func(t *testing.T) {
+		var calls int
+		err := newRetrier(3, 3).RetryConnect(context.TODO(), func() error {
+			calls++
+			if calls < 2 {
+				return errors.New("connection refused")
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should not retry when retries are zero", func(t *testing.T) {
+		var calls int
+		err := newRetrier(0, 0).RetryQuery(context.TODO(), func() error {
+			calls++
+			return errors.New("deadlock found")
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+	t.Run("should not retry authentication failures", func(t *testing.T) {
+		var calls int
+		authErr := errors.New("Error 1045: Access denied for user 'meteor'@'localhost' (using password: YES)")
+		err := newRetrier(3, 3).RetryConnect(context.TODO(), func() error {
+			calls++
+			return authErr
+		})
+
+		assert.Equal(t, authErr, err)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestConnectionConfig(t *testing.T) {
+	t.Run("should default to a one second interval", func(t *testing.T) {
+		var config retry.ConnectionConfig
+		require.NoError(t, utils.BuildConfig(map[string]interface{}{
+			"connection_retries": 3,
+		}, &config))
+
+		policy, err := config.ConnectPolicy()
+		require.NoError(t, err)
+		assert.Equal(t, retry.Policy{MaxRetries: 3, InitialInterval: time.Second}, policy)
+	})
+
+	t.Run("should parse the interval", func(t *testing.T) {
+		policy, err := retry.ConnectionConfig{ConnectionRetries: 2, ConnectionRetryInterval: "500ms"}.ConnectPolicy()
+		require.NoError(t, err)
+		assert.Equal(t, retry.Policy{MaxRetries: 2, InitialInterval: 500 * time.Millisecond}, policy)
+	})
+
+	t.Run("should return error for an invalid interval", func(t *testing.T) {
+		_, err := retry.ConnectionConfig{ConnectionRetryInterval: "soon"}.ConnectPolicy()
+		assert.Error(t, err)
+
+		_, err = retry.ConnectionConfig{ConnectionRetryInterval: "0s"}.ConnectPolicy()
+		assert.Error(t, err)
+	})
+}
+
+func TestIsAuthError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{errors.New("Error 1045: Access denied for user 'meteor'@'localhost' (using password: YES)"), true},
+		{errors.New("ORA-01017: invalid username/password; logon denied"), true},
+		{errors.New("auth error: sasl conversation error: unable to authenticate using mechanism \"SCRAM-SHA-256\": (AuthenticationFailed) Authentication failed."), true},
+		{errors.New("Provided username meteor and/or password are incorrect"), true},
+		{errors.New("Unauthorized: Name or password is incorrect."), true},
+		{errors.New("dial tcp 127.0.0.1:3306: connect: connection refused"), false},
+		{errors.New("i/o timeout"), false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, retry.IsAuthError(tt.err), tt.err.Error())
+	}
+}
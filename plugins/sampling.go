@@ -0,0 +1,62 @@
+package plugins
+
+import (
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// SamplingConfig is squashed into the config of the extractors emitting values read from the source,
+// such as preview rows or column profiles.
+type SamplingConfig struct {
+	// RedactSamples only keeps the field names and types of the samples, never their values
+	RedactSamples bool `mapstructure:"redact_samples" default:"true"`
+}
+
+// Sampler builds the samples of an extractor, leaving out their values unless redaction is disabled.
+type Sampler struct {
+	redact bool
+}
+
+// NewSampler returns a Sampler for the config, warning that raw values are emitted if redaction is disabled
+func NewSampler(config SamplingConfig, logger log.Logger) *Sampler {
+	if !config.RedactSamples {
+		logger.Warn("redact_samples is disabled, values sampled from the source are emitted and may hold personal data")
+	}
+
+	return &Sampler{redact: config.RedactSamples}
+}
+
+// Redacting tells if sampled values are left out, so that extractors can skip reading them
+func (s *Sampler) Redacting() bool {
+	return s.redact
+}
+
+// Preview builds the preview of the fields and rows, the rows being left out when redacting
+func (s *Sampler) Preview(fields []string, rows []interface{}) (*facetsv1beta1.Preview, error) {
+	preview := &facetsv1beta1.Preview{Fields: fields}
+	if s.redact {
+		return preview, nil
+	}
+
+	var err error
+	if preview.Rows, err = structpb.NewList(rows); err != nil {
+		return nil, errors.Wrap(err, "error creating preview list")
+	}
+
+	return preview, nil
+}
+
+// ColumnProfile returns the profile with only its counts when redacting,
+// the min, max, average, median and top values being read from the column
+func (s *Sampler) ColumnProfile(profile *facetsv1beta1.ColumnProfile) *facetsv1beta1.ColumnProfile {
+	if !s.redact || profile == nil {
+		return profile
+	}
+
+	return &facetsv1beta1.ColumnProfile{
+		Unique: profile.Unique,
+		Count:  profile.Count,
+	}
+}
@@ -0,0 +1,53 @@
+package plugins_test
+
+import (
+	"bytes"
+	"testing"
+
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	testUtils "github.com/odpf/meteor/test/utils"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSampler(t *testing.T) {
+	fields := []string{"id", "email"}
+	rows := []interface{}{
+		[]interface{}{float64(1), "jane@example.com"},
+	}
+	profile := &facetsv1beta1.ColumnProfile{Min: "a@example.com", Max: "z@example.com", Avg: 1, Med: 1, Unique: 10, Count: 12, Top: "jane@example.com"}
+
+	t.Run("should redact samples by default", func(t *testing.T) {
+		var config plugins.SamplingConfig
+		require.NoError(t, utils.BuildConfig(map[string]interface{}{}, &config))
+		assert.True(t, config.RedactSamples)
+	})
+
+	t.Run("should only keep field names and counts when redacting", func(t *testing.T) {
+		sampler := plugins.NewSampler(plugins.SamplingConfig{RedactSamples: true}, testUtils.Logger)
+		assert.True(t, sampler.Redacting())
+
+		preview, err := sampler.Preview(fields, rows)
+		require.NoError(t, err)
+		assert.Equal(t, fields, preview.Fields)
+		assert.Nil(t, preview.Rows)
+
+		assert.Equal(t, &facetsv1beta1.ColumnProfile{Unique: 10, Count: 12}, sampler.ColumnProfile(profile))
+		assert.Nil(t, sampler.ColumnProfile(nil))
+	})
+
+	t.Run("should keep values and warn when redaction is disabled", func(t *testing.T) {
+		var output bytes.Buffer
+		sampler := plugins.NewSampler(plugins.SamplingConfig{RedactSamples: false}, log.NewLogrus(log.LogrusWithWriter(&output)))
+		assert.False(t, sampler.Redacting())
+		assert.Contains(t, output.String(), "redact_samples is disabled")
+
+		preview, err := sampler.Preview(fields, rows)
+		require.NoError(t, err)
+		assert.Equal(t, rows, preview.Rows.AsSlice())
+		assert.Same(t, profile, sampler.ColumnProfile(profile))
+	})
+}
@@ -0,0 +1,258 @@
+package plugins
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONSchemaDraft is the JSON Schema version of the generated schemas.
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchema describes the config of a plugin, it is the subset of
+// JSON Schema needed to render the config options, e.g. as a form.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty" yaml:"$schema,omitempty"`
+	Type                 string                 `json:"type,omitempty" yaml:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty" yaml:"required,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty" yaml:"items,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty" yaml:"minItems,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty" yaml:"enum,omitempty"`
+	Default              interface{}            `json:"default,omitempty" yaml:"default,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty" yaml:"maximum,omitempty"`
+}
+
+// ConfigSchema reflects over a config struct and returns its JSON Schema.
+// Field names are read from the mapstructure tags, required fields, enums and
+// bounds from the validate tags and default values from the default tags.
+func ConfigSchema(v interface{}) (JSONSchema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return JSONSchema{}, fmt.Errorf("config must be a struct, got %T", v)
+	}
+
+	schema, err := typeSchema(t, map[reflect.Type]bool{})
+	if err != nil {
+		return JSONSchema{}, err
+	}
+	schema.Schema = JSONSchemaDraft
+
+	return *schema, nil
+}
+
+// MustConfigSchema is like ConfigSchema but panics on error,
+// it is meant to be used in Info with the static config of a plugin.
+func MustConfigSchema(v interface{}) JSONSchema {
+	schema, err := ConfigSchema(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return schema
+}
+
+func typeSchema(t reflect.Type, visiting map[reflect.Type]bool) (*JSONSchema, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &JSONSchema{Type: "string"}, nil
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}, nil
+	case reflect.Interface:
+		return &JSONSchema{}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := typeSchema(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		values, err := typeSchema(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &JSONSchema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		// recursive types are only described once
+		if visiting[t] {
+			return &JSONSchema{Type: "object"}, nil
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		schema := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}}
+		if err := addFields(schema, t, visiting); err != nil {
+			return nil, err
+		}
+		return schema, nil
+	}
+
+	return nil, fmt.Errorf("unsupported config type %s", t)
+}
+
+// addFields adds the exported fields of the struct to the schema,
+// fields tagged with squash are added to the schema of their parent
+func addFields(schema *JSONSchema, t reflect.Type, visiting map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, opts := parseTag(field.Tag.Get("mapstructure"))
+		// exported fields of unexported embedded structs are still decoded when squashed
+		if name == "-" || (field.PkgPath != "" && !(field.Anonymous && opts["squash"])) {
+			continue
+		}
+		if opts["squash"] {
+			ft := field.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() != reflect.Struct {
+				return fmt.Errorf("field %s: squash is only supported on structs", field.Name)
+			}
+			if err := addFields(schema, ft, visiting); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema, err := typeSchema(field.Type, visiting)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		required, err := applyValidateTag(fieldSchema, field.Type, field.Tag.Get("validate"))
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if def, ok := field.Tag.Lookup("default"); ok {
+			if fieldSchema.Default, err = parseValue(field.Type, def); err != nil {
+				return fmt.Errorf("field %s: invalid default: %w", field.Name, err)
+			}
+		}
+
+		schema.Properties[name] = fieldSchema
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return nil
+}
+
+// applyValidateTag sets the enum and bounds of the schema from the validate tag.
+// Rules following dive apply to the items of a list or the values of a map.
+func applyValidateTag(schema *JSONSchema, t reflect.Type, tag string) (required bool, err error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		key, param := rule, ""
+		if idx := strings.Index(rule, "="); idx >= 0 {
+			key, param = rule[:idx], rule[idx+1:]
+		}
+
+		switch key {
+		case "required":
+			required = true
+		case "dive":
+			elem := schema.Items
+			if elem == nil {
+				elem = schema.AdditionalProperties
+			}
+			if elem == nil {
+				return false, fmt.Errorf("dive is only supported on lists and maps")
+			}
+			_, err = applyValidateTag(elem, t.Elem(), strings.Join(rules[i+1:], ","))
+			return required, err
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				parsed, err := parseValue(t, value)
+				if err != nil {
+					return false, err
+				}
+				schema.Enum = append(schema.Enum, parsed)
+			}
+		case "min", "gte":
+			switch schema.Type {
+			case "integer", "number":
+				if schema.Minimum, err = parseBound(param); err != nil {
+					return false, err
+				}
+			case "array":
+				minItems, err := strconv.Atoi(param)
+				if err != nil {
+					return false, err
+				}
+				schema.MinItems = &minItems
+			}
+		case "max", "lte":
+			if schema.Type == "integer" || schema.Type == "number" {
+				if schema.Maximum, err = parseBound(param); err != nil {
+					return false, err
+				}
+			}
+		}
+	}
+
+	return required, nil
+}
+
+func parseBound(param string) (*float64, error) {
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bound, nil
+}
+
+// parseValue converts a value of a tag to the kind of the field
+func parseValue(t reflect.Type, value string) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(value, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(value, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	}
+
+	return value, nil
+}
+
+func parseTag(tag string) (name string, opts map[string]bool) {
+	parts := strings.Split(tag, ",")
+	opts = make(map[string]bool)
+	for _, opt := range parts[1:] {
+		opts[opt] = true
+	}
+
+	return parts[0], opts
+}
@@ -0,0 +1,125 @@
+package plugins_test
+
+import (
+	"testing"
+
+	"github.com/odpf/meteor/plugins"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type credentials struct {
+	Username string `mapstructure:"username" validate:"required"`
+	Password string `mapstructure:"password" validate:"required_with=Username"`
+}
+
+type rule struct {
+	Field   string `mapstructure:"field" validate:"required"`
+	Matches string `mapstructure:"matches"`
+}
+
+type common struct {
+	Timeout int `mapstructure:"timeout_seconds" validate:"gte=0" default:"30"`
+}
+
+type testConfig struct {
+	common      `mapstructure:",squash"`
+	Host        string            `mapstructure:"host" validate:"required,url"`
+	Mode        string            `mapstructure:"mode" validate:"oneof=first last" default:"first"`
+	Rate        float64           `mapstructure:"rate" validate:"gte=0,lte=1"`
+	Enabled     bool              `mapstructure:"enabled" default:"true"`
+	Credentials credentials       `mapstructure:"credentials" validate:"required"`
+	Proxy       *credentials      `mapstructure:"proxy"`
+	Rules       []rule            `mapstructure:"rules" validate:"required,min=1,dive"`
+	Ports       []int             `mapstructure:"ports" validate:"dive,gte=1"`
+	Labels      map[string]string `mapstructure:"labels"`
+	Extra       map[string]interface{}
+	Ignored     string `mapstructure:"-"`
+	unexported  string
+}
+
+func TestConfigSchema(t *testing.T) {
+	t.Run("should describe fields of nested structs", func(t *testing.T) {
+		schema, err := plugins.ConfigSchema(testConfig{})
+		require.NoError(t, err)
+
+		minimum, maximum, one := 0.0, 1.0, 1
+		credentialsSchema := &plugins.JSONSchema{
+			Type: "object",
+			Properties: map[string]*plugins.JSONSchema{
+				"username": {Type: "string"},
+				"password": {Type: "string"},
+			},
+			Required: []string{"username"},
+		}
+		assert.Equal(t, plugins.JSONSchema{
+			Schema: plugins.JSONSchemaDraft,
+			Type:   "object",
+			Properties: map[string]*plugins.JSONSchema{
+				"timeout_seconds": {Type: "integer", Minimum: &minimum, Default: int64(30)},
+				"host":            {Type: "string"},
+				"mode":            {Type: "string", Enum: []interface{}{"first", "last"}, Default: "first"},
+				"rate":            {Type: "number", Minimum: &minimum, Maximum: &maximum},
+				"enabled":         {Type: "boolean", Default: true},
+				"credentials":     credentialsSchema,
+				"proxy":           credentialsSchema,
+				"rules": {
+					Type: "array",
+					Items: &plugins.JSONSchema{
+						Type: "object",
+						Properties: map[string]*plugins.JSONSchema{
+							"field":   {Type: "string"},
+							"matches": {Type: "string"},
+						},
+						Required: []string{"field"},
+					},
+					MinItems: &one,
+				},
+				"ports":  {Type: "array", Items: &plugins.JSONSchema{Type: "integer", Minimum: &maximum}},
+				"labels": {Type: "object", AdditionalProperties: &plugins.JSONSchema{Type: "string"}},
+				"Extra":  {Type: "object", AdditionalProperties: &plugins.JSONSchema{}},
+			},
+			Required: []string{"host", "credentials", "rules"},
+		}, schema)
+	})
+
+	t.Run("should accept pointers to structs", func(t *testing.T) {
+		schema, err := plugins.ConfigSchema(&credentials{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"username"}, schema.Required)
+	})
+
+	t.Run("should describe recursive structs once", func(t *testing.T) {
+		type node struct {
+			Name     string  `mapstructure:"name"`
+			Children []*node `mapstructure:"children"`
+		}
+
+		schema, err := plugins.ConfigSchema(node{})
+		require.NoError(t, err)
+		assert.Equal(t, &plugins.JSONSchema{Type: "object"}, schema.Properties["children"].Items)
+	})
+
+	t.Run("should return error for non struct configs", func(t *testing.T) {
+		_, err := plugins.ConfigSchema("config")
+		assert.EqualError(t, err, "config must be a struct, got string")
+	})
+
+	t.Run("should return error for invalid default", func(t *testing.T) {
+		type config struct {
+			Size int `mapstructure:"size" default:"large"`
+		}
+
+		_, err := plugins.ConfigSchema(config{})
+		assert.Error(t, err)
+	})
+
+	t.Run("should return error for unsupported types", func(t *testing.T) {
+		type config struct {
+			Callback func() `mapstructure:"callback"`
+		}
+
+		_, err := plugins.ConfigSchema(config{})
+		assert.EqualError(t, err, "field Callback: unsupported config type func()")
+	})
+}
@@ -0,0 +1,34 @@
+package plugins
+
+import (
+	"fmt"
+
+	"github.com/odpf/meteor/models"
+)
+
+// Serializer encodes records in the format of a downstream, sinks select one with their format config.
+type Serializer interface {
+	Serialize(record models.Record) ([]byte, error)
+}
+
+// serializers holds the serializers by format, registered on init and only read afterwards
+var serializers = make(map[string]Serializer)
+
+// RegisterSerializer makes a serializer available to sinks under the format name.
+// It panics if the format is already registered.
+func RegisterSerializer(format string, serializer Serializer) {
+	if _, ok := serializers[format]; ok {
+		panic(fmt.Sprintf("serializer \"%s\" is already registered", format))
+	}
+	serializers[format] = serializer
+}
+
+// GetSerializer returns the serializer of the format
+func GetSerializer(format string) (Serializer, error) {
+	serializer, ok := serializers[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown format \"%s\"", format)
+	}
+
+	return serializer, nil
+}
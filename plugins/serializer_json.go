@@ -0,0 +1,17 @@
+package plugins
+
+import "github.com/odpf/meteor/models"
+
+// FormatJSON serializes records with encoding/json, field names being the snake_case names of the models
+const FormatJSON = "json"
+
+// JSONSerializer serializes records as models.ToJSON does
+type JSONSerializer struct{}
+
+func (JSONSerializer) Serialize(record models.Record) ([]byte, error) {
+	return models.ToJSON(record)
+}
+
+func init() {
+	RegisterSerializer(FormatJSON, JSONSerializer{})
+}
@@ -0,0 +1,28 @@
+package plugins
+
+import (
+	"fmt"
+
+	"github.com/odpf/meteor/models"
+	"google.golang.org/protobuf/proto"
+)
+
+// FormatProtobuf serializes records in the protobuf binary wire format
+const FormatProtobuf = "protobuf"
+
+// ProtobufSerializer serializes records with proto.Marshal, failing on lineage edges
+// which are not protobuf messages.
+type ProtobufSerializer struct{}
+
+func (ProtobufSerializer) Serialize(record models.Record) ([]byte, error) {
+	message, ok := record.Data().(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%T is not a protobuf message", record.Data())
+	}
+
+	return proto.Marshal(message)
+}
+
+func init() {
+	RegisterSerializer(FormatProtobuf, ProtobufSerializer{})
+}
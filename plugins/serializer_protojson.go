@@ -0,0 +1,28 @@
+package plugins
+
+import (
+	"github.com/odpf/meteor/models"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// FormatProtoJSON serializes records with the canonical JSON mapping of protobuf,
+// e.g. with camelCase field names and timestamps as RFC 3339 strings
+const FormatProtoJSON = "protojson"
+
+// ProtoJSONSerializer serializes records with protojson.
+// Lineage edges, which are not protobuf messages, are serialized as JSON.
+type ProtoJSONSerializer struct{}
+
+func (ProtoJSONSerializer) Serialize(record models.Record) ([]byte, error) {
+	message, ok := record.Data().(proto.Message)
+	if !ok {
+		return models.ToJSON(record)
+	}
+
+	return protojson.Marshal(message)
+}
+
+func init() {
+	RegisterSerializer(FormatProtoJSON, ProtoJSONSerializer{})
+}
@@ -0,0 +1,108 @@
+package plugins_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newTable() *assetsv1beta1.Table {
+	return &assetsv1beta1.Table{
+		Resource: &commonv1beta1.Resource{
+			Urn:     "mysql::localhost/shop/orders",
+			Name:    "orders",
+			Service: "mysql",
+		},
+		Schema: &facetsv1beta1.Columns{
+			Columns: []*facetsv1beta1.Column{
+				{Name: "id", DataType: "int"},
+				{Name: "total", DataType: "decimal", IsNullable: true, Length: 12},
+			},
+		},
+		Profile:    &assetsv1beta1.TableProfile{TotalRows: 2100},
+		Timestamps: &commonv1beta1.Timestamp{CreateTime: timestamppb.New(time.Date(2021, 10, 1, 8, 30, 0, 0, time.UTC))},
+	}
+}
+
+func TestSerializer(t *testing.T) {
+	t.Run("should round-trip records with the json serializer", func(t *testing.T) {
+		serializer, err := plugins.GetSerializer(plugins.FormatJSON)
+		require.NoError(t, err)
+
+		data, err := serializer.Serialize(models.NewRecord(newTable()))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"total_rows":2100`)
+
+		decoded := &assetsv1beta1.Table{}
+		require.NoError(t, json.Unmarshal(data, decoded))
+		assert.True(t, proto.Equal(newTable(), decoded), decoded)
+	})
+
+	t.Run("should round-trip records with the protojson serializer", func(t *testing.T) {
+		serializer, err := plugins.GetSerializer(plugins.FormatProtoJSON)
+		require.NoError(t, err)
+
+		data, err := serializer.Serialize(models.NewRecord(newTable()))
+		require.NoError(t, err)
+		var fields map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &fields))
+		assert.Equal(t, map[string]interface{}{"createTime": "2021-10-01T08:30:00Z"}, fields["timestamps"])
+
+		decoded := &assetsv1beta1.Table{}
+		require.NoError(t, protojson.Unmarshal(data, decoded))
+		assert.True(t, proto.Equal(newTable(), decoded), decoded)
+	})
+
+	t.Run("should round-trip records with the protobuf serializer", func(t *testing.T) {
+		serializer, err := plugins.GetSerializer(plugins.FormatProtobuf)
+		require.NoError(t, err)
+
+		data, err := serializer.Serialize(models.NewRecord(newTable()))
+		require.NoError(t, err)
+
+		decoded := &assetsv1beta1.Table{}
+		require.NoError(t, proto.Unmarshal(data, decoded))
+		assert.True(t, proto.Equal(newTable(), decoded), decoded)
+	})
+
+	t.Run("should serialize lineage edges as json except in protobuf", func(t *testing.T) {
+		record := models.NewLineageRecord(&commonv1beta1.Resource{Urn: "orders"}, &commonv1beta1.Resource{Urn: "orders-topic"})
+		expected, err := models.ToJSON(record)
+		require.NoError(t, err)
+
+		for _, format := range []string{plugins.FormatJSON, plugins.FormatProtoJSON} {
+			serializer, err := plugins.GetSerializer(format)
+			require.NoError(t, err)
+			data, err := serializer.Serialize(record)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, data, format)
+		}
+
+		serializer, err := plugins.GetSerializer(plugins.FormatProtobuf)
+		require.NoError(t, err)
+		_, err = serializer.Serialize(record)
+		assert.Error(t, err)
+	})
+
+	t.Run("should return error for unknown formats", func(t *testing.T) {
+		_, err := plugins.GetSerializer("avro")
+		assert.EqualError(t, err, `unknown format "avro"`)
+	})
+
+	t.Run("should panic when registering a format twice", func(t *testing.T) {
+		assert.Panics(t, func() {
+			plugins.RegisterSerializer(plugins.FormatJSON, plugins.JSONSerializer{})
+		})
+	})
+}
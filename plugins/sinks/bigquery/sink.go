@@ -0,0 +1,238 @@
+package bigquery
+
+import (
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/odpf/meteor/models"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+//go:embed README.md
+var summary string
+
+// Schema is the schema of the table the records are inserted into
+var Schema = bigquery.Schema{
+	{Name: "urn", Type: bigquery.StringFieldType, Required: true},
+	{Name: "type", Type: bigquery.StringFieldType},
+	{Name: "name", Type: bigquery.StringFieldType},
+	{Name: "service", Type: bigquery.StringFieldType},
+	{Name: "data", Type: bigquery.StringFieldType, Description: "metadata of the record as JSON"},
+	{Name: "extracted_at", Type: bigquery.TimestampFieldType, Required: true},
+}
+
+type Config struct {
+	ProjectID          string `mapstructure:"project_id" validate:"required"`
+	Dataset            string `mapstructure:"dataset" validate:"required"`
+	Table              string `mapstructure:"table" validate:"required"`
+	ServiceAccountJSON string `mapstructure:"service_account_json"`
+	// CreateIfNotExists creates the table, partitioned daily by extracted_at, if it does not exist
+	CreateIfNotExists bool `mapstructure:"create_if_not_exists"`
+	// MaxRowsPerInsert limits the number of rows streamed by each insert request
+	MaxRowsPerInsert int `mapstructure:"max_rows_per_insert" validate:"gte=1" default:"500"`
+}
+
+var sampleConfig = `
+project_id: google-project-id
+dataset: meteor
+table: metadata
+create_if_not_exists: true
+max_rows_per_insert: 500
+service_account_json: |-
+  {
+    "type": "service_account",
+    "private_key_id": "xxxxxxx",
+    "private_key": "xxxxxxx",
+    "client_email": "xxxxxxx",
+    "client_id": "xxxxxxx",
+    "auth_uri": "https://accounts.google.com/o/oauth2/auth",
+    "token_uri": "https://oauth2.googleapis.com/token",
+    "auth_provider_x509_cert_url": "xxxxxxx",
+    "client_x509_cert_url": "xxxxxxx"
+  }`
+
+// Row is a record inserted into the table
+type Row struct {
+	Record      models.Record
+	ExtractedAt time.Time
+}
+
+// Save implements bigquery.ValueSaver.
+// The insert id is a hash of the urn and the data of the record, so the rows of a batch
+// sent again by the agent are dropped by the best-effort deduplication of BigQuery.
+func (r Row) Save() (map[string]bigquery.Value, string, error) {
+	data := r.Record.Data()
+	dataBytes, err := models.ToJSON(r.Record)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to serialize record as json")
+	}
+
+	resource := data.GetResource()
+	hash := sha256.New()
+	hash.Write([]byte(resource.GetUrn()))
+	hash.Write([]byte{0})
+	hash.Write(dataBytes)
+
+	return map[string]bigquery.Value{
+		"urn":          resource.GetUrn(),
+		"type":         models.AssetType(data),
+		"name":         resource.GetName(),
+		"service":      resource.GetService(),
+		"data":         string(dataBytes),
+		"extracted_at": r.ExtractedAt,
+	}, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+type Sink struct {
+	client   *bigquery.Client
+	inserter *bigquery.Inserter
+	config   Config
+	logger   log.Logger
+}
+
+func New(logger log.Logger) plugins.Syncer {
+	return &Sink{logger: logger}
+}
+
+func (s *Sink) SetLogger(logger log.Logger) {
+	s.logger = logger
+}
+
+func (s *Sink) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Insert metadata as rows of a BigQuery table",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"gcp", "bigquery", "sink"},
+	}
+}
+
+func (s *Sink) Validate(configMap map[string]interface{}) (err error) {
+	return utils.BuildConfig(configMap, &Config{})
+}
+
+func (s *Sink) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &s.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeSink}
+	}
+
+	if s.client, err = s.createClient(ctx); err != nil {
+		return errors.Wrap(err, "failed to create client")
+	}
+	table := s.client.Dataset(s.config.Dataset).Table(s.config.Table)
+	if s.config.CreateIfNotExists {
+		if err = s.createTable(ctx, table); err != nil {
+			return err
+		}
+	}
+	s.inserter = table.Inserter()
+
+	return
+}
+
+// Sink streams the batch into the table, extracted_at being the time the batch is received
+func (s *Sink) Sink(ctx context.Context, batch []models.Record) (err error) {
+	extractedAt := time.Now().UTC()
+	rows := make([]*Row, 0, len(batch))
+	for _, record := range batch {
+		rows = append(rows, &Row{Record: record, ExtractedAt: extractedAt})
+	}
+
+	for start := 0; start < len(rows); start += s.config.MaxRowsPerInsert {
+		end := start + s.config.MaxRowsPerInsert
+		if end > len(rows) {
+			end = len(rows)
+		}
+		if err = s.inserter.Put(ctx, rows[start:end]); err != nil {
+			return s.insertError(err)
+		}
+	}
+
+	s.logger.Info("successfully sinked records", "table", s.tableID(), "count", len(batch))
+	return
+}
+
+func (s *Sink) Close() (err error) {
+	if s.client == nil {
+		return
+	}
+	return s.client.Close()
+}
+
+func (s *Sink) createClient(ctx context.Context) (*bigquery.Client, error) {
+	if s.config.ServiceAccountJSON == "" {
+		s.logger.Info("credentials are not specified, creating bigquery client using default credentials...")
+		return bigquery.NewClient(ctx, s.config.ProjectID)
+	}
+
+	return bigquery.NewClient(ctx, s.config.ProjectID, option.WithCredentialsJSON([]byte(s.config.ServiceAccountJSON)))
+}
+
+func (s *Sink) createTable(ctx context.Context, table *bigquery.Table) error {
+	_, err := table.Metadata(ctx)
+	if err == nil {
+		return nil
+	}
+	if apiErr, ok := err.(*googleapi.Error); !ok || apiErr.Code != http.StatusNotFound {
+		return errors.Wrapf(err, "failed to get table \"%s\"", s.tableID())
+	}
+
+	err = table.Create(ctx, &bigquery.TableMetadata{
+		Schema: Schema,
+		TimePartitioning: &bigquery.TimePartitioning{
+			Type:  bigquery.DayPartitioningType,
+			Field: "extracted_at",
+		},
+	})
+	// the table might have been created concurrently
+	if apiErr, ok := err.(*googleapi.Error); ok && apiErr.Code == http.StatusConflict {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to create table \"%s\"", s.tableID())
+	}
+
+	s.logger.Info("created table", "table", s.tableID())
+	return nil
+}
+
+// insertError marks failures of the request as retryable, rows rejected by BigQuery are not
+func (s *Sink) insertError(err error) error {
+	err = errors.Wrapf(err, "failed to insert rows into \"%s\"", s.tableID())
+
+	switch cause := errors.Cause(err).(type) {
+	case bigquery.PutMultiError:
+		return err
+	case *googleapi.Error:
+		if cause.Code >= http.StatusInternalServerError || cause.Code == http.StatusTooManyRequests {
+			return plugins.NewRetryError(err)
+		}
+		return err
+	}
+
+	return plugins.NewRetryError(err)
+}
+
+func (s *Sink) tableID() string {
+	return s.config.ProjectID + "." + s.config.Dataset + "." + s.config.Table
+}
+
+func init() {
+	if err := registry.Sinks.Register("bigquery", func() plugins.Syncer {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
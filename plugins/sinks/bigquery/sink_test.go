@@ -0,0 +1,105 @@
+package bigquery_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	bqsink "github.com/odpf/meteor/plugins/sinks/bigquery"
+	testUtils "github.com/odpf/meteor/test/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("should return InvalidConfigError on invalid config", func(t *testing.T) {
+		invalidConfigs := []map[string]interface{}{
+			{},
+			{"project_id": "google-project-id", "dataset": "meteor"},
+			{"project_id": "google-project-id", "dataset": "meteor", "table": "metadata", "max_rows_per_insert": 0},
+		}
+		for i, config := range invalidConfigs {
+			t.Run(fmt.Sprintf("test invalid config #%d", i+1), func(t *testing.T) {
+				err := bqsink.New(testUtils.Logger).Init(context.TODO(), config)
+
+				assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeSink}, err)
+			})
+		}
+	})
+}
+
+func TestRowSave(t *testing.T) {
+	extractedAt := time.Date(2021, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("should map asset to row", func(t *testing.T) {
+		table := &assetsv1beta1.Table{
+			Resource: &commonv1beta1.Resource{
+				Urn:     "bigquery::project/dataset/orders",
+				Name:    "orders",
+				Service: "bigquery",
+			},
+		}
+
+		row, insertID, err := bqsink.Row{Record: models.NewRecord(table), ExtractedAt: extractedAt}.Save()
+		require.NoError(t, err)
+
+		expectedData, err := json.Marshal(table)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]bigquery.Value{
+			"urn":          "bigquery::project/dataset/orders",
+			"type":         "table",
+			"name":         "orders",
+			"service":      "bigquery",
+			"data":         string(expectedData),
+			"extracted_at": extractedAt,
+		}, row)
+		assert.NotEqual(t, bigquery.NoDedupeID, insertID)
+	})
+
+	t.Run("should keep the insert id of a record sent again", func(t *testing.T) {
+		record := models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "orders"}})
+		other := models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "customers"}})
+
+		_, insertID, err := bqsink.Row{Record: record, ExtractedAt: extractedAt}.Save()
+		require.NoError(t, err)
+		_, retriedID, err := bqsink.Row{Record: record, ExtractedAt: extractedAt.Add(time.Second)}.Save()
+		require.NoError(t, err)
+		_, otherID, err := bqsink.Row{Record: other, ExtractedAt: extractedAt}.Save()
+		require.NoError(t, err)
+
+		assert.Equal(t, insertID, retriedID)
+		assert.NotEqual(t, insertID, otherID)
+	})
+
+	t.Run("should map lineage edge to row", func(t *testing.T) {
+		record := models.NewLineageRecord(
+			&commonv1beta1.Resource{Urn: "source-urn"},
+			&commonv1beta1.Resource{Urn: "target-urn"},
+		)
+
+		row, _, err := bqsink.Row{Record: record, ExtractedAt: extractedAt}.Save()
+		require.NoError(t, err)
+
+		assert.Equal(t, "lineage", row["type"])
+		assert.Equal(t, record.Data().GetResource().GetUrn(), row["urn"])
+	})
+}
+
+func TestSchema(t *testing.T) {
+	t.Run("should have a column for each row value", func(t *testing.T) {
+		row, _, err := bqsink.Row{Record: models.NewRecord(&assetsv1beta1.Table{}), ExtractedAt: time.Now()}.Save()
+		require.NoError(t, err)
+
+		assert.Len(t, bqsink.Schema, len(row))
+		for _, field := range bqsink.Schema {
+			assert.Contains(t, row, field.Name)
+		}
+	})
+}
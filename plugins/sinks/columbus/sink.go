@@ -48,10 +48,15 @@ func New(c httpClient, logger log.Logger) plugins.Syncer {
 	return sink
 }
 
+func (s *Sink) SetLogger(logger log.Logger) {
+	s.logger = logger
+}
+
 func (s *Sink) Info() plugins.Info {
 	return plugins.Info{
 		Description:  "Send metadata to columbus http service",
 		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
 		Summary:      summary,
 		Tags:         []string{"http", "sink"},
 	}
@@ -71,6 +76,12 @@ func (s *Sink) Init(ctx context.Context, configMap map[string]interface{}) (err
 
 func (s *Sink) Sink(ctx context.Context, batch []models.Record) (err error) {
 	for _, record := range batch {
+		// lineage edges are not assets, columbus only receives lineage attached to assets
+		if models.IsLineageRecord(record) {
+			s.logger.Debug("skipping lineage record", "record", record.Data().GetResource().Urn)
+			continue
+		}
+
 		metadata := record.Data()
 		s.logger.Info("sinking record to columbus", "record", metadata.GetResource().Urn)
 
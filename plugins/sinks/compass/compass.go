@@ -0,0 +1,32 @@
+package compass
+
+// RequestPayload is the body of an asset upsert request
+type RequestPayload struct {
+	Asset       Asset           `json:"asset"`
+	Upstreams   []LineageRecord `json:"upstreams"`
+	Downstreams []LineageRecord `json:"downstreams"`
+}
+
+type Asset struct {
+	URN         string            `json:"urn"`
+	Type        string            `json:"type"`
+	Name        string            `json:"name"`
+	Service     string            `json:"service"`
+	Description string            `json:"description"`
+	Data        interface{}       `json:"data"`
+	Labels      map[string]string `json:"labels"`
+	Owners      []Owner           `json:"owners"`
+}
+
+type LineageRecord struct {
+	URN     string `json:"urn"`
+	Type    string `json:"type"`
+	Service string `json:"service"`
+}
+
+type Owner struct {
+	URN   string `json:"urn"`
+	Name  string `json:"name"`
+	Role  string `json:"role"`
+	Email string `json:"email"`
+}
@@ -0,0 +1,230 @@
+package compass
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/odpf/meteor/models"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+)
+
+//go:embed README.md
+var summary string
+
+// maxErrorBodySize limits the response body included in errors
+const maxErrorBodySize = 1024
+
+type Config struct {
+	Host        string            `mapstructure:"host" validate:"required,url"`
+	Headers     map[string]string `mapstructure:"headers"`
+	BearerToken string            `mapstructure:"bearer_token"`
+	// TimeoutSeconds is the timeout of each request
+	TimeoutSeconds int `mapstructure:"timeout_seconds" validate:"gte=0" default:"30"`
+}
+
+var sampleConfig = `
+# The hostname of the compass service
+host: https://compass.com
+# Headers added to each request, e.g. the user compass attributes the changes to
+headers:
+  Compass-User-Email: meteor@odpf.io
+bearer_token: xxxxxxx
+timeout_seconds: 30`
+
+type httpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+type Sink struct {
+	client httpClient
+	config Config
+	logger log.Logger
+}
+
+func New(c httpClient, logger log.Logger) plugins.Syncer {
+	sink := &Sink{client: c, logger: logger}
+	return sink
+}
+
+func (s *Sink) SetLogger(logger log.Logger) {
+	s.logger = logger
+}
+
+func (s *Sink) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Upsert assets to compass catalog service",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"http", "sink"},
+	}
+}
+
+func (s *Sink) Validate(configMap map[string]interface{}) (err error) {
+	return utils.BuildConfig(configMap, &Config{})
+}
+
+func (s *Sink) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &s.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeSink}
+	}
+	if c, ok := s.client.(*http.Client); ok {
+		c.Timeout = time.Duration(s.config.TimeoutSeconds) * time.Second
+	}
+
+	return
+}
+
+// Sink upserts each asset of the batch, compass taking one asset per request
+func (s *Sink) Sink(ctx context.Context, batch []models.Record) (err error) {
+	for _, record := range batch {
+		// lineage edges are not assets, compass only receives lineage attached to assets
+		if models.IsLineageRecord(record) {
+			s.logger.Debug("skipping lineage record", "record", record.Data().GetResource().Urn)
+			continue
+		}
+
+		metadata := record.Data()
+		payload, err := json.Marshal(s.buildPayload(metadata))
+		if err != nil {
+			return errors.Wrap(err, "failed to build compass payload")
+		}
+		if err = s.send(ctx, payload); err != nil {
+			return errors.Wrapf(err, "error sending \"%s\"", metadata.GetResource().Urn)
+		}
+	}
+
+	s.logger.Info("successfully sinked records to compass", "host", s.config.Host, "count", len(batch))
+	return
+}
+
+func (s *Sink) Close() (err error) { return }
+
+func (s *Sink) send(ctx context.Context, payload []byte) (err error) {
+	url := fmt.Sprintf("%s/v1beta1/assets", strings.TrimSuffix(s.config.Host, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewBuffer(payload))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.config.Headers {
+		req.Header.Set(key, value)
+	}
+	if s.config.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.config.BearerToken)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		// network errors and timeouts are worth retrying
+		return plugins.NewRetryError(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return
+	}
+
+	bodyBytes, err := ioutil.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+	if err != nil {
+		return
+	}
+	err = fmt.Errorf("compass returns %d: %v", res.StatusCode, string(bodyBytes))
+
+	switch code := res.StatusCode; {
+	case code >= 500, code == http.StatusTooManyRequests:
+		return plugins.NewRetryError(err)
+	default:
+		return err
+	}
+}
+
+func (s *Sink) buildPayload(metadata models.Metadata) RequestPayload {
+	resource := metadata.GetResource()
+	assetType := models.AssetType(metadata)
+	if assetType == "" {
+		assetType = resource.GetType()
+	}
+	upstreams, downstreams := s.buildLineage(metadata)
+
+	return RequestPayload{
+		Asset: Asset{
+			URN:         resource.GetUrn(),
+			Type:        assetType,
+			Name:        resource.GetName(),
+			Service:     resource.GetService(),
+			Description: resource.GetDescription(),
+			Data:        metadata,
+			Labels:      metadata.GetProperties().GetLabels(),
+			Owners:      s.buildOwners(metadata),
+		},
+		Upstreams:   upstreams,
+		Downstreams: downstreams,
+	}
+}
+
+func (s *Sink) buildLineage(metadata models.Metadata) (upstreams, downstreams []LineageRecord) {
+	lm, modelHasLineage := metadata.(models.LineageMetadata)
+	if !modelHasLineage {
+		return
+	}
+
+	lineage := lm.GetLineage()
+	if lineage == nil {
+		return
+	}
+
+	for _, upstream := range lineage.Upstreams {
+		upstreams = append(upstreams, LineageRecord{
+			URN:     upstream.Urn,
+			Type:    upstream.Type,
+			Service: upstream.Service,
+		})
+	}
+	for _, downstream := range lineage.Downstreams {
+		downstreams = append(downstreams, LineageRecord{
+			URN:     downstream.Urn,
+			Type:    downstream.Type,
+			Service: downstream.Service,
+		})
+	}
+
+	return
+}
+
+func (s *Sink) buildOwners(metadata models.Metadata) (owners []Owner) {
+	om, modelHasOwnership := metadata.(models.OwnershipMetadata)
+	if !modelHasOwnership {
+		return
+	}
+
+	for _, ownerProto := range om.GetOwnership().GetOwners() {
+		owners = append(owners, Owner{
+			URN:   ownerProto.Urn,
+			Name:  ownerProto.Name,
+			Role:  ownerProto.Role,
+			Email: ownerProto.Email,
+		})
+	}
+
+	return
+}
+
+func init() {
+	if err := registry.Sinks.Register("compass", func() plugins.Syncer {
+		return New(&http.Client{}, plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,137 @@
+package compass_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/sinks/compass"
+	testUtils "github.com/odpf/meteor/test/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("should return InvalidConfigError on invalid config", func(t *testing.T) {
+		invalidConfigs := []map[string]interface{}{
+			{},
+			{"host": "not a url"},
+		}
+		for i, config := range invalidConfigs {
+			t.Run(fmt.Sprintf("test invalid config #%d", i+1), func(t *testing.T) {
+				err := compass.New(&http.Client{}, testUtils.Logger).Init(context.TODO(), config)
+
+				assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeSink}, err)
+			})
+		}
+	})
+}
+
+func TestSink(t *testing.T) {
+	t.Run("should upsert each asset with its lineage, owners and labels", func(t *testing.T) {
+		var payloads []compass.RequestPayload
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPatch, r.Method)
+			assert.Equal(t, "/v1beta1/assets", r.URL.Path)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "meteor@odpf.io", r.Header.Get("Compass-User-Email"))
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			var payload compass.RequestPayload
+			require.NoError(t, json.Unmarshal(body, &payload))
+			payloads = append(payloads, payload)
+		}))
+		defer server.Close()
+
+		sink := compass.New(&http.Client{}, testUtils.Logger)
+		require.NoError(t, sink.Init(context.TODO(), map[string]interface{}{
+			"host":         server.URL,
+			"headers":      map[string]interface{}{"Compass-User-Email": "meteor@odpf.io"},
+			"bearer_token": "token",
+		}))
+
+		err := sink.Sink(context.TODO(), []models.Record{
+			models.NewRecord(&assetsv1beta1.Table{
+				Resource: &commonv1beta1.Resource{
+					Urn:         "sales.orders",
+					Name:        "orders",
+					Service:     "postgres",
+					Description: "orders of customers",
+				},
+				Properties: &facetsv1beta1.Properties{Labels: map[string]string{"team": "sales"}},
+				Ownership: &facetsv1beta1.Ownership{
+					Owners: []*facetsv1beta1.Owner{{Urn: "user-1", Email: "jane@odpf.io", Role: "owner"}},
+				},
+				Lineage: &facetsv1beta1.Lineage{
+					Upstreams: []*commonv1beta1.Resource{{Urn: "orders-topic", Type: "topic", Service: "kafka"}},
+				},
+			}),
+			models.NewRecord(&models.LineageEdge{
+				Source: &commonv1beta1.Resource{Urn: "sales.orders"},
+				Target: &commonv1beta1.Resource{Urn: "sales.invoices"},
+			}),
+			models.NewRecord(&assetsv1beta1.Topic{
+				Resource: &commonv1beta1.Resource{Urn: "orders-topic", Name: "orders", Service: "kafka"},
+			}),
+		})
+		require.NoError(t, err)
+
+		require.Len(t, payloads, 2)
+		assert.Equal(t, compass.Asset{
+			URN:         "sales.orders",
+			Type:        "table",
+			Name:        "orders",
+			Service:     "postgres",
+			Description: "orders of customers",
+			Data:        payloads[0].Asset.Data,
+			Labels:      map[string]string{"team": "sales"},
+			Owners:      []compass.Owner{{URN: "user-1", Email: "jane@odpf.io", Role: "owner"}},
+		}, payloads[0].Asset)
+		assert.Equal(t, []compass.LineageRecord{{URN: "orders-topic", Type: "topic", Service: "kafka"}}, payloads[0].Upstreams)
+		assert.Empty(t, payloads[0].Downstreams)
+		assert.Equal(t, "topic", payloads[1].Asset.Type)
+		assert.Equal(t, "orders-topic", payloads[1].Asset.URN)
+	})
+
+	cases := []struct {
+		description string
+		status      int
+		retry       bool
+	}{
+		{description: "should return retry error on 5xx", status: http.StatusServiceUnavailable, retry: true},
+		{description: "should return retry error on 429", status: http.StatusTooManyRequests, retry: true},
+		{description: "should return plain error on 4xx", status: http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, `{"reason":"some error"}`)
+			}))
+			defer server.Close()
+
+			sink := compass.New(&http.Client{}, testUtils.Logger)
+			require.NoError(t, sink.Init(context.TODO(), map[string]interface{}{
+				"host": server.URL,
+			}))
+
+			err := sink.Sink(context.TODO(), []models.Record{
+				models.NewRecord(&assetsv1beta1.Topic{Resource: &commonv1beta1.Resource{Urn: "orders-topic"}}),
+			})
+			assert.EqualError(t, err, fmt.Sprintf("error sending \"orders-topic\": compass returns %d: {\"reason\":\"some error\"}", tc.status))
+			assert.Equal(t, tc.retry, errors.Is(err, plugins.RetryError{}))
+		})
+	}
+}
@@ -3,46 +3,82 @@ package console
 import (
 	"context"
 	_ "embed"
-	"encoding/json"
 	"fmt"
 
 	"github.com/odpf/meteor/models"
 	"github.com/odpf/meteor/plugins"
 	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
 	"github.com/odpf/salt/log"
 )
 
 //go:embed README.md
 var summary string
 
+type Config struct {
+	// Format is the serialization of the records, one of the JSON formats
+	Format string `mapstructure:"format" validate:"oneof=json protojson" default:"json"`
+	// FieldCasing is the casing of the JSON field names, either snake_case or camelCase.
+	// Field names are kept as marshaled by the models if empty.
+	FieldCasing string `mapstructure:"field_casing"`
+}
+
+var sampleConfig = `
+# serialization of the records, either json or protojson
+format: json
+# casing of the JSON field names, either snake_case or camelCase
+field_casing: camelCase`
+
 type Sink struct {
-	logger log.Logger
+	logger     log.Logger
+	config     Config
+	serializer plugins.Serializer
 }
 
 func New() plugins.Syncer {
 	return new(Sink)
 }
 
+func (s *Sink) SetLogger(logger log.Logger) {
+	s.logger = logger
+}
+
 func (s *Sink) Info() plugins.Info {
 	return plugins.Info{
 		Description:  "Log to standard output",
-		SampleConfig: "",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
 		Summary:      summary,
 		Tags:         []string{"log", "sink"},
 	}
 }
 
 func (s *Sink) Validate(configMap map[string]interface{}) (err error) {
-	return nil
+	var config Config
+	if err = utils.BuildConfig(configMap, &config); err != nil {
+		return err
+	}
+
+	return utils.ValidateCasing(config.FieldCasing)
 }
 
-func (s *Sink) Init(ctx context.Context, config map[string]interface{}) (err error) {
+func (s *Sink) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &s.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeSink}
+	}
+	if err = utils.ValidateCasing(s.config.FieldCasing); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeSink}
+	}
+	if s.serializer, err = plugins.GetSerializer(s.config.Format); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeSink}
+	}
+
 	return
 }
 
 func (s *Sink) Sink(ctx context.Context, batch []models.Record) (err error) {
 	for _, record := range batch {
-		if err := s.process(record.Data()); err != nil {
+		if err := s.process(record); err != nil {
 			return err
 		}
 	}
@@ -51,8 +87,12 @@ func (s *Sink) Sink(ctx context.Context, batch []models.Record) (err error) {
 
 func (s *Sink) Close() (err error) { return }
 
-func (s *Sink) process(value interface{}) error {
-	jsonBytes, err := json.Marshal(value)
+func (s *Sink) process(record models.Record) error {
+	jsonBytes, err := s.serializer.Serialize(record)
+	if err != nil {
+		return err
+	}
+	jsonBytes, err = utils.ConvertCasing(jsonBytes, record.Data(), s.config.FieldCasing)
 	if err != nil {
 		return err
 	}
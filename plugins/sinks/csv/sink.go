@@ -0,0 +1,141 @@
+package csv
+
+import (
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"os"
+	"strconv"
+
+	"github.com/odpf/meteor/models"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+)
+
+//go:embed README.md
+var summary string
+
+// header of the written file, each row describes a column of a table
+var header = []string{"table_urn", "table_name", "column_name", "data_type", "is_nullable", "description"}
+
+type Config struct {
+	// Path of the file written, it is replaced if it exists
+	Path string `mapstructure:"path" validate:"required"`
+	// SkipNonTables drops the assets other than tables instead of failing the batch
+	SkipNonTables bool `mapstructure:"skip_non_tables"`
+}
+
+var sampleConfig = `
+# file written with a row per table column, replaced if it exists
+path: ./tables.csv
+# drop assets other than tables instead of failing
+skip_non_tables: true`
+
+type Sink struct {
+	config Config
+	file   *os.File
+	writer *csv.Writer
+	logger log.Logger
+}
+
+func New(logger log.Logger) plugins.Syncer {
+	return &Sink{logger: logger}
+}
+
+func (s *Sink) SetLogger(logger log.Logger) {
+	s.logger = logger
+}
+
+func (s *Sink) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Write the columns of tables to a CSV file",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"file", "sink"},
+	}
+}
+
+func (s *Sink) Validate(configMap map[string]interface{}) (err error) {
+	return utils.BuildConfig(configMap, &Config{})
+}
+
+// Init creates the file and writes its header
+func (s *Sink) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &s.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeSink}
+	}
+
+	if s.file, err = os.Create(s.config.Path); err != nil {
+		return errors.Wrapf(err, "failed to create file \"%s\"", s.config.Path)
+	}
+	s.writer = csv.NewWriter(s.file)
+	if err = s.writer.WriteAll([][]string{header}); err != nil {
+		return errors.Wrap(err, "failed to write header")
+	}
+
+	return
+}
+
+// Sink appends a row for each column of the tables in the batch, tables without columns get a single row.
+// Nothing is written if the batch holds an asset other than a table and those are not skipped.
+func (s *Sink) Sink(ctx context.Context, batch []models.Record) (err error) {
+	var rows [][]string
+	for _, record := range batch {
+		table, ok := record.Data().(*assetsv1beta1.Table)
+		if !ok {
+			if s.config.SkipNonTables {
+				continue
+			}
+			return errors.Errorf("unsupported asset type \"%s\" for \"%s\", only tables can be written", models.AssetType(record.Data()), record.Data().GetResource().GetUrn())
+		}
+		rows = append(rows, tableRows(table)...)
+	}
+
+	if err = s.writer.WriteAll(rows); err != nil {
+		return errors.Wrapf(err, "failed to write to file \"%s\"", s.config.Path)
+	}
+
+	s.logger.Info("successfully sinked records", "path", s.config.Path, "rows", len(rows))
+	return
+}
+
+func (s *Sink) Close() (err error) {
+	if s.file == nil {
+		return
+	}
+	return s.file.Close()
+}
+
+func tableRows(table *assetsv1beta1.Table) (rows [][]string) {
+	urn := table.GetResource().GetUrn()
+	name := table.GetResource().GetName()
+	columns := table.GetSchema().GetColumns()
+	if len(columns) == 0 {
+		return [][]string{{urn, name, "", "", "", ""}}
+	}
+
+	for _, column := range columns {
+		rows = append(rows, []string{
+			urn,
+			name,
+			column.GetName(),
+			column.GetDataType(),
+			strconv.FormatBool(column.GetIsNullable()),
+			column.GetDescription(),
+		})
+	}
+	return
+}
+
+func init() {
+	if err := registry.Sinks.Register("csv", func() plugins.Syncer {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,104 @@
+package csv_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/sinks/csv"
+	testUtils "github.com/odpf/meteor/test/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const header = "table_urn,table_name,column_name,data_type,is_nullable,description\n"
+
+func TestInit(t *testing.T) {
+	t.Run("should return InvalidConfigError on invalid config", func(t *testing.T) {
+		err := csv.New(testUtils.Logger).Init(context.TODO(), map[string]interface{}{})
+		assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeSink}, err)
+	})
+
+	t.Run("should write header to the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tables.csv")
+		require.NoError(t, os.WriteFile(path, []byte("previous content\n"), 0600))
+
+		sink := csv.New(testUtils.Logger)
+		require.NoError(t, sink.Init(context.TODO(), map[string]interface{}{"path": path}))
+		require.NoError(t, sink.Close())
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, header, string(content))
+	})
+}
+
+func TestSink(t *testing.T) {
+	orders := models.NewRecord(&assetsv1beta1.Table{
+		Resource: &commonv1beta1.Resource{Urn: "sales.orders", Name: "orders"},
+		Schema: &facetsv1beta1.Columns{
+			Columns: []*facetsv1beta1.Column{
+				{Name: "id", DataType: "int", Description: "order id"},
+				{Name: "note", DataType: "varchar", IsNullable: true, Description: "free, \"quoted\" text"},
+			},
+		},
+	})
+	empty := models.NewRecord(&assetsv1beta1.Table{
+		Resource: &commonv1beta1.Resource{Urn: "sales.empty", Name: "empty"},
+	})
+	topic := models.NewRecord(&assetsv1beta1.Topic{
+		Resource: &commonv1beta1.Resource{Urn: "orders-topic", Name: "orders"},
+	})
+	newSink := func(t *testing.T, config map[string]interface{}) (plugins.Syncer, string) {
+		path := filepath.Join(t.TempDir(), "tables.csv")
+		config["path"] = path
+
+		sink := csv.New(testUtils.Logger)
+		require.NoError(t, sink.Init(context.TODO(), config))
+		return sink, path
+	}
+	read := func(t *testing.T, path string) string {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("should append a row per column of each batch", func(t *testing.T) {
+		sink, path := newSink(t, map[string]interface{}{})
+
+		require.NoError(t, sink.Sink(context.TODO(), []models.Record{orders}))
+		require.NoError(t, sink.Sink(context.TODO(), []models.Record{empty}))
+		require.NoError(t, sink.Close())
+
+		assert.Equal(t, header+
+			"sales.orders,orders,id,int,false,order id\n"+
+			"sales.orders,orders,note,varchar,true,\"free, \"\"quoted\"\" text\"\n"+
+			"sales.empty,empty,,,,\n", read(t, path))
+	})
+
+	t.Run("should fail the batch on non table assets", func(t *testing.T) {
+		sink, path := newSink(t, map[string]interface{}{})
+
+		err := sink.Sink(context.TODO(), []models.Record{orders, topic})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported asset type \"topic\" for \"orders-topic\"")
+		require.NoError(t, sink.Close())
+
+		assert.Equal(t, header, read(t, path))
+	})
+
+	t.Run("should skip non table assets when configured", func(t *testing.T) {
+		sink, path := newSink(t, map[string]interface{}{"skip_non_tables": true})
+
+		require.NoError(t, sink.Sink(context.TODO(), []models.Record{topic, empty}))
+		require.NoError(t, sink.Close())
+
+		assert.Equal(t, header+"sales.empty,empty,,,,\n", read(t, path))
+	})
+}
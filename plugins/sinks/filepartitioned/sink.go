@@ -0,0 +1,210 @@
+package filepartitioned
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/odpf/meteor/models"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+)
+
+//go:embed README.md
+var summary string
+
+// Granularities of the partitions
+const (
+	granularityDaily  = "daily"
+	granularityHourly = "hourly"
+)
+
+type Config struct {
+	// BasePath is the directory holding the partitions, created if it does not exist
+	BasePath string `mapstructure:"base_path" validate:"required"`
+	// Granularity is the time span of a partition, daily partitions are YYYY/MM/DD directories
+	// and hourly partitions YYYY/MM/DD/HH directories, in UTC
+	Granularity string `mapstructure:"granularity" validate:"oneof=daily hourly" default:"daily"`
+	// Name of the files written to the partitions, the recipe name if empty
+	Name string `mapstructure:"name" validate:"excludesall=/\\"`
+	// Format is the serialization of the records, one of the JSON formats
+	Format string `mapstructure:"format" validate:"oneof=json protojson" default:"json"`
+}
+
+var sampleConfig = `
+# directory holding the partitions, records are appended to base_path/YYYY/MM/DD/<name>.ndjson
+base_path: ./archive
+# daily or hourly partitions
+granularity: daily
+# name of the files, defaults to the recipe name
+name: bigquery-production
+# json or protojson
+format: json`
+
+// Option provides extension abstraction to Sink constructor
+type Option func(*Sink)
+
+// WithClock sets the clock the partition of the records is picked with
+func WithClock(now func() time.Time) Option {
+	return func(s *Sink) {
+		s.now = now
+	}
+}
+
+type Sink struct {
+	config     Config
+	logger     log.Logger
+	serializer plugins.Serializer
+	name       string
+	now        func() time.Time
+	// path is the file of the current partition, appended to until the partition changes
+	path string
+	file *os.File
+}
+
+func New(logger log.Logger, opts ...Option) plugins.Syncer {
+	s := &Sink{
+		logger: logger,
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *Sink) SetLogger(logger log.Logger) {
+	s.logger = logger
+}
+
+func (s *Sink) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Append records to newline delimited JSON files partitioned by date",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"file", "sink"},
+	}
+}
+
+func (s *Sink) Validate(configMap map[string]interface{}) (err error) {
+	return utils.BuildConfig(configMap, &Config{})
+}
+
+// Init creates the base path, files are only created once records are sinked
+func (s *Sink) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &s.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeSink}
+	}
+	if s.serializer, err = plugins.GetSerializer(s.config.Format); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeSink}
+	}
+
+	s.name = s.config.Name
+	if s.name == "" {
+		s.name = plugins.RecipeName(ctx)
+	}
+	if s.name == "" {
+		return errors.New("no file name, name must be set when the sink is not run from a recipe")
+	}
+	if filepath.Base(s.name) != s.name {
+		return errors.Errorf("invalid file name \"%s\", the recipe name can not be a path", s.name)
+	}
+
+	if err = os.MkdirAll(s.config.BasePath, 0755); err != nil {
+		return pathError(err, "create base path", s.config.BasePath)
+	}
+
+	return
+}
+
+// Sink appends the batch to the file of the current partition, a line per record.
+// Nothing is written if a record can not be serialized.
+func (s *Sink) Sink(ctx context.Context, batch []models.Record) (err error) {
+	var buf bytes.Buffer
+	for _, record := range batch {
+		line, err := s.serializer.Serialize(record)
+		if err != nil {
+			return errors.Wrapf(err, "failed to serialize \"%s\"", record.Data().GetResource().GetUrn())
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	if path := s.partitionPath(s.now().UTC()); path != s.path {
+		if err = s.open(path); err != nil {
+			return err
+		}
+	}
+	if _, err = s.file.Write(buf.Bytes()); err != nil {
+		return pathError(err, "write to file", s.path)
+	}
+
+	s.logger.Info("successfully sinked records", "path", s.path, "count", len(batch))
+	return
+}
+
+func (s *Sink) Close() (err error) {
+	if s.file == nil {
+		return
+	}
+	if err = s.file.Close(); err != nil {
+		return pathError(err, "close file", s.path)
+	}
+	s.file = nil
+
+	return
+}
+
+// partitionPath returns the file of the partition holding t
+func (s *Sink) partitionPath(t time.Time) string {
+	dir := filepath.Join(s.config.BasePath, t.Format("2006"), t.Format("01"), t.Format("02"))
+	if s.config.Granularity == granularityHourly {
+		dir = filepath.Join(dir, t.Format("15"))
+	}
+
+	return filepath.Join(dir, s.name+".ndjson")
+}
+
+// open closes the file of the previous partition and opens the file at path for appending,
+// creating it and its partition directories if needed
+func (s *Sink) open(path string) (err error) {
+	if err = s.Close(); err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return pathError(err, "create partition directory", dir)
+	}
+	if s.file, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err != nil {
+		return pathError(err, "open file", path)
+	}
+	s.path = path
+
+	return
+}
+
+// pathError wraps err with the failed action on path, pointing to the permissions of path when they are the cause
+func pathError(err error, action, path string) error {
+	if os.IsPermission(err) {
+		return errors.Wrapf(err, "failed to %s \"%s\", the user running meteor lacks permissions on it", action, path)
+	}
+
+	return errors.Wrapf(err, "failed to %s \"%s\"", action, path)
+}
+
+func init() {
+	if err := registry.Sinks.Register("file_partitioned", func() plugins.Syncer {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,147 @@
+package filepartitioned_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/sinks/filepartitioned"
+	testUtils "github.com/odpf/meteor/test/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("should return InvalidConfigError on invalid config", func(t *testing.T) {
+		invalidConfigs := []map[string]interface{}{
+			{},
+			{"base_path": t.TempDir(), "granularity": "weekly"},
+			{"base_path": t.TempDir(), "format": "protobuf"},
+			{"base_path": t.TempDir(), "name": "archive/tables"},
+		}
+		for _, config := range invalidConfigs {
+			err := filepartitioned.New(testUtils.Logger).Init(context.TODO(), config)
+			assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeSink}, err)
+		}
+	})
+
+	t.Run("should return error without name outside of recipes", func(t *testing.T) {
+		err := filepartitioned.New(testUtils.Logger).Init(context.TODO(), map[string]interface{}{"base_path": t.TempDir()})
+		assert.EqualError(t, err, "no file name, name must be set when the sink is not run from a recipe")
+	})
+
+	t.Run("should create the base path", func(t *testing.T) {
+		basePath := filepath.Join(t.TempDir(), "archive", "metadata")
+		sink := filepartitioned.New(testUtils.Logger)
+		require.NoError(t, sink.Init(plugins.WithRecipeName(context.TODO(), "sample"), map[string]interface{}{"base_path": basePath}))
+		require.NoError(t, sink.Close())
+
+		info, err := os.Stat(basePath)
+		require.NoError(t, err)
+		assert.True(t, info.IsDir())
+	})
+
+	t.Run("should return error if the base path can not be created", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(file, nil, 0600))
+
+		err := filepartitioned.New(testUtils.Logger).Init(context.TODO(), map[string]interface{}{
+			"base_path": filepath.Join(file, "archive"),
+			"name":      "sample",
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to create base path")
+	})
+
+	t.Run("should point to permissions if the base path is not writable", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("permissions are not enforced for root")
+		}
+		dir := t.TempDir()
+		require.NoError(t, os.Chmod(dir, 0500))
+		defer os.Chmod(dir, 0700)
+
+		err := filepartitioned.New(testUtils.Logger).Init(context.TODO(), map[string]interface{}{
+			"base_path": filepath.Join(dir, "archive"),
+			"name":      "sample",
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "lacks permissions")
+	})
+}
+
+func TestSink(t *testing.T) {
+	orders := models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sales.orders", Name: "orders"}})
+	users := models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "sales.users", Name: "users"}})
+	clock := func(times ...time.Time) func() time.Time {
+		return func() time.Time {
+			now := times[0]
+			if len(times) > 1 {
+				times = times[1:]
+			}
+			return now
+		}
+	}
+	read := func(t *testing.T, path string) string {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("should append records to the daily partition named after the recipe", func(t *testing.T) {
+		basePath := t.TempDir()
+		now := time.Date(2022, 1, 9, 23, 30, 0, 0, time.UTC)
+		ctx := plugins.WithRecipeName(context.TODO(), "sample")
+
+		// a second run appends to the file of the first one
+		for i := 0; i < 2; i++ {
+			sink := filepartitioned.New(testUtils.Logger, filepartitioned.WithClock(clock(now)))
+			require.NoError(t, sink.Init(ctx, map[string]interface{}{"base_path": basePath}))
+			require.NoError(t, sink.Sink(ctx, []models.Record{orders, users}))
+			require.NoError(t, sink.Close())
+		}
+
+		line := `{"resource":{"urn":"sales.orders","name":"orders"}}` + "\n" + `{"resource":{"urn":"sales.users","name":"users"}}` + "\n"
+		assert.Equal(t, line+line, read(t, filepath.Join(basePath, "2022", "01", "09", "sample.ndjson")))
+	})
+
+	t.Run("should move to the next partition once the hour changes", func(t *testing.T) {
+		basePath := t.TempDir()
+		// partitions are picked in utc
+		zone := time.FixedZone("UTC+7", 7*60*60)
+		sink := filepartitioned.New(testUtils.Logger, filepartitioned.WithClock(clock(
+			time.Date(2022, 1, 10, 6, 59, 0, 0, zone),
+			time.Date(2022, 1, 10, 7, 0, 0, 0, zone),
+		)))
+		require.NoError(t, sink.Init(context.TODO(), map[string]interface{}{
+			"base_path":   basePath,
+			"granularity": "hourly",
+			"name":        "tables",
+			"format":      "protojson",
+		}))
+		require.NoError(t, sink.Sink(context.TODO(), []models.Record{orders}))
+		require.NoError(t, sink.Sink(context.TODO(), []models.Record{users}))
+		require.NoError(t, sink.Close())
+
+		assert.JSONEq(t, `{"resource":{"urn":"sales.orders","name":"orders"}}`, read(t, filepath.Join(basePath, "2022", "01", "09", "23", "tables.ndjson")))
+		assert.JSONEq(t, `{"resource":{"urn":"sales.users","name":"users"}}`, read(t, filepath.Join(basePath, "2022", "01", "10", "00", "tables.ndjson")))
+	})
+
+	t.Run("should return error if the partition directory can not be created", func(t *testing.T) {
+		basePath := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(basePath, "2022"), nil, 0600))
+
+		sink := filepartitioned.New(testUtils.Logger, filepartitioned.WithClock(clock(time.Date(2022, 1, 9, 0, 0, 0, 0, time.UTC))))
+		require.NoError(t, sink.Init(context.TODO(), map[string]interface{}{"base_path": basePath, "name": "sample"}))
+		err := sink.Sink(context.TODO(), []models.Record{orders})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to create partition directory")
+		assert.NoError(t, sink.Close())
+	})
+}
@@ -0,0 +1,229 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	_ "embed"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/odpf/meteor/models"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+//go:embed README.md
+var summary string
+
+// TLSConfig holds the transport security of the connection
+type TLSConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// CACertFile is a PEM file of the certificate authorities trusted in addition to the system ones
+	CACertFile         string `mapstructure:"ca_cert_file"`
+	ServerName         string `mapstructure:"server_name"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+}
+
+type Config struct {
+	Address string `mapstructure:"address" validate:"required"`
+	// Method is the full name of the client-streaming method, e.g. "/odpf.metadata.v1.IngestionService/Ingest"
+	Method      string            `mapstructure:"method" validate:"required,startswith=/"`
+	TLS         TLSConfig         `mapstructure:"tls"`
+	Metadata    map[string]string `mapstructure:"metadata"`
+	BearerToken string            `mapstructure:"bearer_token"`
+	// TimeoutSeconds is the timeout of the connection to the service
+	TimeoutSeconds int `mapstructure:"timeout_seconds" validate:"gte=0" default:"30"`
+}
+
+var sampleConfig = `
+# host and port of the service
+address: metadata-service:443
+# full name of the client-streaming method receiving the records
+method: /odpf.metadata.v1.IngestionService/Ingest
+tls:
+  enabled: true
+  # certificate authorities trusted in addition to the system ones
+  ca_cert_file: /etc/ssl/metadata-ca.pem
+# metadata sent along the stream
+metadata:
+  x-team: data-platform
+bearer_token: xxxxxxx
+timeout_seconds: 30`
+
+type Sink struct {
+	config Config
+	logger log.Logger
+	conn   *grpclib.ClientConn
+}
+
+func New(logger log.Logger) plugins.Syncer {
+	return &Sink{logger: logger}
+}
+
+func (s *Sink) SetLogger(logger log.Logger) {
+	s.logger = logger
+}
+
+func (s *Sink) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Stream records to a gRPC service",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"grpc", "sink"},
+	}
+}
+
+func (s *Sink) Validate(configMap map[string]interface{}) (err error) {
+	return utils.BuildConfig(configMap, &Config{})
+}
+
+func (s *Sink) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &s.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeSink}
+	}
+
+	transport, err := s.transportOption()
+	if err != nil {
+		return errors.Wrap(err, "failed to setup tls")
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, time.Duration(s.config.TimeoutSeconds)*time.Second)
+	defer cancel()
+	if s.conn, err = grpclib.DialContext(dialCtx, s.config.Address, transport, grpclib.WithBlock()); err != nil {
+		return errors.Wrapf(err, "failed to connect to %s", s.config.Address)
+	}
+
+	return
+}
+
+// Sink sends each record of the batch as a google.protobuf.Any message on a new stream,
+// and closes the stream so the batch is acknowledged by the service before returning.
+func (s *Sink) Sink(ctx context.Context, batch []models.Record) (err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := s.openStream(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range batch {
+		message, ok := record.Data().(proto.Message)
+		if !ok {
+			// lineage edges are not proto messages
+			s.logger.Debug("skipping record without proto message", "record", record.Data().GetResource().GetUrn())
+			continue
+		}
+		payload, err := anypb.New(message)
+		if err != nil {
+			return errors.Wrap(err, "failed to build message")
+		}
+		if err = stream.SendMsg(payload); err != nil {
+			return errors.Wrapf(streamStatus(stream, err), "error sending \"%s\"", record.Data().GetResource().GetUrn())
+		}
+	}
+
+	if err = stream.CloseSend(); err == nil {
+		err = stream.RecvMsg(&emptypb.Empty{})
+	}
+	if err != nil {
+		return errors.Wrap(retryable(err), "failed to close stream")
+	}
+
+	s.logger.Info("successfully sent records to stream", "address", s.config.Address, "count", len(batch))
+	return
+}
+
+func (s *Sink) Close() (err error) {
+	if s.conn != nil {
+		return s.conn.Close()
+	}
+
+	return
+}
+
+func (s *Sink) openStream(ctx context.Context) (grpclib.ClientStream, error) {
+	md := metadata.New(s.config.Metadata)
+	if s.config.BearerToken != "" {
+		md.Set("authorization", "Bearer "+s.config.BearerToken)
+	}
+	ctx = metadata.NewOutgoingContext(ctx, md)
+
+	desc := &grpclib.StreamDesc{StreamName: s.config.Method, ClientStreams: true}
+	stream, err := s.conn.NewStream(ctx, desc, s.config.Method)
+	if err != nil {
+		return nil, errors.Wrap(retryable(err), "failed to open stream")
+	}
+
+	return stream, nil
+}
+
+// streamStatus returns the status the service ended the failed stream with
+func streamStatus(stream grpclib.ClientStream, err error) error {
+	if err == io.EOF {
+		// the service ended the stream, its status is returned on receive
+		if err = stream.RecvMsg(&emptypb.Empty{}); err == nil {
+			err = errors.New("stream closed by the service")
+		}
+	}
+
+	return retryable(err)
+}
+
+func (s *Sink) transportOption() (grpclib.DialOption, error) {
+	if !s.config.TLS.Enabled {
+		return grpclib.WithInsecure(), nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         s.config.TLS.ServerName,
+		InsecureSkipVerify: s.config.TLS.InsecureSkipVerify,
+	}
+	if s.config.TLS.CACertFile != "" {
+		pem, err := ioutil.ReadFile(s.config.TLS.CACertFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read ca_cert_file")
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificate found in ca_cert_file")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return grpclib.WithTransportCredentials(credentials.NewTLS(tlsConfig)), nil
+}
+
+// retryable marks the errors of unavailable services and exceeded deadlines as worth retrying
+func retryable(err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return plugins.NewRetryError(err)
+	default:
+		return err
+	}
+}
+
+func init() {
+	if err := registry.Sinks.Register("grpc", func() plugins.Syncer {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,227 @@
+package grpc_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/sinks/grpc"
+	testUtils "github.com/odpf/meteor/test/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const method = "/odpf.metadata.v1.IngestionService/Ingest"
+
+// server is a metadata service receiving the records of any client-streaming method
+type server struct {
+	mu       sync.Mutex
+	methods  []string
+	metadata []metadata.MD
+	received []*anypb.Any
+	// handle ends the stream once the messages are received, with an OK status when nil
+	handle func(grpclib.ServerStream) error
+}
+
+func (s *server) serve(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := grpclib.NewServer(grpclib.UnknownServiceHandler(func(_ interface{}, stream grpclib.ServerStream) error {
+		name, _ := grpclib.MethodFromServerStream(stream)
+		md, _ := metadata.FromIncomingContext(stream.Context())
+		s.mu.Lock()
+		s.methods = append(s.methods, name)
+		s.metadata = append(s.metadata, md)
+		s.mu.Unlock()
+
+		if s.handle != nil {
+			return s.handle(stream)
+		}
+		for {
+			msg := &anypb.Any{}
+			err := stream.RecvMsg(msg)
+			if err == io.EOF {
+				return stream.SendMsg(&emptypb.Empty{})
+			}
+			if err != nil {
+				return err
+			}
+			s.mu.Lock()
+			s.received = append(s.received, msg)
+			s.mu.Unlock()
+		}
+	}))
+	go srv.Serve(listener)
+	t.Cleanup(srv.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestInit(t *testing.T) {
+	t.Run("should return InvalidConfigError on invalid config", func(t *testing.T) {
+		invalidConfigs := []map[string]interface{}{
+			{},
+			{"address": "localhost:50051"},
+			{"address": "localhost:50051", "method": "IngestionService/Ingest"},
+		}
+		for i, config := range invalidConfigs {
+			t.Run(fmt.Sprintf("test invalid config #%d", i+1), func(t *testing.T) {
+				err := grpc.New(testUtils.Logger).Init(context.TODO(), config)
+
+				assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeSink}, err)
+			})
+		}
+	})
+
+	t.Run("should return error if the service can not be reached", func(t *testing.T) {
+		err := grpc.New(testUtils.Logger).Init(context.TODO(), map[string]interface{}{
+			"address":         "127.0.0.1:1",
+			"method":          method,
+			"timeout_seconds": 1,
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestSink(t *testing.T) {
+	records := []models.Record{
+		models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "orders", Name: "orders"}}),
+		models.NewLineageRecord(&commonv1beta1.Resource{Urn: "orders"}, &commonv1beta1.Resource{Urn: "orders-topic"}),
+		models.NewRecord(&assetsv1beta1.Topic{Resource: &commonv1beta1.Resource{Urn: "orders-topic", Name: "orders"}}),
+	}
+
+	t.Run("should stream each batch on its own stream", func(t *testing.T) {
+		srv := &server{}
+		address := srv.serve(t)
+
+		sink := grpc.New(testUtils.Logger)
+		require.NoError(t, sink.Init(context.TODO(), map[string]interface{}{
+			"address":      address,
+			"method":       method,
+			"metadata":     map[string]interface{}{"x-team": "data-platform"},
+			"bearer_token": "token",
+		}))
+		require.NoError(t, sink.Sink(context.TODO(), records[:2]))
+		require.NoError(t, sink.Sink(context.TODO(), records[2:]))
+		require.NoError(t, sink.Close())
+
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		assert.Equal(t, []string{method, method}, srv.methods)
+		assert.Equal(t, []string{"data-platform"}, srv.metadata[0].Get("x-team"))
+		assert.Equal(t, []string{"Bearer token"}, srv.metadata[0].Get("authorization"))
+
+		require.Len(t, srv.received, 2)
+		table := &assetsv1beta1.Table{}
+		require.NoError(t, srv.received[0].UnmarshalTo(table))
+		assert.Equal(t, "orders", table.Resource.Urn)
+		topic := &assetsv1beta1.Topic{}
+		require.NoError(t, srv.received[1].UnmarshalTo(topic))
+		assert.Equal(t, "orders-topic", topic.Resource.Urn)
+	})
+
+	t.Run("should return the status of the service once the batch is sent", func(t *testing.T) {
+		srv := &server{handle: func(stream grpclib.ServerStream) error {
+			for {
+				if err := stream.RecvMsg(&anypb.Any{}); err != nil {
+					return status.Error(codes.InvalidArgument, "invalid asset")
+				}
+			}
+		}}
+		address := srv.serve(t)
+
+		sink := grpc.New(testUtils.Logger)
+		require.NoError(t, sink.Init(context.TODO(), map[string]interface{}{
+			"address": address,
+			"method":  method,
+		}))
+		defer sink.Close()
+
+		err := sink.Sink(context.TODO(), records[:1])
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, plugins.RetryError{}))
+		assert.Equal(t, codes.InvalidArgument, status.Code(errors.Cause(err)))
+	})
+
+	t.Run("should return retry error once the stream fails with unavailable", func(t *testing.T) {
+		srv := &server{handle: func(stream grpclib.ServerStream) error {
+			return status.Error(codes.Unavailable, "overloaded")
+		}}
+		address := srv.serve(t)
+
+		sink := grpc.New(testUtils.Logger)
+		require.NoError(t, sink.Init(context.TODO(), map[string]interface{}{
+			"address": address,
+			"method":  method,
+		}))
+		defer sink.Close()
+
+		err := sink.Sink(context.TODO(), records[:1])
+		assert.True(t, errors.Is(err, plugins.RetryError{}))
+		assert.Contains(t, err.Error(), "overloaded")
+	})
+
+	t.Run("should keep the acknowledged batches when a later stream fails", func(t *testing.T) {
+		srv := &server{}
+		var streams int
+		srv.handle = func(stream grpclib.ServerStream) error {
+			srv.mu.Lock()
+			streams++
+			failing := streams == 2
+			srv.mu.Unlock()
+
+			for {
+				msg := &anypb.Any{}
+				err := stream.RecvMsg(msg)
+				if err == io.EOF {
+					if failing {
+						return status.Error(codes.Unavailable, "overloaded")
+					}
+					return stream.SendMsg(&emptypb.Empty{})
+				}
+				if err != nil {
+					return err
+				}
+				if !failing {
+					srv.mu.Lock()
+					srv.received = append(srv.received, msg)
+					srv.mu.Unlock()
+				}
+			}
+		}
+		address := srv.serve(t)
+
+		sink := grpc.New(testUtils.Logger)
+		require.NoError(t, sink.Init(context.TODO(), map[string]interface{}{
+			"address": address,
+			"method":  method,
+		}))
+		require.NoError(t, sink.Sink(context.TODO(), records[:1]))
+
+		err := sink.Sink(context.TODO(), records[2:])
+		assert.True(t, errors.Is(err, plugins.RetryError{}))
+		require.NoError(t, sink.Close())
+
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		require.Len(t, srv.received, 1)
+		table := &assetsv1beta1.Table{}
+		require.NoError(t, srv.received[0].UnmarshalTo(table))
+		assert.Equal(t, "orders", table.Resource.Urn)
+	})
+}
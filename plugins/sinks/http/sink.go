@@ -0,0 +1,194 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/odpf/meteor/models"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+)
+
+//go:embed README.md
+var summary string
+
+// maxErrorBodySize limits the response body included in errors
+const maxErrorBodySize = 1024
+
+// BasicAuth holds the credentials for HTTP basic authentication
+type BasicAuth struct {
+	Username string `mapstructure:"username" validate:"required_with=Password"`
+	Password string `mapstructure:"password"`
+}
+
+type Config struct {
+	URL         string            `mapstructure:"url" validate:"required,url"`
+	Headers     map[string]string `mapstructure:"headers"`
+	BearerToken string            `mapstructure:"bearer_token"`
+	BasicAuth   BasicAuth         `mapstructure:"basic_auth"`
+	// TimeoutSeconds is the timeout of each request
+	TimeoutSeconds int `mapstructure:"timeout_seconds" validate:"gte=0" default:"30"`
+	// SuccessCodes are the response status codes considered successful, any 2xx status if empty
+	SuccessCodes []int `mapstructure:"success_codes"`
+	// Format is the serialization of the records, one of the JSON formats as they are posted in a JSON array
+	Format string `mapstructure:"format" validate:"oneof=json protojson" default:"json"`
+}
+
+var sampleConfig = `
+# The url records are posted to as a JSON array
+url: https://catalog.com/api/v1/assets
+headers:
+  X-Source: meteor
+# Either a bearer token or basic auth credentials
+bearer_token: xxxxxxx
+timeout_seconds: 30
+success_codes: [200, 201]
+# Serialization of the records, either json or protojson
+format: json`
+
+type httpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+type Sink struct {
+	client     httpClient
+	config     Config
+	logger     log.Logger
+	serializer plugins.Serializer
+}
+
+func New(c httpClient, logger log.Logger) plugins.Syncer {
+	sink := &Sink{client: c, logger: logger}
+	return sink
+}
+
+func (s *Sink) SetLogger(logger log.Logger) {
+	s.logger = logger
+}
+
+func (s *Sink) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Post metadata to an http endpoint",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"http", "sink"},
+	}
+}
+
+func (s *Sink) Validate(configMap map[string]interface{}) (err error) {
+	return utils.BuildConfig(configMap, &Config{})
+}
+
+func (s *Sink) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &s.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeSink}
+	}
+	if s.serializer, err = plugins.GetSerializer(s.config.Format); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeSink}
+	}
+	if c, ok := s.client.(*http.Client); ok {
+		c.Timeout = time.Duration(s.config.TimeoutSeconds) * time.Second
+	}
+
+	return
+}
+
+// Sink posts the batch as a JSON array of the records' metadata
+func (s *Sink) Sink(ctx context.Context, batch []models.Record) (err error) {
+	data := make([]json.RawMessage, 0, len(batch))
+	for _, record := range batch {
+		jsonBytes, err := s.serializer.Serialize(record)
+		if err != nil {
+			return errors.Wrapf(err, "failed to serialize record as %s", s.config.Format)
+		}
+		data = append(data, jsonBytes)
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to build payload")
+	}
+	if err = s.send(ctx, payload); err != nil {
+		return errors.Wrap(err, "error sending data")
+	}
+
+	s.logger.Info("successfully sinked records", "url", s.config.URL, "count", len(batch))
+	return
+}
+
+func (s *Sink) Close() (err error) { return }
+
+func (s *Sink) send(ctx context.Context, payload []byte) (err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.URL, bytes.NewBuffer(payload))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.config.Headers {
+		req.Header.Set(key, value)
+	}
+	switch {
+	case s.config.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+s.config.BearerToken)
+	case s.config.BasicAuth.Username != "":
+		req.SetBasicAuth(s.config.BasicAuth.Username, s.config.BasicAuth.Password)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		// network errors and timeouts are worth retrying
+		return plugins.NewRetryError(err)
+	}
+	defer res.Body.Close()
+	if s.isSuccess(res.StatusCode) {
+		return
+	}
+
+	// the status is reported even if the body could not be read
+	body := "<empty body>"
+	if bodyBytes, readErr := ioutil.ReadAll(io.LimitReader(res.Body, maxErrorBodySize)); readErr != nil {
+		body = fmt.Sprintf("<failed to read body: %v>", readErr)
+	} else if len(bodyBytes) > 0 {
+		body = string(bodyBytes)
+	}
+	err = fmt.Errorf("%s returns %d: %v", s.config.URL, res.StatusCode, body)
+
+	switch code := res.StatusCode; {
+	case code >= 500, code == http.StatusTooManyRequests:
+		return plugins.NewRetryError(err)
+	default:
+		return err
+	}
+}
+
+func (s *Sink) isSuccess(code int) bool {
+	if len(s.config.SuccessCodes) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, c := range s.config.SuccessCodes {
+		if c == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+func init() {
+	if err := registry.Sinks.Register("http", func() plugins.Syncer {
+		return New(&http.Client{}, plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,176 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	httpsink "github.com/odpf/meteor/plugins/sinks/http"
+	testUtils "github.com/odpf/meteor/test/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("should return InvalidConfigError on invalid config", func(t *testing.T) {
+		invalidConfigs := []map[string]interface{}{
+			{},
+			{"url": "not a url"},
+			{"url": "http://catalog.com", "basic_auth": map[string]interface{}{"password": "secret"}},
+			{"url": "http://catalog.com", "format": "protobuf"},
+		}
+		for i, config := range invalidConfigs {
+			t.Run(fmt.Sprintf("test invalid config #%d", i+1), func(t *testing.T) {
+				err := httpsink.New(&http.Client{}, testUtils.Logger).Init(context.TODO(), config)
+
+				assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeSink}, err)
+			})
+		}
+	})
+}
+
+func TestSink(t *testing.T) {
+	records := []models.Record{
+		models.NewRecord(&assetsv1beta1.Table{Resource: &commonv1beta1.Resource{Urn: "table-1", Name: "orders"}}),
+		models.NewRecord(&assetsv1beta1.Topic{Resource: &commonv1beta1.Resource{Urn: "topic-1", Name: "events"}}),
+	}
+
+	t.Run("should post batch as json array with auth and headers", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "meteor", r.Header.Get("X-Source"))
+			user, pass, ok := r.BasicAuth()
+			assert.True(t, ok)
+			assert.Equal(t, "user", user)
+			assert.Equal(t, "secret", pass)
+
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			var payload []map[string]interface{}
+			require.NoError(t, json.Unmarshal(body, &payload))
+			require.Len(t, payload, 2)
+			assert.Equal(t, "table-1", payload[0]["resource"].(map[string]interface{})["urn"])
+			assert.Equal(t, "topic-1", payload[1]["resource"].(map[string]interface{})["urn"])
+
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		sink := httpsink.New(&http.Client{}, testUtils.Logger)
+		require.NoError(t, sink.Init(context.TODO(), map[string]interface{}{
+			"url":        server.URL,
+			"headers":    map[string]interface{}{"X-Source": "meteor"},
+			"basic_auth": map[string]interface{}{"username": "user", "password": "secret"},
+		}))
+
+		assert.NoError(t, sink.Sink(context.TODO(), records))
+	})
+
+	t.Run("should post records serialized in the configured format", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			var payload []map[string]interface{}
+			require.NoError(t, json.Unmarshal(body, &payload))
+			require.Len(t, payload, 1)
+			assert.Equal(t, map[string]interface{}{"totalRows": "3"}, payload[0]["profile"])
+		}))
+		defer server.Close()
+
+		sink := httpsink.New(&http.Client{}, testUtils.Logger)
+		require.NoError(t, sink.Init(context.TODO(), map[string]interface{}{
+			"url":    server.URL,
+			"format": "protojson",
+		}))
+
+		assert.NoError(t, sink.Sink(context.TODO(), []models.Record{
+			models.NewRecord(&assetsv1beta1.Table{
+				Resource: &commonv1beta1.Resource{Urn: "table-1"},
+				Profile:  &assetsv1beta1.TableProfile{TotalRows: 3},
+			}),
+		}))
+	})
+
+	t.Run("should send bearer token", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		}))
+		defer server.Close()
+
+		sink := httpsink.New(&http.Client{}, testUtils.Logger)
+		require.NoError(t, sink.Init(context.TODO(), map[string]interface{}{
+			"url":          server.URL,
+			"bearer_token": "token",
+		}))
+
+		assert.NoError(t, sink.Sink(context.TODO(), records))
+	})
+
+	cases := []struct {
+		description  string
+		status       int
+		successCodes []int
+		retry        bool
+		success      bool
+	}{
+		{description: "should return retry error on 5xx", status: http.StatusBadGateway, retry: true},
+		{description: "should return retry error on 429", status: http.StatusTooManyRequests, retry: true},
+		{description: "should return plain error on 4xx", status: http.StatusBadRequest},
+		{description: "should return error on status outside success codes", status: http.StatusAccepted, successCodes: []int{200}},
+		{description: "should succeed on configured success codes", status: http.StatusAccepted, successCodes: []int{200, 202}, success: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, "some error")
+			}))
+			defer server.Close()
+
+			sink := httpsink.New(&http.Client{}, testUtils.Logger)
+			require.NoError(t, sink.Init(context.TODO(), map[string]interface{}{
+				"url":           server.URL,
+				"success_codes": tc.successCodes,
+			}))
+
+			err := sink.Sink(context.TODO(), records)
+			if tc.success {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Equal(t, tc.retry, errors.Is(err, plugins.RetryError{}))
+		})
+	}
+
+	t.Run("should return retry error on 5xx when body could not be read", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", "100")
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, "some")
+			w.(http.Flusher).Flush()
+			// the connection is closed before the rest of the body is sent
+			panic(http.ErrAbortHandler)
+		}))
+		defer server.Close()
+
+		sink := httpsink.New(&http.Client{}, testUtils.Logger)
+		require.NoError(t, sink.Init(context.TODO(), map[string]interface{}{
+			"url": server.URL,
+		}))
+
+		err := sink.Sink(context.TODO(), records)
+		assert.True(t, errors.Is(err, plugins.RetryError{}))
+		assert.Contains(t, err.Error(), "returns 502: <failed to read body")
+	})
+}
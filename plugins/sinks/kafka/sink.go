@@ -2,7 +2,10 @@ package kafka
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	_ "embed"
+	"io/ioutil"
 	"reflect"
 	"strings"
 
@@ -13,18 +16,47 @@ import (
 	"github.com/odpf/meteor/plugins"
 	"github.com/odpf/meteor/registry"
 	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
 	"github.com/pkg/errors"
 	kafka "github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
 	"google.golang.org/protobuf/proto"
 )
 
 //go:embed README.md
 var summary string
 
+const (
+	saslPlain       = "plain"
+	saslScramSHA256 = "scram-sha-256"
+	saslScramSHA512 = "scram-sha-512"
+)
+
 type Config struct {
 	Brokers string `mapstructure:"brokers" validate:"required"`
 	Topic   string `mapstructure:"topic" validate:"required"`
+	// KeyPath is the path to the key field in the payload, messages are keyed by the asset urn if empty
 	KeyPath string `mapstructure:"key_path"`
+	// Format is the serialization of the records, any format of plugins.GetSerializer
+	Format string     `mapstructure:"format" default:"protobuf"`
+	SASL   SASLConfig `mapstructure:"sasl"`
+	TLS    TLSConfig  `mapstructure:"tls"`
+}
+
+// SASLConfig holds the SASL authentication settings
+type SASLConfig struct {
+	Mechanism string `mapstructure:"mechanism" validate:"omitempty,oneof=plain scram-sha-256 scram-sha-512"`
+	Username  string `mapstructure:"username" validate:"required_with=Mechanism"`
+	Password  string `mapstructure:"password"`
+}
+
+// TLSConfig holds the TLS settings to connect to the brokers
+type TLSConfig struct {
+	Enabled            bool   `mapstructure:"enabled"`
+	CACertPath         string `mapstructure:"ca_cert_path"`
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
 }
 
 var sampleConfig = `
@@ -32,20 +64,35 @@ var sampleConfig = `
  brokers: "localhost:9092"
  # The Kafka topic to write to
  topic: sample-topic-name
- # The path to the key field in the payload
- key_path: xxx`
+ # The path to the key field in the payload, messages are keyed by the asset urn if empty
+ key_path: xxx
+ # Serialization of the records, one of protobuf, json or protojson
+ format: protobuf
+ sasl:
+   mechanism: scram-sha-512
+   username: meteor
+   password: xxxxxxx
+ tls:
+   enabled: true
+   ca_cert_path: /etc/kafka/ca.pem`
 
 type ProtoReflector interface {
 	ProtoReflect() protoreflect.Message
 }
 
 type Sink struct {
-	writer *kafka.Writer
-	config Config
+	writer     *kafka.Writer
+	config     Config
+	serializer plugins.Serializer
+	logger     log.Logger
+}
+
+func New(logger log.Logger) plugins.Syncer {
+	return &Sink{logger: logger}
 }
 
-func New() plugins.Syncer {
-	return new(Sink)
+func (s *Sink) SetLogger(logger log.Logger) {
+	s.logger = logger
 }
 
 func (s *Sink) Info() plugins.Info {
@@ -53,74 +100,104 @@ func (s *Sink) Info() plugins.Info {
 		Description:  "Sink metadata to Apache Kafka topic",
 		Summary:      summary,
 		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
 		Tags:         []string{"kafka", "topic", "sink"},
 	}
 }
 
 func (s *Sink) Validate(configMap map[string]interface{}) (err error) {
-	return utils.BuildConfig(configMap, &Config{})
+	var config Config
+	if err = utils.BuildConfig(configMap, &config); err != nil {
+		return err
+	}
+
+	_, err = plugins.GetSerializer(config.Format)
+	return err
 }
 
 func (s *Sink) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
 	if err := utils.BuildConfig(configMap, &s.config); err != nil {
-		return err
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeSink}
+	}
+	if s.serializer, err = plugins.GetSerializer(s.config.Format); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeSink}
 	}
 
-	s.writer = createWriter(s.config)
+	if s.writer, err = createWriter(s.config); err != nil {
+		return errors.Wrap(err, "failed to create writer")
+	}
 
 	return
 }
 
 func (s *Sink) Sink(ctx context.Context, batch []models.Record) (err error) {
+	messages := make([]kafka.Message, 0, len(batch))
 	for _, record := range batch {
-		if err := s.push(ctx, record.Data()); err != nil {
+		// lineage edges are not protobuf messages, only the JSON formats can serialize them
+		if models.IsLineageRecord(record) && s.config.Format == plugins.FormatProtobuf {
+			s.logger.Debug("skipping lineage record", "record", record.Data().GetResource().GetUrn())
+			continue
+		}
+		message, err := s.buildMessage(record)
+		if err != nil {
 			return err
 		}
+		messages = append(messages, message)
+	}
+	if len(messages) == 0 {
+		return
+	}
+
+	if err = s.writer.WriteMessages(ctx, messages...); err != nil {
+		return plugins.NewRetryError(errors.Wrap(err, "failed to write messages"))
 	}
 
 	return
 }
 
+// Close flushes pending messages and closes the writer
 func (s *Sink) Close() (err error) {
+	if s.writer == nil {
+		return
+	}
 	return s.writer.Close()
 }
 
-func (s *Sink) push(ctx context.Context, payload interface{}) error {
-	kafkaValue, err := s.buildValue(payload)
+func (s *Sink) buildMessage(record models.Record) (kafka.Message, error) {
+	kafkaValue, err := s.buildValue(record)
 	if err != nil {
-		return err
+		return kafka.Message{}, err
 	}
 
-	kafkaKey, err := s.buildKey(payload, s.config.KeyPath)
-	if err != nil {
-		return err
+	keyPath := s.config.KeyPath
+	if models.IsLineageRecord(record) {
+		// key_path addresses the fields of assets, edges are keyed by their urn
+		keyPath = ""
 	}
-
-	err = s.writer.WriteMessages(ctx,
-		kafka.Message{
-			Key:   kafkaKey,
-			Value: kafkaValue,
-		},
-	)
+	kafkaKey, err := s.buildKey(record.Data(), keyPath)
 	if err != nil {
-		return errors.Wrap(err, "failed to write messages")
+		return kafka.Message{}, err
 	}
 
-	return nil
+	return kafka.Message{
+		Key:   kafkaKey,
+		Value: kafkaValue,
+	}, nil
 }
 
-func (s *Sink) buildValue(value interface{}) ([]byte, error) {
-	protoBytes, err := proto.Marshal(value.(proto.Message))
+func (s *Sink) buildValue(record models.Record) ([]byte, error) {
+	value, err := s.serializer.Serialize(record)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to serialize payload as a protobuf message")
+		return nil, errors.Wrapf(err, "failed to serialize payload as %s", s.config.Format)
 	}
-	return protoBytes, nil
+	return value, nil
 }
 
 // we can optimize this by caching descriptor and key path
-func (s *Sink) buildKey(payload interface{}, keyPath string) ([]byte, error) {
+func (s *Sink) buildKey(payload models.Metadata, keyPath string) ([]byte, error) {
+	// updates of an asset land in the same partition
 	if keyPath == "" {
-		return nil, nil
+		return []byte(payload.GetResource().GetUrn()), nil
 	}
 
 	// extract key field name and value
@@ -187,18 +264,57 @@ func (s *Sink) getTopLevelKeyFromPath(keyPath string) (string, error) {
 	return keyPaths[1], nil
 }
 
-func createWriter(config Config) *kafka.Writer {
+func createWriter(config Config) (*kafka.Writer, error) {
 	brokers := strings.Split(config.Brokers, ",")
+	transport := &kafka.Transport{}
+
+	mechanism, err := saslMechanism(config.SASL)
+	if err != nil {
+		return nil, err
+	}
+	transport.SASL = mechanism
+
+	if config.TLS.Enabled {
+		tlsConfig := &tls.Config{InsecureSkipVerify: config.TLS.InsecureSkipVerify}
+		if config.TLS.CACertPath != "" {
+			caCert, err := ioutil.ReadFile(config.TLS.CACertPath)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to read CA certificate")
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+				return nil, errors.New("failed to parse CA certificate")
+			}
+		}
+		transport.TLS = tlsConfig
+	}
+
 	return &kafka.Writer{
-		Addr:     kafka.TCP(brokers...),
-		Topic:    config.Topic,
-		Balancer: &kafka.LeastBytes{},
+		Addr:      kafka.TCP(brokers...),
+		Topic:     config.Topic,
+		Balancer:  &kafka.Hash{},
+		Transport: transport,
+	}, nil
+}
+
+func saslMechanism(config SASLConfig) (sasl.Mechanism, error) {
+	switch config.Mechanism {
+	case "":
+		return nil, nil
+	case saslPlain:
+		return plain.Mechanism{Username: config.Username, Password: config.Password}, nil
+	case saslScramSHA256:
+		return scram.Mechanism(scram.SHA256, config.Username, config.Password)
+	case saslScramSHA512:
+		return scram.Mechanism(scram.SHA512, config.Username, config.Password)
 	}
+
+	return nil, errors.Errorf("unsupported sasl mechanism \"%s\"", config.Mechanism)
 }
 
 func init() {
 	if err := registry.Sinks.Register("kafka", func() plugins.Syncer {
-		return &Sink{}
+		return New(plugins.GetLog())
 	}); err != nil {
 		panic(err)
 	}
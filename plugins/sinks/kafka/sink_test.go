@@ -0,0 +1,61 @@
+package kafka_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/sinks/kafka"
+	testUtils "github.com/odpf/meteor/test/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("should return InvalidConfigError on invalid config", func(t *testing.T) {
+		invalidConfigs := []map[string]interface{}{
+			{},
+			{"brokers": "localhost:9092"},
+			{"brokers": "localhost:9092", "topic": "metadata", "format": "avro"},
+			{"brokers": "localhost:9092", "topic": "metadata", "sasl": map[string]interface{}{"mechanism": "gssapi", "username": "meteor"}},
+			{"brokers": "localhost:9092", "topic": "metadata", "sasl": map[string]interface{}{"mechanism": "plain"}},
+		}
+		for i, config := range invalidConfigs {
+			t.Run(fmt.Sprintf("test invalid config #%d", i+1), func(t *testing.T) {
+				err := kafka.New(testUtils.Logger).Init(context.TODO(), config)
+
+				assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeSink}, err)
+			})
+		}
+	})
+
+	t.Run("should return error when CA certificate cannot be read", func(t *testing.T) {
+		err := kafka.New(testUtils.Logger).Init(context.TODO(), map[string]interface{}{
+			"brokers": "localhost:9092",
+			"topic":   "metadata",
+			"tls":     map[string]interface{}{"enabled": true, "ca_cert_path": "/non/existent/ca.pem"},
+		})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("should create writer with sasl and tls", func(t *testing.T) {
+		sink := kafka.New(testUtils.Logger)
+		err := sink.Init(context.TODO(), map[string]interface{}{
+			"brokers": "localhost:9092,localhost:9093",
+			"topic":   "metadata",
+			"format":  "json",
+			"sasl":    map[string]interface{}{"mechanism": "scram-sha-512", "username": "meteor", "password": "secret"},
+			"tls":     map[string]interface{}{"enabled": true},
+		})
+
+		assert.NoError(t, err)
+		assert.NoError(t, sink.Close())
+	})
+}
+
+func TestClose(t *testing.T) {
+	t.Run("should not fail when sink was not initialized", func(t *testing.T) {
+		assert.NoError(t, kafka.New(testUtils.Logger).Close())
+	})
+}
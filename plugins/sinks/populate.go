@@ -1,7 +1,14 @@
 package sinks
 
 import (
+	_ "github.com/odpf/meteor/plugins/sinks/bigquery"
 	_ "github.com/odpf/meteor/plugins/sinks/columbus"
+	_ "github.com/odpf/meteor/plugins/sinks/compass"
 	_ "github.com/odpf/meteor/plugins/sinks/console"
+	_ "github.com/odpf/meteor/plugins/sinks/csv"
+	_ "github.com/odpf/meteor/plugins/sinks/filepartitioned"
+	_ "github.com/odpf/meteor/plugins/sinks/grpc"
+	_ "github.com/odpf/meteor/plugins/sinks/http"
 	_ "github.com/odpf/meteor/plugins/sinks/kafka"
+	_ "github.com/odpf/meteor/plugins/sinks/sqs"
 )
@@ -0,0 +1,225 @@
+package sqs
+
+import (
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/odpf/meteor/models"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/registry"
+	"github.com/odpf/meteor/utils"
+	"github.com/odpf/salt/log"
+	"github.com/pkg/errors"
+)
+
+//go:embed README.md
+var summary string
+
+const (
+	// maxBatchEntries is the maximum number of messages of a SendMessageBatch request
+	maxBatchEntries = 10
+	// maxBatchSize is the maximum total size in bytes of the messages of a SendMessageBatch request
+	maxBatchSize = 256 * 1024
+)
+
+type Config struct {
+	QueueURL        string `mapstructure:"queue_url" validate:"required,url"`
+	Region          string `mapstructure:"region" validate:"required"`
+	AccessKeyID     string `mapstructure:"access_key_id" validate:"required_with=SecretAccessKey"`
+	SecretAccessKey string `mapstructure:"secret_access_key" validate:"required_with=AccessKeyID"`
+	SessionToken    string `mapstructure:"session_token"`
+	// Endpoint overrides the SQS endpoint, e.g. for SQS compatible services
+	Endpoint string `mapstructure:"endpoint"`
+	// MessageGroupID is required by FIFO queues
+	MessageGroupID string `mapstructure:"message_group_id"`
+}
+
+var sampleConfig = `
+queue_url: https://sqs.ap-southeast-1.amazonaws.com/123456789012/metadata
+region: ap-southeast-1
+# Credentials are read from the default credential chain if not specified
+access_key_id: xxxxxxx
+secret_access_key: xxxxxxx`
+
+type Sink struct {
+	client *sqs.SQS
+	config Config
+	logger log.Logger
+	// sent holds the ids of the messages of the last batch failing with a retry error which were sent,
+	// so they are skipped when the agent retries the batch. The batch is told by the digest of its messages.
+	sent      map[string]bool
+	sentBatch string
+}
+
+func New(logger log.Logger) plugins.Syncer {
+	return &Sink{logger: logger}
+}
+
+func (s *Sink) SetLogger(logger log.Logger) {
+	s.logger = logger
+}
+
+func (s *Sink) Info() plugins.Info {
+	return plugins.Info{
+		Description:  "Send metadata as messages to an Amazon SQS queue",
+		SampleConfig: sampleConfig,
+		ConfigSchema: plugins.MustConfigSchema(Config{}),
+		Summary:      summary,
+		Tags:         []string{"aws", "sqs", "sink"},
+	}
+}
+
+func (s *Sink) Validate(configMap map[string]interface{}) (err error) {
+	return utils.BuildConfig(configMap, &Config{})
+}
+
+func (s *Sink) Init(ctx context.Context, configMap map[string]interface{}) (err error) {
+	if err = utils.BuildConfig(configMap, &s.config); err != nil {
+		return plugins.InvalidConfigError{Type: plugins.PluginTypeSink}
+	}
+
+	if s.client, err = s.createClient(); err != nil {
+		return errors.Wrap(err, "failed to create client")
+	}
+
+	return
+}
+
+// Sink sends each record of the batch as a JSON message, grouped in SendMessageBatch requests.
+// The messages sent before a retryable failure are not sent again when the agent retries the batch.
+func (s *Sink) Sink(ctx context.Context, batch []models.Record) (err error) {
+	bodies := make([][]byte, len(batch))
+	digest := sha256.New()
+	for i, record := range batch {
+		if bodies[i], err = models.ToJSON(record); err != nil {
+			return errors.Wrap(err, "failed to serialize record as json")
+		}
+		digest.Write(bodies[i])
+		digest.Write([]byte{0})
+	}
+	if batchDigest := hex.EncodeToString(digest.Sum(nil)); batchDigest != s.sentBatch {
+		s.sent, s.sentBatch = make(map[string]bool), batchDigest
+	}
+
+	if err = s.sendBatch(ctx, bodies); errors.Is(err, plugins.RetryError{}) {
+		return err
+	}
+	// the batch will not be retried
+	s.sent, s.sentBatch = nil, ""
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("successfully sinked records", "queue_url", s.config.QueueURL, "count", len(batch))
+	return
+}
+
+// sendBatch sends the messages not sent yet, the id of a message being its index in the batch
+func (s *Sink) sendBatch(ctx context.Context, bodies [][]byte) error {
+	var (
+		entries []*sqs.SendMessageBatchRequestEntry
+		size    int
+	)
+	for i, body := range bodies {
+		id := strconv.Itoa(i)
+		if s.sent[id] {
+			continue
+		}
+		if len(entries) == maxBatchEntries || (len(entries) > 0 && size+len(body) > maxBatchSize) {
+			if err := s.send(ctx, entries); err != nil {
+				return err
+			}
+			entries, size = nil, 0
+		}
+
+		entry := &sqs.SendMessageBatchRequestEntry{
+			Id:          aws.String(id),
+			MessageBody: aws.String(string(body)),
+		}
+		if s.config.MessageGroupID != "" {
+			entry.MessageGroupId = aws.String(s.config.MessageGroupID)
+		}
+		entries = append(entries, entry)
+		size += len(body)
+	}
+	if len(entries) > 0 {
+		return s.send(ctx, entries)
+	}
+
+	return nil
+}
+
+func (s *Sink) Close() (err error) { return }
+
+func (s *Sink) send(ctx context.Context, entries []*sqs.SendMessageBatchRequestEntry) error {
+	res, err := s.client.SendMessageBatchWithContext(ctx, &sqs.SendMessageBatchInput{
+		QueueUrl: aws.String(s.config.QueueURL),
+		Entries:  entries,
+	})
+	if err != nil {
+		err = errors.Wrap(err, "failed to send messages")
+		if reqErr, ok := errors.Cause(err).(awserr.RequestFailure); ok &&
+			reqErr.StatusCode() < http.StatusInternalServerError && !request.IsErrorThrottle(reqErr) {
+			return err
+		}
+		return plugins.NewRetryError(err)
+	}
+	for _, entry := range res.Successful {
+		s.sent[aws.StringValue(entry.Id)] = true
+	}
+	if len(res.Failed) == 0 {
+		return nil
+	}
+
+	retryable := false
+	failures := make([]string, 0, len(res.Failed))
+	for _, entry := range res.Failed {
+		retryable = retryable || !aws.BoolValue(entry.SenderFault)
+		failures = append(failures, fmt.Sprintf("%s: %s", aws.StringValue(entry.Code), aws.StringValue(entry.Message)))
+	}
+	err = fmt.Errorf("failed to send %d of %d messages: %s", len(res.Failed), len(entries), strings.Join(failures, ", "))
+	if retryable {
+		return plugins.NewRetryError(err)
+	}
+
+	return err
+}
+
+func (s *Sink) createClient() (*sqs.SQS, error) {
+	cfg := aws.NewConfig().WithRegion(s.config.Region)
+	if s.config.Endpoint != "" {
+		cfg = cfg.WithEndpoint(s.config.Endpoint)
+	}
+	if s.config.AccessKeyID == "" {
+		s.logger.Info("credentials are not specified, creating sqs client using the default credential chain...")
+	} else {
+		cfg = cfg.WithCredentials(credentials.NewStaticCredentials(s.config.AccessKeyID, s.config.SecretAccessKey, s.config.SessionToken))
+	}
+
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return sqs.New(sess), nil
+}
+
+func init() {
+	if err := registry.Sinks.Register("sqs", func() plugins.Syncer {
+		return New(plugins.GetLog())
+	}); err != nil {
+		panic(err)
+	}
+}
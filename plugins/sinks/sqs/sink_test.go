@@ -0,0 +1,220 @@
+package sqs_test
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	sqssink "github.com/odpf/meteor/plugins/sinks/sqs"
+	testUtils "github.com/odpf/meteor/test/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const queueURL = "https://sqs.ap-southeast-1.amazonaws.com/123456789012/metadata"
+
+// fakeSQS answers SendMessageBatch requests, failing the entries with the given ids
+type fakeSQS struct {
+	mu          sync.Mutex
+	requests    [][]string
+	failedIDs   map[string]bool
+	senderFault bool
+}
+
+func (f *fakeSQS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var (
+		bodies     []string
+		successful strings.Builder
+		failed     strings.Builder
+	)
+	for i := 1; r.PostForm.Get(fmt.Sprintf("SendMessageBatchRequestEntry.%d.Id", i)) != ""; i++ {
+		id := r.PostForm.Get(fmt.Sprintf("SendMessageBatchRequestEntry.%d.Id", i))
+		body := r.PostForm.Get(fmt.Sprintf("SendMessageBatchRequestEntry.%d.MessageBody", i))
+		bodies = append(bodies, body)
+		if f.failedIDs[id] {
+			fmt.Fprintf(&failed, "<BatchResultErrorEntry><Id>%s</Id><Code>InternalError</Code><Message>failed</Message><SenderFault>%t</SenderFault></BatchResultErrorEntry>", id, f.senderFault)
+			continue
+		}
+		sum := md5.Sum([]byte(body))
+		fmt.Fprintf(&successful, "<SendMessageBatchResultEntry><Id>%s</Id><MessageId>%s</MessageId><MD5OfMessageBody>%s</MD5OfMessageBody></SendMessageBatchResultEntry>", id, id, hex.EncodeToString(sum[:]))
+	}
+
+	f.mu.Lock()
+	f.requests = append(f.requests, bodies)
+	f.mu.Unlock()
+
+	fmt.Fprintf(w, "<SendMessageBatchResponse><SendMessageBatchResult>%s%s</SendMessageBatchResult><ResponseMetadata><RequestId>id</RequestId></ResponseMetadata></SendMessageBatchResponse>", successful.String(), failed.String())
+}
+
+func TestInit(t *testing.T) {
+	t.Run("should return InvalidConfigError on invalid config", func(t *testing.T) {
+		invalidConfigs := []map[string]interface{}{
+			{},
+			{"queue_url": queueURL},
+			{"queue_url": "not a url", "region": "ap-southeast-1"},
+			{"queue_url": queueURL, "region": "ap-southeast-1", "access_key_id": "key"},
+		}
+		for i, config := range invalidConfigs {
+			t.Run(fmt.Sprintf("test invalid config #%d", i+1), func(t *testing.T) {
+				err := sqssink.New(testUtils.Logger).Init(context.TODO(), config)
+
+				assert.Equal(t, plugins.InvalidConfigError{Type: plugins.PluginTypeSink}, err)
+			})
+		}
+	})
+}
+
+func TestSink(t *testing.T) {
+	newSink := func(t *testing.T, server *httptest.Server) plugins.Syncer {
+		sink := sqssink.New(testUtils.Logger)
+		err := sink.Init(context.TODO(), map[string]interface{}{
+			"queue_url":         queueURL,
+			"region":            "ap-southeast-1",
+			"access_key_id":     "key",
+			"secret_access_key": "secret",
+			"endpoint":          server.URL,
+		})
+		require.NoError(t, err)
+		return sink
+	}
+	newBatch := func(size int) []models.Record {
+		batch := make([]models.Record, 0, size)
+		for i := 0; i < size; i++ {
+			batch = append(batch, models.NewRecord(&assetsv1beta1.Table{
+				Resource: &commonv1beta1.Resource{Urn: fmt.Sprintf("urn-%d", i)},
+			}))
+		}
+		return batch
+	}
+
+	t.Run("should send records as json in batches of ten", func(t *testing.T) {
+		fake := &fakeSQS{}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		err := newSink(t, server).Sink(context.TODO(), newBatch(23))
+		require.NoError(t, err)
+
+		require.Len(t, fake.requests, 3)
+		assert.Len(t, fake.requests[0], 10)
+		assert.Len(t, fake.requests[1], 10)
+		assert.Len(t, fake.requests[2], 3)
+
+		var table assetsv1beta1.Table
+		require.NoError(t, json.Unmarshal([]byte(fake.requests[2][2]), &table))
+		assert.Equal(t, "urn-22", table.Resource.Urn)
+	})
+
+	t.Run("should return retry error on partial failure", func(t *testing.T) {
+		fake := &fakeSQS{failedIDs: map[string]bool{"1": true}}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		err := newSink(t, server).Sink(context.TODO(), newBatch(3))
+
+		assert.True(t, errors.Is(err, plugins.RetryError{}))
+	})
+
+	t.Run("should only send the failed messages when the batch is retried", func(t *testing.T) {
+		fake := &fakeSQS{failedIDs: map[string]bool{"1": true}}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		sink := newSink(t, server)
+		batch := newBatch(13)
+		err := sink.Sink(context.TODO(), batch)
+		assert.True(t, errors.Is(err, plugins.RetryError{}))
+
+		fake.failedIDs = nil
+		require.NoError(t, sink.Sink(context.TODO(), batch))
+
+		// the failed message and the ones not sent after the failure
+		require.Len(t, fake.requests, 2)
+		var urns []string
+		for _, body := range fake.requests[1] {
+			var table assetsv1beta1.Table
+			require.NoError(t, json.Unmarshal([]byte(body), &table))
+			urns = append(urns, table.Resource.Urn)
+		}
+		assert.Equal(t, []string{"urn-1", "urn-10", "urn-11", "urn-12"}, urns)
+
+		require.NoError(t, sink.Sink(context.TODO(), newBatch(3)))
+		require.Len(t, fake.requests, 3)
+		assert.Len(t, fake.requests[2], 3)
+	})
+
+	t.Run("should send all messages of a new batch sharing the array of a failed batch", func(t *testing.T) {
+		fake := &fakeSQS{failedIDs: map[string]bool{"1": true}}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		sink := newSink(t, server)
+		batch := newBatch(3)
+		err := sink.Sink(context.TODO(), batch)
+		assert.True(t, errors.Is(err, plugins.RetryError{}))
+
+		// the retries are exhausted, the next batch reuses the same array
+		fake.failedIDs = nil
+		for i := range batch {
+			batch[i] = models.NewRecord(&assetsv1beta1.Table{
+				Resource: &commonv1beta1.Resource{Urn: fmt.Sprintf("other-urn-%d", i)},
+			})
+		}
+		require.NoError(t, sink.Sink(context.TODO(), batch))
+
+		require.Len(t, fake.requests, 2)
+		assert.Len(t, fake.requests[1], 3)
+	})
+
+	t.Run("should not retry messages rejected as sender fault", func(t *testing.T) {
+		fake := &fakeSQS{failedIDs: map[string]bool{"1": true}, senderFault: true}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		err := newSink(t, server).Sink(context.TODO(), newBatch(3))
+
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, plugins.RetryError{}))
+	})
+
+	t.Run("should not retry client errors", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, "<ErrorResponse><Error><Type>Sender</Type><Code>AWS.SimpleQueueService.NonExistentQueue</Code><Message>queue does not exist</Message></Error><RequestId>id</RequestId></ErrorResponse>")
+		}))
+		defer server.Close()
+
+		err := newSink(t, server).Sink(context.TODO(), newBatch(1))
+
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, plugins.RetryError{}))
+	})
+
+	t.Run("should not send request for empty batch", func(t *testing.T) {
+		fake := &fakeSQS{}
+		server := httptest.NewServer(fake)
+		defer server.Close()
+
+		err := newSink(t, server).Sink(context.TODO(), nil)
+
+		assert.NoError(t, err)
+		assert.Empty(t, fake.requests)
+	})
+}
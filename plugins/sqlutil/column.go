@@ -0,0 +1,57 @@
+package sqlutil
+
+import (
+	"database/sql"
+	"strings"
+
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/utils"
+	"github.com/pkg/errors"
+)
+
+// Units of the declared length of character columns, as in the CHAR_USED column of Oracle catalogs
+const (
+	CharLengthSemantics = "C"
+	ByteLengthSemantics = "B"
+)
+
+// ParseNullable converts the nullability of a column as reported by catalogs, "YES"/"NO" in
+// information schemas and "Y"/"N" in Oracle, to a boolean. Unknown values return false with an error.
+func ParseNullable(value string) (bool, error) {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "YES", "Y":
+		return true, nil
+	case "NO", "N":
+		return false, nil
+	default:
+		return false, errors.Errorf("unknown nullable value %q", value)
+	}
+}
+
+// ParseLength returns the declared length of a column, in characters for character columns declared in
+// characters, e.g. VARCHAR2(10 CHAR), and in bytes otherwise. semantics is CharLengthSemantics or
+// ByteLengthSemantics, empty for columns without character length. Columns without length return 0.
+func ParseLength(semantics string, charLength, byteLength sql.NullInt64) int64 {
+	if strings.EqualFold(semantics, CharLengthSemantics) && charLength.Valid {
+		return charLength.Int64
+	}
+	if byteLength.Valid {
+		return byteLength.Int64
+	}
+	if charLength.Valid {
+		return charLength.Int64
+	}
+
+	return 0
+}
+
+// DataTypeProperties returns the properties of a column holding the canonical type of its data type,
+// as named by service, in the plugins.NormalizedDataTypeAttribute attribute.
+func DataTypeProperties(service, dataType string) *facetsv1beta1.Properties {
+	return &facetsv1beta1.Properties{
+		Attributes: utils.TryParseMapToProto(map[string]interface{}{
+			plugins.NormalizedDataTypeAttribute: plugins.NormalizeDataType(service, dataType),
+		}),
+	}
+}
@@ -0,0 +1,61 @@
+package sqlutil_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/plugins/sqlutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseNullable(t *testing.T) {
+	t.Run("should parse values of information schemas and oracle", func(t *testing.T) {
+		for value, expected := range map[string]bool{"YES": true, "NO": false, "Y": true, "N": false, "yes": true, " n ": false} {
+			nullable, err := sqlutil.ParseNullable(value)
+			assert.NoError(t, err, value)
+			assert.Equal(t, expected, nullable, value)
+		}
+	})
+
+	t.Run("should return error on unknown values", func(t *testing.T) {
+		for _, value := range []string{"", "TRUE", "1", "NULL"} {
+			nullable, err := sqlutil.ParseNullable(value)
+			assert.Error(t, err, value)
+			assert.False(t, nullable, value)
+		}
+	})
+}
+
+func TestParseLength(t *testing.T) {
+	length := func(n int64) sql.NullInt64 { return sql.NullInt64{Int64: n, Valid: true} }
+
+	t.Run("should return the char length of columns declared in characters", func(t *testing.T) {
+		assert.Equal(t, int64(10), sqlutil.ParseLength("C", length(10), length(40)))
+		assert.Equal(t, int64(10), sqlutil.ParseLength("c", length(10), length(40)))
+	})
+
+	t.Run("should return the byte length of columns declared in bytes", func(t *testing.T) {
+		assert.Equal(t, int64(40), sqlutil.ParseLength("B", length(40), length(40)))
+		assert.Equal(t, int64(22), sqlutil.ParseLength("", sql.NullInt64{}, length(22)))
+	})
+
+	t.Run("should fall back to the length available", func(t *testing.T) {
+		assert.Equal(t, int64(40), sqlutil.ParseLength("C", sql.NullInt64{}, length(40)))
+		assert.Equal(t, int64(255), sqlutil.ParseLength("", length(255), sql.NullInt64{}))
+	})
+
+	t.Run("should return 0 for columns without length", func(t *testing.T) {
+		assert.Zero(t, sqlutil.ParseLength("", sql.NullInt64{}, sql.NullInt64{}))
+		assert.Zero(t, sqlutil.ParseLength("C", sql.NullInt64{}, sql.NullInt64{}))
+	})
+}
+
+func TestDataTypeProperties(t *testing.T) {
+	t.Run("should hold the normalized data type", func(t *testing.T) {
+		assert.Equal(t, map[string]interface{}{plugins.NormalizedDataTypeAttribute: plugins.DataTypeString},
+			sqlutil.DataTypeProperties("oracle", "VARCHAR2").Attributes.AsMap())
+		assert.Equal(t, map[string]interface{}{plugins.NormalizedDataTypeAttribute: plugins.DataTypeUnknown},
+			sqlutil.DataTypeProperties("mysql", "geometry").Attributes.AsMap())
+	})
+}
@@ -0,0 +1,29 @@
+package sqlutil
+
+// EndpointConfig holds the connection URLs of a database, to be squashed into the config of the extractor.
+// At least one of them is required.
+type EndpointConfig struct {
+	// ConnectionURL is the primary of the database
+	ConnectionURL string `mapstructure:"connection_url" validate:"required_without=ReadConnectionURL"`
+	// ReadConnectionURL is a read replica all the extraction queries are run against when set
+	ReadConnectionURL string `mapstructure:"read_connection_url" validate:"required_without=ConnectionURL"`
+}
+
+// ReadURL returns the URL extraction queries are run against, the read replica if set.
+func (c EndpointConfig) ReadURL() string {
+	if c.ReadConnectionURL != "" {
+		return c.ReadConnectionURL
+	}
+
+	return c.ConnectionURL
+}
+
+// PrimaryURL returns the URL identifying the database, e.g. in urns, so that the urns of tables
+// do not change when extracting from a replica. The read replica is used if no primary is set.
+func (c EndpointConfig) PrimaryURL() string {
+	if c.ConnectionURL != "" {
+		return c.ConnectionURL
+	}
+
+	return c.ReadConnectionURL
+}
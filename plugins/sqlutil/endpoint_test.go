@@ -0,0 +1,45 @@
+package sqlutil_test
+
+import (
+	"testing"
+
+	"github.com/odpf/meteor/plugins/sqlutil"
+	"github.com/odpf/meteor/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointConfig(t *testing.T) {
+	t.Run("should require at least one url", func(t *testing.T) {
+		var config sqlutil.EndpointConfig
+		assert.Error(t, utils.BuildConfig(map[string]interface{}{}, &config))
+	})
+
+	t.Run("should use the primary without a read replica", func(t *testing.T) {
+		var config sqlutil.EndpointConfig
+		require.NoError(t, utils.BuildConfig(map[string]interface{}{
+			"connection_url": "primary:3306",
+		}, &config))
+
+		assert.Equal(t, "primary:3306", config.ReadURL())
+		assert.Equal(t, "primary:3306", config.PrimaryURL())
+	})
+
+	t.Run("should read from the read replica", func(t *testing.T) {
+		var config sqlutil.EndpointConfig
+		require.NoError(t, utils.BuildConfig(map[string]interface{}{
+			"connection_url":      "primary:3306",
+			"read_connection_url": "replica:3306",
+		}, &config))
+
+		assert.Equal(t, "replica:3306", config.ReadURL())
+		assert.Equal(t, "primary:3306", config.PrimaryURL())
+	})
+
+	t.Run("should identify the database by the read replica without a primary", func(t *testing.T) {
+		config := sqlutil.EndpointConfig{ReadConnectionURL: "replica:3306"}
+
+		assert.Equal(t, "replica:3306", config.ReadURL())
+		assert.Equal(t, "replica:3306", config.PrimaryURL())
+	})
+}
@@ -0,0 +1,93 @@
+package sqlutil
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FreshnessAttribute is the attribute holding the freshness of a table.
+const FreshnessAttribute = "data_freshness"
+
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// FreshnessColumn maps tables with names matching the Table glob pattern
+// to the timestamp Column holding their last update time, e.g. updated_at.
+type FreshnessColumn struct {
+	Table  string `mapstructure:"table" validate:"required"`
+	Column string `mapstructure:"column" validate:"required"`
+}
+
+// FreshnessMatcher finds the freshness column of a table, the first matching pattern wins.
+type FreshnessMatcher struct {
+	columns []FreshnessColumn
+}
+
+// NewFreshnessMatcher returns a FreshnessMatcher for the given columns.
+func NewFreshnessMatcher(columns []FreshnessColumn) (*FreshnessMatcher, error) {
+	matcher := &FreshnessMatcher{}
+	for _, c := range columns {
+		pattern := strings.ToLower(c.Table)
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid freshness table pattern \"%s\"", c.Table)
+		}
+		matcher.columns = append(matcher.columns, FreshnessColumn{Table: pattern, Column: c.Column})
+	}
+
+	return matcher, nil
+}
+
+// Column returns the freshness column configured for the table, if any.
+func (m *FreshnessMatcher) Column(table string) (column string, ok bool) {
+	table = strings.ToLower(table)
+	for _, c := range m.columns {
+		if match, _ := path.Match(c.Table, table); match {
+			return c.Column, true
+		}
+	}
+
+	return "", false
+}
+
+// QueryFreshness runs the given MAX(column) query and returns the latest timestamp.
+// ok is false when the table has no rows.
+func QueryFreshness(ctx context.Context, db *sql.DB, query string) (freshness time.Time, ok bool, err error) {
+	var value interface{}
+	if err = db.QueryRowContext(ctx, query).Scan(&value); err != nil {
+		return
+	}
+
+	return ParseTimestamp(value)
+}
+
+// ParseTimestamp converts a timestamp scanned from a database driver into a time.
+// ok is false for NULL values.
+func ParseTimestamp(value interface{}) (t time.Time, ok bool, err error) {
+	switch v := value.(type) {
+	case nil:
+		return
+	case time.Time:
+		return v, true, nil
+	case []byte:
+		return ParseTimestamp(string(v))
+	case string:
+		for _, layout := range timestampLayouts {
+			if t, err = time.Parse(layout, v); err == nil {
+				return t, true, nil
+			}
+		}
+		return t, false, fmt.Errorf("unsupported timestamp \"%s\"", v)
+	}
+
+	return t, false, fmt.Errorf("unsupported timestamp type %T", value)
+}
@@ -0,0 +1,64 @@
+package sqlutil_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odpf/meteor/plugins/sqlutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreshnessMatcher(t *testing.T) {
+	t.Run("should return column of the first matching pattern", func(t *testing.T) {
+		m, err := sqlutil.NewFreshnessMatcher([]sqlutil.FreshnessColumn{
+			{Table: "orders_archive", Column: "archived_at"},
+			{Table: "ORDERS*", Column: "updated_at"},
+		})
+		require.NoError(t, err)
+
+		column, ok := m.Column("orders_archive")
+		assert.True(t, ok)
+		assert.Equal(t, "archived_at", column)
+
+		column, ok = m.Column("orders_2021")
+		assert.True(t, ok)
+		assert.Equal(t, "updated_at", column)
+
+		_, ok = m.Column("users")
+		assert.False(t, ok)
+	})
+
+	t.Run("should return error for invalid pattern", func(t *testing.T) {
+		_, err := sqlutil.NewFreshnessMatcher([]sqlutil.FreshnessColumn{{Table: "orders[", Column: "updated_at"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestParseTimestamp(t *testing.T) {
+	expected := time.Date(2021, 11, 2, 10, 31, 39, 0, time.UTC)
+
+	for _, value := range []interface{}{
+		expected,
+		"2021-11-02T10:31:39Z",
+		[]byte("2021-11-02 10:31:39"),
+	} {
+		actual, ok, err := sqlutil.ParseTimestamp(value)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, expected.Equal(actual), "%v", value)
+	}
+
+	t.Run("should not return timestamp for null", func(t *testing.T) {
+		_, ok, err := sqlutil.ParseTimestamp(nil)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("should return error for values that are not timestamps", func(t *testing.T) {
+		_, _, err := sqlutil.ParseTimestamp(int64(42))
+		assert.Error(t, err)
+		_, _, err = sqlutil.ParseTimestamp("yesterday")
+		assert.Error(t, err)
+	})
+}
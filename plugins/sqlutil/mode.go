@@ -0,0 +1,10 @@
+package sqlutil
+
+// Run modes of the sql extractors, set by their mode config.
+// The default mode is up to each extractor, and stated in its README.
+const (
+	// ModeSchema only extracts the schema of tables, without any query reading their rows
+	ModeSchema = "schema"
+	// ModeProfile extracts the schema of tables along with their profile, e.g. counting their rows
+	ModeProfile = "profile"
+)
@@ -0,0 +1,68 @@
+package sqlutil
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/utils"
+)
+
+// Types of routines, as set in the resource type of their assets
+const (
+	RoutineProcedure = "procedure"
+	RoutineFunction  = "function"
+)
+
+// Routine is a stored procedure or function of a database schema
+type Routine struct {
+	Schema string
+	Name   string
+	// Type is RoutineProcedure or RoutineFunction
+	Type        string
+	Description string
+	// ReturnType is the data type returned by functions
+	ReturnType string
+	// Definition is the source body of the routine, empty if not extracted or not readable
+	Definition string
+}
+
+// RoutineType maps the PROCEDURE and FUNCTION types of the catalogs to a routine type
+func RoutineType(catalogType string) string {
+	return strings.ToLower(catalogType)
+}
+
+// RoutineURN builds the urn of a routine of the namespace, a database or schema. Functions and
+// procedures having separate namespaces in some databases, the type is part of the urn.
+func RoutineURN(service, host, namespace, routineType, name string) string {
+	return models.JobURN(service, host, fmt.Sprintf("%s/%s/%s", namespace, routineType, name))
+}
+
+// Asset builds the job asset of the routine
+func (r Routine) Asset(urn, service string) *assetsv1beta1.Job {
+	attributes := map[string]interface{}{
+		"schema": r.Schema,
+	}
+	if r.ReturnType != "" {
+		attributes["return_type"] = r.ReturnType
+	}
+	if r.Definition != "" {
+		attributes["definition"] = r.Definition
+	}
+
+	return &assetsv1beta1.Job{
+		Resource: &commonv1beta1.Resource{
+			Urn:         urn,
+			Name:        r.Name,
+			Service:     service,
+			Type:        r.Type,
+			Description: r.Description,
+		},
+		Properties: &facetsv1beta1.Properties{
+			Attributes: utils.TryParseMapToProto(attributes),
+		},
+	}
+}
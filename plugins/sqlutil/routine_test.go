@@ -0,0 +1,44 @@
+package sqlutil_test
+
+import (
+	"testing"
+
+	"github.com/odpf/meteor/plugins/sqlutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutine(t *testing.T) {
+	t.Run("should namespace urns by schema and type", func(t *testing.T) {
+		assert.Equal(t, "mysql::localhost:3306/shop/function/total",
+			sqlutil.RoutineURN("mysql", "localhost:3306", "shop", sqlutil.RoutineFunction, "total"))
+		assert.Equal(t, "procedure", sqlutil.RoutineType("PROCEDURE"))
+	})
+
+	t.Run("should build job assets of routines", func(t *testing.T) {
+		routine := sqlutil.Routine{
+			Schema:      "shop",
+			Name:        "total",
+			Type:        sqlutil.RoutineFunction,
+			Description: "order total",
+			ReturnType:  "decimal(12,2)",
+			Definition:  "RETURN 1",
+		}
+
+		job := routine.Asset("mysql::localhost:3306/shop/function/total", "mysql")
+		assert.Equal(t, "mysql::localhost:3306/shop/function/total", job.Resource.Urn)
+		assert.Equal(t, "total", job.Resource.Name)
+		assert.Equal(t, "mysql", job.Resource.Service)
+		assert.Equal(t, "function", job.Resource.Type)
+		assert.Equal(t, "order total", job.Resource.Description)
+		assert.Equal(t, map[string]interface{}{
+			"schema":      "shop",
+			"return_type": "decimal(12,2)",
+			"definition":  "RETURN 1",
+		}, job.Properties.Attributes.AsMap())
+	})
+
+	t.Run("should leave out unset return type and definition", func(t *testing.T) {
+		job := sqlutil.Routine{Schema: "shop", Name: "refresh", Type: sqlutil.RoutineProcedure}.Asset("urn", "mysql")
+		assert.Equal(t, map[string]interface{}{"schema": "shop"}, job.Properties.Attributes.AsMap())
+	})
+}
@@ -0,0 +1,4 @@
+package sqlutil
+
+// SizeAttribute is the attribute holding the size in bytes of a table, its indexes included.
+const SizeAttribute = "size_bytes"
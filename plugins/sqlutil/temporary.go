@@ -0,0 +1,74 @@
+package sqlutil
+
+import (
+	"path"
+	"strings"
+
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	"github.com/pkg/errors"
+)
+
+// TemporaryTag is the tag added to tables classified as temporary.
+const TemporaryTag = "temporary"
+
+// TemporaryTableConfig holds the name patterns of temporary or staging tables,
+// e.g. "tmp_*" or "stg_*", and whether they should be skipped instead of tagged.
+type TemporaryTableConfig struct {
+	Patterns []string `mapstructure:"patterns"`
+	Skip     bool     `mapstructure:"skip"`
+}
+
+// TableClassifier classifies tables as temporary by matching their names
+// against glob patterns, case insensitively.
+type TableClassifier struct {
+	patterns []string
+	skip     bool
+}
+
+// NewTableClassifier returns a TableClassifier for the given config.
+func NewTableClassifier(cfg TemporaryTableConfig) (*TableClassifier, error) {
+	patterns := make([]string, len(cfg.Patterns))
+	for i, pattern := range cfg.Patterns {
+		pattern = strings.ToLower(pattern)
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid temporary table pattern \"%s\"", pattern)
+		}
+		patterns[i] = pattern
+	}
+
+	return &TableClassifier{
+		patterns: patterns,
+		skip:     cfg.Skip,
+	}, nil
+}
+
+// IsTemporary checks if the table name matches any of the patterns.
+func (c *TableClassifier) IsTemporary(name string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range c.patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ShouldSkip checks if the table is temporary and temporary tables are skipped.
+func (c *TableClassifier) ShouldSkip(name string) bool {
+	return c.skip && c.IsTemporary(name)
+}
+
+// Tag adds TemporaryTag to the properties if the table is temporary.
+// It returns the given properties, creating them if needed.
+func (c *TableClassifier) Tag(name string, properties *facetsv1beta1.Properties) *facetsv1beta1.Properties {
+	if !c.IsTemporary(name) {
+		return properties
+	}
+	if properties == nil {
+		properties = &facetsv1beta1.Properties{}
+	}
+	properties.Tags = append(properties.Tags, TemporaryTag)
+
+	return properties
+}
@@ -0,0 +1,61 @@
+package sqlutil_test
+
+import (
+	"testing"
+
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	"github.com/odpf/meteor/plugins/sqlutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableClassifier(t *testing.T) {
+	t.Run("should classify tables matching any pattern case insensitively", func(t *testing.T) {
+		c, err := sqlutil.NewTableClassifier(sqlutil.TemporaryTableConfig{
+			Patterns: []string{"tmp_*", "STG_*"},
+		})
+		require.NoError(t, err)
+
+		assert.True(t, c.IsTemporary("tmp_orders"))
+		assert.True(t, c.IsTemporary("TMP_ORDERS"))
+		assert.True(t, c.IsTemporary("stg_users"))
+		assert.False(t, c.IsTemporary("orders"))
+		assert.False(t, c.IsTemporary("orders_tmp_"))
+	})
+
+	t.Run("should not classify any table without patterns", func(t *testing.T) {
+		c, err := sqlutil.NewTableClassifier(sqlutil.TemporaryTableConfig{})
+		require.NoError(t, err)
+
+		assert.False(t, c.IsTemporary("tmp_orders"))
+		assert.Nil(t, c.Tag("tmp_orders", nil))
+	})
+
+	t.Run("should only skip temporary tables when skip is set", func(t *testing.T) {
+		tagging, err := sqlutil.NewTableClassifier(sqlutil.TemporaryTableConfig{Patterns: []string{"tmp_*"}})
+		require.NoError(t, err)
+		skipping, err := sqlutil.NewTableClassifier(sqlutil.TemporaryTableConfig{Patterns: []string{"tmp_*"}, Skip: true})
+		require.NoError(t, err)
+
+		assert.False(t, tagging.ShouldSkip("tmp_orders"))
+		assert.True(t, skipping.ShouldSkip("tmp_orders"))
+		assert.False(t, skipping.ShouldSkip("orders"))
+	})
+
+	t.Run("should tag temporary tables", func(t *testing.T) {
+		c, err := sqlutil.NewTableClassifier(sqlutil.TemporaryTableConfig{Patterns: []string{"tmp_*"}})
+		require.NoError(t, err)
+
+		props := c.Tag("tmp_orders", &facetsv1beta1.Properties{Tags: []string{"sales"}})
+		assert.Equal(t, []string{"sales", sqlutil.TemporaryTag}, props.Tags)
+		assert.Equal(t, []string{sqlutil.TemporaryTag}, c.Tag("tmp_users", nil).Tags)
+
+		props = c.Tag("orders", &facetsv1beta1.Properties{Tags: []string{"sales"}})
+		assert.Equal(t, []string{"sales"}, props.Tags)
+	})
+
+	t.Run("should return error for invalid pattern", func(t *testing.T) {
+		_, err := sqlutil.NewTableClassifier(sqlutil.TemporaryTableConfig{Patterns: []string{"tmp_["}})
+		assert.Error(t, err)
+	})
+}
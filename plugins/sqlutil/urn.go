@@ -0,0 +1,89 @@
+package sqlutil
+
+import (
+	"net/url"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// URNConfig holds the template of the urns of tables, to be squashed into extractor configs.
+type URNConfig struct {
+	// URNTemplate builds the urn of a table by replacing the {service}, {host}, {database},
+	// {schema} and {table} placeholders, e.g. "{service}::{host}/{database}/{table}".
+	// The extractor format is used if empty.
+	URNTemplate string `mapstructure:"urn_template"`
+}
+
+// URNFields are the values of the urn template placeholders.
+// The fields an extractor does not know about, e.g. the schema of a mysql table, are empty.
+type URNFields struct {
+	Service  string
+	Host     string
+	Database string
+	Schema   string
+	Table    string
+}
+
+func (f URNFields) value(name string) (string, bool) {
+	switch name {
+	case "service":
+		return f.Service, true
+	case "host":
+		return f.Host, true
+	case "database":
+		return f.Database, true
+	case "schema":
+		return f.Schema, true
+	case "table":
+		return f.Table, true
+	}
+
+	return "", false
+}
+
+// placeholders are not Go template actions as recipes are rendered as templates
+var placeholderPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// URNBuilder builds the urns of tables from the configured template, or the extractor format.
+type URNBuilder struct {
+	template string
+	format   func(URNFields) string
+}
+
+// NewURNBuilder returns a URNBuilder replacing the placeholders of the template of the config,
+// format keeps the current urns of the extractor when no template is set.
+func NewURNBuilder(cfg URNConfig, format func(URNFields) string) (*URNBuilder, error) {
+	for _, match := range placeholderPattern.FindAllStringSubmatch(cfg.URNTemplate, -1) {
+		if _, ok := (URNFields{}).value(match[1]); !ok {
+			return nil, errors.Errorf("unknown placeholder \"%s\" in urn template \"%s\"", match[0], cfg.URNTemplate)
+		}
+	}
+
+	return &URNBuilder{
+		template: cfg.URNTemplate,
+		format:   format,
+	}, nil
+}
+
+// URN returns the urn of the table.
+func (b *URNBuilder) URN(fields URNFields) string {
+	if b.template == "" {
+		return b.format(fields)
+	}
+
+	return placeholderPattern.ReplaceAllStringFunc(b.template, func(placeholder string) string {
+		value, _ := fields.value(placeholder[1 : len(placeholder)-1])
+		return value
+	})
+}
+
+// URLHost returns the host of a connection url, or an empty string if it is not a url.
+func URLHost(connectionURL string) string {
+	u, err := url.Parse(connectionURL)
+	if err != nil {
+		return ""
+	}
+
+	return u.Host
+}
@@ -0,0 +1,267 @@
+package sqlutil
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// TableReference is a table referenced by a view definition.
+// Schema is empty for unqualified names, which resolve to the schema of the view.
+type TableReference struct {
+	Schema string
+	Name   string
+}
+
+// ViewReferences returns the tables a view definition selects from, i.e. the names following FROM,
+// JOIN and the commas of FROM lists. Subqueries are followed, common table expressions and table
+// functions are not tables. fold converts unquoted identifiers, e.g. to upper case for Oracle, and
+// may be nil. This is an identifier-level parse, definitions it does not understand return an error.
+func ViewReferences(definition string, fold func(string) string) ([]TableReference, error) {
+	tokens, err := tokenizeSQL(definition)
+	if err != nil {
+		return nil, err
+	}
+	if fold == nil {
+		fold = func(s string) string { return s }
+	}
+
+	ctes := commonTableExpressions(tokens)
+	// each parenthesis opens a frame, query frames are the ones whose FROM clauses hold tables,
+	// as opposed to expressions such as EXTRACT(YEAR FROM date)
+	type frame struct {
+		query    bool
+		fromList bool
+	}
+	frames := []frame{{query: true}}
+	expectTable := false
+	seen := make(map[TableReference]bool)
+	var refs []TableReference
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		top := &frames[len(frames)-1]
+		switch {
+		case tok.isSymbol("("):
+			if expectTable {
+				// derived tables and parenthesized joins
+				frames = append(frames, frame{query: true, fromList: true})
+			} else {
+				frames = append(frames, frame{})
+			}
+		case tok.isSymbol(")"):
+			if len(frames) == 1 {
+				return nil, errors.New("unbalanced parentheses")
+			}
+			if expectTable {
+				return nil, errors.New("missing table name")
+			}
+			frames = frames[:len(frames)-1]
+		case tok.isSymbol(","):
+			if top.fromList && !expectTable {
+				expectTable = true
+			}
+		case tok.keyword() == "SELECT":
+			top.query = true
+			top.fromList = false
+			expectTable = false
+		case tok.keyword() == "FROM":
+			if top.query {
+				top.fromList = true
+				expectTable = true
+			}
+		case tok.keyword() == "JOIN" || tok.keyword() == "APPLY":
+			expectTable = true
+		case clauseKeywords[tok.keyword()]:
+			if expectTable {
+				return nil, errors.Errorf("missing table name before %s", tok.value)
+			}
+			top.fromList = false
+		case expectTable && (tok.keyword() == "LATERAL" || tok.keyword() == "ONLY"):
+		case expectTable && tok.kind == identifierToken:
+			parts := []token{tok}
+			for i+2 < len(tokens) && tokens[i+1].isSymbol(".") && tokens[i+2].kind == identifierToken {
+				parts = append(parts, tokens[i+2])
+				i += 2
+			}
+			expectTable = false
+			if i+1 < len(tokens) && tokens[i+1].isSymbol("(") {
+				// table functions, e.g. TABLE(...) or JSON_TABLE(...)
+				continue
+			}
+			if i+1 < len(tokens) && tokens[i+1].isSymbol("@") {
+				return nil, errors.Errorf("reference to a remote table %s", joinTokens(parts))
+			}
+			if len(parts) > 2 {
+				return nil, errors.Errorf("ambiguous table reference %s", joinTokens(parts))
+			}
+
+			name := parts[len(parts)-1]
+			if len(parts) == 1 && !name.quoted && (ctes[strings.ToUpper(name.value)] || strings.EqualFold(name.value, "DUAL")) {
+				continue
+			}
+			ref := TableReference{Name: name.identifier(fold)}
+			if len(parts) == 2 {
+				ref.Schema = parts[0].identifier(fold)
+			}
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+	if len(frames) != 1 {
+		return nil, errors.New("unbalanced parentheses")
+	}
+	if expectTable {
+		return nil, errors.New("missing table name")
+	}
+
+	return refs, nil
+}
+
+// clauseKeywords end the FROM clause of a query
+var clauseKeywords = map[string]bool{
+	"WHERE": true, "GROUP": true, "HAVING": true, "ORDER": true, "LIMIT": true, "OFFSET": true, "FETCH": true,
+	"UNION": true, "INTERSECT": true, "MINUS": true, "EXCEPT": true, "ON": true, "USING": true,
+	"CONNECT": true, "START": true, "WINDOW": true, "FOR": true, "WITH": true, "PIVOT": true, "UNPIVOT": true,
+}
+
+// commonTableExpressions returns the upper cased names of the common table expressions,
+// i.e. the names followed by AS and a parenthesis, with an optional column list in between.
+func commonTableExpressions(tokens []token) map[string]bool {
+	ctes := make(map[string]bool)
+	for i, tok := range tokens {
+		if tok.kind != identifierToken {
+			continue
+		}
+		next := i + 1
+		if next < len(tokens) && tokens[next].isSymbol("(") {
+			for next < len(tokens) && !tokens[next].isSymbol(")") {
+				next++
+			}
+			next++
+		}
+		if next+1 < len(tokens) && tokens[next].keyword() == "AS" && tokens[next+1].isSymbol("(") {
+			ctes[strings.ToUpper(tok.value)] = true
+		}
+	}
+
+	return ctes
+}
+
+type tokenKind int
+
+const (
+	identifierToken tokenKind = iota
+	symbolToken
+	literalToken
+)
+
+type token struct {
+	kind   tokenKind
+	value  string
+	quoted bool
+}
+
+func (t token) isSymbol(symbol string) bool {
+	return t.kind == symbolToken && t.value == symbol
+}
+
+// keyword returns the upper cased value of unquoted words, empty otherwise
+func (t token) keyword() string {
+	if t.kind != identifierToken || t.quoted {
+		return ""
+	}
+	return strings.ToUpper(t.value)
+}
+
+func (t token) identifier(fold func(string) string) string {
+	if t.quoted {
+		return t.value
+	}
+	return fold(t.value)
+}
+
+func joinTokens(tokens []token) string {
+	values := make([]string, len(tokens))
+	for i, tok := range tokens {
+		values[i] = tok.value
+	}
+	return strings.Join(values, ".")
+}
+
+// tokenizeSQL splits a statement into words, quoted identifiers and symbols, dropping comments.
+// Backticks and double quotes delimit identifiers, single quotes delimit string literals.
+func tokenizeSQL(s string) (tokens []token, err error) {
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := i + 2
+			for end+1 < len(runes) && !(runes[end] == '*' && runes[end+1] == '/') {
+				end++
+			}
+			if end+1 >= len(runes) {
+				return nil, errors.New("unterminated comment")
+			}
+			i = end + 2
+		case r == '`' || r == '"' || r == '\'':
+			value, n, err := readQuoted(runes[i:])
+			if err != nil {
+				return nil, err
+			}
+			if r == '\'' {
+				tokens = append(tokens, token{kind: literalToken, value: value})
+			} else {
+				tokens = append(tokens, token{kind: identifierToken, value: value, quoted: true})
+			}
+			i += n
+		case isWordRune(r):
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: identifierToken, value: string(runes[start:i])})
+		default:
+			tokens = append(tokens, token{kind: symbolToken, value: string(r)})
+			i++
+		}
+	}
+
+	return tokens, nil
+}
+
+// readQuoted reads a quoted value, a doubled quote being an escaped quote,
+// and returns it along with the number of runes read.
+func readQuoted(runes []rune) (string, int, error) {
+	quote := runes[0]
+	var b strings.Builder
+	for i := 1; i < len(runes); i++ {
+		switch {
+		case quote == '\'' && runes[i] == '\\' && i+1 < len(runes):
+			i++
+			b.WriteRune(runes[i])
+		case runes[i] != quote:
+			b.WriteRune(runes[i])
+		case i+1 < len(runes) && runes[i+1] == quote:
+			i++
+			b.WriteRune(quote)
+		default:
+			return b.String(), i + 1, nil
+		}
+	}
+
+	return "", 0, errors.Errorf("unterminated %c", quote)
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || r == '$' || r == '#' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
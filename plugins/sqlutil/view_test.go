@@ -0,0 +1,76 @@
+package sqlutil_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/odpf/meteor/plugins/sqlutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewReferences(t *testing.T) {
+	t.Run("should return the tables of mysql view definitions", func(t *testing.T) {
+		// as stored in information_schema.VIEWS
+		definition := "select `o`.`id` AS `id`,`c`.`name` AS `name` " +
+			"from (`sales`.`orders` `o` join `crm`.`customers` `c` on((`o`.`customer_id` = `c`.`id`))) " +
+			"where (`o`.`status` = 'from x')"
+
+		refs, err := sqlutil.ViewReferences(definition, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []sqlutil.TableReference{
+			{Schema: "sales", Name: "orders"},
+			{Schema: "crm", Name: "customers"},
+		}, refs)
+	})
+
+	t.Run("should fold unquoted identifiers", func(t *testing.T) {
+		definition := `SELECT e.name, d.name FROM hr.employees e, "Departments" d
+			WHERE e.department_id = d.id AND EXTRACT(YEAR FROM e.hired_at) > 2000`
+
+		refs, err := sqlutil.ViewReferences(definition, strings.ToUpper)
+		require.NoError(t, err)
+		assert.Equal(t, []sqlutil.TableReference{
+			{Schema: "HR", Name: "EMPLOYEES"},
+			{Name: "Departments"},
+		}, refs)
+	})
+
+	t.Run("should follow subqueries and skip common table expressions", func(t *testing.T) {
+		definition := `WITH recent (id) AS (SELECT id FROM orders WHERE created_at > sysdate - 1) -- last day
+			SELECT r.id, (SELECT max(amount) FROM payments p WHERE p.order_id = r.id) /* paid */
+			FROM recent r
+			LEFT JOIN (SELECT order_id FROM refunds UNION SELECT order_id FROM returns) x ON x.order_id = r.id
+			WHERE r.id IN (SELECT order_id FROM orders) AND 1 = (SELECT 1 FROM dual)`
+
+		refs, err := sqlutil.ViewReferences(definition, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []sqlutil.TableReference{
+			{Name: "orders"},
+			{Name: "payments"},
+			{Name: "refunds"},
+			{Name: "returns"},
+		}, refs)
+	})
+
+	t.Run("should return no tables for views without any", func(t *testing.T) {
+		refs, err := sqlutil.ViewReferences("select 1 AS `one`", nil)
+		require.NoError(t, err)
+		assert.Empty(t, refs)
+	})
+
+	t.Run("should return error for definitions it does not understand", func(t *testing.T) {
+		for _, definition := range []string{
+			"select * from",
+			"select * from (select 1",
+			"select * from orders where name = 'unterminated",
+			"select * from orders /* unterminated",
+			"select * from catalog.sales.orders",
+			"select * from orders@remote",
+			"select * from where x = 1",
+		} {
+			_, err := sqlutil.ViewReferences(definition, nil)
+			assert.Error(t, err, definition)
+		}
+	})
+}
@@ -0,0 +1,166 @@
+package storageutil
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ObjectCountAttribute is the attribute holding the number of objects of a dataset.
+	ObjectCountAttribute = "object_count"
+	// TotalSizeAttribute is the attribute holding the total size in bytes of the objects of a dataset.
+	TotalSizeAttribute = "total_size_bytes"
+	// FormatAttribute is the attribute holding the inferred format of a dataset.
+	FormatAttribute = "format"
+)
+
+// Object is a stored object, e.g. an S3 or GCS object.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Dataset summarizes the objects sharing a common prefix.
+type Dataset struct {
+	// Prefix is the common prefix of the objects, ending with a "/" unless it is empty.
+	Prefix       string
+	ObjectCount  int64
+	TotalSize    int64
+	Format       string
+	LastModified time.Time
+	// SampleKey is the key of an object of the dataset format, to infer its schema from.
+	SampleKey string
+
+	formats map[string]string
+}
+
+// Attributes returns the summary of the dataset as table attributes.
+func (d Dataset) Attributes(bucket string) map[string]interface{} {
+	return map[string]interface{}{
+		"bucket":             bucket,
+		"prefix":             d.Prefix,
+		ObjectCountAttribute: d.ObjectCount,
+		TotalSizeAttribute:   d.TotalSize,
+		FormatAttribute:      d.Format,
+	}
+}
+
+// DatasetConfig configures how objects are grouped into datasets.
+type DatasetConfig struct {
+	// Prefix limits the objects to the ones with keys starting with it.
+	Prefix string `mapstructure:"prefix"`
+	// Depth is the number of path segments below Prefix forming a dataset,
+	// e.g. with a depth of 1 "orders/dt=2021-01-01/part-0.csv" belongs to "orders/".
+	Depth int `mapstructure:"depth" validate:"gte=1" default:"1"`
+	// Exclude skips datasets with prefixes matching any of the glob patterns, e.g. "tmp/*".
+	Exclude []string `mapstructure:"exclude"`
+}
+
+// Grouper groups objects into datasets by their common prefix.
+type Grouper struct {
+	config   DatasetConfig
+	datasets map[string]*Dataset
+}
+
+// NewGrouper returns a Grouper for the given config.
+func NewGrouper(config DatasetConfig) (*Grouper, error) {
+	if config.Depth < 1 {
+		config.Depth = 1
+	}
+	for _, pattern := range config.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid exclude pattern \"%s\"", pattern)
+		}
+	}
+
+	return &Grouper{
+		config:   config,
+		datasets: make(map[string]*Dataset),
+	}, nil
+}
+
+// Add adds the object to its dataset.
+// Objects outside of the prefix, in excluded datasets or representing folders are ignored.
+func (g *Grouper) Add(o Object) {
+	if !strings.HasPrefix(o.Key, g.config.Prefix) || strings.HasSuffix(o.Key, "/") {
+		return
+	}
+
+	prefix := g.datasetPrefix(o.Key)
+	if g.isExcluded(prefix) {
+		return
+	}
+
+	d, ok := g.datasets[prefix]
+	if !ok {
+		d = &Dataset{Prefix: prefix, formats: make(map[string]string)}
+		g.datasets[prefix] = d
+	}
+	d.ObjectCount++
+	d.TotalSize += o.Size
+	if o.LastModified.After(d.LastModified) {
+		d.LastModified = o.LastModified
+	}
+	if format := InferFormat(o.Key); format != "" {
+		if _, exists := d.formats[format]; !exists {
+			d.formats[format] = o.Key
+		}
+	}
+}
+
+// Datasets returns the datasets sorted by prefix.
+func (g *Grouper) Datasets() []Dataset {
+	datasets := make([]Dataset, 0, len(g.datasets))
+	for _, d := range g.datasets {
+		d.Format, d.SampleKey = datasetFormat(d.formats)
+		datasets = append(datasets, *d)
+	}
+	sort.Slice(datasets, func(i, j int) bool {
+		return datasets[i].Prefix < datasets[j].Prefix
+	})
+
+	return datasets
+}
+
+// datasetPrefix returns the prefix of the object key up to the configured depth below the prefix.
+func (g *Grouper) datasetPrefix(key string) string {
+	segments := strings.Split(strings.TrimPrefix(key, g.config.Prefix), "/")
+	// the last segment is the object name
+	segments = segments[:len(segments)-1]
+	if len(segments) > g.config.Depth {
+		segments = segments[:g.config.Depth]
+	}
+	if len(segments) == 0 {
+		return g.config.Prefix
+	}
+
+	return g.config.Prefix + strings.Join(segments, "/") + "/"
+}
+
+func (g *Grouper) isExcluded(prefix string) bool {
+	name := strings.TrimSuffix(prefix, "/")
+	for _, pattern := range g.config.Exclude {
+		if match, _ := path.Match(pattern, name); match {
+			return true
+		}
+	}
+
+	return false
+}
+
+// datasetFormat returns the single format of the dataset, or FormatMixed with no sample if there are several.
+func datasetFormat(formats map[string]string) (format, sampleKey string) {
+	if len(formats) > 1 {
+		return FormatMixed, ""
+	}
+	for format, sampleKey = range formats {
+		return
+	}
+
+	return "", ""
+}
@@ -0,0 +1,117 @@
+package storageutil_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	"github.com/odpf/meteor/plugins/storageutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrouper(t *testing.T) {
+	t1 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+	objects := []storageutil.Object{
+		{Key: "warehouse/orders/dt=2021-01-01/part-0.parquet", Size: 10, LastModified: t1},
+		{Key: "warehouse/orders/dt=2021-01-02/part-0.parquet", Size: 20, LastModified: t2},
+		{Key: "warehouse/orders/_SUCCESS", Size: 0, LastModified: t2},
+		{Key: "warehouse/users/users.csv.gz", Size: 5, LastModified: t1},
+		{Key: "warehouse/events/a.json", Size: 1, LastModified: t1},
+		{Key: "warehouse/events/b.csv", Size: 1, LastModified: t1},
+		{Key: "warehouse/tmp/scratch.csv", Size: 1, LastModified: t1},
+		{Key: "warehouse/readme.txt", Size: 3, LastModified: t1},
+		{Key: "warehouse/empty/", Size: 0, LastModified: t1},
+		{Key: "other/file.csv", Size: 1, LastModified: t1},
+	}
+
+	t.Run("should group objects by prefix up to the depth", func(t *testing.T) {
+		g, err := storageutil.NewGrouper(storageutil.DatasetConfig{
+			Prefix:  "warehouse/",
+			Depth:   1,
+			Exclude: []string{"warehouse/tmp"},
+		})
+		require.NoError(t, err)
+		for _, o := range objects {
+			g.Add(o)
+		}
+
+		assert.Equal(t, []storageutil.Dataset{
+			{Prefix: "warehouse/"},
+			{Prefix: "warehouse/events/"},
+			{Prefix: "warehouse/orders/"},
+			{Prefix: "warehouse/users/"},
+		}, prefixes(g.Datasets()))
+
+		datasets := g.Datasets()
+		assert.Equal(t, int64(1), datasets[0].ObjectCount)
+		assert.Equal(t, "", datasets[0].Format)
+		assert.Equal(t, storageutil.FormatMixed, datasets[1].Format)
+		assert.Equal(t, "", datasets[1].SampleKey)
+
+		orders := datasets[2]
+		assert.Equal(t, int64(3), orders.ObjectCount)
+		assert.Equal(t, int64(30), orders.TotalSize)
+		assert.Equal(t, storageutil.FormatParquet, orders.Format)
+		assert.Equal(t, t2, orders.LastModified)
+		assert.Equal(t, "warehouse/orders/dt=2021-01-01/part-0.parquet", orders.SampleKey)
+
+		assert.Equal(t, storageutil.FormatCSV, datasets[3].Format)
+	})
+
+	t.Run("should group objects into deeper datasets", func(t *testing.T) {
+		g, err := storageutil.NewGrouper(storageutil.DatasetConfig{Prefix: "warehouse/orders/", Depth: 2})
+		require.NoError(t, err)
+		for _, o := range objects {
+			g.Add(o)
+		}
+
+		assert.Equal(t, []storageutil.Dataset{
+			{Prefix: "warehouse/orders/"},
+			{Prefix: "warehouse/orders/dt=2021-01-01/"},
+			{Prefix: "warehouse/orders/dt=2021-01-02/"},
+		}, prefixes(g.Datasets()))
+	})
+
+	t.Run("should return error for invalid exclude pattern", func(t *testing.T) {
+		_, err := storageutil.NewGrouper(storageutil.DatasetConfig{Exclude: []string{"tmp["}})
+		assert.Error(t, err)
+	})
+}
+
+func TestInferFormat(t *testing.T) {
+	for key, format := range map[string]string{
+		"a/b.parquet":        storageutil.FormatParquet,
+		"a/b.CSV":            storageutil.FormatCSV,
+		"a/b.csv.gz":         storageutil.FormatCSV,
+		"a/b.jsonl":          storageutil.FormatJSON,
+		"a/b.snappy.parquet": storageutil.FormatParquet,
+		"a/b.avro":           storageutil.FormatAvro,
+		"a/_SUCCESS":         "",
+		"a/b.gz":             "",
+	} {
+		assert.Equal(t, format, storageutil.InferFormat(key), key)
+	}
+}
+
+func TestInferCSVColumns(t *testing.T) {
+	t.Run("should read columns from header", func(t *testing.T) {
+		columns, err := storageutil.InferCSVColumns(strings.NewReader("id, name,email\n1,a,a@example.com\n2,b"))
+		require.NoError(t, err)
+		assert.Equal(t, []*facetsv1beta1.Column{{Name: "id"}, {Name: "name"}, {Name: "email"}}, columns)
+	})
+
+	t.Run("should return error for empty object", func(t *testing.T) {
+		_, err := storageutil.InferCSVColumns(strings.NewReader(""))
+		assert.Error(t, err)
+	})
+}
+
+func prefixes(datasets []storageutil.Dataset) (result []storageutil.Dataset) {
+	for _, d := range datasets {
+		result = append(result, storageutil.Dataset{Prefix: d.Prefix})
+	}
+	return
+}
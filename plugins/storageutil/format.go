@@ -0,0 +1,68 @@
+package storageutil
+
+import (
+	"encoding/csv"
+	"io"
+	"path"
+	"strings"
+
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	"github.com/pkg/errors"
+)
+
+// Formats inferred from object extensions.
+const (
+	FormatParquet = "parquet"
+	FormatCSV     = "csv"
+	FormatJSON    = "json"
+	FormatAvro    = "avro"
+	FormatORC     = "orc"
+	// FormatMixed is the format of datasets holding objects of several formats.
+	FormatMixed = "mixed"
+)
+
+var extensionFormats = map[string]string{
+	".parquet": FormatParquet,
+	".csv":     FormatCSV,
+	".json":    FormatJSON,
+	".jsonl":   FormatJSON,
+	".ndjson":  FormatJSON,
+	".avro":    FormatAvro,
+	".orc":     FormatORC,
+}
+
+var compressionExtensions = map[string]bool{
+	".gz":     true,
+	".bz2":    true,
+	".snappy": true,
+	".zst":    true,
+}
+
+// InferFormat infers the format of an object from its key extension, ignoring compression extensions.
+// It returns an empty string for unknown formats.
+func InferFormat(key string) string {
+	key = strings.ToLower(key)
+	ext := path.Ext(key)
+	if compressionExtensions[ext] {
+		ext = path.Ext(strings.TrimSuffix(key, ext))
+	}
+
+	return extensionFormats[ext]
+}
+
+// InferCSVColumns reads the header of a CSV object into columns.
+func InferCSVColumns(r io.Reader) ([]*facetsv1beta1.Column, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read csv header")
+	}
+
+	columns := make([]*facetsv1beta1.Column, 0, len(header))
+	for _, name := range header {
+		columns = append(columns, &facetsv1beta1.Column{Name: strings.TrimSpace(name)})
+	}
+
+	return columns, nil
+}
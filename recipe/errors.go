@@ -2,6 +2,7 @@ package recipe
 
 import (
 	"fmt"
+	"strings"
 )
 
 // InvalidRecipeError hold the field to show the error message
@@ -12,3 +13,29 @@ type InvalidRecipeError struct {
 func (err InvalidRecipeError) Error() string {
 	return fmt.Sprintf("invalid recipe: \"%s\"", err.Message)
 }
+
+// FileError holds the error of reading the recipe file at Path
+type FileError struct {
+	Path string
+	Err  error
+}
+
+func (err FileError) Error() string {
+	return fmt.Sprintf("failed to read \"%s\": %v", err.Path, err.Err)
+}
+
+func (err FileError) Unwrap() error {
+	return err.Err
+}
+
+// FileErrors lists the recipe files which failed to be read
+type FileErrors []FileError
+
+func (errs FileErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
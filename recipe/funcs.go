@@ -0,0 +1,38 @@
+package recipe
+
+import (
+	"os"
+	"text/template"
+	"time"
+)
+
+// templateFuncs are the functions available to recipe templates, e.g.
+// {{ now | addDate -1 | date "2006-01-02" }} for the date of yesterday.
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		// now returns the time the recipe is read at
+		"now": time.Now,
+		// utc converts a time to UTC
+		"utc": func(t time.Time) time.Time {
+			return t.UTC()
+		},
+		// date formats a time with a Go layout, e.g. "2006-01-02"
+		"date": func(layout string, t time.Time) string {
+			return t.Format(layout)
+		},
+		// addDate adds a number of days to a time, negative to go back
+		"addDate": func(days int, t time.Time) time.Time {
+			return t.AddDate(0, 0, days)
+		},
+		// addDuration adds a duration to a time, e.g. "-1h30m"
+		"addDuration": func(duration string, t time.Time) (time.Time, error) {
+			d, err := time.ParseDuration(duration)
+			if err != nil {
+				return t, err
+			}
+			return t.Add(d), nil
+		},
+		// env returns the value of an environment variable, unlike variables it is not prefixed with METEOR_
+		"env": os.Getenv,
+	}
+}
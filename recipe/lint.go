@@ -0,0 +1,175 @@
+package recipe
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/odpf/meteor/plugins"
+)
+
+// undefinedVariable is rendered by text/template in place of variables missing from the data
+const undefinedVariable = "<no value>"
+
+// LintSeverity tells if a lint issue makes the recipe invalid or only looks like a mistake.
+type LintSeverity string
+
+// LintSeverity values
+const (
+	LintSeverityError   LintSeverity = "error"
+	LintSeverityWarning LintSeverity = "warning"
+)
+
+// LintIssue is a mistake found in a recipe without connecting to anything.
+type LintIssue struct {
+	Severity LintSeverity `json:"severity"`
+	// Field is the path of the offending field in the recipe, e.g. "sinks[1].name"
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+func (i LintIssue) Error() string {
+	if i.Field == "" {
+		return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", i.Severity, i.Field, i.Message)
+}
+
+// PluginRegistry looks plugins up by name, e.g. registry.Extractors.
+type PluginRegistry interface {
+	Info(name string) (plugins.Info, error)
+}
+
+// Registries holds the plugins known to Lint, plugin names are not checked against nil registries.
+type Registries struct {
+	Extractors PluginRegistry
+	Processors PluginRegistry
+	Sinks      PluginRegistry
+}
+
+// Lint checks the recipe for common mistakes: missing name or source, unknown plugins,
+// no or duplicated sinks, duplicated processors and variables missing when the recipe was read.
+// Unlike validating the recipe with an agent, plugin configs are not validated.
+func Lint(rcp Recipe, registries Registries) (issues []LintIssue) {
+	add := func(severity LintSeverity, field, format string, args ...interface{}) {
+		issues = append(issues, LintIssue{Severity: severity, Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+	checkPlugin := func(reg PluginRegistry, typ plugins.PluginType, field, name string) {
+		if name == "" || reg == nil {
+			return
+		}
+		if _, err := reg.Info(name); err != nil {
+			add(LintSeverityError, field, "unknown %s \"%s\"", typ, name)
+		}
+	}
+	checkProcessors := func(processors []ProcessorRecipe, field string) {
+		seen := make(map[string]bool)
+		for i, p := range processors {
+			pField := fmt.Sprintf("%s[%d]", field, i)
+			if p.Name == "" {
+				add(LintSeverityError, pField+".name", "processor name is required")
+				continue
+			}
+			checkPlugin(registries.Processors, plugins.PluginTypeProcessor, pField+".name", p.Name)
+			if seen[p.Name] {
+				add(LintSeverityWarning, pField+".name", "duplicate processor \"%s\"", p.Name)
+			}
+			seen[p.Name] = true
+			issues = append(issues, lintVariables(p.Config, pField+".config")...)
+		}
+	}
+
+	if strings.TrimSpace(rcp.Name) == "" {
+		add(LintSeverityError, "name", "recipe name is required")
+	}
+	if rcp.Source.Type == "" {
+		add(LintSeverityError, "source.type", "source is required")
+	}
+	if rcp.Timeout < 0 {
+		add(LintSeverityError, "timeout", "timeout must not be negative")
+	}
+	if rcp.MaxRecordsPerSecond < 0 {
+		add(LintSeverityError, "max_records_per_second", "max_records_per_second must not be negative")
+	}
+	if rcp.ExpectedMinRecords < 0 {
+		add(LintSeverityError, "expected_min_records", "expected_min_records must not be negative")
+	}
+	if rcp.ExpectedMaxRecords < 0 {
+		add(LintSeverityError, "expected_max_records", "expected_max_records must not be negative")
+	}
+	if rcp.ExpectedMaxRecords > 0 && rcp.ExpectedMinRecords > rcp.ExpectedMaxRecords {
+		add(LintSeverityError, "expected_max_records", "expected_max_records must not be less than expected_min_records")
+	}
+	checkPlugin(registries.Extractors, plugins.PluginTypeExtractor, "source.type", rcp.Source.Type)
+	issues = append(issues, lintVariables(rcp.Source.Config, "source.config")...)
+
+	if len(rcp.Sinks) == 0 {
+		add(LintSeverityError, "sinks", "at least one sink is required")
+	}
+	for i, s := range rcp.Sinks {
+		field := fmt.Sprintf("sinks[%d]", i)
+		if s.Name == "" {
+			add(LintSeverityError, field+".name", "sink name is required")
+			continue
+		}
+		checkPlugin(registries.Sinks, plugins.PluginTypeSink, field+".name", s.Name)
+		// the same sink may be used twice with different configs, e.g. two kafka topics
+		for j := 0; j < i; j++ {
+			if rcp.Sinks[j].Name == s.Name && reflect.DeepEqual(rcp.Sinks[j].Config, s.Config) {
+				add(LintSeverityWarning, field, "duplicate of sinks[%d]", j)
+				break
+			}
+		}
+		issues = append(issues, lintVariables(s.Config, field+".config")...)
+	}
+
+	checkProcessors(rcp.Processors, "processors")
+	assetTypes := make([]string, 0, len(rcp.AssetProcessors))
+	for assetType := range rcp.AssetProcessors {
+		assetTypes = append(assetTypes, assetType)
+	}
+	sort.Strings(assetTypes)
+	for _, assetType := range assetTypes {
+		checkProcessors(rcp.AssetProcessors[assetType], "asset_processors."+assetType)
+	}
+
+	return
+}
+
+// lintVariables finds the config values holding a variable that was not defined when the recipe was read
+func lintVariables(config map[string]interface{}, field string) (issues []LintIssue) {
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		issues = append(issues, lintValue(config[key], field+"."+key)...)
+	}
+
+	return
+}
+
+func lintValue(value interface{}, field string) (issues []LintIssue) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return lintVariables(v, field)
+	case []interface{}:
+		for i, item := range v {
+			issues = append(issues, lintValue(item, fmt.Sprintf("%s[%d]", field, i))...)
+		}
+	case string:
+		if strings.Contains(v, undefinedVariable) {
+			issues = append(issues, LintIssue{
+				Severity: LintSeverityError,
+				Field:    field,
+				Message:  "undefined variable, set it with a METEOR_ prefixed environment variable",
+			})
+		}
+	}
+
+	return
+}
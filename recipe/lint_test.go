@@ -0,0 +1,153 @@
+package recipe_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/odpf/meteor/plugins"
+	"github.com/odpf/meteor/recipe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type registry map[string]plugins.PluginType
+
+func (r registry) Info(name string) (plugins.Info, error) {
+	if typ, ok := r[name]; ok {
+		return plugins.Info{Description: string(typ)}, nil
+	}
+	return plugins.Info{}, plugins.NotFoundError{Name: name}
+}
+
+func TestLint(t *testing.T) {
+	registries := recipe.Registries{
+		Extractors: registry{"mysql": plugins.PluginTypeExtractor},
+		Processors: registry{"enrich": plugins.PluginTypeProcessor, "filter": plugins.PluginTypeProcessor},
+		Sinks:      registry{"console": plugins.PluginTypeSink, "kafka": plugins.PluginTypeSink},
+	}
+
+	t.Run("should return no issues for a valid recipe", func(t *testing.T) {
+		issues := recipe.Lint(recipe.Recipe{
+			Name:   "mysql-to-kafka",
+			Source: recipe.SourceRecipe{Type: "mysql", Config: map[string]interface{}{"connection_url": "admin@tcp(localhost)/"}},
+			Sinks: []recipe.SinkRecipe{
+				{Name: "kafka", Config: map[string]interface{}{"topic": "tables"}},
+				{Name: "kafka", Config: map[string]interface{}{"topic": "audit"}},
+			},
+			Processors: []recipe.ProcessorRecipe{{Name: "enrich"}, {Name: "filter"}},
+			AssetProcessors: map[string][]recipe.ProcessorRecipe{
+				"table": {{Name: "enrich"}},
+			},
+		}, registries)
+
+		assert.Empty(t, issues)
+	})
+
+	t.Run("should return issues for common mistakes", func(t *testing.T) {
+		issues := recipe.Lint(recipe.Recipe{
+			Source: recipe.SourceRecipe{Type: "mysqll"},
+			Sinks: []recipe.SinkRecipe{
+				{Name: "console"},
+				{Name: "columbus"},
+				{Name: "console"},
+			},
+			Processors: []recipe.ProcessorRecipe{{Name: "enrich"}, {Name: "enrich"}},
+			AssetProcessors: map[string][]recipe.ProcessorRecipe{
+				"table": {{Name: "labels"}},
+			},
+		}, registries)
+
+		assert.Equal(t, []recipe.LintIssue{
+			{Severity: recipe.LintSeverityError, Field: "name", Message: "recipe name is required"},
+			{Severity: recipe.LintSeverityError, Field: "source.type", Message: "unknown extractor \"mysqll\""},
+			{Severity: recipe.LintSeverityError, Field: "sinks[1].name", Message: "unknown sink \"columbus\""},
+			{Severity: recipe.LintSeverityWarning, Field: "sinks[2]", Message: "duplicate of sinks[0]"},
+			{Severity: recipe.LintSeverityWarning, Field: "processors[1].name", Message: "duplicate processor \"enrich\""},
+			{Severity: recipe.LintSeverityError, Field: "asset_processors.table[0].name", Message: "unknown processor \"labels\""},
+		}, issues)
+	})
+
+	t.Run("should return errors for missing source and sinks", func(t *testing.T) {
+		issues := recipe.Lint(recipe.Recipe{Name: "empty"}, registries)
+
+		assert.Equal(t, []recipe.LintIssue{
+			{Severity: recipe.LintSeverityError, Field: "source.type", Message: "source is required"},
+			{Severity: recipe.LintSeverityError, Field: "sinks", Message: "at least one sink is required"},
+		}, issues)
+	})
+
+	t.Run("should return error for negative timeout", func(t *testing.T) {
+		issues := recipe.Lint(recipe.Recipe{
+			Name:    "negative-timeout",
+			Source:  recipe.SourceRecipe{Type: "mysql"},
+			Sinks:   []recipe.SinkRecipe{{Name: "console"}},
+			Timeout: -time.Minute,
+		}, registries)
+
+		assert.Equal(t, []recipe.LintIssue{
+			{Severity: recipe.LintSeverityError, Field: "timeout", Message: "timeout must not be negative"},
+		}, issues)
+	})
+
+	t.Run("should return error for negative max_records_per_second", func(t *testing.T) {
+		issues := recipe.Lint(recipe.Recipe{
+			Name:                "negative-rate",
+			Source:              recipe.SourceRecipe{Type: "mysql"},
+			Sinks:               []recipe.SinkRecipe{{Name: "console"}},
+			MaxRecordsPerSecond: -10,
+		}, registries)
+
+		assert.Equal(t, []recipe.LintIssue{
+			{Severity: recipe.LintSeverityError, Field: "max_records_per_second", Message: "max_records_per_second must not be negative"},
+		}, issues)
+	})
+
+	t.Run("should return error for invalid expected records", func(t *testing.T) {
+		issues := recipe.Lint(recipe.Recipe{
+			Name:               "negative-expectation",
+			Source:             recipe.SourceRecipe{Type: "mysql"},
+			Sinks:              []recipe.SinkRecipe{{Name: "console"}},
+			ExpectedMinRecords: -1,
+			ExpectedMaxRecords: -1,
+		}, registries)
+		assert.Equal(t, []recipe.LintIssue{
+			{Severity: recipe.LintSeverityError, Field: "expected_min_records", Message: "expected_min_records must not be negative"},
+			{Severity: recipe.LintSeverityError, Field: "expected_max_records", Message: "expected_max_records must not be negative"},
+		}, issues)
+
+		issues = recipe.Lint(recipe.Recipe{
+			Name:               "inverted-expectation",
+			Source:             recipe.SourceRecipe{Type: "mysql"},
+			Sinks:              []recipe.SinkRecipe{{Name: "console"}},
+			ExpectedMinRecords: 10,
+			ExpectedMaxRecords: 5,
+		}, registries)
+		assert.Equal(t, []recipe.LintIssue{
+			{Severity: recipe.LintSeverityError, Field: "expected_max_records", Message: "expected_max_records must not be less than expected_min_records"},
+		}, issues)
+	})
+
+	t.Run("should not check plugin names without registries", func(t *testing.T) {
+		issues := recipe.Lint(recipe.Recipe{
+			Name:   "unknown-plugins",
+			Source: recipe.SourceRecipe{Type: "mysqll"},
+			Sinks:  []recipe.SinkRecipe{{Name: "columbus"}},
+		}, recipe.Registries{})
+
+		assert.Empty(t, issues)
+	})
+
+	t.Run("should return errors for undefined variables", func(t *testing.T) {
+		rcps, err := recipe.NewReader().Read("./testdata/test-recipe-variables.yaml")
+		require.NoError(t, err)
+		require.Len(t, rcps, 1)
+
+		issues := recipe.Lint(rcps[0], recipe.Registries{})
+
+		message := "undefined variable, set it with a METEOR_ prefixed environment variable"
+		assert.Equal(t, []recipe.LintIssue{
+			{Severity: recipe.LintSeverityError, Field: "source.config.password", Message: message},
+			{Severity: recipe.LintSeverityError, Field: "source.config.username", Message: message},
+		}, issues)
+	})
+}
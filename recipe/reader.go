@@ -6,22 +6,34 @@ import (
 	"path/filepath"
 	"text/template"
 
+	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 )
 
 // Reader is a struct that reads recipe files.
 type Reader struct {
-	data map[string]string
+	data      map[string]string
+	resolvers map[string]SecretResolver
 }
 
-// NewReader returns a new Reader.
+// NewReader returns a new Reader resolving "env://NAME" secret references.
 func NewReader() *Reader {
 	reader := &Reader{}
 	reader.data = populateData()
+	reader.resolvers = map[string]SecretResolver{
+		"env": EnvResolver{},
+	}
 
 	return reader
 }
 
+// WithSecretResolver resolves the config values referencing secrets with the given scheme,
+// e.g. "vault" for "vault://secret/data/mysql#password", replacing any resolver of the scheme.
+func (r *Reader) WithSecretResolver(scheme string, resolver SecretResolver) *Reader {
+	r.resolvers[scheme] = resolver
+	return r
+}
+
 //  Read loads the list of recipes from a give file or directory path.
 func (r *Reader) Read(path string) (recipes []Recipe, err error) {
 	fi, err := os.Stat(path)
@@ -44,15 +56,73 @@ func (r *Reader) Read(path string) (recipes []Recipe, err error) {
 	return
 }
 
+// FromDir reads the recipes of the files in dir matching the glob pattern, e.g. "*.yaml".
+// See FromGlob for an empty pattern and error handling.
+func (r *Reader) FromDir(dir, pattern string) ([]Recipe, error) {
+	if pattern == "" {
+		return r.FromGlob(filepath.Join(dir, "*.yaml"), filepath.Join(dir, "*.yml"))
+	}
+
+	return r.FromGlob(filepath.Join(dir, pattern))
+}
+
+// FromGlob reads the recipes of the files matching any of the glob patterns, e.g. "recipes/*/*.yaml".
+// Unlike Read, files failing to be read or parsed do not stop the others from being read:
+// recipes are returned along with a FileErrors listing the failing files.
+func (r *Reader) FromGlob(patterns ...string) (recipes []Recipe, err error) {
+	var errs FileErrors
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern \"%s\"", pattern)
+		}
+
+		for _, path := range matches {
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+			if fi, err := os.Stat(path); err != nil || fi.IsDir() {
+				continue
+			}
+
+			recipe, err := r.readFile(path)
+			if err != nil {
+				errs = append(errs, FileError{Path: path, Err: err})
+				continue
+			}
+			recipes = append(recipes, recipe)
+		}
+	}
+	if len(errs) > 0 {
+		return recipes, errs
+	}
+
+	return recipes, nil
+}
+
+// FromDir reads the recipes of the files in dir matching the glob pattern with a new Reader.
+func FromDir(dir, pattern string) ([]Recipe, error) {
+	return NewReader().FromDir(dir, pattern)
+}
+
+// FromGlob reads the recipes of the files matching any of the glob patterns with a new Reader.
+func FromGlob(patterns ...string) ([]Recipe, error) {
+	return NewReader().FromGlob(patterns...)
+}
+
 func (r *Reader) readFile(path string) (recipe Recipe, err error) {
-	template, err := template.ParseFiles(path)
+	template, err := template.New(filepath.Base(path)).Funcs(templateFuncs()).ParseFiles(path)
 	if err != nil {
+		err = errors.Wrapf(err, "failed to parse template \"%s\"", path)
 		return
 	}
 
 	var buff bytes.Buffer
 	err = template.Execute(&buff, r.data)
 	if err != nil {
+		err = errors.Wrapf(err, "failed to render template \"%s\"", path)
 		return
 	}
 
@@ -61,6 +131,7 @@ func (r *Reader) readFile(path string) (recipe Recipe, err error) {
 		return
 	}
 
+	err = resolveSecrets(&recipe, r.resolvers)
 	return
 }
 
@@ -72,6 +143,11 @@ func (r *Reader) readDir(path string) (recipes []Recipe, err error) {
 
 	for _, entry := range entries {
 		recipe, err := r.readFile(filepath.Join(path, entry.Name()))
+		// files which are not recipes are skipped, secrets failing to resolve are not
+		var secretErr SecretError
+		if errors.As(err, &secretErr) {
+			return nil, errors.Wrapf(err, "failed to read \"%s\"", entry.Name())
+		}
 		if err != nil {
 			continue
 		}
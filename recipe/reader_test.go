@@ -1,11 +1,15 @@
 package recipe_test
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/odpf/meteor/recipe"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestReaderRead(t *testing.T) {
@@ -160,3 +164,134 @@ func TestReaderRead(t *testing.T) {
 		assert.Equal(t, expected, results)
 	})
 }
+
+func TestReaderFromGlob(t *testing.T) {
+	writeFiles := func(t *testing.T, files map[string]string) string {
+		dir := t.TempDir()
+		for name, content := range files {
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+			require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		}
+		return dir
+	}
+
+	t.Run("should read recipes of yaml files in directory by default", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{
+			"a.yaml":     "name: a\nsource:\n  type: test-source\n",
+			"b.yml":      "name: b\nsource:\n  type: test-source\n",
+			"notes.txt":  "name: c",
+			"sub/d.yaml": "name: d\nsource:\n  type: test-source\n",
+		})
+
+		recipes, err := recipe.FromDir(dir, "")
+		require.NoError(t, err)
+
+		var names []string
+		for _, rcp := range recipes {
+			names = append(names, rcp.Name)
+		}
+		assert.Equal(t, []string{"a", "b"}, names)
+	})
+
+	t.Run("should read recipes of files matching the pattern", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{
+			"prod/a.yaml":    "name: a\n",
+			"prod/b.yaml":    "name: b\n",
+			"staging/c.yaml": "name: c\n",
+		})
+
+		recipes, err := recipe.FromDir(dir, "prod/*.yaml")
+		require.NoError(t, err)
+		assert.Len(t, recipes, 2)
+
+		recipes, err = recipe.FromGlob(filepath.Join(dir, "*", "*.yaml"), filepath.Join(dir, "prod", "a.yaml"))
+		require.NoError(t, err)
+		assert.Len(t, recipes, 3)
+	})
+
+	t.Run("should collect errors per file and return the other recipes", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{
+			"a.yaml":   "name: a\n",
+			"bad.yaml": "name: [a\n",
+			"tpl.yaml": "name: {{ .name\n",
+			"z.yaml":   "name: z\n",
+		})
+
+		recipes, err := recipe.NewReader().FromDir(dir, "*.yaml")
+
+		require.Len(t, recipes, 2)
+		assert.Equal(t, "a", recipes[0].Name)
+		assert.Equal(t, "z", recipes[1].Name)
+
+		var fileErrs recipe.FileErrors
+		require.True(t, errors.As(err, &fileErrs))
+		require.Len(t, fileErrs, 2)
+		assert.Equal(t, filepath.Join(dir, "bad.yaml"), fileErrs[0].Path)
+		assert.Equal(t, filepath.Join(dir, "tpl.yaml"), fileErrs[1].Path)
+		assert.Contains(t, err.Error(), "bad.yaml")
+	})
+
+	t.Run("should return error for invalid pattern", func(t *testing.T) {
+		_, err := recipe.FromGlob("[")
+		assert.Error(t, err)
+	})
+}
+
+func TestReaderTemplateFuncs(t *testing.T) {
+	writeRecipe := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "recipe.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+
+	t.Run("should render functions before parsing", func(t *testing.T) {
+		os.Setenv("TEST_RECIPE_SCHEMA", "sales")
+		defer os.Unsetenv("TEST_RECIPE_SCHEMA")
+		path := writeRecipe(t, `name: test-recipe
+source:
+  type: test-source
+  config:
+    date_filter: '{{ now | addDate -1 | date "2006-01-02" }}'
+    year: "{{ now | utc | date "2006" }}"
+    hour_ago: '{{ now | addDuration "-1h" | date "2006-01-02" }}'
+    schema: {{ env "TEST_RECIPE_SCHEMA" }}
+`)
+		now := time.Now()
+
+		recipes, err := recipe.NewReader().Read(path)
+		require.NoError(t, err)
+
+		config := recipes[0].Source.Config
+		assert.Equal(t, now.AddDate(0, 0, -1).Format("2006-01-02"), config["date_filter"])
+		assert.Equal(t, now.UTC().Format("2006"), config["year"])
+		assert.Equal(t, now.Add(-time.Hour).Format("2006-01-02"), config["hour_ago"])
+		assert.Equal(t, "sales", config["schema"])
+	})
+
+	t.Run("should return template errors with the file name", func(t *testing.T) {
+		path := writeRecipe(t, "name: {{ now | date }}\n")
+
+		_, err := recipe.NewReader().Read(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), path)
+
+		path = writeRecipe(t, "name: {{ now | addDuration \"yesterday\" }}\n")
+
+		_, err = recipe.NewReader().Read(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to render template \""+path+"\"")
+	})
+}
+
+func TestReaderTimeout(t *testing.T) {
+	t.Run("should parse timeout as a duration", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "recipe.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("name: test-recipe\ntimeout: 1h30m\n"), 0600))
+
+		recipes, err := recipe.NewReader().Read(path)
+		require.NoError(t, err)
+
+		assert.Equal(t, 90*time.Minute, recipes[0].Timeout)
+	})
+}
@@ -1,5 +1,7 @@
 package recipe
 
+import "time"
+
 // SourceRecipe contains the json data for a recipe that is used to generate
 // the source code for a recipe.
 type SourceRecipe struct {
@@ -10,8 +12,11 @@ type SourceRecipe struct {
 // SinkRecipe contains the json data for a recipe that is being used for
 // generating the sink code for a recipe.
 type SinkRecipe struct {
-	Name   string                 `json:"name" yaml:"name" validate:"required"`
-	Config map[string]interface{} `json:"config" yaml:"config"`
+	Name      string                 `json:"name" yaml:"name" validate:"required"`
+	Config    map[string]interface{} `json:"config" yaml:"config"`
+	BatchSize int                    `json:"batch_size,omitempty" yaml:"batch_size,omitempty"`
+	// Types limits the records sent to the sink to the given asset types, e.g. "table", all records are sent if empty
+	Types []string `json:"types,omitempty" yaml:"types,omitempty"`
 }
 
 // ProcessorRecipe contains the json data for a recipe that is being used for
@@ -27,4 +32,16 @@ type Recipe struct {
 	Source     SourceRecipe      `json:"source" yaml:"source" validate:"required"`
 	Sinks      []SinkRecipe      `json:"sinks" yaml:"sinks" validate:"required,min=1"`
 	Processors []ProcessorRecipe `json:"processors" yaml:"processors"`
+	// AssetProcessors holds processor chains keyed by asset type (e.g. "table", "dashboard").
+	// A chain only receives records of its asset type, after they went through Processors.
+	AssetProcessors map[string][]ProcessorRecipe `json:"asset_processors,omitempty" yaml:"asset_processors,omitempty"`
+	// Timeout limits how long a run of the recipe may take, e.g. "30m", no limit is applied if unset.
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// MaxRecordsPerSecond limits the rate records are extracted at, e.g. to spare a busy database.
+	// The rate is not limited if unset.
+	MaxRecordsPerSecond float64 `json:"max_records_per_second,omitempty" yaml:"max_records_per_second,omitempty"`
+	// ExpectedMinRecords and ExpectedMaxRecords fail a run whose RecordCount is out of their range,
+	// e.g. to catch a broken source. No bound is checked if unset.
+	ExpectedMinRecords int `json:"expected_min_records,omitempty" yaml:"expected_min_records,omitempty"`
+	ExpectedMaxRecords int `json:"expected_max_records,omitempty" yaml:"expected_max_records,omitempty"`
 }
@@ -0,0 +1,116 @@
+package recipe
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const maskedValue = "****"
+
+var (
+	// config keys containing any of these are considered secrets
+	secretKeys = []string{"password", "passwd", "token", "secret", "api_key", "apikey", "credential", "private_key"}
+	// matches the password of "user:password@" in connection urls
+	urlPasswordPattern = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9+.-]*://)?([^:/@\s]+):([^@\s]+)@`)
+)
+
+// Render returns the recipe, after variable substitution, encoded in the given format ("yaml" or "json").
+// Secrets in the plugin configs are masked, see Mask.
+func Render(rcp Recipe, format string) ([]byte, error) {
+	masked := Mask(rcp)
+	switch format {
+	case "yaml", "yml":
+		return yaml.Marshal(masked)
+	case "json":
+		return json.MarshalIndent(masked, "", "  ")
+	}
+
+	return nil, fmt.Errorf("unsupported format \"%s\"", format)
+}
+
+// Mask returns a copy of the recipe where config values of secret keys (e.g. password, token)
+// and passwords embedded in connection urls are masked.
+func Mask(rcp Recipe) Recipe {
+	masked := rcp
+	masked.Source.Config = maskMap(rcp.Source.Config)
+
+	masked.Sinks = make([]SinkRecipe, len(rcp.Sinks))
+	for i, s := range rcp.Sinks {
+		s.Config = maskMap(s.Config)
+		masked.Sinks[i] = s
+	}
+
+	masked.Processors = maskProcessors(rcp.Processors)
+	if rcp.AssetProcessors != nil {
+		masked.AssetProcessors = make(map[string][]ProcessorRecipe, len(rcp.AssetProcessors))
+		for assetType, processors := range rcp.AssetProcessors {
+			masked.AssetProcessors[assetType] = maskProcessors(processors)
+		}
+	}
+
+	return masked
+}
+
+func maskProcessors(processors []ProcessorRecipe) []ProcessorRecipe {
+	if processors == nil {
+		return nil
+	}
+
+	masked := make([]ProcessorRecipe, len(processors))
+	for i, p := range processors {
+		p.Config = maskMap(p.Config)
+		masked[i] = p
+	}
+
+	return masked
+}
+
+func maskMap(src map[string]interface{}) map[string]interface{} {
+	if src == nil {
+		return nil
+	}
+
+	dst := make(map[string]interface{}, len(src))
+	for key, value := range src {
+		if isSecretKey(key) {
+			dst[key] = maskedValue
+			continue
+		}
+
+		dst[key] = maskValue(value)
+	}
+
+	return dst
+}
+
+func maskValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return maskMap(v)
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, item := range v {
+			masked[i] = maskValue(item)
+		}
+		return masked
+	case string:
+		return urlPasswordPattern.ReplaceAllString(v, "${1}${2}:"+maskedValue+"@")
+	}
+
+	return value
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return true
+		}
+	}
+
+	return false
+}
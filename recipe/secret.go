@@ -0,0 +1,152 @@
+package recipe
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// secretRefPattern matches secret references such as "vault://secret/data/db#password"
+var secretRefPattern = regexp.MustCompile(`^([a-z][a-z0-9+.-]*)://([^#]*)(?:#(.*))?$`)
+
+// SecretRef is a reference to a secret in a recipe config value, e.g. "vault://secret/data/db#password"
+type SecretRef struct {
+	Scheme string
+	Path   string
+	Key    string
+}
+
+func (ref SecretRef) String() string {
+	if ref.Key == "" {
+		return fmt.Sprintf("%s://%s", ref.Scheme, ref.Path)
+	}
+
+	return fmt.Sprintf("%s://%s#%s", ref.Scheme, ref.Path, ref.Key)
+}
+
+// SecretResolver returns the value of the secrets referenced with its scheme
+type SecretResolver interface {
+	Resolve(ref SecretRef) (string, error)
+}
+
+// SecretResolverFunc adapts a function to a SecretResolver
+type SecretResolverFunc func(ref SecretRef) (string, error)
+
+// Resolve calls f
+func (f SecretResolverFunc) Resolve(ref SecretRef) (string, error) {
+	return f(ref)
+}
+
+// NoopResolver keeps references as they are, e.g. to read recipes without fetching their secrets
+type NoopResolver struct{}
+
+// Resolve returns the reference itself
+func (NoopResolver) Resolve(ref SecretRef) (string, error) {
+	return ref.String(), nil
+}
+
+// EnvResolver resolves "env://NAME" references to the value of the NAME environment variable
+type EnvResolver struct{}
+
+// Resolve returns the value of the environment variable named by the path
+func (EnvResolver) Resolve(ref SecretRef) (string, error) {
+	if ref.Key != "" {
+		return "", fmt.Errorf("unexpected key \"%s\" for environment variable \"%s\"", ref.Key, ref.Path)
+	}
+	value, ok := os.LookupEnv(ref.Path)
+	if !ok {
+		return "", fmt.Errorf("environment variable \"%s\" is not set", ref.Path)
+	}
+
+	return value, nil
+}
+
+// SecretError is returned when a secret referenced by a recipe cannot be resolved
+type SecretError struct {
+	// Field is the path of the config value, e.g. "source.config.password"
+	Field string
+	Err   error
+}
+
+func (e SecretError) Error() string {
+	return fmt.Sprintf("failed to resolve secret for %s: %v", e.Field, e.Err)
+}
+
+func (e SecretError) Unwrap() error {
+	return e.Err
+}
+
+// resolveSecrets replaces the secret references in the plugin configs of the recipe,
+// only references of schemes with a resolver are replaced.
+func resolveSecrets(rcp *Recipe, resolvers map[string]SecretResolver) (err error) {
+	if len(resolvers) == 0 {
+		return nil
+	}
+
+	if err = resolveMap(rcp.Source.Config, "source.config", resolvers); err != nil {
+		return
+	}
+	for i, s := range rcp.Sinks {
+		if err = resolveMap(s.Config, fmt.Sprintf("sinks[%d].config", i), resolvers); err != nil {
+			return
+		}
+	}
+	for i, p := range rcp.Processors {
+		if err = resolveMap(p.Config, fmt.Sprintf("processors[%d].config", i), resolvers); err != nil {
+			return
+		}
+	}
+	for assetType, processors := range rcp.AssetProcessors {
+		for i, p := range processors {
+			if err = resolveMap(p.Config, fmt.Sprintf("asset_processors.%s[%d].config", assetType, i), resolvers); err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+func resolveMap(config map[string]interface{}, field string, resolvers map[string]SecretResolver) error {
+	for key, value := range config {
+		resolved, err := resolveValue(value, field+"."+key, resolvers)
+		if err != nil {
+			return err
+		}
+		config[key] = resolved
+	}
+
+	return nil
+}
+
+func resolveValue(value interface{}, field string, resolvers map[string]SecretResolver) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v, resolveMap(v, field, resolvers)
+	case []interface{}:
+		for i, item := range v {
+			resolved, err := resolveValue(item, fmt.Sprintf("%s[%d]", field, i), resolvers)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = resolved
+		}
+		return v, nil
+	case string:
+		match := secretRefPattern.FindStringSubmatch(v)
+		if match == nil {
+			return v, nil
+		}
+		resolver, ok := resolvers[match[1]]
+		if !ok {
+			return v, nil
+		}
+		secret, err := resolver.Resolve(SecretRef{Scheme: match[1], Path: match[2], Key: match[3]})
+		if err != nil {
+			return nil, SecretError{Field: field, Err: err}
+		}
+		return secret, nil
+	}
+
+	return value, nil
+}
@@ -0,0 +1,142 @@
+package recipe_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/odpf/meteor/recipe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReaderReadSecrets(t *testing.T) {
+	setenv := func(t *testing.T, key, value string) {
+		os.Setenv(key, value)
+		t.Cleanup(func() { os.Unsetenv(key) })
+	}
+
+	t.Run("should resolve secret references with the resolver of their scheme", func(t *testing.T) {
+		setenv(t, "TEST_SOURCE_PASSWORD", "1234")
+		setenv(t, "TEST_SINK_TOKEN", "token")
+		var refs []recipe.SecretRef
+
+		rcps, err := recipe.NewReader().
+			WithSecretResolver("vault", recipe.SecretResolverFunc(func(ref recipe.SecretRef) (string, error) {
+				refs = append(refs, ref)
+				return "key", nil
+			})).
+			Read("./testdata/secrets/test-recipe-secrets.yaml")
+		require.NoError(t, err)
+
+		require.Len(t, rcps, 1)
+		assert.Equal(t, map[string]interface{}{
+			"url":      "http://localhost:8080",
+			"password": "1234",
+			"tls":      map[string]interface{}{"key": "key"},
+		}, rcps[0].Source.Config)
+		assert.Equal(t, map[string]interface{}{
+			"headers": []interface{}{"token"},
+		}, rcps[0].Sinks[0].Config)
+		assert.Equal(t, []recipe.SecretRef{{Scheme: "vault", Path: "secret/data/source", Key: "tls_key"}}, refs)
+	})
+
+	t.Run("should keep references with no-op resolver", func(t *testing.T) {
+		setenv(t, "TEST_SOURCE_PASSWORD", "1234")
+		setenv(t, "TEST_SINK_TOKEN", "token")
+
+		rcps, err := recipe.NewReader().
+			WithSecretResolver("vault", recipe.NoopResolver{}).
+			WithSecretResolver("env", recipe.NoopResolver{}).
+			Read("./testdata/secrets/test-recipe-secrets.yaml")
+		require.NoError(t, err)
+
+		assert.Equal(t, "env://TEST_SOURCE_PASSWORD", rcps[0].Source.Config["password"])
+		assert.Equal(t, map[string]interface{}{"key": "vault://secret/data/source#tls_key"}, rcps[0].Source.Config["tls"])
+	})
+
+	t.Run("should return error identifying the field of unresolved secret", func(t *testing.T) {
+		setenv(t, "TEST_SOURCE_PASSWORD", "1234")
+
+		_, err := recipe.NewReader().
+			WithSecretResolver("vault", recipe.NoopResolver{}).
+			Read("./testdata/secrets/test-recipe-secrets.yaml")
+
+		var secretErr recipe.SecretError
+		require.True(t, errors.As(err, &secretErr))
+		assert.Equal(t, "sinks[0].config.headers[0]", secretErr.Field)
+		assert.EqualError(t, err, "failed to resolve secret for sinks[0].config.headers[0]: environment variable \"TEST_SINK_TOKEN\" is not set")
+	})
+
+	t.Run("should abort reading directory when a secret is not resolved", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "recipe.yaml"), []byte("name: test\nsource:\n  type: test-source\n  config:\n    password: env://TEST_UNSET_PASSWORD\n"), 0600))
+
+		rcps, err := recipe.NewReader().Read(dir)
+		assert.Error(t, err)
+		assert.Empty(t, rcps)
+	})
+}
+
+func TestVaultResolver(t *testing.T) {
+	newServer := func(t *testing.T, requests *int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*requests++
+			assert.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+			switch r.URL.Path {
+			case "/v1/secret/data/mysql":
+				fmt.Fprint(w, `{"data": {"data": {"password": "1234", "port": 3306}, "metadata": {"version": 1}}}`)
+			case "/v1/kv/mysql":
+				fmt.Fprint(w, `{"data": {"password": "5678"}}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"errors": []}`)
+			}
+		}))
+	}
+
+	t.Run("should read keys of kv secrets once per path", func(t *testing.T) {
+		var requests int
+		server := newServer(t, &requests)
+		defer server.Close()
+		resolver := recipe.NewVaultResolver(server.URL, "vault-token")
+
+		password, err := resolver.Resolve(recipe.SecretRef{Scheme: "vault", Path: "secret/data/mysql", Key: "password"})
+		require.NoError(t, err)
+		assert.Equal(t, "1234", password)
+
+		port, err := resolver.Resolve(recipe.SecretRef{Scheme: "vault", Path: "secret/data/mysql", Key: "port"})
+		require.NoError(t, err)
+		assert.Equal(t, "3306", port)
+		assert.Equal(t, 1, requests)
+
+		password, err = resolver.Resolve(recipe.SecretRef{Scheme: "vault", Path: "kv/mysql", Key: "password"})
+		require.NoError(t, err)
+		assert.Equal(t, "5678", password)
+	})
+
+	t.Run("should return error for missing secret or key", func(t *testing.T) {
+		var requests int
+		server := newServer(t, &requests)
+		defer server.Close()
+		resolver := recipe.NewVaultResolver(server.URL, "vault-token")
+
+		_, err := resolver.Resolve(recipe.SecretRef{Scheme: "vault", Path: "secret/data/unknown", Key: "password"})
+		assert.Error(t, err)
+
+		_, err = resolver.Resolve(recipe.SecretRef{Scheme: "vault", Path: "secret/data/mysql", Key: "user"})
+		assert.EqualError(t, err, "key \"user\" not found in vault secret \"secret/data/mysql\"")
+
+		_, err = resolver.Resolve(recipe.SecretRef{Scheme: "vault", Path: "secret/data/mysql"})
+		assert.Error(t, err)
+	})
+
+	t.Run("should return error if address is not configured", func(t *testing.T) {
+		_, err := recipe.NewVaultResolver("", "").Resolve(recipe.SecretRef{Scheme: "vault", Path: "secret/data/mysql", Key: "password"})
+		assert.EqualError(t, err, "vault address is not configured")
+	})
+}
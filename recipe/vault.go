@@ -0,0 +1,107 @@
+package recipe
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxVaultErrorSize limits the response body included in errors
+const maxVaultErrorSize = 1024
+
+// VaultResolver resolves "vault://path#key" references with the key of the secret
+// read at path from the HashiCorp Vault http API, e.g. "vault://secret/data/mysql#password".
+// Both versions of the KV secrets engine are supported.
+type VaultResolver struct {
+	Address string
+	Token   string
+	Client  *http.Client
+
+	mu      sync.Mutex
+	secrets map[string]map[string]interface{}
+}
+
+// NewVaultResolver returns a VaultResolver for the Vault server at address, e.g. "https://vault.example.com:8200"
+func NewVaultResolver(address, token string) *VaultResolver {
+	return &VaultResolver{
+		Address: strings.TrimSuffix(address, "/"),
+		Token:   token,
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Resolve returns the value of the key of the secret, secrets are read once per path
+func (v *VaultResolver) Resolve(ref SecretRef) (string, error) {
+	if v.Address == "" {
+		return "", errors.New("vault address is not configured")
+	}
+	if ref.Key == "" {
+		return "", fmt.Errorf("missing key of vault secret \"%s\"", ref.Path)
+	}
+
+	secret, err := v.read(ref.Path)
+	if err != nil {
+		return "", err
+	}
+	value, ok := secret[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key \"%s\" not found in vault secret \"%s\"", ref.Key, ref.Path)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	return fmt.Sprint(value), nil
+}
+
+func (v *VaultResolver) read(path string) (map[string]interface{}, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if secret, ok := v.secrets[path]; ok {
+		return secret, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, v.Address+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.Token)
+
+	res, err := v.Client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read vault secret \"%s\"", path)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(res.Body, maxVaultErrorSize))
+		return nil, fmt.Errorf("failed to read vault secret \"%s\": status %d: %s", path, res.StatusCode, string(body))
+	}
+
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err = json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode vault secret \"%s\"", path)
+	}
+
+	// secrets of the KV version 2 engine are nested with their metadata
+	secret := response.Data
+	if data, ok := secret["data"].(map[string]interface{}); ok {
+		if _, ok := secret["metadata"]; ok {
+			secret = data
+		}
+	}
+
+	if v.secrets == nil {
+		v.secrets = make(map[string]map[string]interface{})
+	}
+	v.secrets[path] = secret
+	return secret, nil
+}
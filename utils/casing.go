@@ -0,0 +1,186 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// SnakeCase is the casing of field names as marshaled by the models, e.g. "dashboard_urn"
+	SnakeCase = "snake_case"
+	// CamelCase converts field names to lower camel case, e.g. "dashboardUrn"
+	CamelCase = "camelCase"
+)
+
+// ValidateCasing checks if the casing is supported by ConvertCasing, empty being allowed
+func ValidateCasing(casing string) error {
+	switch casing {
+	case "", SnakeCase, CamelCase:
+		return nil
+	}
+
+	return fmt.Errorf("invalid casing \"%s\", supported casings are %s and %s", casing, SnakeCase, CamelCase)
+}
+
+// ConvertCasing converts the field names of jsonBytes, the JSON serialization of the model, to the given casing.
+// Only the field names of the schema of the model are converted, the keys of maps and structs
+// such as labels and attributes are owned by users and kept as they are.
+// An empty casing keeps the field names as they are serialized.
+func ConvertCasing(jsonBytes []byte, model interface{}, casing string) ([]byte, error) {
+	if casing == "" {
+		return jsonBytes, nil
+	}
+	if err := ValidateCasing(casing); err != nil {
+		return nil, err
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(jsonBytes, &data); err != nil {
+		return nil, err
+	}
+	if message, ok := model.(proto.Message); ok {
+		return json.Marshal(convertMessage(data, message.ProtoReflect().Descriptor(), casing))
+	}
+
+	return json.Marshal(convertStruct(data, structMessages(model), casing))
+}
+
+// structMessages returns the descriptors of the message fields of a struct by JSON key,
+// for models which are not protobuf messages, e.g. lineage edges
+func structMessages(model interface{}) map[string]protoreflect.MessageDescriptor {
+	messages := make(map[string]protoreflect.MessageDescriptor)
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return messages
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if message, ok := reflect.Zero(field.Type).Interface().(proto.Message); ok {
+			key := strings.Split(field.Tag.Get("json"), ",")[0]
+			messages[key] = message.ProtoReflect().Descriptor()
+		}
+	}
+
+	return messages
+}
+
+func convertStruct(data interface{}, messages map[string]protoreflect.MessageDescriptor, casing string) interface{} {
+	object, ok := data.(map[string]interface{})
+	if !ok {
+		return data
+	}
+
+	converted := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		if desc, ok := messages[key]; ok {
+			value = convertMessage(value, desc, casing)
+		}
+		converted[convertName(key, casing)] = value
+	}
+
+	return converted
+}
+
+func convertMessage(data interface{}, desc protoreflect.MessageDescriptor, casing string) interface{} {
+	object, ok := data.(map[string]interface{})
+	if !ok || desc.FullName().Parent() == "google.protobuf" {
+		// well known types such as structs are serialized as plain JSON values
+		return data
+	}
+
+	converted := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		field := desc.Fields().ByName(protoreflect.Name(key))
+		if field == nil {
+			// protojson serializes the fields with their JSON names
+			field = desc.Fields().ByJSONName(key)
+		}
+		if field == nil {
+			// keys missing from the schema are converted, but not their values
+			converted[convertName(key, casing)] = value
+			continue
+		}
+		converted[fieldName(field, casing)] = convertValue(value, field, casing)
+	}
+
+	return converted
+}
+
+func convertValue(value interface{}, field protoreflect.FieldDescriptor, casing string) interface{} {
+	if field.IsMap() {
+		// map keys are owned by users, only message values are converted
+		entries, ok := value.(map[string]interface{})
+		if !ok || field.MapValue().Message() == nil {
+			return value
+		}
+		for key, entry := range entries {
+			entries[key] = convertMessage(entry, field.MapValue().Message(), casing)
+		}
+		return entries
+	}
+	if field.Message() == nil {
+		return value
+	}
+	if items, ok := value.([]interface{}); ok {
+		for i, item := range items {
+			items[i] = convertMessage(item, field.Message(), casing)
+		}
+		return items
+	}
+
+	return convertMessage(value, field.Message(), casing)
+}
+
+func fieldName(field protoreflect.FieldDescriptor, casing string) string {
+	if casing == CamelCase {
+		return field.JSONName()
+	}
+
+	return string(field.Name())
+}
+
+func convertName(name, casing string) string {
+	if casing == CamelCase {
+		return toCamelCase(name)
+	}
+
+	return toSnakeCase(name)
+}
+
+func toCamelCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] == "" {
+			continue
+		}
+		runes := []rune(parts[i])
+		runes[0] = unicode.ToUpper(runes[0])
+		parts[i] = string(runes)
+	}
+
+	return strings.Join(parts, "")
+}
+
+func toSnakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
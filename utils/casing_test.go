@@ -0,0 +1,119 @@
+package utils_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/odpf/meteor/models"
+	commonv1beta1 "github.com/odpf/meteor/models/odpf/assets/common/v1beta1"
+	facetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/facets/v1beta1"
+	assetsv1beta1 "github.com/odpf/meteor/models/odpf/assets/v1beta1"
+	"github.com/odpf/meteor/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func TestConvertCasing(t *testing.T) {
+	chart := &assetsv1beta1.Chart{
+		Urn:          "chart-1",
+		DashboardUrn: "dashboard-1",
+		Properties: &facetsv1beta1.Properties{
+			Attributes: utils.TryParseMapToProto(map[string]interface{}{
+				"createdBy": "user-1",
+			}),
+		},
+	}
+	dashboard := &assetsv1beta1.Dashboard{
+		Resource: &commonv1beta1.Resource{Urn: "dashboard-1"},
+		Charts:   []*assetsv1beta1.Chart{chart},
+	}
+	table := &assetsv1beta1.Table{
+		Resource: &commonv1beta1.Resource{Urn: "orders"},
+		Properties: &facetsv1beta1.Properties{
+			Labels: map[string]string{"cost_center": "x"},
+			Attributes: utils.TryParseMapToProto(map[string]interface{}{
+				"database_charset": "utf8",
+				"partition_info":   map[string]interface{}{"partition_field": "created_at"},
+			}),
+		},
+	}
+	marshal := func(t *testing.T, model interface{}) []byte {
+		jsonBytes, err := json.Marshal(model)
+		require.NoError(t, err)
+		return jsonBytes
+	}
+
+	t.Run("should convert field names to camelCase", func(t *testing.T) {
+		out, err := utils.ConvertCasing(marshal(t, dashboard), dashboard, utils.CamelCase)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"resource": {"urn": "dashboard-1"},
+			"charts": [{
+				"urn": "chart-1",
+				"dashboardUrn": "dashboard-1",
+				"properties": {"attributes": {"createdBy": "user-1"}}
+			}]
+		}`, string(out))
+	})
+
+	t.Run("should convert field names to snake_case", func(t *testing.T) {
+		jsonBytes, err := protojson.Marshal(dashboard)
+		require.NoError(t, err)
+
+		out, err := utils.ConvertCasing(jsonBytes, dashboard, utils.SnakeCase)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"resource": {"urn": "dashboard-1"},
+			"charts": [{
+				"urn": "chart-1",
+				"dashboard_urn": "dashboard-1",
+				"properties": {"attributes": {"createdBy": "user-1"}}
+			}]
+		}`, string(out))
+	})
+
+	t.Run("should keep the keys of labels and attributes", func(t *testing.T) {
+		out, err := utils.ConvertCasing(marshal(t, table), table, utils.CamelCase)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"resource": {"urn": "orders"},
+			"properties": {
+				"labels": {"cost_center": "x"},
+				"attributes": {
+					"database_charset": "utf8",
+					"partition_info": {"partition_field": "created_at"}
+				}
+			}
+		}`, string(out))
+	})
+
+	t.Run("should convert field names of lineage edges", func(t *testing.T) {
+		edge := models.NewLineageRecord(
+			&commonv1beta1.Resource{Urn: "orders", Name: "orders"},
+			&commonv1beta1.Resource{Urn: "orders-topic"},
+		).Data()
+		edge.(*models.LineageEdge).Properties = &facetsv1beta1.Properties{Labels: map[string]string{"cost_center": "x"}}
+
+		out, err := utils.ConvertCasing(marshal(t, edge), edge, utils.CamelCase)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"source": {"urn": "orders", "name": "orders"},
+			"target": {"urn": "orders-topic"},
+			"properties": {"labels": {"cost_center": "x"}}
+		}`, string(out))
+	})
+
+	t.Run("should keep field names when casing is empty", func(t *testing.T) {
+		jsonBytes := marshal(t, chart)
+		out, err := utils.ConvertCasing(jsonBytes, chart, "")
+		require.NoError(t, err)
+		assert.Equal(t, jsonBytes, out)
+	})
+
+	t.Run("should return error for invalid casing", func(t *testing.T) {
+		_, err := utils.ConvertCasing(marshal(t, chart), chart, "kebab-case")
+		assert.Error(t, err)
+		assert.Error(t, utils.ValidateCasing("kebab-case"))
+	})
+}
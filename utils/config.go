@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"reflect"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/mcuadros/go-defaults"
 	"github.com/mitchellh/mapstructure"
@@ -10,6 +13,14 @@ var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+	// report config keys instead of struct field names in validation errors
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("mapstructure"), ",", 2)[0]
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
 }
 
 // BuildConfig builds a config struct from a map
@@ -18,6 +18,9 @@ func GetCustomProperties(metadata models.Metadata) map[string]interface{} {
 	}
 
 	// return custom fields as map
+	if customProps.Attributes == nil {
+		return make(map[string]interface{})
+	}
 	return parseToMap(customProps.Attributes)
 }
 
@@ -43,6 +46,8 @@ func SetCustomProperties(metadata models.Metadata, customFields map[string]inter
 		metadata.Properties = properties
 	case *assetsv1beta1.User:
 		metadata.Properties = properties
+	case *models.LineageEdge:
+		metadata.Properties = properties
 	}
 
 	return metadata, nil